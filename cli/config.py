@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+"""Load per-project defaults for devopsos commands from .devopsos.yaml.
+
+The file mirrors the command tree: nested mappings name a command and the
+scalar keys inside them are option names, spelled like the flag (``go-version``
+or ``go_version``). The top-level ``output`` key sets the global --output
+format::
+
+    output: json
+    iac:
+      terraform:
+        cloud: aws
+        out: infra
+    generate:
+      helm:
+        name: orders
+      ci:
+        github:
+          go-version: "1.23"
+
+Values only replace built-in defaults. For every option the precedence is:
+explicit flag > environment variable > .devopsos.yaml > built-in default.
+"""
+
+from __future__ import annotations
+
+import dataclasses
+from pathlib import Path
+from typing import Any, Optional
+
+import click
+import yaml
+
+from cli.output import FORMATS
+
+CONFIG_FILE = ".devopsos.yaml"
+
+
+class ConfigError(ValueError):
+    """Raised when the config file cannot be read or names unknown options."""
+
+
+@dataclasses.dataclass
+class Config:
+    """Settings read from a config file; empty when no file was found."""
+    path: Optional[Path] = None
+    output: Optional[str] = None
+    # Command path ("generate helm") -> option name as written -> value
+    commands: dict[str, dict[str, Any]] = dataclasses.field(default_factory=dict)
+
+    def default_map(self, root: click.Group) -> dict:
+        """Return a Click ``default_map`` for *root* holding the file's values.
+
+        Option names are resolved against the real commands, so a typo in
+        the file fails loudly instead of being ignored.
+        """
+        defaults: dict = {}
+        for command_path, options in self.commands.items():
+            command, node = root, defaults
+            for name in command_path.split():
+                sub = command.get_command(click.Context(command), name) if isinstance(command, click.Group) else None
+                if sub is None:
+                    raise ConfigError(f"{self._where()}: unknown command '{command_path}'")
+                command, node = sub, node.setdefault(name, {})
+            for key, value in options.items():
+                param = _find_param(command, key)
+                if param is None:
+                    raise ConfigError(f"{self._where()}: '{command_path}' has no option '{key}'")
+                if isinstance(value, list):
+                    value = ",".join(str(item) for item in value)
+                node[param.name] = value
+        return defaults
+
+    def _where(self) -> str:
+        return str(self.path or CONFIG_FILE)
+
+
+def _find_param(command: click.Command, key: str) -> Optional[click.Parameter]:
+    wanted = key.replace("_", "-").lstrip("-")
+    for param in command.params:
+        names = [param.name.replace("_", "-")] + [opt.lstrip("-") for opt in param.opts]
+        if wanted in names:
+            return param
+    return None
+
+
+def _flatten(section: dict, prefix: str, commands: dict, where: str) -> None:
+    options = {}
+    for key, value in section.items():
+        if isinstance(value, dict):
+            _flatten(value, f"{prefix} {key}".strip(), commands, where)
+        elif prefix:
+            options[str(key)] = value
+        else:
+            raise ConfigError(f"{where}: unknown top-level key '{key}'")
+    if options:
+        commands[prefix] = options
+
+
+def load_config(path: Optional[str | Path] = None) -> Config:
+    """Read *path*, or ./.devopsos.yaml when *path* is None.
+
+    A missing default file yields an empty Config; a missing explicit *path*
+    is an error.
+    """
+    explicit = path is not None
+    path = Path(path) if explicit else Path.cwd() / CONFIG_FILE
+    if not path.is_file():
+        if explicit:
+            raise ConfigError(f"config file {path} not found")
+        return Config()
+
+    try:
+        data = yaml.safe_load(path.read_text()) or {}
+    except yaml.YAMLError as exc:
+        raise ConfigError(f"{path}: invalid YAML: {exc}") from exc
+    if not isinstance(data, dict):
+        raise ConfigError(f"{path}: expected a mapping at the top level")
+
+    output = data.pop("output", None)
+    if output is not None and output not in FORMATS:
+        raise ConfigError(f"{path}: output must be one of {', '.join(FORMATS)}, got '{output}'")
+    commands: dict[str, dict[str, Any]] = {}
+    _flatten(data, "", commands, str(path))
+    return Config(path=path, output=output, commands=commands)
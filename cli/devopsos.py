@@ -1,5 +1,9 @@
+import contextlib
 import enum
+import io
 import sys
+import click
+from click.core import ParameterSource
 import typer
 from InquirerPy import inquirer
 import json
@@ -17,7 +21,19 @@ import cli.scaffold_sre as scaffold_sre
 import cli.scaffold_devcontainer as scaffold_devcontainer
 import cli.scaffold_unittest as scaffold_unittest
 import cli.scaffold_hardening as scaffold_hardening
+import cli.scaffold_terraform as scaffold_terraform
+import cli.scaffold_pulumi as scaffold_pulumi
+import cli.scaffold_dockerfile as scaffold_dockerfile
+import cli.scaffold_k8s as scaffold_k8s
+import cli.scaffold_helm as scaffold_helm
+import cli.scaffold_ci_github as scaffold_ci_github
+import cli.scaffold_argocd_app as scaffold_argocd_app
+import cli.scaffold_goproject as scaffold_goproject
 import cli.process_first as process_first
+import cli.products as products
+import cli.server_config as server_config
+from cli.config import ConfigError, load_config
+from cli.output import render
 from cli import __version__
 from cli.devcontainer_templates import (
     ALL_BUILD_TOOLS,
@@ -39,6 +55,13 @@ class ProcessFirstSection(str, enum.Enum):
     all = "all"
 
 
+class OutputFormat(str, enum.Enum):
+    """Formats accepted by the global --output flag."""
+    table = "table"
+    json = "json"
+    yaml = "yaml"
+
+
 def _version_callback(value: bool) -> None:
     if value:
         typer.echo(f"devopsos version {__version__}")
@@ -50,6 +73,7 @@ app = typer.Typer(no_args_is_help=True)
 
 @app.callback()
 def main(
+    ctx: typer.Context,
     version: bool = typer.Option(
         False,
         "--version",
@@ -58,6 +82,20 @@ def main(
         callback=_version_callback,
         is_eager=True,
     ),
+    output: OutputFormat = typer.Option(
+        OutputFormat.table,
+        "--output",
+        "-o",
+        envvar="DEVOPS_OS_OUTPUT",
+        help="Result format: table (human-readable), json or yaml. "
+             "json and yaml print the list of generated files instead of the usual summary.",
+    ),
+    config: Optional[Path] = typer.Option(
+        None,
+        "--config",
+        envvar="DEVOPS_OS_CONFIG",
+        help="YAML file with default option values per command (default: ./.devopsos.yaml when present).",
+    ),
 ) -> None:
     """DevOps-OS: automate your entire DevOps lifecycle.
 
@@ -72,9 +110,30 @@ def main(
       python -m cli.devopsos scaffold sre --help                     # SRE resources (SLOs, alerts, dashboards)
       python -m cli.devopsos scaffold devcontainer --help            # dev container configuration
       python -m cli.devopsos scaffold cicd --help                    # combined CI/CD scaffold
+      python -m cli.devopsos iac terraform --cloud aws               # Terraform skeleton with remote state
+      python -m cli.devopsos iac pulumi --language go --cloud aws    # Pulumi project in Go
+      python -m cli.devopsos generate dockerfile --port 8080         # multi-stage Dockerfile for a Go service
+      python -m cli.devopsos generate k8s --name my-app              # Deployment, Service and optional Ingress
+      python -m cli.devopsos generate helm --name myapp              # Helm chart with standard helpers
+      python -m cli.devopsos generate ci github --go-version 1.23    # Go CI workflow with tagged releases
+      python -m cli.devopsos generate argocd --app-name my-app ...   # ArgoCD Application (and AppProject)
+      python -m cli.devopsos init my-svc --module example.com/svc    # new Go service from this layout
+      python -m cli.devopsos products adjust-price --category books --percent 10 --dry-run
       python -m cli.devopsos process-first                           # Process-First SDLC overview
+      python -m cli.devopsos -o json generate k8s --name my-app      # generated file list as JSON
+      python -m cli.devopsos --config ci.yaml iac terraform          # option defaults from a config file
       python -m cli.devopsos --version                               # show installed version
     """
+    try:
+        settings = load_config(config)
+        ctx.default_map = settings.default_map(ctx.command)
+    except ConfigError as exc:
+        typer.echo(f"Error: {exc}", err=True)
+        raise typer.Exit(1)
+    # A flag or DEVOPS_OS_OUTPUT still wins over the file
+    if settings.output and ctx.get_parameter_source("output") is ParameterSource.DEFAULT:
+        output = OutputFormat(settings.output)
+    ctx.obj = {"output": output.value}
 
 # ---------------------------------------------------------------------------
 # scaffold sub-app — each scaffold target is a proper Typer subcommand so
@@ -90,20 +149,54 @@ scaffold_app = typer.Typer(
 app.add_typer(scaffold_app, name="scaffold")
 
 
+def _output_format() -> str:
+    """Return the global --output format of the running command."""
+    ctx = click.get_current_context(silent=True)
+    obj = ctx.find_root().obj if ctx is not None else None
+    return (obj or {}).get("output", OutputFormat.table.value)
+
+
+def _command_name() -> str:
+    """Return the invoked sub-command path, e.g. ``generate helm``."""
+    ctx = click.get_current_context()
+    names = []
+    while ctx.parent is not None:
+        names.append(ctx.info_name)
+        ctx = ctx.parent
+    return " ".join(reversed(names))
+
+
 def _run_scaffold(module_main, flags: list):
     """Call *module_main()* with the given CLI flag list via sys.argv.
 
     Each scaffold module uses argparse internally.  We temporarily replace
     sys.argv so argparse sees only the program name and the explicit flags
     we build from the Typer-parsed options, then restore sys.argv afterwards.
+
+    With ``--output json`` or ``yaml`` the module's own summary is captured
+    and replaced by ``{"command": ..., "files": [...]}`` built from the paths
+    *module_main()* returns. Generators that print their result instead of
+    writing files (such as ``generate argocd`` without --out) return no paths,
+    and their captured text is reported as ``output``.
     """
+    fmt = _output_format()
     _saved = sys.argv[:]
     sys.argv = sys.argv[:1] + flags
     try:
-        module_main()
+        if fmt == OutputFormat.table.value:
+            module_main()
+            return
+        captured = io.StringIO()
+        with contextlib.redirect_stdout(captured):
+            files = module_main() or []
     finally:
         sys.argv = _saved
 
+    result = {"command": _command_name(), "files": [str(path) for path in files]}
+    if not files and captured.getvalue():
+        result["output"] = captured.getvalue()
+    typer.echo(render(result, fmt))
+
 
 def _show_help_if_no_opts(ctx: typer.Context) -> None:
     """Print the command's help text and exit when the user provides no options.
@@ -111,9 +204,10 @@ def _show_help_if_no_opts(ctx: typer.Context) -> None:
     This gives a friendly usage summary instead of silently running with all
     defaults, which can be confusing.  Flags are detected by looking for any
     ``--`` argument in sys.argv; ``--help`` is always handled by Typer/Click
-    before our function body runs, so that path is unaffected.
+    before our function body runs, so that path is unaffected.  Commands with
+    defaults from .devopsos.yaml run as if those options had been given.
     """
-    if not any(a.startswith("-") for a in sys.argv[1:]):
+    if not ctx.default_map and not any(a.startswith("-") for a in sys.argv[1:]):
         typer.echo(ctx.get_help())
         raise typer.Exit()
 
@@ -692,11 +786,463 @@ def scaffold_hardening_cmd(
     _run_scaffold(scaffold_hardening.main, flags)
 
 
+# ---------------------------------------------------------------------------
+# iac sub-app — infrastructure-as-code skeletons rendered from the templates
+# in cli/templates/.
+# ---------------------------------------------------------------------------
+
+iac_app = typer.Typer(
+    name="iac",
+    help="Generate infrastructure-as-code skeletons (Terraform, Pulumi).",
+    no_args_is_help=True,
+)
+app.add_typer(iac_app, name="iac")
+
+
+# ── iac terraform ───────────────────────────────────────────────────────────
+
+@iac_app.command("terraform")
+def iac_terraform_cmd(
+    ctx: typer.Context,
+    cloud: str = typer.Option("aws", envvar="DEVOPS_OS_TERRAFORM_CLOUD",
+                               help="Cloud provider: aws | gcp | azure"),
+    name: str = typer.Option("my-app", envvar="DEVOPS_OS_TERRAFORM_NAME",
+                              help="Project name, used for resource names and the state bucket"),
+    region: str = typer.Option("", envvar="DEVOPS_OS_TERRAFORM_REGION",
+                                help="Region / location (default: us-east-1, us-central1 or eastus)"),
+    out: str = typer.Option("infra", "--out", envvar="DEVOPS_OS_TERRAFORM_OUT",
+                             help="Output directory for the generated .tf files"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_TERRAFORM_FORCE",
+                                help="Overwrite existing files"),
+):
+    """Generate a Terraform skeleton with a remote-state backend.
+
+    \b
+    Output files (default: infra/ directory):
+      infra/main.tf        Locals and a place to add resources
+      infra/variables.tf   Project, environment and region inputs
+      infra/provider.tf    required_providers pin and provider block
+      infra/backend.tf     Remote state (S3, GCS or Azure Storage)
+
+    \b
+    Examples:
+      devopsos iac terraform --cloud aws --name my-app
+      devopsos iac terraform --cloud gcp --region europe-west1 --out infra/gcp
+      devopsos iac terraform --cloud azure --force
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--cloud", cloud,
+        "--name", name,
+        "--out", out,
+    ]
+    if region:
+        flags += ["--region", region]
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_terraform.main, flags)
+
+
+# ── iac pulumi ──────────────────────────────────────────────────────────────
+
+@iac_app.command("pulumi")
+def iac_pulumi_cmd(
+    ctx: typer.Context,
+    language: str = typer.Option("go", envvar="DEVOPS_OS_PULUMI_LANGUAGE",
+                                  help="Program language: go | typescript | python"),
+    cloud: str = typer.Option("aws", envvar="DEVOPS_OS_PULUMI_CLOUD",
+                               help="Cloud provider: aws | gcp | azure"),
+    name: str = typer.Option("my-app", envvar="DEVOPS_OS_PULUMI_NAME",
+                              help="Pulumi project name"),
+    region: str = typer.Option("", envvar="DEVOPS_OS_PULUMI_REGION",
+                                help="Region / location for the dev stack (default depends on --cloud)"),
+    out: str = typer.Option("infra", "--out", envvar="DEVOPS_OS_PULUMI_OUT",
+                             help="Output directory for the Pulumi project"),
+    dry_run: bool = typer.Option(False, "--dry-run", envvar="DEVOPS_OS_PULUMI_DRY_RUN",
+                                  help="Print the files that would be written without writing them"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_PULUMI_FORCE",
+                                help="Overwrite existing files"),
+):
+    """Generate a Pulumi project with a dev stack.
+
+    \b
+    Output files (default: infra/ directory):
+      infra/Pulumi.yaml       Project file with the language runtime
+      infra/Pulumi.dev.yaml   dev stack config (region / location)
+      infra/main.go           Program (index.ts for typescript, __main__.py for python)
+      infra/go.mod            Dependencies (package.json / requirements.txt)
+
+    \b
+    Examples:
+      devopsos iac pulumi --language go --cloud aws
+      devopsos iac pulumi --language typescript --cloud gcp --region europe-west1
+      devopsos iac pulumi --language python --cloud azure --dry-run
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--language", language,
+        "--cloud", cloud,
+        "--name", name,
+        "--out", out,
+    ]
+    if region:
+        flags += ["--region", region]
+    if dry_run:
+        flags.append("--dry-run")
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_pulumi.main, flags)
+
+
+# ---------------------------------------------------------------------------
+# generate sub-app — deployment artifacts for a Go service such as the one in
+# go-project/, rendered from the templates in cli/templates/.
+# ---------------------------------------------------------------------------
+
+generate_app = typer.Typer(
+    name="generate",
+    help="Generate deployment artifacts for a Go service (Dockerfile, Kubernetes manifests, Helm chart, CI, ArgoCD).",
+    no_args_is_help=True,
+)
+app.add_typer(generate_app, name="generate")
+
+
+# ── generate dockerfile ─────────────────────────────────────────────────────
+
+@generate_app.command("dockerfile")
+def generate_dockerfile_cmd(
+    ctx: typer.Context,
+    go_version: str = typer.Option(scaffold_dockerfile.DEFAULT_GO_VERSION, "--go-version",
+                                    envvar="DEVOPS_OS_DOCKERFILE_GO_VERSION",
+                                    help="Go version of the builder image, e.g. 1.23 or 1.23.4"),
+    port: int = typer.Option(scaffold_dockerfile.DEFAULT_PORT, envvar="DEVOPS_OS_DOCKERFILE_PORT",
+                              help="Port the service listens on"),
+    binary: str = typer.Option("server", envvar="DEVOPS_OS_DOCKERFILE_BINARY",
+                                help="Name of the compiled binary"),
+    build_path: str = typer.Option("./cmd", "--build-path", envvar="DEVOPS_OS_DOCKERFILE_BUILD_PATH",
+                                    help="Package to build, relative to the build context"),
+    runtime: str = typer.Option("distroless", envvar="DEVOPS_OS_DOCKERFILE_RUNTIME",
+                                 help="Runtime base image: distroless | scratch"),
+    out: str = typer.Option("Dockerfile", "--out", envvar="DEVOPS_OS_DOCKERFILE_OUT",
+                             help="Output file path"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_DOCKERFILE_FORCE",
+                                help="Overwrite an existing Dockerfile"),
+):
+    """Generate a multi-stage Dockerfile for a Go HTTP service.
+
+    \b
+    The image builds a static binary in a golang stage, runs it from a
+    distroless (or scratch) image as a non-root user, and health-checks
+    GET /healthz on the given port.
+
+    \b
+    Examples:
+      devopsos generate dockerfile --go-version 1.23 --port 8080
+      devopsos generate dockerfile --binary api --build-path ./cmd/api --runtime scratch
+      devopsos generate dockerfile --out go-project/Dockerfile --force
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--go-version", go_version,
+        "--port", str(port),
+        "--binary", binary,
+        "--build-path", build_path,
+        "--runtime", runtime,
+        "--out", out,
+    ]
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_dockerfile.main, flags)
+
+
+# ── generate k8s ────────────────────────────────────────────────────────────
+
+@generate_app.command("k8s")
+def generate_k8s_cmd(
+    ctx: typer.Context,
+    name: str = typer.Option("my-app", envvar="DEVOPS_OS_K8S_NAME",
+                              help="Application name, used for every resource"),
+    image: str = typer.Option("ghcr.io/myorg/my-app:latest", envvar="DEVOPS_OS_K8S_IMAGE",
+                               help="Container image reference"),
+    replicas: int = typer.Option(2, envvar="DEVOPS_OS_K8S_REPLICAS",
+                                  help="Number of pod replicas"),
+    port: int = typer.Option(8080, envvar="DEVOPS_OS_K8S_PORT",
+                              help="Container port the service listens on"),
+    namespace: str = typer.Option("default", envvar="DEVOPS_OS_K8S_NAMESPACE",
+                                   help="Kubernetes namespace"),
+    cpu_request: str = typer.Option("100m", "--cpu-request", envvar="DEVOPS_OS_K8S_CPU_REQUEST",
+                                     help="CPU request"),
+    memory_request: str = typer.Option("64Mi", "--memory-request", envvar="DEVOPS_OS_K8S_MEMORY_REQUEST",
+                                        help="Memory request"),
+    cpu_limit: str = typer.Option("500m", "--cpu-limit", envvar="DEVOPS_OS_K8S_CPU_LIMIT",
+                                   help="CPU limit"),
+    memory_limit: str = typer.Option("128Mi", "--memory-limit", envvar="DEVOPS_OS_K8S_MEMORY_LIMIT",
+                                      help="Memory limit"),
+    liveness_path: str = typer.Option(scaffold_k8s.DEFAULT_LIVENESS_PATH, "--liveness-path",
+                                       envvar="DEVOPS_OS_K8S_LIVENESS_PATH",
+                                       help="HTTP path of the liveness probe"),
+    readiness_path: str = typer.Option(scaffold_k8s.DEFAULT_READINESS_PATH, "--readiness-path",
+                                        envvar="DEVOPS_OS_K8S_READINESS_PATH",
+                                        help="HTTP path of the readiness probe"),
+    ingress_host: str = typer.Option("", "--ingress-host", envvar="DEVOPS_OS_K8S_INGRESS_HOST",
+                                      help="Host name for an Ingress (no Ingress when empty)"),
+    ingress_class: str = typer.Option("", "--ingress-class", envvar="DEVOPS_OS_K8S_INGRESS_CLASS",
+                                       help="ingressClassName for the Ingress"),
+    out: str = typer.Option("k8s", "--out", envvar="DEVOPS_OS_K8S_OUT",
+                             help="Output directory for the manifests"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_K8S_FORCE",
+                                help="Overwrite existing files"),
+):
+    """Generate a Deployment, Service and optional Ingress with health probes.
+
+    \b
+    Output files (default: k8s/ directory):
+      k8s/deployment.yaml   Deployment with liveness and readiness probes
+      k8s/service.yaml      ClusterIP Service on port 80
+      k8s/ingress.yaml      Ingress (only with --ingress-host)
+
+    \b
+    Examples:
+      devopsos generate k8s --name my-app --image ghcr.io/org/my-app:1.0.0
+      devopsos generate k8s --name my-app --namespace prod --replicas 3 --cpu-limit 1
+      devopsos generate k8s --name my-app --ingress-host app.example.com --ingress-class nginx
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--name", name,
+        "--image", image,
+        "--replicas", str(replicas),
+        "--port", str(port),
+        "--namespace", namespace,
+        "--cpu-request", cpu_request,
+        "--memory-request", memory_request,
+        "--cpu-limit", cpu_limit,
+        "--memory-limit", memory_limit,
+        "--liveness-path", liveness_path,
+        "--readiness-path", readiness_path,
+        "--out", out,
+    ]
+    if ingress_host:
+        flags += ["--ingress-host", ingress_host]
+    if ingress_class:
+        flags += ["--ingress-class", ingress_class]
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_k8s.main, flags)
+
+
+# ── generate helm ───────────────────────────────────────────────────────────
+
+@generate_app.command("helm")
+def generate_helm_cmd(
+    ctx: typer.Context,
+    name: str = typer.Option("myapp", envvar="DEVOPS_OS_HELM_NAME",
+                              help="Chart name (DNS-1123 label)"),
+    image: str = typer.Option("", envvar="DEVOPS_OS_HELM_IMAGE",
+                               help="Default image.repository (default: ghcr.io/myorg/<name>)"),
+    port: int = typer.Option(8080, envvar="DEVOPS_OS_HELM_PORT",
+                              help="Container port the service listens on"),
+    out: str = typer.Option("", "--out", envvar="DEVOPS_OS_HELM_OUT",
+                             help="Output directory (default: charts/<name>)"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_HELM_FORCE",
+                                help="Overwrite existing files"),
+):
+    """Generate a Helm chart with the standard naming helpers.
+
+    \b
+    Output files (default: charts/<name>/ directory):
+      Chart.yaml                  chart metadata
+      values.yaml                 image, replicaCount, service.port, probe paths
+      templates/_helpers.tpl      <name>.name, .fullname, .chart, .labels, .selectorLabels
+      templates/deployment.yaml   Deployment with liveness and readiness probes
+      templates/service.yaml      Service targeting the http container port
+
+    \b
+    Examples:
+      devopsos generate helm --name myapp
+      devopsos generate helm --name myapp --image ghcr.io/org/myapp --port 9090
+      devopsos generate helm --name myapp --out deploy/chart --force
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--name", name,
+        "--port", str(port),
+    ]
+    if image:
+        flags += ["--image", image]
+    if out:
+        flags += ["--out", out]
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_helm.main, flags)
+
+
+# ── generate ci github ──────────────────────────────────────────────────────
+
+ci_app = typer.Typer(
+    name="ci",
+    help="Generate CI pipelines for a Go service.",
+    no_args_is_help=True,
+)
+generate_app.add_typer(ci_app, name="ci")
+
+
+@ci_app.command("github")
+def generate_ci_github_cmd(
+    ctx: typer.Context,
+    go_version: str = typer.Option(scaffold_dockerfile.DEFAULT_GO_VERSION, "--go-version",
+                                    envvar="DEVOPS_OS_CI_GITHUB_GO_VERSION",
+                                    help="Go version for actions/setup-go, e.g. 1.23 or 1.23.4"),
+    working_directory: str = typer.Option(".", "--working-directory",
+                                           envvar="DEVOPS_OS_CI_GITHUB_WORKING_DIRECTORY",
+                                           help="Directory containing go.mod, relative to the repository root"),
+    branch: str = typer.Option("main", envvar="DEVOPS_OS_CI_GITHUB_BRANCH",
+                                help="Branch that triggers the workflow"),
+    binary: str = typer.Option("server", envvar="DEVOPS_OS_CI_GITHUB_BINARY",
+                                help="Name of the release binaries"),
+    build_path: str = typer.Option("./cmd", "--build-path", envvar="DEVOPS_OS_CI_GITHUB_BUILD_PATH",
+                                    help="Package the release job builds"),
+    lint: bool = typer.Option(True, "--lint/--no-lint", envvar="DEVOPS_OS_CI_GITHUB_LINT",
+                               help="Run golangci-lint in the test job"),
+    docker: bool = typer.Option(False, "--docker/--no-docker", envvar="DEVOPS_OS_CI_GITHUB_DOCKER",
+                                 help="Add a Docker build/push job"),
+    image: str = typer.Option(scaffold_ci_github.DEFAULT_IMAGE, envvar="DEVOPS_OS_CI_GITHUB_IMAGE",
+                               help="Image name for the Docker job"),
+    out: str = typer.Option(".github/workflows/ci.yml", "--out", envvar="DEVOPS_OS_CI_GITHUB_OUT",
+                             help="Output file path"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_CI_GITHUB_FORCE",
+                                help="Overwrite an existing workflow"),
+):
+    """Generate a GitHub Actions CI workflow for a Go module.
+
+    \b
+    Jobs:
+      test      go vet, go test -race and go build (plus golangci-lint unless --no-lint)
+      docker    build the image and push it outside pull requests (only with --docker)
+      release   cross-compile binaries and publish a GitHub release for v* tags
+
+    \b
+    Examples:
+      devopsos generate ci github --go-version 1.23
+      devopsos generate ci github --working-directory go-project --no-lint
+      devopsos generate ci github --docker --image ghcr.io/myorg/my-service --force
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--go-version", go_version,
+        "--working-directory", working_directory,
+        "--branch", branch,
+        "--binary", binary,
+        "--build-path", build_path,
+        "--lint" if lint else "--no-lint",
+        "--docker" if docker else "--no-docker",
+        "--image", image,
+        "--out", out,
+    ]
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_ci_github.main, flags)
+
+
+# ── generate argocd ─────────────────────────────────────────────────────────
+
+@generate_app.command("argocd")
+def generate_argocd_cmd(
+    ctx: typer.Context,
+    app_name: str = typer.Option("", "--app-name", envvar="DEVOPS_OS_ARGOCD_APP_NAME",
+                                  help="Application name (required)"),
+    repo_url: str = typer.Option("", "--repo-url", envvar="DEVOPS_OS_ARGOCD_APP_REPO_URL",
+                                  help="Git repository URL holding the manifests (required)"),
+    path: str = typer.Option("", envvar="DEVOPS_OS_ARGOCD_APP_PATH",
+                              help="Path inside the repository to the manifests (required)"),
+    revision: str = typer.Option("HEAD", envvar="DEVOPS_OS_ARGOCD_APP_REVISION",
+                                  help="Git revision / branch / tag to sync"),
+    dest_namespace: str = typer.Option("default", "--dest-namespace", envvar="DEVOPS_OS_ARGOCD_APP_DEST_NAMESPACE",
+                                        help="Namespace to deploy into"),
+    dest_server: str = typer.Option("https://kubernetes.default.svc", "--dest-server",
+                                     envvar="DEVOPS_OS_ARGOCD_APP_DEST_SERVER",
+                                     help="Destination Kubernetes API server"),
+    project: str = typer.Option("", envvar="DEVOPS_OS_ARGOCD_APP_PROJECT",
+                                 help="ArgoCD project (default: the app name with --appproject, otherwise 'default')"),
+    appproject: bool = typer.Option(False, "--appproject", envvar="DEVOPS_OS_ARGOCD_APP_APPPROJECT",
+                                     help="Also emit an AppProject for the application"),
+    allow_any_source_repo: bool = typer.Option(False, "--allow-any-source-repo",
+                                                envvar="DEVOPS_OS_ARGOCD_APP_ALLOW_ANY_SOURCE_REPO",
+                                                help="Set AppProject sourceRepos to ['*'] instead of the repo URL"),
+    auto_sync: bool = typer.Option(False, "--auto-sync", envvar="DEVOPS_OS_ARGOCD_APP_AUTO_SYNC",
+                                    help="Enable automated sync with prune and self-heal"),
+    out: str = typer.Option("", "--out", envvar="DEVOPS_OS_ARGOCD_APP_OUT",
+                             help="Output file (default: stdout)"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_ARGOCD_APP_FORCE",
+                                help="Overwrite an existing output file"),
+):
+    """Generate an ArgoCD Application, optionally with a locked-down AppProject.
+
+    \b
+    The AppProject's sourceRepos is limited to --repo-url. Pass
+    --allow-any-source-repo to set it to ["*"] instead.
+
+    \b
+    Examples:
+      devopsos generate argocd --app-name my-app --repo-url https://github.com/org/repo.git --path k8s
+      devopsos generate argocd --app-name my-app --repo-url URL --path k8s --appproject --out app.yaml
+      devopsos generate argocd --app-name my-app --repo-url URL --path k8s --appproject --allow-any-source-repo
+    """
+    _show_help_if_no_opts(ctx)
+    flags = [
+        "--app-name", app_name,
+        "--repo-url", repo_url,
+        "--path", path,
+        "--revision", revision,
+        "--dest-namespace", dest_namespace,
+        "--dest-server", dest_server,
+        "--project", project,
+        "--out", out,
+    ]
+    if appproject:
+        flags.append("--appproject")
+    if allow_any_source_repo:
+        flags.append("--allow-any-source-repo")
+    if auto_sync:
+        flags.append("--auto-sync")
+    if force:
+        flags.append("--force")
+    _run_scaffold(scaffold_argocd_app.main, flags)
+
+
 @app.command()
 def init(
-    directory: str = typer.Option(".", "--dir", help="Target directory in which the .devcontainer folder will be created (defaults to the current directory)"),
+    name: Optional[str] = typer.Argument(None, help="Scaffold a new Go service in <dir>/<name> instead of running the wizard"),
+    directory: str = typer.Option(".", "--dir", help="Target directory in which the .devcontainer folder (or the <name> project) will be created (defaults to the current directory)"),
+    module: str = typer.Option("", "--module", envvar="DEVOPS_OS_INIT_MODULE",
+                                help="Go module path for a new project (default: the project name)"),
+    go_version: str = typer.Option(scaffold_dockerfile.DEFAULT_GO_VERSION, "--go-version",
+                                    envvar="DEVOPS_OS_INIT_GO_VERSION",
+                                    help="Go version for the go directive in go.mod"),
+    force: bool = typer.Option(False, "--force", envvar="DEVOPS_OS_INIT_FORCE",
+                                help="Scaffold a new project into a non-empty directory"),
 ):
-    """Interactive project initializer."""
+    """Interactive project initializer, or a new Go service when NAME is given.
+
+    \b
+    With NAME, creates <dir>/<name> with the go-project/ layout:
+    cmd/main.go, internal/handlers, internal/models, pkg/utils and a go.mod
+    for --module. Without NAME, runs the dev container wizard.
+
+    \b
+    Examples:
+      devopsos init                                          # dev container wizard
+      devopsos init orders --module github.com/you/orders    # new Go service in ./orders
+    """
+    if name is not None:
+        flags = ["--name", name, "--dir", directory, "--go-version", go_version]
+        if module:
+            flags += ["--module", module]
+        if force:
+            flags.append("--force")
+        _run_scaffold(scaffold_goproject.main, flags)
+        return
+
     typer.echo("Welcome to DevOps-OS Init Wizard!")
     typer.echo("Tools are grouped by Process-First DevOps principles (Systems Thinking).\n")
 
@@ -825,6 +1371,118 @@ def init(
     typer.echo(f"Wrote devcontainer.json to {written['json']}")
 
 
+# ---------------------------------------------------------------------------
+# products sub-app — operations against a running go-project API
+# ---------------------------------------------------------------------------
+
+products_app = typer.Typer(
+    name="products",
+    help="Manage the products of a running go-project API.",
+    no_args_is_help=True,
+)
+app.add_typer(products_app, name="products")
+
+
+# ── products adjust-price ───────────────────────────────────────────────────
+
+@products_app.command("adjust-price")
+def products_adjust_price_cmd(
+    category: str = typer.Option(..., "--category", envvar="DEVOPS_OS_PRODUCTS_CATEGORY",
+                                 help="Slug of the category whose products are repriced"),
+    percent: float = typer.Option(..., "--percent", envvar="DEVOPS_OS_PRODUCTS_PERCENT",
+                                  help="Price change in percent: 10 raises prices by 10%, -10 lowers them"),
+    dry_run: bool = typer.Option(False, "--dry-run", envvar="DEVOPS_OS_PRODUCTS_DRY_RUN",
+                                 help="Print the new prices without changing anything"),
+    api_url: str = typer.Option(products.DEFAULT_API_URL, "--api-url", envvar="DEVOPS_OS_API_URL",
+                                help="Base URL of the go-project API"),
+):
+    """Raise or lower the price of every product in a category by a percentage.
+
+    \b
+    Prints a before/after table. Each product is updated on its own with
+    If-Match, so one that fails or was changed meanwhile is reported and
+    the rest still go through; the exit status is 1 if any failed.
+
+    \b
+    Examples:
+      devopsos products adjust-price --category books --percent 10 --dry-run
+      devopsos products adjust-price --category books --percent -15
+      devopsos products adjust-price --category books --percent 5 --api-url https://shop.example.com
+    """
+    try:
+        rows = products.adjust_prices(api_url, category, percent, dry_run=dry_run)
+    except (products.APIError, ValueError) as exc:
+        typer.echo(f"Error: {exc}", err=True)
+        raise typer.Exit(1)
+    fmt = _output_format()
+    if fmt == OutputFormat.table.value and not rows:
+        typer.echo(f"No products in category '{category}'.")
+        return
+    typer.echo(render(rows, fmt))
+    if dry_run and fmt == OutputFormat.table.value:
+        typer.echo("\nDry run: no prices were changed.")
+    if any(row["status"].startswith("error") for row in rows):
+        raise typer.Exit(1)
+
+
+# ---------------------------------------------------------------------------
+# config sub-app — checks on the go-project server configuration
+# ---------------------------------------------------------------------------
+
+config_app = typer.Typer(
+    name="config",
+    help="Check the configuration of the go-project server.",
+    no_args_is_help=True,
+)
+app.add_typer(config_app, name="config")
+
+
+# ── config validate ─────────────────────────────────────────────────────────
+
+@config_app.command("validate")
+def config_validate_cmd(
+    config_file: Optional[Path] = typer.Option(None, "--config", envvar="DEVOPS_OS_SERVER_CONFIG",
+                                               help="JSON config file of the server (default: its CONFIG_FILE)"),
+    server: str = typer.Option(server_config.DEFAULT_SERVER, "--server", envvar="DEVOPS_OS_SERVER_COMMAND",
+                               help="Command that starts the server, e.g. a built binary"),
+    project_dir: Path = typer.Option(Path(server_config.DEFAULT_PROJECT_DIR), "--project-dir",
+                                     envvar="DEVOPS_OS_SERVER_DIR",
+                                     help="Directory the server command runs in"),
+):
+    """Check the server configuration before deploying it.
+
+    \b
+    Runs every check the server makes at startup against the current
+    environment and the config file: the listen address, feature flags,
+    timeouts, rate limit and CORS settings, that the TLS certificate and
+    key exist and load, webhook URLs and the rest. Prints one line per
+    check; the exit status is 1 if any failed, so CI can stop a bad
+    deploy, and 2 if the server could not run, such as when it fails to
+    compile. Relative paths in TLS_CERT_FILE and the other path variables
+    are read from the current directory. The server is not started and no
+    data is touched.
+
+    \b
+    Examples:
+      devopsos config validate
+      devopsos config validate --config deploy/prod.json
+      TLS_CERT_FILE=cert.pem TLS_KEY_FILE=key.pem devopsos config validate --server ./bin/server
+    """
+    try:
+        result = server_config.validate(config_file, server, str(project_dir))
+    except server_config.ServerError as exc:
+        typer.echo(f"Error: {exc}", err=True)
+        raise typer.Exit(2)
+    typer.echo(result.stdout, nl=False)
+    if result.stderr:
+        typer.echo(result.stderr, err=True, nl=False)
+    if not server_config.is_report(result):
+        typer.echo(f"Error: {server!r} exited with status {result.returncode} without a report", err=True)
+        raise typer.Exit(2)
+    if result.returncode != 0:
+        raise typer.Exit(1)
+
+
 @app.command("process-first")
 def process_first_cmd(
     section: ProcessFirstSection = typer.Option(
@@ -856,7 +1514,11 @@ def process_first_cmd(
       python -m cli.process_first
       python -m cli.process_first --section best_practices
     """
-    process_first.display(section.value)
+    fmt = _output_format()
+    if fmt == OutputFormat.table.value:
+        process_first.display(section.value)
+        return
+    typer.echo(render({"section": section.value, "text": process_first.SECTIONS[section.value]}, fmt))
 
 
 if __name__ == "__main__":
@@ -0,0 +1,95 @@
+#!/usr/bin/env python3
+"""Catalog of the generators devopsos offers, for callers that are not the
+command line, such as the MCP server's list_generators tool.
+
+Each entry names the CLI command that runs a generator. Its parameters,
+defaults, help text and environment variables are read from that command's
+Click options, the same tree .devopsos.yaml is resolved against, so the
+catalog cannot drift from what the CLI accepts.
+"""
+
+from __future__ import annotations
+
+import enum
+from typing import Any
+
+import click
+
+# Generator name -> command path under `devopsos`
+GENERATORS = {
+    "terraform": "iac terraform",
+    "pulumi": "iac pulumi",
+    "dockerfile": "generate dockerfile",
+    "k8s": "generate k8s",
+    "helm": "generate helm",
+    "github-actions": "generate ci github",
+    "argocd": "generate argocd",
+}
+
+_JSON_TYPES = {
+    click.INT: "integer",
+    click.FLOAT: "number",
+    click.BOOL: "boolean",
+}
+
+
+def catalog(root: click.Group | None = None) -> list[dict[str, Any]]:
+    """Describe every generator in GENERATORS, in order.
+
+    *root* is the devopsos command tree; the real one is built from
+    cli.devopsos when it is not given. Each description has the generator's
+    ``name``, the ``command`` that runs it, a one-line ``description`` and
+    its ``parameters`` as a JSON Schema object whose properties also carry
+    the option's ``flag`` and ``envvar``.
+    """
+    if root is None:
+        import typer
+        from cli.devopsos import app
+
+        root = typer.main.get_command(app)
+    return [describe(name, path, find_command(root, path)) for name, path in GENERATORS.items()]
+
+
+def find_command(root: click.Group, path: str) -> click.Command:
+    """Return the command at *path*, e.g. ``generate ci github``, under *root*."""
+    command = root
+    for name in path.split():
+        sub = command.get_command(click.Context(command), name) if isinstance(command, click.Group) else None
+        if sub is None:
+            raise KeyError(f"devopsos has no command '{path}'")
+        command = sub
+    return command
+
+
+def describe(name: str, path: str, command: click.Command) -> dict[str, Any]:
+    """Describe the generator *name* run by *command* at *path*."""
+    properties, required = {}, []
+    for param in command.params:
+        if not isinstance(param, click.Option) or param.hidden:
+            continue
+        properties[param.name] = _schema(param)
+        if param.required:
+            required.append(param.name)
+    return {
+        "name": name,
+        "command": f"devopsos {path}",
+        "description": command.get_short_help_str(limit=200),
+        "parameters": {"type": "object", "properties": properties, "required": required},
+    }
+
+
+def _schema(param: click.Option) -> dict[str, Any]:
+    schema: dict[str, Any] = {"type": _JSON_TYPES.get(param.type, "string")}
+    if isinstance(param.type, click.Choice):
+        schema["enum"] = list(param.type.choices)
+    default = param.get_default(click.Context(click.Command("catalog")), call=True)
+    if isinstance(default, enum.Enum):
+        default = default.value
+    if default is not None:
+        schema["default"] = default if isinstance(default, (bool, int, float, str)) else str(default)
+    if param.help:
+        schema["description"] = param.help
+    schema["flag"] = max(param.opts, key=len)
+    if param.envvar:
+        schema["envvar"] = param.envvar
+    return schema
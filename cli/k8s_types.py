@@ -0,0 +1,418 @@
+#!/usr/bin/env python3
+"""Typed subset of the Kubernetes API used by the manifest generators.
+
+Generators build these dataclasses and serialise them with :func:`encode`, so
+field names always come out in the API's camelCase.  :func:`decode` goes the
+other way: it checks a parsed YAML/JSON document against the same classes and
+reports every missing, mistyped or (in strict mode) unknown field, which lets
+tests prove generated output round-trips into the typed structures.
+
+Only the fields the generators emit are modelled; this is not a full schema.
+The Argo CD Application custom resource is included because the ArgoCD
+generators emit it alongside the core kinds.
+"""
+
+from __future__ import annotations
+
+import re
+import typing
+from dataclasses import MISSING, dataclass, field, fields, is_dataclass
+from typing import Any, Optional, Union
+
+IntOrString = Union[int, str]
+
+_DNS1123_LABEL = re.compile(r"^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+
+
+def is_dns1123_label(name: str) -> bool:
+    """Report whether *name* is a valid RFC 1123 label: at most 63 lowercase
+    alphanumeric characters or '-', starting and ending with an alphanumeric."""
+    return len(name) <= 63 and bool(_DNS1123_LABEL.match(name))
+
+
+# ---------------------------------------------------------------------------
+# Shared
+# ---------------------------------------------------------------------------
+
+@dataclass(kw_only=True)
+class ObjectMeta:
+    name: Optional[str] = None
+    namespace: Optional[str] = None
+    labels: Optional[dict[str, str]] = None
+    annotations: Optional[dict[str, str]] = None
+
+
+@dataclass(kw_only=True)
+class LabelSelector:
+    match_labels: dict[str, str]
+
+
+# ---------------------------------------------------------------------------
+# Deployment
+# ---------------------------------------------------------------------------
+
+@dataclass(kw_only=True)
+class ContainerPort:
+    container_port: int
+    name: Optional[str] = None
+    protocol: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class EnvVar:
+    name: str
+    value: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class ResourceRequirements:
+    requests: Optional[dict[str, str]] = None
+    limits: Optional[dict[str, str]] = None
+
+
+@dataclass(kw_only=True)
+class HTTPGetAction:
+    path: str
+    port: IntOrString
+
+
+@dataclass(kw_only=True)
+class Probe:
+    http_get: HTTPGetAction
+    initial_delay_seconds: Optional[int] = None
+    period_seconds: Optional[int] = None
+    timeout_seconds: Optional[int] = None
+    failure_threshold: Optional[int] = None
+
+
+@dataclass(kw_only=True)
+class SecurityContext:
+    run_as_non_root: Optional[bool] = None
+    run_as_user: Optional[int] = None
+    allow_privilege_escalation: Optional[bool] = None
+    read_only_root_filesystem: Optional[bool] = None
+
+
+@dataclass(kw_only=True)
+class Container:
+    name: str
+    image: str
+    ports: Optional[list[ContainerPort]] = None
+    env: Optional[list[EnvVar]] = None
+    resources: Optional[ResourceRequirements] = None
+    liveness_probe: Optional[Probe] = None
+    readiness_probe: Optional[Probe] = None
+    security_context: Optional[SecurityContext] = None
+
+
+@dataclass(kw_only=True)
+class PodSpec:
+    containers: list[Container]
+
+
+@dataclass(kw_only=True)
+class PodTemplateSpec:
+    metadata: ObjectMeta
+    spec: PodSpec
+
+
+@dataclass(kw_only=True)
+class DeploymentSpec:
+    replicas: Optional[int] = None
+    selector: LabelSelector
+    template: PodTemplateSpec
+
+
+@dataclass(kw_only=True)
+class Deployment:
+    API_VERSION = "apps/v1"
+    KIND = "Deployment"
+
+    api_version: str = API_VERSION
+    kind: str = KIND
+    metadata: ObjectMeta
+    spec: DeploymentSpec
+
+
+# ---------------------------------------------------------------------------
+# Service
+# ---------------------------------------------------------------------------
+
+@dataclass(kw_only=True)
+class ServicePort:
+    port: int
+    name: Optional[str] = None
+    target_port: Optional[IntOrString] = None
+    protocol: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class ServiceSpec:
+    ports: list[ServicePort]
+    selector: Optional[dict[str, str]] = None
+    type: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class Service:
+    API_VERSION = "v1"
+    KIND = "Service"
+
+    api_version: str = API_VERSION
+    kind: str = KIND
+    metadata: ObjectMeta
+    spec: ServiceSpec
+
+
+# ---------------------------------------------------------------------------
+# Ingress
+# ---------------------------------------------------------------------------
+
+@dataclass(kw_only=True)
+class ServiceBackendPort:
+    number: Optional[int] = None
+    name: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class IngressServiceBackend:
+    name: str
+    port: ServiceBackendPort
+
+
+@dataclass(kw_only=True)
+class IngressBackend:
+    service: IngressServiceBackend
+
+
+@dataclass(kw_only=True)
+class HTTPIngressPath:
+    path: str
+    path_type: str
+    backend: IngressBackend
+
+
+@dataclass(kw_only=True)
+class HTTPIngressRuleValue:
+    paths: list[HTTPIngressPath]
+
+
+@dataclass(kw_only=True)
+class IngressRule:
+    host: Optional[str] = None
+    http: HTTPIngressRuleValue
+
+
+@dataclass(kw_only=True)
+class IngressTLS:
+    hosts: list[str]
+    secret_name: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class IngressSpec:
+    rules: list[IngressRule]
+    ingress_class_name: Optional[str] = None
+    tls: Optional[list[IngressTLS]] = None
+
+
+@dataclass(kw_only=True)
+class Ingress:
+    API_VERSION = "networking.k8s.io/v1"
+    KIND = "Ingress"
+
+    api_version: str = API_VERSION
+    kind: str = KIND
+    metadata: ObjectMeta
+    spec: IngressSpec
+
+
+# ---------------------------------------------------------------------------
+# Argo CD Application (argoproj.io/v1alpha1)
+# ---------------------------------------------------------------------------
+
+@dataclass(kw_only=True)
+class ApplicationSource:
+    repo_url: str = field(metadata={"json": "repoURL"})
+    path: Optional[str] = None
+    target_revision: Optional[str] = None
+    chart: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class ApplicationDestination:
+    server: Optional[str] = None
+    name: Optional[str] = None
+    namespace: Optional[str] = None
+
+
+@dataclass(kw_only=True)
+class SyncPolicyAutomated:
+    prune: Optional[bool] = None
+    self_heal: Optional[bool] = None
+
+
+@dataclass(kw_only=True)
+class SyncPolicy:
+    automated: Optional[SyncPolicyAutomated] = None
+    sync_options: Optional[list[str]] = None
+
+
+@dataclass(kw_only=True)
+class ApplicationSpec:
+    project: str
+    source: ApplicationSource
+    destination: ApplicationDestination
+    sync_policy: Optional[SyncPolicy] = None
+
+
+@dataclass(kw_only=True)
+class Application:
+    API_VERSION = "argoproj.io/v1alpha1"
+    KIND = "Application"
+
+    api_version: str = API_VERSION
+    kind: str = KIND
+    metadata: ObjectMeta
+    spec: ApplicationSpec
+
+
+KINDS = {cls.KIND: cls for cls in (Deployment, Service, Ingress, Application)}
+
+
+# ---------------------------------------------------------------------------
+# Encoding and decoding
+# ---------------------------------------------------------------------------
+
+class ManifestError(ValueError):
+    """A document does not match its typed schema. ``errors`` lists one
+    ``"<field path>: <problem>"`` string per problem found."""
+
+    def __init__(self, errors: list[str]):
+        super().__init__("; ".join(errors))
+        self.errors = errors
+
+
+def _json_name(name: str) -> str:
+    head, *rest = name.split("_")
+    return head + "".join(part.title() for part in rest)
+
+
+def _field_key(f) -> str:
+    """JSON key of a dataclass field; ``metadata={"json": ...}`` overrides
+    the camelCase default for names like repoURL."""
+    return f.metadata.get("json") or _json_name(f.name)
+
+
+def encode(obj: Any) -> Any:
+    """Convert a dataclass tree into plain dicts and lists with camelCase keys,
+    dropping fields that are None."""
+    if is_dataclass(obj):
+        out = {}
+        for f in fields(obj):
+            value = getattr(obj, f.name)
+            if value is not None:
+                out[_field_key(f)] = encode(value)
+        return out
+    if isinstance(obj, list):
+        return [encode(v) for v in obj]
+    if isinstance(obj, dict):
+        return {k: encode(v) for k, v in obj.items()}
+    return obj
+
+
+def _type_name(tp) -> str:
+    return {int: "an integer", str: "a string", bool: "a boolean"}.get(tp, f"a {getattr(tp, '__name__', tp)}")
+
+
+def _decode(tp, value, path: str, errors: list[str], strict: bool):
+    origin = typing.get_origin(tp)
+    if origin is Union:
+        options = [a for a in typing.get_args(tp) if a is not type(None)]
+        if value is None:
+            return None
+        if len(options) == 1:
+            return _decode(options[0], value, path, errors, strict)
+        for option in options:
+            if _matches_scalar(option, value):
+                return value
+        errors.append(f"{path}: must be {' or '.join(_type_name(o) for o in options)}")
+        return None
+    if origin is list:
+        if not isinstance(value, list):
+            errors.append(f"{path}: must be a list")
+            return None
+        (item,) = typing.get_args(tp)
+        return [_decode(item, v, f"{path}[{i}]", errors, strict) for i, v in enumerate(value)]
+    if origin is dict:
+        if not isinstance(value, dict):
+            errors.append(f"{path}: must be a mapping")
+            return None
+        _, item = typing.get_args(tp)
+        return {k: _decode(item, v, f"{path}.{k}", errors, strict) for k, v in value.items()}
+    if is_dataclass(tp):
+        return _decode_object(tp, value, path, errors, strict)
+    if not _matches_scalar(tp, value):
+        errors.append(f"{path}: must be {_type_name(tp)}")
+        return None
+    return value
+
+
+def _matches_scalar(tp, value) -> bool:
+    if tp is int:
+        return isinstance(value, int) and not isinstance(value, bool)
+    return isinstance(value, tp)
+
+
+def _decode_object(cls, value, path: str, errors: list[str], strict: bool):
+    if not isinstance(value, dict):
+        errors.append(f"{path or cls.__name__}: must be a mapping")
+        return None
+    hints = typing.get_type_hints(cls)
+    prefix = f"{path}." if path else ""
+    kwargs, known = {}, set()
+    for f in fields(cls):
+        key = _field_key(f)
+        known.add(key)
+        if key in value:
+            kwargs[f.name] = _decode(hints[f.name], value[key], prefix + key, errors, strict)
+        elif f.default is MISSING and f.default_factory is MISSING:
+            errors.append(f"{prefix}{key}: is required")
+    if strict:
+        for key in value:
+            if key not in known:
+                errors.append(f"{prefix}{key}: unknown field")
+    try:
+        return cls(**kwargs)
+    except TypeError:
+        return None
+
+
+def decode(cls, doc: Any, strict: bool = True):
+    """Decode *doc* into an instance of *cls*, raising ManifestError listing
+    every problem. Top-level kinds also check apiVersion and kind."""
+    errors: list[str] = []
+    if hasattr(cls, "KIND") and isinstance(doc, dict):
+        for key, want in (("apiVersion", cls.API_VERSION), ("kind", cls.KIND)):
+            if key not in doc:
+                errors.append(f"{key}: is required")
+            elif doc[key] != want:
+                errors.append(f"{key}: must be {want!r}, got {doc[key]!r}")
+        metadata = doc.get("metadata")
+        if isinstance(metadata, dict) and "name" not in metadata:
+            errors.append("metadata.name: is required")
+    obj = _decode_object(cls, doc, "", errors, strict)
+    if errors:
+        raise ManifestError(errors)
+    return obj
+
+
+def decode_manifest(doc: Any, strict: bool = True):
+    """Decode a document into the typed class named by its ``kind``."""
+    if not isinstance(doc, dict):
+        raise ManifestError(["document: must be a mapping"])
+    cls = KINDS.get(doc.get("kind"))
+    if cls is None:
+        supported = ", ".join(KINDS)
+        raise ManifestError([f"kind: must be one of {supported}, got {doc.get('kind')!r}"])
+    return decode(cls, doc, strict=strict)
@@ -0,0 +1,87 @@
+#!/usr/bin/env python3
+"""Render command results as a table, JSON or YAML.
+
+Every command that honours the global ``--output`` / ``-o`` flag passes its
+result through :func:`render`, so scripts can consume the same data the
+table shows. Results may be dicts, lists, dataclasses, enums or paths; they
+are converted to plain JSON-compatible values first.
+"""
+
+from __future__ import annotations
+
+import dataclasses
+import enum
+import json
+from pathlib import PurePath
+from typing import Any
+
+import yaml
+
+FORMATS = ("table", "json", "yaml")
+
+
+def to_plain(value: Any) -> Any:
+    """Convert *value* into dicts, lists and scalars that JSON can encode."""
+    if dataclasses.is_dataclass(value) and not isinstance(value, type):
+        return to_plain(dataclasses.asdict(value))
+    if isinstance(value, dict):
+        return {str(k): to_plain(v) for k, v in value.items()}
+    if isinstance(value, (list, tuple, set, frozenset)):
+        return [to_plain(v) for v in value]
+    if isinstance(value, enum.Enum):
+        return to_plain(value.value)
+    if isinstance(value, PurePath):
+        return str(value)
+    return value
+
+
+def render(value: Any, fmt: str = "table") -> str:
+    """Return *value* formatted as ``table``, ``json`` or ``yaml``.
+
+    Tables have one row per item for a list of mappings (columns are the
+    union of keys, in first-seen order), one FIELD / VALUE row per key for a
+    mapping, and a single VALUE column for a list of scalars. Columns are
+    padded to a common width; the output never has trailing whitespace.
+    """
+    plain = to_plain(value)
+    if fmt == "json":
+        return json.dumps(plain, indent=2)
+    if fmt == "yaml":
+        return yaml.safe_dump(plain, sort_keys=False, default_flow_style=False).rstrip("\n")
+    if fmt == "table":
+        return _table(plain)
+    raise ValueError(f"unknown output format '{fmt}' (choose from: {', '.join(FORMATS)})")
+
+
+def _cell(value: Any) -> str:
+    if value is None:
+        return ""
+    if isinstance(value, bool):
+        return str(value).lower()
+    if isinstance(value, list):
+        return ", ".join(_cell(v) for v in value)
+    if isinstance(value, dict):
+        return json.dumps(value, separators=(",", ":"))
+    return str(value)
+
+
+def _table(plain: Any) -> str:
+    if isinstance(plain, dict):
+        header = ["FIELD", "VALUE"]
+        rows = [[key, _cell(value)] for key, value in plain.items()]
+    elif isinstance(plain, list) and plain and all(isinstance(item, dict) for item in plain):
+        columns: list[str] = []
+        for item in plain:
+            columns.extend(key for key in item if key not in columns)
+        header = [column.upper() for column in columns]
+        rows = [[_cell(item.get(column)) for column in columns] for item in plain]
+    elif isinstance(plain, list):
+        header = ["VALUE"]
+        rows = [[_cell(item)] for item in plain]
+    else:
+        return _cell(plain)
+
+    widths = [max(len(row[i]) for row in [header, *rows]) for i in range(len(header))]
+    lines = ["  ".join(cell.ljust(width) for cell, width in zip(row, widths)).rstrip()
+             for row in [header, *rows]]
+    return "\n".join(lines)
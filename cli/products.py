@@ -0,0 +1,148 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS product price adjustments against the go-project API
+
+Backs ``devopsos products adjust-price``: fetches the products in a category
+from a running go-project server, raises or lowers each price by a
+percentage and writes the new price back with ``PUT /api/products/{id}``.
+
+Each product is re-read just before it is written and the PUT carries that
+response's ETag in ``If-Match``, so a product changed by someone else in the
+meantime is reported as an error instead of being overwritten. An error on
+one product does not stop the others.
+
+Only the standard library is used to talk to the API.
+"""
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from decimal import ROUND_HALF_UP, Decimal, InvalidOperation
+
+DEFAULT_API_URL = "http://localhost:8080"
+
+# The largest page GET /api/products returns.
+PAGE_SIZE = 100
+
+TIMEOUT = 10
+
+
+class APIError(Exception):
+    """A request to the API failed, with the server's message when it sent one."""
+
+    def __init__(self, message, status=None):
+        super().__init__(message)
+        self.status = status
+
+
+def _request(method, url, body=None, headers=None):
+    """Send a JSON request and return ``(decoded body, response headers)``."""
+    data = None
+    headers = dict(headers or {})
+    headers.setdefault("Accept", "application/json")
+    if body is not None:
+        data = json.dumps(body).encode()
+        headers["Content-Type"] = "application/json"
+    req = urllib.request.Request(url, data=data, method=method, headers=headers)
+    try:
+        with urllib.request.urlopen(req, timeout=TIMEOUT) as resp:
+            return json.load(resp), resp.headers
+    except urllib.error.HTTPError as exc:
+        raise APIError(_error_message(exc), exc.code) from None
+    except (urllib.error.URLError, OSError) as exc:
+        reason = getattr(exc, "reason", exc)
+        raise APIError(f"{method} {url}: {reason}") from None
+    except ValueError as exc:
+        raise APIError(f"{method} {url}: response is not JSON: {exc}") from None
+
+
+def _error_message(exc):
+    """Return the message of an ``{"error": {...}}`` envelope, or the HTTP reason."""
+    try:
+        envelope = json.load(exc)
+        message = envelope["error"]["message"]
+    except (ValueError, KeyError, TypeError):
+        message = exc.reason
+    return f"{exc.code} {message}"
+
+
+def list_products(api_url, category):
+    """Return every product in *category*, following the API's pagination."""
+    products, offset = [], 0
+    while True:
+        query = urllib.parse.urlencode({"category": category, "limit": PAGE_SIZE, "offset": offset})
+        page, _ = _request("GET", f"{api_url}/api/products?{query}")
+        products.extend(page["data"])
+        next_offset = page["pagination"].get("next_offset")
+        if next_offset is None:
+            return products
+        offset = next_offset
+
+
+def adjusted_amount(amount, percent):
+    """Return the decimal string *amount* changed by *percent*.
+
+    Like Product.ApplyDiscount in the Go models, the result is rounded half
+    away from zero and keeps the number of decimal places of *amount*, which
+    the API always gives in the currency's minor unit.
+    """
+    try:
+        value = Decimal(amount)
+    except InvalidOperation:
+        raise ValueError(f"price amount {amount!r} is not a decimal") from None
+    changed = value * (Decimal(100) + Decimal(str(percent))) / Decimal(100)
+    return str(changed.quantize(value, rounding=ROUND_HALF_UP))
+
+
+def _replacement(product, amount):
+    """Return the PUT body that gives *product* the new price *amount*."""
+    body = {
+        "name": product["name"],
+        "price": {"amount": amount, "currency": product["price"]["currency"]},
+        "stock": product.get("stock", 0),
+    }
+    if product.get("category"):
+        body["category"] = product["category"]
+    return body
+
+
+def adjust_prices(api_url, category, percent, dry_run=False):
+    """Change the price of every product in *category* by *percent*.
+
+    Returns one row per product with its old and new price and a status:
+    ``planned`` with *dry_run*, ``updated`` once written, ``unchanged`` when
+    rounding leaves the price as it was, or ``error: ...``. Failing to list
+    the category raises APIError.
+    """
+    if percent < -100:
+        raise ValueError("percent must not be below -100")
+    api_url = api_url.rstrip("/")
+    rows = []
+    for listed in list_products(api_url, category):
+        row = {
+            "id": listed["id"],
+            "name": listed["name"],
+            "currency": listed["price"]["currency"],
+            "before": listed["price"]["amount"],
+            "after": "",
+        }
+        rows.append(row)
+        try:
+            if dry_run:
+                row["after"] = adjusted_amount(row["before"], percent)
+                row["status"] = "planned"
+                continue
+            url = f"{api_url}/api/products/{listed['id']}"
+            current, headers = _request("GET", url)
+            row["before"] = current["price"]["amount"]
+            row["after"] = adjusted_amount(row["before"], percent)
+            if row["after"] == row["before"]:
+                row["status"] = "unchanged"
+                continue
+            etag = headers.get("ETag")
+            _request("PUT", url, _replacement(current, row["after"]), {"If-Match": etag} if etag else None)
+            row["status"] = "updated"
+        except (APIError, ValueError) as exc:
+            row["status"] = f"error: {exc}"
+    return rows
@@ -305,6 +305,7 @@ def main():
     print(f"GitOps configs generated ({args.method}):")
     for p in generated:
         print(f"  {p}")
+    return generated
 
 
 if __name__ == "__main__":
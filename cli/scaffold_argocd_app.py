@@ -0,0 +1,137 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS ArgoCD Application Generator
+
+Generates an ArgoCD Application and, with --appproject, an AppProject that
+confines it. The AppProject's sourceRepos is locked to --repo-url unless
+--allow-any-source-repo is given, in which case it is exactly ["*"]. The
+resources are built with the generators in cli/scaffold_argocd.py.
+
+Outputs:
+  stdout               (default; a multi-document YAML stream)
+  <file>               (with --out)
+"""
+
+import os
+import re
+import sys
+import argparse
+from pathlib import Path
+
+import yaml
+
+from cli.k8s_types import is_dns1123_label
+from cli.scaffold_argocd import generate_argocd_application, generate_argocd_appproject
+from cli.templating import write_files
+
+ENV_PREFIX = "DEVOPS_OS_ARGOCD_APP_"
+REQUIRED = ("app_name", "repo_url", "path")
+
+_REPO_URL = re.compile(r"^(https?://|ssh://|git@)\S+$")
+
+
+def _env_flag(name):
+    return os.environ.get(f"{ENV_PREFIX}{name}", "false").lower() in ("true", "1", "yes")
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate an ArgoCD Application for DevOps-OS")
+    parser.add_argument("--app-name", default=os.environ.get(f"{ENV_PREFIX}NAME", ""),
+                        help="Application name (required)")
+    parser.add_argument("--repo-url", default=os.environ.get(f"{ENV_PREFIX}REPO_URL", ""),
+                        help="Git repository URL holding the manifests (required)")
+    parser.add_argument("--path", default=os.environ.get(f"{ENV_PREFIX}PATH", ""),
+                        help="Path inside the repository to the manifests (required)")
+    parser.add_argument("--revision", default=os.environ.get(f"{ENV_PREFIX}REVISION", "HEAD"),
+                        help="Git revision / branch / tag to sync")
+    parser.add_argument("--dest-namespace", default=os.environ.get(f"{ENV_PREFIX}DEST_NAMESPACE", "default"),
+                        help="Namespace to deploy into")
+    parser.add_argument("--dest-server", default=os.environ.get(f"{ENV_PREFIX}DEST_SERVER", "https://kubernetes.default.svc"),
+                        help="Destination Kubernetes API server")
+    parser.add_argument("--project", default=os.environ.get(f"{ENV_PREFIX}PROJECT", ""),
+                        help="ArgoCD project (default: the app name with --appproject, otherwise 'default')")
+    parser.add_argument("--appproject", action="store_true", default=_env_flag("APPPROJECT"),
+                        help="Also emit an AppProject for the application")
+    parser.add_argument("--allow-any-source-repo", action="store_true", default=_env_flag("ALLOW_ANY_SOURCE_REPO"),
+                        help="Set AppProject sourceRepos to ['*'] instead of the repo URL")
+    parser.add_argument("--auto-sync", action="store_true", default=_env_flag("AUTO_SYNC"),
+                        help="Enable automated sync with prune and self-heal")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", ""),
+                        help="Output file (default: stdout)")
+    parser.add_argument("--force", action="store_true", default=_env_flag("FORCE"),
+                        help="Overwrite an existing output file")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generator
+# ---------------------------------------------------------------------------
+
+def validate_args(args):
+    """Raise ValueError describing missing or invalid options."""
+    missing = [f"--{name.replace('_', '-')}" for name in REQUIRED if not getattr(args, name)]
+    if missing:
+        raise ValueError(f"missing required options: {', '.join(missing)}")
+    for label, value in (("app name", args.app_name), ("destination namespace", args.dest_namespace),
+                         ("project", args.project)):
+        if value and not is_dns1123_label(value):
+            raise ValueError(
+                f"{label} '{value}' is not a valid DNS-1123 label "
+                "(lowercase letters, digits and '-', at most 63 characters)")
+    if not _REPO_URL.match(args.repo_url):
+        raise ValueError(f"repo URL '{args.repo_url}' must start with https://, http://, ssh:// or git@")
+    if args.allow_any_source_repo and not args.appproject:
+        raise ValueError("--allow-any-source-repo only applies to the AppProject; add --appproject")
+
+
+def build_documents(args):
+    """Return the manifests to emit: the AppProject (when requested) first, then the Application."""
+    validate_args(args)
+    project = args.project or (args.app_name if args.appproject else "default")
+    # The shared generators take the `scaffold argocd` option names
+    shared = argparse.Namespace(
+        name=args.app_name, repo=args.repo_url, revision=args.revision, path=args.path,
+        namespace=args.dest_namespace, server=args.dest_server, project=project,
+        auto_sync=args.auto_sync, allow_any_source_repo=args.allow_any_source_repo,
+    )
+    docs = []
+    if args.appproject:
+        appproject = generate_argocd_appproject(shared)
+        appproject["spec"]["sourceRepos"] = ["*"] if args.allow_any_source_repo else [args.repo_url]
+        docs.append(appproject)
+    docs.append(generate_argocd_application(shared))
+    return docs
+
+
+def render_documents(args):
+    return yaml.safe_dump_all(build_documents(args), sort_keys=False, default_flow_style=False)
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        content = render_documents(args)
+        if not args.out:
+            sys.stdout.write(content)
+            return []
+        written = write_files({Path(args.out): content}, force=args.force)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"ArgoCD manifests generated ({args.app_name}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
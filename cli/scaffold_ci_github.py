@@ -0,0 +1,134 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS GitHub Actions CI Generator for Go Services
+
+Generates a GitHub Actions workflow for a Go module such as go-project/:
+a test job that runs go vet, go test -race and go build, an optional
+golangci-lint step, an optional Docker build/push job, and a release job that
+only runs for v* tags and publishes cross-compiled binaries. The templates live
+in cli/templates/ci/github/.
+
+Outputs:
+  .github/workflows/ci.yml   (default; see --out)
+"""
+
+import os
+import sys
+import argparse
+from pathlib import Path
+
+import yaml
+
+from cli.scaffold_dockerfile import DEFAULT_GO_VERSION, validate_go_version
+from cli.templating import load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_CI_GITHUB_"
+DEFAULT_IMAGE = "ghcr.io/${{ github.repository }}"
+
+# Credentials for docker/login-action; GITHUB_TOKEN only works for ghcr.io
+_GHCR_CREDENTIALS = ("${{ github.actor }}", "${{ secrets.GITHUB_TOKEN }}")
+_REGISTRY_CREDENTIALS = ("${{ secrets.REGISTRY_USERNAME }}", "${{ secrets.REGISTRY_PASSWORD }}")
+
+
+def _env_flag(name, default):
+    return os.environ.get(f"{ENV_PREFIX}{name}", default).lower() in ("true", "1", "yes")
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate a GitHub Actions CI workflow for a Go service")
+    parser.add_argument("--go-version", default=os.environ.get(f"{ENV_PREFIX}GO_VERSION", DEFAULT_GO_VERSION),
+                        help="Go version for actions/setup-go, e.g. 1.23 or 1.23.4")
+    parser.add_argument("--working-directory", default=os.environ.get(f"{ENV_PREFIX}WORKING_DIRECTORY", "."),
+                        help="Directory containing go.mod, relative to the repository root")
+    parser.add_argument("--branch", default=os.environ.get(f"{ENV_PREFIX}BRANCH", "main"),
+                        help="Branch that triggers the workflow on push and pull_request")
+    parser.add_argument("--binary", default=os.environ.get(f"{ENV_PREFIX}BINARY", "server"),
+                        help="Name of the release binaries")
+    parser.add_argument("--build-path", default=os.environ.get(f"{ENV_PREFIX}BUILD_PATH", "./cmd"),
+                        help="Package the release job builds")
+    parser.add_argument("--lint", action=argparse.BooleanOptionalAction, default=_env_flag("LINT", "true"),
+                        help="Run golangci-lint in the test job")
+    parser.add_argument("--docker", action=argparse.BooleanOptionalAction, default=_env_flag("DOCKER", "false"),
+                        help="Add a job that builds the Docker image and pushes it outside pull requests")
+    parser.add_argument("--image", default=os.environ.get(f"{ENV_PREFIX}IMAGE", DEFAULT_IMAGE),
+                        help="Image name for the Docker job")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", ".github/workflows/ci.yml"),
+                        help="Output file path")
+    parser.add_argument("--force", action="store_true", default=_env_flag("FORCE", "false"),
+                        help="Overwrite an existing workflow")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generator
+# ---------------------------------------------------------------------------
+
+def _registry(image):
+    """Return the registry host of an image reference (docker.io when implicit)."""
+    head, _, rest = image.partition("/")
+    if rest and ("." in head or ":" in head or head == "localhost"):
+        return head
+    return "docker.io"
+
+
+def _include(template, key, fragment):
+    """Replace the line holding __KEY__ with *fragment*, or drop it when empty."""
+    return template.replace(f"__{key}__\n", fragment)
+
+
+def render_workflow(go_version=DEFAULT_GO_VERSION, working_directory=".", branch="main",
+                    binary="server", build_path="./cmd", lint=True, docker=False,
+                    image=DEFAULT_IMAGE):
+    """Return the rendered workflow YAML, after checking that it parses."""
+    validate_go_version(go_version)
+    registry = _registry(image)
+    username, password = _GHCR_CREDENTIALS if registry == "ghcr.io" else _REGISTRY_CREDENTIALS
+
+    template = load_template("ci", "github", "ci.yml.tpl")
+    template = _include(template, "LINT_STEP", load_template("ci", "github", "lint-step.yml.tpl") if lint else "")
+    template = _include(template, "DOCKER_JOB", load_template("ci", "github", "docker-job.yml.tpl") if docker else "")
+    content = render(template, {
+        "GO_VERSION": go_version,
+        "WORKING_DIRECTORY": working_directory,
+        "BRANCH": branch,
+        "BINARY": binary,
+        "BUILD_PATH": build_path,
+        "IMAGE": image,
+        "REGISTRY": registry,
+        "REGISTRY_USERNAME": username,
+        "REGISTRY_PASSWORD": password,
+        "RELEASE_NEEDS": "[test, docker]" if docker else "[test]",
+    })
+    try:
+        yaml.safe_load(content)
+    except yaml.YAMLError as exc:
+        raise ValueError(f"generated workflow is not valid YAML: {exc}") from exc
+    return content
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        content = render_workflow(args.go_version, args.working_directory, args.branch,
+                                  args.binary, args.build_path, args.lint, args.docker, args.image)
+        written = write_files({Path(args.out): content}, force=args.force)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"GitHub Actions CI workflow generated (go {args.go_version}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
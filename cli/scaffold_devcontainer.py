@@ -356,6 +356,7 @@ def main():
     print("Dev container configuration generated:")
     print(f"  {env_json_path}")
     print(f"  {dc_json_path}")
+    return [str(env_json_path), str(dc_json_path)]
 
 
 if __name__ == "__main__":
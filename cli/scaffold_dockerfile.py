@@ -0,0 +1,114 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Go Service Dockerfile Generator
+
+Generates a multi-stage Dockerfile for a Go HTTP service such as the one in
+go-project/: a golang builder stage and a distroless (or scratch) runtime
+stage that runs as a non-root user and health-checks GET /healthz.
+The template lives in cli/templates/docker/.
+
+Outputs:
+  Dockerfile   (default; see --out)
+"""
+
+import os
+import re
+import sys
+import argparse
+from pathlib import Path
+
+from cli.templating import load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_DOCKERFILE_"
+RUNTIME_IMAGES = {
+    "distroless": "gcr.io/distroless/static-debian12:nonroot",
+    "scratch": "scratch",
+}
+DEFAULT_GO_VERSION = "1.23"
+DEFAULT_PORT = 8080
+
+_GO_VERSION = re.compile(r"^\d+\.\d+(\.\d+)?$")
+_BINARY_NAME = re.compile(r"^[A-Za-z0-9][A-Za-z0-9._-]*$")
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate a Dockerfile for a Go service")
+    parser.add_argument("--go-version", default=os.environ.get(f"{ENV_PREFIX}GO_VERSION", DEFAULT_GO_VERSION),
+                        help="Go version of the builder image, e.g. 1.23 or 1.23.4")
+    parser.add_argument("--port", type=int, default=int(os.environ.get(f"{ENV_PREFIX}PORT", DEFAULT_PORT)),
+                        help="Port the service listens on")
+    parser.add_argument("--binary", default=os.environ.get(f"{ENV_PREFIX}BINARY", "server"),
+                        help="Name of the compiled binary")
+    parser.add_argument("--build-path", default=os.environ.get(f"{ENV_PREFIX}BUILD_PATH", "./cmd"),
+                        help="Package to build, relative to the build context")
+    parser.add_argument("--runtime", choices=sorted(RUNTIME_IMAGES),
+                        default=os.environ.get(f"{ENV_PREFIX}RUNTIME", "distroless"),
+                        help="Runtime base image: distroless or scratch")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", "Dockerfile"),
+                        help="Output file path")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Overwrite an existing Dockerfile")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generator
+# ---------------------------------------------------------------------------
+
+def validate_go_version(go_version):
+    """Raise ValueError unless *go_version* looks like 1.23 or 1.23.4."""
+    if not _GO_VERSION.match(str(go_version)):
+        raise ValueError(f"go_version must look like 1.23 or 1.23.4, got '{go_version}'")
+
+
+def validate_options(go_version, port, binary):
+    """Raise ValueError describing the first invalid option."""
+    validate_go_version(go_version)
+    if isinstance(port, bool) or not isinstance(port, int) or not 1 <= port <= 65535:
+        raise ValueError(f"port must be between 1 and 65535, got {port!r}")
+    if not _BINARY_NAME.match(str(binary)):
+        raise ValueError(f"binary name may only contain letters, digits, '.', '_' and '-', got '{binary}'")
+
+
+def render_dockerfile(go_version=DEFAULT_GO_VERSION, port=DEFAULT_PORT, binary="server",
+                      build_path="./cmd", runtime="distroless"):
+    """Return the rendered Dockerfile text."""
+    validate_options(go_version, port, binary)
+    if runtime not in RUNTIME_IMAGES:
+        raise ValueError(f"runtime must be one of: {', '.join(sorted(RUNTIME_IMAGES))}")
+    return render(load_template("docker", "Dockerfile.go.tpl"), {
+        "GO_VERSION": go_version,
+        "PORT": port,
+        "BINARY": binary,
+        "BUILD_PATH": build_path,
+        "RUNTIME_IMAGE": RUNTIME_IMAGES[runtime],
+    })
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        content = render_dockerfile(args.go_version, args.port, args.binary,
+                                    args.build_path, args.runtime)
+        written = write_files({Path(args.out): content}, force=args.force)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Dockerfile generated (go {args.go_version}, port {args.port}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
@@ -1084,6 +1084,7 @@ def main():
     print(f"Languages: {args.languages}")
     if args.kubernetes:
         print(f"Kubernetes deployment method: {args.k8s_method}")
+    return [filepath]
 
 if __name__ == "__main__":
     main()
@@ -338,6 +338,7 @@ def main():
     print(f"Languages: {args.languages}")
     if args.kubernetes:
         print(f"Kubernetes deployment method: {args.k8s_method}")
+    return [str(output_path)]
 
 
 if __name__ == "__main__":
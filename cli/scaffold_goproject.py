@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Go Project Generator
+
+Scaffolds a new Go HTTP service with the same layout as go-project/: a main
+package under cmd/, handlers and models under internal/, shared helpers under
+pkg/utils, and a go.mod for the given module path. The generated code only
+uses the standard library, so `go build ./...` works without downloading
+anything. The templates live in cli/templates/goproject/.
+
+Outputs (default: ./<name>/ directory):
+  <name>/
+  ├── go.mod
+  ├── README.md
+  ├── .gitignore
+  ├── cmd/main.go
+  ├── internal/
+  │   ├── handlers/   handler.go, health.go, middleware.go (+ tests)
+  │   └── models/     model.go (+ test)
+  └── pkg/utils/      slug.go (+ test)
+"""
+
+import os
+import re
+import sys
+import argparse
+from pathlib import Path
+
+from cli.scaffold_dockerfile import DEFAULT_GO_VERSION, validate_go_version
+from cli.templating import TEMPLATE_ROOT, load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_INIT_"
+
+# Template names that do not map to the output path by dropping ".tpl"
+_RENAMED = {"gitignore.tpl": ".gitignore"}
+
+_PROJECT_NAME = re.compile(r"^[A-Za-z0-9][A-Za-z0-9._-]*$")
+# One or more slash-separated path elements as allowed in Go module paths
+_MODULE_PATH = re.compile(r"^[A-Za-z0-9][A-Za-z0-9._~-]*(/[A-Za-z0-9._~-]+)*$")
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Scaffold a new Go service for DevOps-OS")
+    parser.add_argument("--name", default=os.environ.get(f"{ENV_PREFIX}NAME", ""),
+                        help="Project name, also the directory created under --dir")
+    parser.add_argument("--module", default=os.environ.get(f"{ENV_PREFIX}MODULE", ""),
+                        help="Go module path (default: the project name)")
+    parser.add_argument("--go-version", default=os.environ.get(f"{ENV_PREFIX}GO_VERSION", DEFAULT_GO_VERSION),
+                        help="Go version for the go directive in go.mod")
+    parser.add_argument("--dir", default=os.environ.get(f"{ENV_PREFIX}DIR", "."),
+                        help="Parent directory of the new project")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Scaffold into a non-empty directory, overwriting existing files")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generator
+# ---------------------------------------------------------------------------
+
+def validate_options(name, module, go_version):
+    """Raise ValueError describing the first invalid option."""
+    if not _PROJECT_NAME.match(name):
+        raise ValueError(f"project name may only contain letters, digits, '.', '_' and '-', got '{name}'")
+    if not _MODULE_PATH.match(module) or "//" in module or module.endswith("/"):
+        raise ValueError(f"'{module}' is not a valid Go module path, e.g. github.com/you/{name}")
+    validate_go_version(go_version)
+
+
+def _template_paths():
+    root = TEMPLATE_ROOT / "goproject"
+    for template in sorted(root.rglob("*.tpl")):
+        rel = template.relative_to(root)
+        out = _RENAMED.get(rel.as_posix(), rel.as_posix()[:-len(".tpl")])
+        yield rel.parts, out
+
+
+def render_project(name, module="", go_version=DEFAULT_GO_VERSION):
+    """Return a mapping of project-relative path to rendered file content."""
+    module = module or name
+    validate_options(name, module, go_version)
+    values = {"NAME": name, "MODULE": module, "GO_VERSION": go_version}
+    return {out: render(load_template("goproject", *parts), values) for parts, out in _template_paths()}
+
+
+def ensure_empty(target, force=False):
+    """Raise FileExistsError when *target* has content and *force* is not set."""
+    target = Path(target)
+    if target.exists() and not target.is_dir():
+        raise FileExistsError(f"{target} exists and is not a directory")
+    if not force and target.is_dir() and any(target.iterdir()):
+        raise FileExistsError(f"refusing to scaffold into non-empty directory {target} (use --force)")
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        if not args.name:
+            raise ValueError("a project name is required")
+        files = render_project(args.name, args.module, args.go_version)
+        target = Path(args.dir) / args.name
+        ensure_empty(target, force=args.force)
+        written = write_files({target / path: content for path, content in files.items()}, force=True)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Go project generated ({args.module or args.name}):")
+    for path in written:
+        print(f"  {path}")
+    print(f"\nNext: cd {target} && go build ./... && go test ./...")
+    return written
+
+
+if __name__ == "__main__":
+    main()
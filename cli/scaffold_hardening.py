@@ -1981,6 +1981,7 @@ def main():
             f"No files generated for --standard={args.standard} --type={args.output_type}. "
             "Check that the standard supports the requested output type."
         )
+    return [str(path) for path in generated]
 
 
 if __name__ == "__main__":
@@ -0,0 +1,113 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Helm Chart Generator
+
+Generates a Helm chart for an HTTP service with the standard naming helpers
+(<chart>.name, <chart>.fullname, <chart>.chart, <chart>.labels and
+<chart>.selectorLabels) and a Deployment and Service that use them. values.yaml
+exposes image.repository / image.tag, replicaCount, service.port and the
+liveness / readiness probe paths. Templates live in cli/templates/helm/.
+
+Outputs (default: ./charts/<name>/ directory):
+  charts/<name>/
+  ├── Chart.yaml
+  ├── values.yaml
+  ├── .helmignore
+  └── templates/
+      ├── _helpers.tpl
+      ├── deployment.yaml
+      └── service.yaml
+"""
+
+import os
+import sys
+import argparse
+from pathlib import Path
+
+from cli.k8s_types import is_dns1123_label
+from cli.templating import load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_HELM_"
+
+# Chart-relative output path -> template under cli/templates/helm/
+TEMPLATE_FILES = {
+    "Chart.yaml": "Chart.yaml.tpl",
+    "values.yaml": "values.yaml.tpl",
+    ".helmignore": "helmignore.tpl",
+    "templates/_helpers.tpl": "templates/_helpers.tpl.tpl",
+    "templates/deployment.yaml": "templates/deployment.yaml.tpl",
+    "templates/service.yaml": "templates/service.yaml.tpl",
+}
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate a Helm chart for DevOps-OS")
+    parser.add_argument("--name", default=os.environ.get(f"{ENV_PREFIX}NAME", "myapp"),
+                        help="Chart name")
+    parser.add_argument("--image", default=os.environ.get(f"{ENV_PREFIX}IMAGE", ""),
+                        help="Default image.repository (default: ghcr.io/myorg/<name>)")
+    parser.add_argument("--port", type=int, default=int(os.environ.get(f"{ENV_PREFIX}PORT", 8080)),
+                        help="Container port the service listens on")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", ""),
+                        help="Output directory (default: charts/<name>)")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Overwrite existing files")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generator
+# ---------------------------------------------------------------------------
+
+def validate_chart_name(name):
+    """Raise ValueError unless *name* is a chart name Helm accepts."""
+    if not is_dns1123_label(name):
+        raise ValueError(
+            f"chart name '{name}' is not a valid DNS-1123 label "
+            "(lowercase letters, digits and '-', at most 63 characters)")
+
+
+def render_chart(name, image="", port=8080):
+    """Return a mapping of chart-relative path to rendered file content."""
+    validate_chart_name(name)
+    if not 1 <= port <= 65535:
+        raise ValueError(f"port must be between 1 and 65535, got {port}")
+    values = {
+        "NAME": name,
+        "IMAGE_REPOSITORY": image or f"ghcr.io/myorg/{name}",
+        "PORT": port,
+    }
+    return {
+        path: render(load_template("helm", *template.split("/")), values)
+        for path, template in TEMPLATE_FILES.items()
+    }
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        rendered = render_chart(args.name, args.image, args.port)
+        out_dir = Path(args.out or os.path.join("charts", args.name))
+        written = write_files({out_dir / path: content for path, content in rendered.items()},
+                              force=args.force)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Helm chart generated ({args.name}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
@@ -540,6 +540,7 @@ def main():
         print(f"Kubernetes deployment method: {args.k8s_method}")
     if args.parameters:
         print("Pipeline includes runtime parameters")
+    return [str(output_path)]
 
 if __name__ == "__main__":
     main()
@@ -0,0 +1,223 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Kubernetes Manifest Generator
+
+Generates a Deployment, a ClusterIP Service and, when --ingress-host is given,
+an Ingress for an HTTP service. Containers get liveness (/healthz) and
+readiness (/readyz) probes, on paths --liveness-path and --readiness-path
+can change, and a non-root security context matching the
+Dockerfile from `devopsos generate dockerfile`. Manifests are built from the
+typed structures in cli/k8s_types.py, so field names match the Kubernetes API.
+
+Outputs (default: ./k8s/ directory):
+  k8s/
+  ├── deployment.yaml
+  ├── service.yaml
+  └── ingress.yaml     (only with --ingress-host)
+"""
+
+import os
+import re
+import sys
+import argparse
+from pathlib import Path
+
+import yaml
+
+from cli import k8s_types as k8s
+from cli.templating import write_files
+
+ENV_PREFIX = "DEVOPS_OS_K8S_"
+
+# Resource quantities such as 250m, 0.5, 128Mi or 1Gi
+_QUANTITY = re.compile(r"^\d+(\.\d+)?(m|k|M|G|T|Ki|Mi|Gi|Ti)?$")
+
+# UID of the distroless "nonroot" user the generated Dockerfile runs as
+NONROOT_UID = 65532
+
+# Probe paths served by the go-project server
+DEFAULT_LIVENESS_PATH = "/healthz"
+DEFAULT_READINESS_PATH = "/readyz"
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate Kubernetes manifests for DevOps-OS")
+    parser.add_argument("--name", default=os.environ.get(f"{ENV_PREFIX}NAME", "my-app"),
+                        help="Application name, used for every resource")
+    parser.add_argument("--image", default=os.environ.get(f"{ENV_PREFIX}IMAGE", "ghcr.io/myorg/my-app:latest"),
+                        help="Container image reference")
+    parser.add_argument("--replicas", type=int, default=int(os.environ.get(f"{ENV_PREFIX}REPLICAS", 2)),
+                        help="Number of pod replicas")
+    parser.add_argument("--port", type=int, default=int(os.environ.get(f"{ENV_PREFIX}PORT", 8080)),
+                        help="Container port the service listens on")
+    parser.add_argument("--namespace", default=os.environ.get(f"{ENV_PREFIX}NAMESPACE", "default"),
+                        help="Kubernetes namespace")
+    parser.add_argument("--cpu-request", default=os.environ.get(f"{ENV_PREFIX}CPU_REQUEST", "100m"),
+                        help="CPU request")
+    parser.add_argument("--memory-request", default=os.environ.get(f"{ENV_PREFIX}MEMORY_REQUEST", "64Mi"),
+                        help="Memory request")
+    parser.add_argument("--cpu-limit", default=os.environ.get(f"{ENV_PREFIX}CPU_LIMIT", "500m"),
+                        help="CPU limit")
+    parser.add_argument("--memory-limit", default=os.environ.get(f"{ENV_PREFIX}MEMORY_LIMIT", "128Mi"),
+                        help="Memory limit")
+    parser.add_argument("--liveness-path",
+                        default=os.environ.get(f"{ENV_PREFIX}LIVENESS_PATH", DEFAULT_LIVENESS_PATH),
+                        help="HTTP path of the liveness probe")
+    parser.add_argument("--readiness-path",
+                        default=os.environ.get(f"{ENV_PREFIX}READINESS_PATH", DEFAULT_READINESS_PATH),
+                        help="HTTP path of the readiness probe")
+    parser.add_argument("--ingress-host", default=os.environ.get(f"{ENV_PREFIX}INGRESS_HOST", ""),
+                        help="Host name for an Ingress (no Ingress when empty)")
+    parser.add_argument("--ingress-class", default=os.environ.get(f"{ENV_PREFIX}INGRESS_CLASS", ""),
+                        help="ingressClassName for the Ingress")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", "k8s"),
+                        help="Output directory")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Overwrite existing files")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generators
+# ---------------------------------------------------------------------------
+
+def validate_args(args):
+    """Raise ValueError describing the first invalid option."""
+    for label, value in (("name", args.name), ("namespace", args.namespace)):
+        if not k8s.is_dns1123_label(value):
+            raise ValueError(
+                f"{label} '{value}' is not a valid DNS-1123 label "
+                "(lowercase letters, digits and '-', at most 63 characters)")
+    if args.replicas < 0:
+        raise ValueError(f"replicas must not be negative, got {args.replicas}")
+    if not 1 <= args.port <= 65535:
+        raise ValueError(f"port must be between 1 and 65535, got {args.port}")
+    for flag in ("liveness_path", "readiness_path"):
+        value = getattr(args, flag)
+        if not value.startswith("/") or any(c.isspace() for c in value):
+            raise ValueError(f"--{flag.replace('_', '-')} '{value}' must be a path starting with '/'")
+    for flag in ("cpu_request", "memory_request", "cpu_limit", "memory_limit"):
+        value = getattr(args, flag)
+        if not _QUANTITY.match(value):
+            raise ValueError(f"--{flag.replace('_', '-')} '{value}' is not a valid resource quantity")
+
+
+def _labels(args):
+    return {"app.kubernetes.io/name": args.name}
+
+
+def _probe(path):
+    return k8s.Probe(
+        http_get=k8s.HTTPGetAction(path=path, port="http"),
+        initial_delay_seconds=5,
+        period_seconds=10,
+        timeout_seconds=2,
+        failure_threshold=3,
+    )
+
+
+def build_deployment(args):
+    labels = _labels(args)
+    container = k8s.Container(
+        name=args.name,
+        image=args.image,
+        ports=[k8s.ContainerPort(container_port=args.port, name="http", protocol="TCP")],
+        env=[k8s.EnvVar(name="PORT", value=str(args.port))],
+        resources=k8s.ResourceRequirements(
+            requests={"cpu": args.cpu_request, "memory": args.memory_request},
+            limits={"cpu": args.cpu_limit, "memory": args.memory_limit},
+        ),
+        liveness_probe=_probe(args.liveness_path),
+        readiness_probe=_probe(args.readiness_path),
+        security_context=k8s.SecurityContext(
+            run_as_non_root=True,
+            run_as_user=NONROOT_UID,
+            allow_privilege_escalation=False,
+            read_only_root_filesystem=True,
+        ),
+    )
+    return k8s.Deployment(
+        metadata=k8s.ObjectMeta(name=args.name, namespace=args.namespace, labels=labels),
+        spec=k8s.DeploymentSpec(
+            replicas=args.replicas,
+            selector=k8s.LabelSelector(match_labels=labels),
+            template=k8s.PodTemplateSpec(
+                metadata=k8s.ObjectMeta(labels=labels),
+                spec=k8s.PodSpec(containers=[container]),
+            ),
+        ),
+    )
+
+
+def build_service(args):
+    labels = _labels(args)
+    return k8s.Service(
+        metadata=k8s.ObjectMeta(name=args.name, namespace=args.namespace, labels=labels),
+        spec=k8s.ServiceSpec(
+            type="ClusterIP",
+            selector=labels,
+            ports=[k8s.ServicePort(name="http", port=80, target_port="http", protocol="TCP")],
+        ),
+    )
+
+
+def build_ingress(args):
+    backend = k8s.IngressBackend(service=k8s.IngressServiceBackend(
+        name=args.name, port=k8s.ServiceBackendPort(name="http")))
+    return k8s.Ingress(
+        metadata=k8s.ObjectMeta(name=args.name, namespace=args.namespace, labels=_labels(args)),
+        spec=k8s.IngressSpec(
+            ingress_class_name=args.ingress_class or None,
+            rules=[k8s.IngressRule(
+                host=args.ingress_host,
+                http=k8s.HTTPIngressRuleValue(paths=[
+                    k8s.HTTPIngressPath(path="/", path_type="Prefix", backend=backend),
+                ]),
+            )],
+        ),
+    )
+
+
+def render_manifests(args):
+    """Return a mapping of file name to YAML for every manifest to write."""
+    validate_args(args)
+    objects = {
+        "deployment.yaml": build_deployment(args),
+        "service.yaml": build_service(args),
+    }
+    if args.ingress_host:
+        objects["ingress.yaml"] = build_ingress(args)
+    return {
+        name: yaml.safe_dump(k8s.encode(obj), sort_keys=False, default_flow_style=False)
+        for name, obj in objects.items()
+    }
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        rendered = render_manifests(args)
+        out_dir = Path(args.out)
+        written = write_files({out_dir / name: content for name, content in rendered.items()},
+                              force=args.force)
+    except (ValueError, FileExistsError) as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Kubernetes manifests generated ({args.name}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
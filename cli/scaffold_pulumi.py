@@ -0,0 +1,149 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Pulumi Project Generator
+
+Generates a starting Pulumi project for AWS, Google Cloud, or Azure in Go,
+TypeScript, or Python. Templates live in cli/templates/pulumi/.
+
+Outputs (default: ./infra/ directory):
+  infra/
+  ├── Pulumi.yaml                       Project file with the language runtime
+  ├── Pulumi.dev.yaml                   "dev" stack config (region / location)
+  ├── main.go | index.ts | __main__.py  Program with one starter resource
+  └── go.mod | package.json | requirements.txt
+"""
+
+import os
+import sys
+import argparse
+from pathlib import Path
+
+from cli.scaffold_terraform import CLOUDS, DEFAULT_REGIONS
+from cli.templating import load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_PULUMI_"
+CLOUD_TITLES = {"aws": "AWS", "gcp": "Google Cloud", "azure": "Azure"}
+
+# Per-language output file names and template extension.
+LANGUAGES = {
+    "go": {"program": "main.go", "ext": "go", "deps": "go.mod"},
+    "typescript": {"program": "index.ts", "ext": "ts", "deps": "package.json"},
+    "python": {"program": "__main__.py", "ext": "py", "deps": "requirements.txt"},
+}
+
+# Provider package and version constraint for every supported
+# (language, cloud) pair, in the syntax of that language's dependency file.
+PROVIDERS = {
+    ("go", "aws"): ("github.com/pulumi/pulumi-aws/sdk/v6", "v6.50.0"),
+    ("go", "gcp"): ("github.com/pulumi/pulumi-gcp/sdk/v7", "v7.38.0"),
+    ("go", "azure"): ("github.com/pulumi/pulumi-azure-native-sdk/resources/v2", "v2.59.0"),
+    ("typescript", "aws"): ("@pulumi/aws", "^6.50.0"),
+    ("typescript", "gcp"): ("@pulumi/gcp", "^7.38.0"),
+    ("typescript", "azure"): ("@pulumi/azure-native", "^2.59.0"),
+    ("python", "aws"): ("pulumi-aws", ">=6.0.0,<7.0.0"),
+    ("python", "gcp"): ("pulumi-gcp", ">=7.0.0,<8.0.0"),
+    ("python", "azure"): ("pulumi-azure-native", ">=2.0.0,<3.0.0"),
+}
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate a Pulumi project for DevOps-OS")
+    parser.add_argument("--language", default=os.environ.get(f"{ENV_PREFIX}LANGUAGE", "go"),
+                        help="Program language: go, typescript or python")
+    parser.add_argument("--cloud", default=os.environ.get(f"{ENV_PREFIX}CLOUD", "aws"),
+                        help="Cloud provider: aws, gcp or azure")
+    parser.add_argument("--name", default=os.environ.get(f"{ENV_PREFIX}NAME", "my-app"),
+                        help="Pulumi project name")
+    parser.add_argument("--region", default=os.environ.get(f"{ENV_PREFIX}REGION", ""),
+                        help="Region / location for the dev stack (default depends on --cloud)")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", "infra"),
+                        help="Output directory")
+    parser.add_argument("--dry-run", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}DRY_RUN", "false").lower() in ("true", "1", "yes"),
+                        help="Print the files that would be written without writing them")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Overwrite existing files")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generators
+# ---------------------------------------------------------------------------
+
+def validate_target(language, cloud):
+    """Raise ValueError unless *language* and *cloud* form a supported pair."""
+    if language not in LANGUAGES:
+        raise ValueError(
+            f"unsupported language '{language}' (choose from: {', '.join(LANGUAGES)})")
+    if cloud not in CLOUDS:
+        raise ValueError(
+            f"unsupported cloud '{cloud}' (choose from: {', '.join(CLOUDS)})")
+    if (language, cloud) not in PROVIDERS:
+        supported = ", ".join(c for lang, c in PROVIDERS if lang == language)
+        raise ValueError(
+            f"{language} is not supported on {cloud} (supported clouds for {language}: {supported})")
+
+
+def render_pulumi(args):
+    """Return a mapping of file name to rendered content for the chosen target."""
+    validate_target(args.language, args.cloud)
+    lang = LANGUAGES[args.language]
+    package, version = PROVIDERS[(args.language, args.cloud)]
+    values = {
+        "NAME": args.name,
+        "CLOUD_TITLE": CLOUD_TITLES[args.cloud],
+        "REGION": args.region or DEFAULT_REGIONS[args.cloud],
+        "PROVIDER_PACKAGE": package,
+        "PROVIDER_VERSION": version,
+    }
+    templates = {
+        "Pulumi.yaml": (args.language, "Pulumi.yaml.tpl"),
+        "Pulumi.dev.yaml": ("stack", f"{args.cloud}.yaml.tpl"),
+        lang["program"]: (args.language, f"{args.cloud}.{lang['ext']}.tpl"),
+        lang["deps"]: (args.language, f"{lang['deps']}.tpl"),
+    }
+    return {
+        name: render(load_template("pulumi", *parts), values)
+        for name, parts in templates.items()
+    }
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    try:
+        rendered = render_pulumi(args)
+    except ValueError as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    out_dir = Path(args.out)
+    files = {out_dir / name: content for name, content in rendered.items()}
+    if args.dry_run:
+        print(f"Pulumi project ({args.language}, {args.cloud}) would write:")
+        for path in files:
+            print(f"  {path}")
+        return list(files)
+
+    try:
+        written = write_files(files, force=args.force)
+    except FileExistsError as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Pulumi project generated ({args.language}, {args.cloud}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
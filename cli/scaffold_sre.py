@@ -502,6 +502,7 @@ def main():
     print("SRE configs generated:")
     for p in generated:
         print(f"  {p}")
+    return generated
 
 
 if __name__ == "__main__":
@@ -0,0 +1,94 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS Terraform Skeleton Generator
+
+Generates a starting Terraform configuration for AWS, Google Cloud, or Azure,
+including a remote-state backend. Templates live in cli/templates/terraform/.
+
+Outputs (default: ./infra/ directory):
+  infra/
+  ├── main.tf        Locals and a place to add resources
+  ├── variables.tf   Project, environment and region inputs
+  ├── provider.tf    required_providers pin and provider block
+  └── backend.tf     Remote state (S3, GCS or Azure Storage)
+"""
+
+import os
+import re
+import sys
+import argparse
+from pathlib import Path
+
+from cli.templating import load_template, render, write_files
+
+ENV_PREFIX = "DEVOPS_OS_TERRAFORM_"
+CLOUDS = ["aws", "gcp", "azure"]
+DEFAULT_REGIONS = {"aws": "us-east-1", "gcp": "us-central1", "azure": "eastus"}
+TEMPLATE_FILES = ["main.tf", "variables.tf", "provider.tf", "backend.tf"]
+
+
+# ---------------------------------------------------------------------------
+# Argument parsing
+# ---------------------------------------------------------------------------
+
+def parse_arguments():
+    parser = argparse.ArgumentParser(description="Generate a Terraform skeleton for DevOps-OS")
+    parser.add_argument("--cloud", choices=CLOUDS,
+                        default=os.environ.get(f"{ENV_PREFIX}CLOUD", "aws"),
+                        help="Cloud provider: aws, gcp or azure")
+    parser.add_argument("--name", default=os.environ.get(f"{ENV_PREFIX}NAME", "my-app"),
+                        help="Project name, used for resource names and the state bucket")
+    parser.add_argument("--region", default=os.environ.get(f"{ENV_PREFIX}REGION", ""),
+                        help="Region / location (default depends on --cloud)")
+    parser.add_argument("--out", default=os.environ.get(f"{ENV_PREFIX}OUT", "infra"),
+                        help="Output directory")
+    parser.add_argument("--force", action="store_true",
+                        default=os.environ.get(f"{ENV_PREFIX}FORCE", "false").lower() in ("true", "1", "yes"),
+                        help="Overwrite existing files")
+    return parser.parse_args()
+
+
+# ---------------------------------------------------------------------------
+# Generators
+# ---------------------------------------------------------------------------
+
+def _storage_account_name(name):
+    """Azure storage account names are 3-24 lowercase letters and digits."""
+    return (re.sub(r"[^a-z0-9]", "", name.lower()) + "tfstate")[:24]
+
+
+def render_terraform(args):
+    """Return a mapping of file name to rendered content for *args.cloud*."""
+    values = {
+        "NAME": args.name,
+        "REGION": args.region or DEFAULT_REGIONS[args.cloud],
+        "STORAGE_ACCOUNT": _storage_account_name(args.name),
+    }
+    return {
+        name: render(load_template("terraform", args.cloud, f"{name}.tpl"), values)
+        for name in TEMPLATE_FILES
+    }
+
+
+# ---------------------------------------------------------------------------
+# Main
+# ---------------------------------------------------------------------------
+
+def main():
+    args = parse_arguments()
+    out_dir = Path(args.out)
+    files = {out_dir / name: content for name, content in render_terraform(args).items()}
+    try:
+        written = write_files(files, force=args.force)
+    except FileExistsError as exc:
+        print(f"Error: {exc}", file=sys.stderr)
+        sys.exit(1)
+
+    print(f"Terraform skeleton generated ({args.cloud}):")
+    for path in written:
+        print(f"  {path}")
+    return written
+
+
+if __name__ == "__main__":
+    main()
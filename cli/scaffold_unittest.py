@@ -603,6 +603,7 @@ def main():
     if args.framework:
         print("Framework override:", args.framework)
     print("Coverage enabled:", args.coverage)
+    return [str(path) for path, _ in written]
 
 
 if __name__ == "__main__":
@@ -0,0 +1,80 @@
+#!/usr/bin/env python3
+"""
+DevOps-OS configuration checks for the go-project server
+
+Backs ``devopsos config validate``: runs the server with ``-validate-config``
+so the configuration its flags, environment and JSON config file make up is
+checked by the same code that reads it at startup, rather than by a second
+copy here that could drift. The server prints one line per check and exits
+with status 1 if any failed, without listening or touching any data.
+
+By default the server is started with ``go run ./cmd`` from the go-project
+directory; pass a built binary instead to skip compiling. Paths given in the
+environment are read relative to the caller's directory, not go-project.
+"""
+
+import os
+import shlex
+import subprocess
+from pathlib import Path
+
+DEFAULT_SERVER = "go run ./cmd"
+DEFAULT_PROJECT_DIR = "go-project"
+
+# Environment variables the server reads as file or directory paths. The
+# server runs in the project directory, so relative values are resolved
+# against the caller's directory first, where the user meant them.
+PATH_ENV_VARS = ("CONFIG_FILE", "TLS_CERT_FILE", "TLS_KEY_FILE", "AUDIT_LOG", "STATIC_DIR")
+
+# Last lines of a finished report; any other output means the server never
+# got as far as checking, e.g. because ``go run`` failed to compile it.
+REPORT_ENDINGS = ("configuration is valid", "configuration is invalid")
+
+
+class ServerError(Exception):
+    """The server command could not be started."""
+
+
+def server_env(environ=None):
+    """Return *environ* (default ``os.environ``) with relative paths made absolute.
+
+    Only the variables in PATH_ENV_VARS are changed. ``AUDIT_LOG=-``, which
+    means standard output, is left alone.
+    """
+    env = dict(os.environ if environ is None else environ)
+    for name in PATH_ENV_VARS:
+        value = env.get(name, "").strip()
+        if value and value != "-" and not os.path.isabs(value):
+            env[name] = str(Path(value).resolve())
+    return env
+
+
+def is_report(result):
+    """Whether *result* holds the server's report rather than a failure to run."""
+    lines = result.stdout.strip().splitlines()
+    return bool(lines) and lines[-1].strip() in REPORT_ENDINGS
+
+
+def validate(config_path=None, server=DEFAULT_SERVER, project_dir=DEFAULT_PROJECT_DIR):
+    """Check the server configuration and return the finished process.
+
+    *server* is the command that starts the server, split like a shell
+    would, and runs in *project_dir*. A relative path to the program, such
+    as ``./bin/server``, is taken from the current directory. *config_path*
+    names the JSON config file passed as ``-config``; otherwise the server
+    reads ``CONFIG_FILE``. The environment is passed through with the paths
+    in PATH_ENV_VARS resolved by server_env. The returned
+    ``subprocess.CompletedProcess`` holds the report in ``stdout`` and a
+    non-zero ``returncode`` when a check failed; use is_report to tell that
+    apart from the command itself failing.
+    """
+    args = shlex.split(server) + ["-validate-config"]
+    if os.sep in args[0] and not os.path.isabs(args[0]):
+        args[0] = str(Path(args[0]).resolve())
+    if config_path is not None:
+        # The server runs in project_dir, so a relative path would move
+        args += ["-config", str(Path(config_path).resolve())]
+    try:
+        return subprocess.run(args, cwd=project_dir, env=server_env(), capture_output=True, text=True, check=False)
+    except OSError as exc:
+        raise ServerError(f"could not run {server!r} in {project_dir}: {exc}") from None
@@ -0,0 +1,72 @@
+name: CI
+
+on:
+  push:
+    branches: [__BRANCH__]
+    tags: ["v*"]
+  pull_request:
+    branches: [__BRANCH__]
+
+permissions:
+  contents: read
+
+defaults:
+  run:
+    working-directory: __WORKING_DIRECTORY__
+
+jobs:
+  test:
+    name: Vet, test and build
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Set up Go
+        uses: actions/setup-go@v5
+        with:
+          go-version: "__GO_VERSION__"
+          cache-dependency-path: __WORKING_DIRECTORY__/go.sum
+
+__LINT_STEP__
+      - name: Vet
+        run: go vet ./...
+
+      - name: Test
+        run: go test -race ./...
+
+      - name: Build
+        run: go build ./...
+
+__DOCKER_JOB__
+  release:
+    name: Release
+    if: startsWith(github.ref, 'refs/tags/v')
+    needs: __RELEASE_NEEDS__
+    runs-on: ubuntu-latest
+    permissions:
+      contents: write
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Set up Go
+        uses: actions/setup-go@v5
+        with:
+          go-version: "__GO_VERSION__"
+          cache-dependency-path: __WORKING_DIRECTORY__/go.sum
+
+      - name: Build release binaries
+        env:
+          CGO_ENABLED: "0"
+        run: |
+          mkdir -p dist
+          for target in linux/amd64 linux/arm64 darwin/arm64; do
+            os="${target%/*}"
+            arch="${target#*/}"
+            GOOS="$os" GOARCH="$arch" go build -trimpath -ldflags="-s -w" \
+              -o "dist/__BINARY__-$os-$arch" __BUILD_PATH__
+          done
+
+      - name: Publish GitHub release
+        env:
+          GH_TOKEN: ${{ github.token }}
+        run: gh release create "${{ github.ref_name }}" dist/* --generate-notes
@@ -0,0 +1,6 @@
+      - name: Lint
+        uses: golangci/golangci-lint-action@v6
+        with:
+          version: latest
+          working-directory: __WORKING_DIRECTORY__
+
@@ -0,0 +1,26 @@
+# __NAME__
+
+A Go HTTP service scaffolded by `devopsos init`.
+
+## Layout
+
+```
+cmd/                  main package: server wiring and graceful shutdown
+internal/handlers/    HTTP handlers and middleware
+internal/models/      domain types and validation
+pkg/utils/            helpers that are safe to import from other modules
+```
+
+## Run
+
+```bash
+go run ./cmd            # listens on :8080, or on $PORT when set
+curl localhost:8080/healthz
+```
+
+## Test
+
+```bash
+go vet ./...
+go test ./...
+```
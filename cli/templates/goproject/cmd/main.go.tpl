@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"__MODULE__/internal/handlers"
+)
+
+// shutdownGrace bounds how long in-flight requests may take once a stop
+// signal arrives.
+const shutdownGrace = 15 * time.Second
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", handlers.HomeHandler)
+	mux.HandleFunc("GET /healthz", handlers.HealthHandler) // Liveness probe
+	mux.HandleFunc("GET /readyz", handlers.ReadyHandler)   // Readiness probe
+	mux.HandleFunc("GET /api/items/{slug}", handlers.ItemHandler)
+
+	srv := &http.Server{
+		Addr:              listenAddr(),
+		Handler:           handlers.Chain(mux, handlers.LoggingMiddleware),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// Stop on Ctrl-C locally and on SIGTERM from Kubernetes during a rollout
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Starting HTTP server on %s", srv.Addr)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Could not start server: %s\n", err)
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("Server stopped with error: %s\n", err)
+	}
+	log.Println("Server stopped")
+}
+
+// listenAddr returns ":$PORT" when PORT is set and ":8080" otherwise.
+func listenAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
+}
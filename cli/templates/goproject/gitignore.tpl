@@ -0,0 +1,11 @@
+# Binaries
+/bin/
+/__NAME__
+*.exe
+*.test
+*.out
+
+# Editor and OS files
+.idea/
+.vscode/
+.DS_Store
@@ -0,0 +1,3 @@
+module __MODULE__
+
+go __GO_VERSION__
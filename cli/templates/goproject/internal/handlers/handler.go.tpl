@@ -0,0 +1,33 @@
+// Package handlers holds the HTTP handlers and middleware for __NAME__.
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"__MODULE__/internal/models"
+	"__MODULE__/pkg/utils"
+)
+
+// HomeHandler responds with a short greeting.
+func HomeHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Welcome to __NAME__"})
+}
+
+// ItemHandler returns an example item whose name is derived from the {slug}
+// path value.
+func ItemHandler(w http.ResponseWriter, r *http.Request) {
+	item := models.Item{Slug: utils.Slugify(r.PathValue("slug")), Name: r.PathValue("slug")}
+	if err := item.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+// writeJSON encodes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+var ready atomic.Bool
+
+func init() { ready.Store(true) }
+
+// SetReady marks the service ready or not ready; ReadyHandler reports 503
+// while it is not ready, so load balancers stop sending it traffic.
+func SetReady(ok bool) { ready.Store(ok) }
+
+// HealthHandler is the liveness probe: it always reports ok while the
+// process can serve HTTP.
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ReadyHandler is the readiness probe.
+func ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	if !ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
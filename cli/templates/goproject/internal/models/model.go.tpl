@@ -0,0 +1,24 @@
+// Package models holds the domain types for __NAME__.
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidItem is returned by Validate for an incomplete Item.
+var ErrInvalidItem = errors.New("invalid item")
+
+// Item is an example domain type; replace it with your own.
+type Item struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// Validate reports whether the item has a slug and a name.
+func (i Item) Validate() error {
+	if strings.TrimSpace(i.Slug) == "" || strings.TrimSpace(i.Name) == "" {
+		return ErrInvalidItem
+	}
+	return nil
+}
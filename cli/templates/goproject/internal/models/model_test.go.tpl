@@ -0,0 +1,15 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestItemValidate(t *testing.T) {
+	if err := (Item{Slug: "a", Name: "A"}).Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+	if err := (Item{Name: "A"}).Validate(); !errors.Is(err, ErrInvalidItem) {
+		t.Fatalf("Validate() = %v, want ErrInvalidItem", err)
+	}
+}
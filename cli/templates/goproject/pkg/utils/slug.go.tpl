@@ -0,0 +1,16 @@
+// Package utils holds small helpers with no dependencies on the rest of
+// __NAME__, so other modules can import them.
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify lowercases s and joins its runs of letters and digits with '-'.
+func Slugify(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, "-")
+}
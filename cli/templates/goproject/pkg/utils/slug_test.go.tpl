@@ -0,0 +1,17 @@
+package utils
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":  "hello-world",
+		"  Go 1.23!  ": "go-1-23",
+		"already-slug": "already-slug",
+		"":             "",
+	}
+	for in, want := range tests {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+apiVersion: v2
+name: __NAME__
+description: A Helm chart for __NAME__
+type: application
+version: 0.1.0
+appVersion: "0.1.0"
@@ -0,0 +1,3 @@
+name: __NAME__
+description: Infrastructure for __NAME__ on __CLOUD_TITLE__
+runtime: go
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/pulumi/pulumi-azure-native-sdk/resources/v2"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		group, err := resources.NewResourceGroup(ctx, "__NAME__-rg", nil)
+		if err != nil {
+			return err
+		}
+		ctx.Export("resourceGroupName", group.Name)
+		return nil
+	})
+}
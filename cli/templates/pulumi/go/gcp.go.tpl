@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/pulumi/pulumi-gcp/sdk/v7/go/gcp/storage"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		bucket, err := storage.NewBucket(ctx, "__NAME__-artifacts", &storage.BucketArgs{
+			Location: pulumi.String("US"),
+		})
+		if err != nil {
+			return err
+		}
+		ctx.Export("bucketName", bucket.Name)
+		return nil
+	})
+}
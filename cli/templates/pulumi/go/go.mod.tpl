@@ -0,0 +1,8 @@
+module __NAME__
+
+go 1.22
+
+require (
+	github.com/pulumi/pulumi/sdk/v3 v3.130.0
+	__PROVIDER_PACKAGE__ __PROVIDER_VERSION__
+)
@@ -0,0 +1,7 @@
+name: __NAME__
+description: Infrastructure for __NAME__ on __CLOUD_TITLE__
+runtime:
+  name: python
+  options:
+    toolchain: pip
+    virtualenv: venv
@@ -0,0 +1,8 @@
+"""Pulumi program for __NAME__ on AWS."""
+
+import pulumi
+import pulumi_aws as aws
+
+bucket = aws.s3.BucketV2("__NAME__-artifacts")
+
+pulumi.export("bucket_name", bucket.id)
@@ -0,0 +1,8 @@
+"""Pulumi program for __NAME__ on Azure."""
+
+import pulumi
+from pulumi_azure_native import resources
+
+resource_group = resources.ResourceGroup("__NAME__-rg")
+
+pulumi.export("resource_group_name", resource_group.name)
@@ -0,0 +1,8 @@
+"""Pulumi program for __NAME__ on Google Cloud."""
+
+import pulumi
+import pulumi_gcp as gcp
+
+bucket = gcp.storage.Bucket("__NAME__-artifacts", location="US")
+
+pulumi.export("bucket_name", bucket.name)
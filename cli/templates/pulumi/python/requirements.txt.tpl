@@ -0,0 +1,2 @@
+pulumi>=3.0.0,<4.0.0
+__PROVIDER_PACKAGE____PROVIDER_VERSION__
@@ -0,0 +1,2 @@
+config:
+  aws:region: __REGION__
@@ -0,0 +1,2 @@
+config:
+  azure-native:location: __REGION__
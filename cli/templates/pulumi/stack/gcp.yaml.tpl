@@ -0,0 +1,4 @@
+config:
+  # Replace with the ID of the Google Cloud project to deploy into.
+  gcp:project: __NAME__
+  gcp:region: __REGION__
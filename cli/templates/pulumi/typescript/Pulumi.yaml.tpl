@@ -0,0 +1,6 @@
+name: __NAME__
+description: Infrastructure for __NAME__ on __CLOUD_TITLE__
+runtime:
+  name: nodejs
+  options:
+    typescript: true
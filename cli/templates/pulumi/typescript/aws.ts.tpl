@@ -0,0 +1,5 @@
+import * as aws from "@pulumi/aws";
+
+const bucket = new aws.s3.BucketV2("__NAME__-artifacts");
+
+export const bucketName = bucket.id;
@@ -0,0 +1,5 @@
+import * as resources from "@pulumi/azure-native/resources";
+
+const resourceGroup = new resources.ResourceGroup("__NAME__-rg");
+
+export const resourceGroupName = resourceGroup.name;
@@ -0,0 +1,7 @@
+import * as gcp from "@pulumi/gcp";
+
+const bucket = new gcp.storage.Bucket("__NAME__-artifacts", {
+    location: "US",
+});
+
+export const bucketName = bucket.name;
@@ -0,0 +1,12 @@
+{
+  "name": "__NAME__",
+  "main": "index.ts",
+  "devDependencies": {
+    "@types/node": "^20.0.0",
+    "typescript": "^5.0.0"
+  },
+  "dependencies": {
+    "@pulumi/pulumi": "^3.130.0",
+    "__PROVIDER_PACKAGE__": "__PROVIDER_VERSION__"
+  }
+}
@@ -0,0 +1,11 @@
+# Remote state in S3 with DynamoDB locking. Create the bucket and table
+# before running `terraform init`; backend blocks cannot use variables.
+terraform {
+  backend "s3" {
+    bucket         = "__NAME__-tfstate"
+    key            = "__NAME__/terraform.tfstate"
+    region         = "__REGION__"
+    dynamodb_table = "__NAME__-tflock"
+    encrypt        = true
+  }
+}
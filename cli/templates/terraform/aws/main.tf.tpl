@@ -0,0 +1,9 @@
+locals {
+  name = "${var.project}-${var.environment}"
+}
+
+# Add your AWS resources here, for example:
+#
+# resource "aws_s3_bucket" "artifacts" {
+#   bucket = "${local.name}-artifacts"
+# }
@@ -0,0 +1,17 @@
+variable "project" {
+  description = "Project name, used to name and tag resources"
+  type        = string
+  default     = "__NAME__"
+}
+
+variable "environment" {
+  description = "Deployment environment (dev, staging, production)"
+  type        = string
+  default     = "dev"
+}
+
+variable "region" {
+  description = "AWS region to deploy into"
+  type        = string
+  default     = "__REGION__"
+}
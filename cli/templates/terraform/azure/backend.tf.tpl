@@ -0,0 +1,11 @@
+# Remote state in an Azure Storage blob container, which locks natively.
+# Create the storage account before running `terraform init`; backend blocks
+# cannot use variables.
+terraform {
+  backend "azurerm" {
+    resource_group_name  = "__NAME__-tfstate"
+    storage_account_name = "__STORAGE_ACCOUNT__"
+    container_name       = "tfstate"
+    key                  = "__NAME__.terraform.tfstate"
+  }
+}
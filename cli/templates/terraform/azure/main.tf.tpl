@@ -0,0 +1,16 @@
+locals {
+  name = "${var.project}-${var.environment}"
+  tags = {
+    project     = var.project
+    environment = var.environment
+    managed-by  = "terraform"
+  }
+}
+
+resource "azurerm_resource_group" "main" {
+  name     = "${local.name}-rg"
+  location = var.location
+  tags     = local.tags
+}
+
+# Add your Azure resources here, placed in azurerm_resource_group.main.
@@ -0,0 +1,8 @@
+# Remote state in a GCS bucket, which locks natively. Create the bucket
+# before running `terraform init`; backend blocks cannot use variables.
+terraform {
+  backend "gcs" {
+    bucket = "__NAME__-tfstate"
+    prefix = "__NAME__"
+  }
+}
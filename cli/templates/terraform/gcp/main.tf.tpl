@@ -0,0 +1,10 @@
+locals {
+  name = "${var.project}-${var.environment}"
+}
+
+# Add your Google Cloud resources here, for example:
+#
+# resource "google_storage_bucket" "artifacts" {
+#   name     = "${local.name}-artifacts"
+#   location = var.region
+# }
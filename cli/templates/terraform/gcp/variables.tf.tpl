@@ -0,0 +1,22 @@
+variable "project" {
+  description = "Project name, used to name and label resources"
+  type        = string
+  default     = "__NAME__"
+}
+
+variable "project_id" {
+  description = "Google Cloud project ID to deploy into"
+  type        = string
+}
+
+variable "environment" {
+  description = "Deployment environment (dev, staging, production)"
+  type        = string
+  default     = "dev"
+}
+
+variable "region" {
+  description = "Google Cloud region to deploy into"
+  type        = string
+  default     = "__REGION__"
+}
@@ -0,0 +1,57 @@
+#!/usr/bin/env python3
+"""Shared helpers for the template-backed file generators.
+
+Templates live under ``cli/templates/<group>/`` and use ``__PLACEHOLDER__``
+markers, the same convention as the devcontainer templates used by ``init``.
+Keeping them as plain files means tests can load and render a template
+without going through the CLI.
+"""
+
+from __future__ import annotations
+
+import re
+from pathlib import Path
+
+TEMPLATE_ROOT = Path(__file__).resolve().parent / "templates"
+
+_PLACEHOLDER = re.compile(r"__[A-Z][A-Z0-9_]*__")
+
+
+def load_template(*parts: str) -> str:
+    """Return the text of the template at ``cli/templates/<parts...>``."""
+    return TEMPLATE_ROOT.joinpath(*parts).read_text(encoding="utf-8")
+
+
+def render(template: str, values: dict[str, str]) -> str:
+    """Replace each ``__KEY__`` marker in *template* with ``values[KEY]``.
+
+    Raises ValueError when a marker is left without a value, so a typo in a
+    template fails loudly instead of leaking into generated files.
+    """
+    for key, value in values.items():
+        template = template.replace(f"__{key}__", str(value))
+    leftover = sorted(set(_PLACEHOLDER.findall(template)))
+    if leftover:
+        raise ValueError(f"unresolved template placeholders: {', '.join(leftover)}")
+    return template
+
+
+def write_files(files: dict[Path, str], force: bool = False) -> list[Path]:
+    """Write every ``path -> content`` pair in *files* and return the paths.
+
+    Unless *force* is set, nothing is written when any target already exists;
+    FileExistsError lists all the conflicting paths so the user can decide.
+    """
+    if not force:
+        existing = [str(path) for path in files if Path(path).exists()]
+        if existing:
+            raise FileExistsError(
+                "refusing to overwrite existing files (use --force): " + ", ".join(existing)
+            )
+    written = []
+    for path, content in files.items():
+        path = Path(path)
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content, encoding="utf-8")
+        written.append(path)
+    return written
@@ -1038,7 +1038,187 @@ def test_scaffold_no_opts_shows_help():
 
 
 
-# -- versioning ------------------------------------------------------------
+# -- iac ------------------------------------------------------------------
+
+def test_iac_help_lists_terraform():
+    result = _run(["-m", "cli.devopsos", "iac", "--help"])
+    assert result.returncode == 0
+    assert "terraform" in result.stdout
+
+
+def test_iac_terraform_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        out = os.path.join(tmp, "infra")
+        result = _run(["-m", "cli.devopsos", "iac", "terraform", "--cloud", "azure", "--out", out])
+        assert result.returncode == 0, result.stderr
+        for name in ("main.tf", "variables.tf", "provider.tf", "backend.tf"):
+            assert os.path.exists(os.path.join(out, name)), name
+        with open(os.path.join(out, "provider.tf")) as fh:
+            assert 'provider "azurerm"' in fh.read()
+
+
+def test_iac_terraform_refuses_to_overwrite_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        Path(tmp, "main.tf").write_text("# hand-written")
+        result = _run(["-m", "cli.devopsos", "iac", "terraform", "--out", tmp])
+        assert result.returncode != 0
+        assert "--force" in result.stderr
+        assert "Traceback" not in result.stderr
+        assert Path(tmp, "main.tf").read_text() == "# hand-written"
+
+
+def test_iac_pulumi_go_aws_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        result = _run(["-m", "cli.devopsos", "iac", "pulumi",
+                       "--language", "go", "--cloud", "aws", "--out", tmp])
+        assert result.returncode == 0, result.stderr
+        for name in ("Pulumi.yaml", "Pulumi.dev.yaml", "main.go", "go.mod"):
+            assert os.path.exists(os.path.join(tmp, name)), name
+
+
+def test_iac_pulumi_invalid_language_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        result = _run(["-m", "cli.devopsos", "iac", "pulumi", "--language", "java", "--out", tmp])
+        assert result.returncode != 0
+        assert "unsupported language 'java'" in result.stderr
+        assert "Traceback" not in result.stderr
+        assert os.listdir(tmp) == []
+
+
+# -- generate -------------------------------------------------------------
+
+def test_generate_dockerfile_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        out = os.path.join(tmp, "Dockerfile")
+        result = _run(["-m", "cli.devopsos", "generate", "dockerfile",
+                       "--go-version", "1.22", "--port", "9090", "--out", out])
+        assert result.returncode == 0, result.stderr
+        content = Path(out).read_text()
+        assert "FROM golang:1.22 AS builder" in content
+        assert "EXPOSE 9090" in content
+
+
+def test_generate_k8s_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        result = _run(["-m", "cli.devopsos", "generate", "k8s", "--name", "my-api",
+                       "--ingress-host", "api.example.com", "--out", tmp])
+        assert result.returncode == 0, result.stderr
+        with open(os.path.join(tmp, "deployment.yaml")) as fh:
+            deployment = yaml.safe_load(fh)
+        assert deployment["kind"] == "Deployment"
+        assert os.path.exists(os.path.join(tmp, "service.yaml"))
+        assert os.path.exists(os.path.join(tmp, "ingress.yaml"))
+
+
+def test_generate_helm_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        out = os.path.join(tmp, "charts", "myapp")
+        result = _run(["-m", "cli.devopsos", "generate", "helm", "--name", "myapp", "--out", out])
+        assert result.returncode == 0, result.stderr
+        with open(os.path.join(out, "Chart.yaml")) as fh:
+            assert yaml.safe_load(fh)["name"] == "myapp"
+        assert os.path.exists(os.path.join(out, "templates", "_helpers.tpl"))
+
+
+def test_generate_helm_rejects_invalid_name():
+    with tempfile.TemporaryDirectory() as tmp:
+        result = _run(["-m", "cli.devopsos", "generate", "helm", "--name", "My_App", "--out", tmp])
+        assert result.returncode != 0
+        assert "DNS-1123" in result.stderr
+
+
+def test_generate_ci_github_via_cli():
+    with tempfile.TemporaryDirectory() as tmp:
+        out = os.path.join(tmp, "ci.yml")
+        result = _run(["-m", "cli.devopsos", "generate", "ci", "github",
+                       "--go-version", "1.22", "--no-lint", "--docker", "--out", out])
+        assert result.returncode == 0, result.stderr
+        with open(out) as fh:
+            workflow = yaml.safe_load(fh)
+        steps = [step.get("name") for step in workflow["jobs"]["test"]["steps"]]
+        assert "Lint" not in steps
+        assert "docker" in workflow["jobs"]
+
+
+def test_generate_argocd_to_stdout():
+    result = _run(["-m", "cli.devopsos", "generate", "argocd", "--app-name", "my-app",
+                   "--repo-url", "https://github.com/myorg/my-app.git", "--path", "k8s",
+                   "--appproject"])
+    assert result.returncode == 0, result.stderr
+    project, application = yaml.safe_load_all(result.stdout)
+    assert project["spec"]["sourceRepos"] == ["https://github.com/myorg/my-app.git"]
+    assert application["spec"]["project"] == "my-app"
+
+
+def test_generate_argocd_requires_repo_url():
+    result = _run(["-m", "cli.devopsos", "generate", "argocd", "--app-name", "my-app", "--path", "k8s"])
+    assert result.returncode != 0
+    assert "--repo-url" in result.stderr
+
+
+def test_init_with_name_scaffolds_go_project():
+    with tempfile.TemporaryDirectory() as tmp:
+        result = _run(["-m", "cli.devopsos", "init", "orders",
+                       "--module", "github.com/you/orders", "--dir", tmp])
+        assert result.returncode == 0, result.stderr
+        with open(os.path.join(tmp, "orders", "go.mod")) as fh:
+            assert fh.readline().strip() == "module github.com/you/orders"
+        assert os.path.exists(os.path.join(tmp, "orders", "cmd", "main.go"))
+
+
+def test_init_with_name_refuses_non_empty_directory():
+    with tempfile.TemporaryDirectory() as tmp:
+        Path(tmp, "orders").mkdir()
+        Path(tmp, "orders", "main.go").write_text("package main\n")
+        result = _run(["-m", "cli.devopsos", "init", "orders", "--dir", tmp])
+        assert result.returncode != 0
+        assert "--force" in result.stderr
+
+
+# -- global --output --------------------------------------------------------
+def test_output_json_lists_generated_files():
+    with tempfile.TemporaryDirectory() as tmp:
+        out = os.path.join(tmp, "chart")
+        result = _run(["-m", "cli.devopsos", "-o", "json", "generate", "helm", "--name", "my-app", "--out", out])
+        assert result.returncode == 0, result.stderr
+        data = json.loads(result.stdout)
+        assert data["command"] == "generate helm"
+        assert os.path.join(out, "Chart.yaml") in data["files"]
+        assert all(os.path.exists(path) for path in data["files"])
+
+
+def test_output_yaml_reports_stdout_generators():
+    result = _run(["-m", "cli.devopsos", "--output", "yaml", "generate", "argocd", "--app-name", "my-app",
+                   "--repo-url", "https://github.com/myorg/my-app.git", "--path", "k8s"])
+    assert result.returncode == 0, result.stderr
+    data = yaml.safe_load(result.stdout)
+    assert data["files"] == []
+    assert yaml.safe_load(data["output"])["kind"] == "Application"
+
+
+def test_output_env_var_selects_format():
+    env = dict(os.environ, DEVOPS_OS_OUTPUT="json")
+    result = subprocess.run([sys.executable, "-m", "cli.devopsos", "process-first", "--section", "what"],
+                            capture_output=True, text=True, env=env,
+                            cwd=os.path.dirname(os.path.dirname(__file__)))
+    assert result.returncode == 0, result.stderr
+    assert json.loads(result.stdout)["section"] == "what"
+
+
+def test_output_rejects_unknown_format():
+    result = _run(["-m", "cli.devopsos", "-o", "xml", "process-first"])
+    assert result.returncode != 0
+
+
+def test_config_file_supplies_defaults_and_flags_override():
+    with tempfile.TemporaryDirectory() as tmp:
+        cfg = Path(tmp, "devopsos.yaml")
+        cfg.write_text(f"generate:\n  helm:\n    name: orders\n    out: {tmp}/chart\n")
+        result = _run(["-m", "cli.devopsos", "--config", str(cfg), "generate", "helm", "--port", "9090"])
+        assert result.returncode == 0, result.stderr
+        values = yaml.safe_load(Path(tmp, "chart", "values.yaml").read_text())
+        assert values["containerPort"] == 9090
+        assert "name: orders" in Path(tmp, "chart", "Chart.yaml").read_text()
 
 
 # -- versioning ------------------------------------------------------------
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// certReloader holds the serving certificate and can reload it from disk,
+// so a renewed certificate, such as a Let's Encrypt rotation, is picked up
+// by new handshakes without restarting the server. Connections already
+// established keep the certificate they were opened with.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// newCertReloader loads the certificate/key pair up front so a bad path or
+// a mismatched key stops startup with a clear error instead of failing
+// every handshake later.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	c := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the certificate and key from disk again and swaps them in.
+// When they fail to load the current certificate stays in use.
+func (c *certReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate %q and key %q: %w", c.certFile, c.keyFile, err)
+	}
+	c.mu.Lock()
+	c.cert = &cert
+	c.mu.Unlock()
+	return nil
+}
+
+// GetCertificate returns the current certificate, for tls.Config.
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// TLSConfig returns a server TLS configuration serving the current
+// certificate on each handshake.
+func (c *certReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: c.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// reloadOn reloads the certificate each time a signal arrives on sig, as
+// sent by main on SIGHUP, until sig is closed. Failures are logged and the
+// previous certificate keeps being served.
+func (c *certReloader) reloadOn(sig <-chan os.Signal) {
+	for range sig {
+		if err := c.Reload(); err != nil {
+			slog.Error("could not reload TLS certificate, still serving the previous one", "error", err)
+			continue
+		}
+		cert, _ := c.GetCertificate(nil)
+		slog.Info("reloaded TLS certificate", "cert", c.certFile, "expires", cert.Leaf.NotAfter)
+	}
+}
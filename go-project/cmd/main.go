@@ -1,19 +1,231 @@
 package main
 
 import (
-    "log"
-    "net/http"
-    "go-project/internal/handlers"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-project/internal/audit"
+	"go-project/internal/buildinfo"
+	"go-project/internal/config"
+	"go-project/internal/handlers"
+	"go-project/internal/lifecycle"
+	"go-project/internal/logging"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+	"go-project/internal/seed"
+	"go-project/internal/tracing"
+	"go-project/internal/webhook"
 )
 
 func main() {
-    // Initialize the HTTP server
-    http.HandleFunc("/", handlers.HomeHandler) // Example route
-    http.HandleFunc("/api/data", handlers.DataHandler) // Example API route
-
-    // Start the server
-    log.Println("Starting server on :8080")
-    if err := http.ListenAndServe(":8080", nil); err != nil {
-        log.Fatalf("Could not start server: %s\n", err)
-    }
-}
\ No newline at end of file
+	// Uptime in /info counts from here
+	started := time.Now()
+	grace := flag.Duration("shutdown-grace", 15*time.Second, "how long to wait for in-flight requests to finish on shutdown")
+	openapiOut := flag.String("openapi", "", "write the OpenAPI document to this file (- for stdout) and exit")
+	validate := flag.Bool("validate-config", false, "check the configuration, print a report and exit, with status 1 if any check fails")
+	flag.Parse()
+
+	if *validate {
+		if !writeConfigReport(os.Stdout, checkConfig()) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// JSON logs by default, text with APP_ENV=development; LoggingMiddleware
+	// and RecoverMiddleware write to the default logger
+	level, err := logging.ParseLevel(config.LogLevel())
+	slog.SetDefault(logging.New(os.Stderr, config.DevMode(), level))
+	if err != nil {
+		fatal("invalid LOG_LEVEL", err)
+	}
+
+	// Feature flags, timeouts and middleware limits; a bad value stops
+	// startup rather than being ignored
+	cfg, err := config.FromEnv()
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+
+	// Spans continue incoming W3C traceparent headers and are passed on to
+	// webhook receivers; no exporter is configured yet, so none leave the
+	// process
+	stopTracing := tracing.Setup(nil)
+
+	// Background workers start with the server and are stopped, each given
+	// the shutdown grace period, once it has drained
+	workers := lifecycle.New()
+	workers.Register("tracing", nil, stopTracing)
+
+	// Bearer tokens are only verified once a secret is configured
+	var jwtSecret []byte
+	if secret := config.JWTSecret(); secret != "" {
+		jwtSecret = []byte(secret)
+	}
+
+	// Dependencies of the handlers in the route table
+	services := handlers.Services{
+		Users:    repository.NewInMemoryUserRepo(),
+		Products: repository.NewInMemoryProductRepo(),
+		// Categories are fixed for now; products are filed under them by slug
+		Categories: repository.NewInMemoryCategoryRepo(
+			models.NewCategory("Books"),
+			models.NewCategory("Electronics"),
+			models.NewCategory("Home & Garden"),
+		),
+		Prices: handlers.NewPriceBroker(),
+
+		JWTSecret:          jwtSecret,
+		NoRateLimit:        !cfg.RateLimit,
+		RateLimitRPS:       cfg.Limits.RateLimitRPS,
+		RateLimitBurst:     cfg.Limits.RateLimitBurst,
+		UserRateLimitRPS:   cfg.Limits.UserRateLimitRPS,
+		UserRateLimitBurst: cfg.Limits.UserRateLimitBurst,
+		RequestTimeout:     cfg.Limits.RequestTimeout,
+		Started:            started,
+		Lifecycle:          workers,
+	}
+	// Fill the empty stores with generated data for local development
+	if seeding, ok, err := config.SeedData(); err != nil {
+		fatal("invalid seed configuration", err)
+	} else if ok {
+		if err := seedData(context.Background(), services, seeding); err != nil {
+			fatal("could not seed data", err)
+		}
+	}
+	// POST price changes to the configured webhooks as well as streaming
+	// them
+	var hooks *webhook.Dispatcher
+	if urls, secret := config.Webhooks(); len(urls) > 0 {
+		if secret == "" {
+			fatal("invalid webhook configuration", errors.New("WEBHOOK_SECRET must be set with WEBHOOK_URLS"))
+		}
+		hooks = webhook.NewDispatcher([]byte(secret))
+		for _, u := range urls {
+			if err := hooks.Register(u); err != nil {
+				fatal("invalid webhook configuration", err)
+			}
+		}
+		workers.Register("webhooks", nil, hooks.Shutdown)
+	}
+	models.OnPriceChange(priceObserver(services.Prices, hooks))
+	// Record who changed which user or product, for compliance
+	if path := config.AuditLog(); path != "" {
+		sink, err := openAuditLog(path)
+		if err != nil {
+			fatal("could not open audit log", err)
+		}
+		defer sink.Close()
+		services.Audit = audit.NewLogger(sink)
+	}
+	handlers.RegisterRepositoryCheck(services.Users)
+	// Serve the web UI when a directory is configured, with index.html as the
+	// fallback for client-side routes
+	if dir := config.StaticDir(); dir != "" {
+		services.UI = os.DirFS(dir)
+	}
+
+	routes := handlers.Routes(services)
+	if *openapiOut != "" {
+		if err := writeOpenAPI(*openapiOut, handlers.OpenAPI(routes)); err != nil {
+			fatal("could not write OpenAPI document", err)
+		}
+		return
+	}
+
+	// Initialize the HTTP server
+	srv := newServer(config.ListenAddr(), newHandler(cfg, routes), cfg.Timeouts)
+
+	// Terminate TLS ourselves when a certificate is configured
+	if certFile, keyFile, ok := config.TLSFiles(); ok {
+		certs, err := newCertReloader(certFile, keyFile)
+		if err != nil {
+			fatal("could not configure TLS", err)
+		}
+		srv.TLSConfig = certs.TLSConfig()
+		// Pick up a renewed certificate on SIGHUP without dropping connections
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go certs.reloadOn(hup)
+		if addr := config.HTTPRedirectAddr(); addr != "" {
+			redirect := newServer(addr, httpsRedirect(srv.Addr), cfg.Timeouts)
+			go func() {
+				slog.Info("redirecting HTTP to HTTPS", "addr", addr)
+				if err := redirect.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					slog.Error("HTTP redirect listener stopped", "error", err)
+				}
+			}()
+			defer redirect.Close()
+		}
+	}
+
+	// Stop on Ctrl-C locally and on SIGTERM from Kubernetes during a rollout
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	// Start the server
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		fatal("could not start server", err)
+	}
+	scheme := "HTTP"
+	if srv.TLSConfig != nil {
+		scheme = "HTTPS"
+	}
+	slog.Info("starting server", "scheme", scheme, "addr", srv.Addr, "version", buildinfo.Version)
+	workers.Start(context.Background())
+	if err := run(srv, ln, stop, *grace); err != nil {
+		fatal("server stopped with error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *grace)
+	defer cancel()
+	if err := workers.Shutdown(ctx); err != nil {
+		slog.Warn("background workers did not stop cleanly", "error", err)
+	}
+	slog.Info("server stopped")
+}
+
+// fatal logs msg and err at error level and exits with status 1.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// seedData loads the generated users and products seeding asks for into
+// the services' repositories, filing products under its categories.
+func seedData(ctx context.Context, s handlers.Services, seeding config.Seeding) error {
+	categories, err := s.Categories.List(ctx)
+	if err != nil {
+		return err
+	}
+	opts := seed.Options{Users: seeding.Users, Products: seeding.Products, Seed: seeding.Seed}
+	for _, c := range categories {
+		opts.Categories = append(opts.Categories, c.Slug)
+	}
+	res, err := seed.Load(ctx, s.Users, s.Products, opts)
+	if err != nil {
+		return err
+	}
+	slog.Info("seeded data", "seed", seeding.Seed, "users", res.Users, "products", res.Products)
+	return nil
+}
+
+// priceObserver returns the models.OnPriceChange observer that streams each
+// price change to prices subscribers and, when hooks is not nil, delivers it
+// to the webhooks as part of the trace of the request that made it.
+func priceObserver(prices *handlers.PriceBroker, hooks *webhook.Dispatcher) func(context.Context, models.PriceChange) {
+	return func(ctx context.Context, c models.PriceChange) {
+		prices.Publish(c)
+		if hooks != nil {
+			hooks.PriceChanged(ctx, c)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-project/internal/config"
+	"go-project/internal/handlers"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+	"go-project/internal/webhook"
+)
+
+func TestRunDrainsInFlightRequestsOnShutdown(t *testing.T) {
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	stop := make(chan os.Signal, 1)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- run(&http.Server{Handler: mux}, ln, stop, 5*time.Second)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		resCh <- result{body: string(b), err: err}
+	}()
+
+	<-started
+	stop <- syscall.SIGTERM
+
+	res := <-resCh
+	if res.err != nil {
+		t.Fatalf("slow request failed during shutdown: %v", res.err)
+	}
+	if res.body != "done" {
+		t.Fatalf("body = %q, want %q", res.body, "done")
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("run returned %v, want nil", err)
+	}
+}
+
+func TestRunTreatsExpiredGracePeriodAsClean(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hang", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	stop := make(chan os.Signal, 1)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- run(&http.Server{Handler: mux}, ln, stop, 50*time.Millisecond)
+	}()
+	go http.Get("http://" + ln.Addr().String() + "/hang")
+
+	<-started
+	stop <- os.Interrupt
+	if err := <-runErr; err != nil {
+		t.Fatalf("run returned %v, want nil after grace period expiry", err)
+	}
+}
+
+func TestNewServerSetsTimeouts(t *testing.T) {
+	srv := newServer(":0", http.NotFoundHandler(), config.DefaultTimeouts)
+	for name, d := range map[string]time.Duration{
+		"ReadHeaderTimeout": srv.ReadHeaderTimeout,
+		"ReadTimeout":       srv.ReadTimeout,
+		"WriteTimeout":      srv.WriteTimeout,
+		"IdleTimeout":       srv.IdleTimeout,
+	} {
+		if d <= 0 {
+			t.Errorf("%s = %v, want a limit", name, d)
+		}
+	}
+	if srv.ReadHeaderTimeout != config.DefaultTimeouts.ReadHeader || srv.IdleTimeout != config.DefaultTimeouts.Idle {
+		t.Errorf("server timeouts do not follow the config: %+v", srv)
+	}
+}
+
+func TestGzipCanBeDisabledFromEnv(t *testing.T) {
+	routes := handlers.Routes(handlers.Services{
+		Users:    repository.NewInMemoryUserRepo(),
+		Products: repository.NewInMemoryProductRepo(),
+		Prices:   handlers.NewPriceBroker(),
+	})
+	for _, tt := range []struct {
+		env, wantEncoding string
+	}{{"", "gzip"}, {"false", ""}} {
+		t.Setenv("GZIP_ENABLED", tt.env)
+		cfg, err := config.FromEnv()
+		if err != nil {
+			t.Fatalf("FromEnv: %v", err)
+		}
+		// The OpenAPI document is well over the size gzip kicks in at.
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		newHandler(cfg, routes).ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GZIP_ENABLED=%q: status = %d", tt.env, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+			t.Errorf("GZIP_ENABLED=%q: Content-Encoding = %q, want %q", tt.env, got, tt.wantEncoding)
+		}
+		if tt.wantEncoding == "" && !json.Valid(rec.Body.Bytes()) {
+			t.Errorf("GZIP_ENABLED=%q: body is not plain JSON", tt.env)
+		}
+	}
+}
+
+func TestRateLimitFollowsEnv(t *testing.T) {
+	// allowed sends a burst of n requests from one client to the
+	// rate-limited /api/data and counts those let through.
+	allowed := func(t *testing.T, n int) int {
+		t.Helper()
+		cfg, err := config.FromEnv()
+		if err != nil {
+			t.Fatalf("FromEnv: %v", err)
+		}
+		h := newHandler(cfg, handlers.Routes(handlers.Services{
+			Users:          repository.NewInMemoryUserRepo(),
+			Products:       repository.NewInMemoryProductRepo(),
+			Prices:         handlers.NewPriceBroker(),
+			RateLimitRPS:   cfg.Limits.RateLimitRPS,
+			RateLimitBurst: cfg.Limits.RateLimitBurst,
+			RequestTimeout: cfg.Limits.RequestTimeout,
+		}))
+		ok := 0
+		for range n {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+			switch rec.Code {
+			case http.StatusOK:
+				ok++
+			case http.StatusTooManyRequests:
+			default:
+				t.Fatalf("status = %d", rec.Code)
+			}
+		}
+		return ok
+	}
+
+	t.Setenv("RATE_LIMIT_RPS", "")
+	t.Setenv("RATE_LIMIT_BURST", "")
+	if got := allowed(t, 10); got != 10 {
+		t.Fatalf("default limit let %d of 10 requests through, want all", got)
+	}
+
+	t.Setenv("RATE_LIMIT_RPS", "0.01")
+	t.Setenv("RATE_LIMIT_BURST", "3")
+	if got := allowed(t, 10); got != 3 {
+		t.Fatalf("RATE_LIMIT_BURST=3 let %d of 10 requests through, want 3", got)
+	}
+}
+
+func TestCORSOriginsFollowEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	cfg, err := config.FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	h := newHandler(cfg, nil)
+	for origin, want := range map[string]string{
+		"https://app.example.com":  "https://app.example.com",
+		"https://evil.example.com": "",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != want {
+			t.Errorf("Origin %s: Access-Control-Allow-Origin = %q, want %q", origin, got, want)
+		}
+	}
+}
+
+func TestWriteOpenAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	routes := handlers.Routes(handlers.Services{
+		Users:    repository.NewInMemoryUserRepo(),
+		Products: repository.NewInMemoryProductRepo(),
+		Prices:   handlers.NewPriceBroker(),
+	})
+	if err := writeOpenAPI(path, handlers.OpenAPI(routes)); err != nil {
+		t.Fatalf("writeOpenAPI: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("written document is not JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" || doc.Paths["/api/users"] == nil {
+		t.Fatalf("document = %+v, want OpenAPI 3.0.3 with /api/users", doc)
+	}
+}
+
+func TestPriceChangeWebhookContinuesRequestTrace(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	received := make(chan string, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("traceparent")
+	}))
+	defer receiver.Close()
+	hooks := webhook.NewDispatcher([]byte("secret"))
+	if err := hooks.Register(receiver.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	products := repository.NewInMemoryProductRepo()
+	products.Create(context.Background(), models.NewProduct("Widget", models.NewMoney(500, "USD")))
+	services := handlers.Services{
+		Users:    repository.NewInMemoryUserRepo(),
+		Products: products,
+		Prices:   handlers.NewPriceBroker(),
+	}
+	models.OnPriceChange(priceObserver(services.Prices, hooks))
+	t.Cleanup(func() { models.OnPriceChange(nil) })
+
+	req := httptest.NewRequest(http.MethodPut, "/api/products/1", strings.NewReader(`{"name":"Widget","price":"6.50"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	newHandler(cfg, handlers.Routes(services)).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d (body %s)", rec.Code, rec.Body)
+	}
+
+	hooks.Wait()
+	select {
+	case got := <-received:
+		if parts := strings.Split(got, "-"); len(parts) != 4 || parts[1] != traceID {
+			t.Fatalf("webhook traceparent = %q, want trace %s of the PUT", got, traceID)
+		}
+	default:
+		t.Fatal("price change was not delivered to the webhook")
+	}
+}
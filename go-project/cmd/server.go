@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go-project/internal/config"
+	"go-project/internal/handlers"
+	"go-project/internal/metrics"
+	"go-project/internal/openapi"
+)
+
+// middleware returns the middleware applied to every route, outermost
+// first, leaving out the features cfg turns off.
+func middleware(cfg config.Config) []handlers.Middleware {
+	mw := []handlers.Middleware{handlers.RequestIDMiddleware, handlers.TracingMiddleware}
+	if cfg.RequestLogging {
+		mw = append(mw, handlers.LoggingMiddleware(nil,
+			handlers.SampleSuccesses(cfg.Limits.LogSampleRate),
+			handlers.ExcludePaths(cfg.Limits.LogExcludePaths...)))
+	}
+	if cfg.Metrics {
+		mw = append(mw, handlers.MetricsMiddleware)
+	}
+	if cfg.Gzip {
+		mw = append(mw, handlers.CompressMiddleware)
+	}
+	return append(mw, handlers.RecoverMiddleware(nil))
+}
+
+// newHandler registers routes behind the middleware for cfg and returns the
+// server's root handler.
+func newHandler(cfg config.Config, routes []handlers.Route) http.Handler {
+	router := handlers.NewRouter()
+	handlers.Register(router, routes, middleware(cfg)...)
+	if cfg.Metrics {
+		router.Get("/metrics", metrics.Handler()) // Prometheus scrape endpoint
+	}
+
+	// CORS wraps the router itself so preflight OPTIONS requests are answered
+	// before method routing would reject them with 405
+	cors := handlers.CORSMiddleware(handlers.CORSOptions{AllowedOrigins: cfg.Limits.CORSOrigins})
+	return handlers.Chain(router, cors)
+}
+
+// newServer returns a server for h on addr with every connection timeout
+// set, so no phase of a request can hold a connection open indefinitely.
+func newServer(addr string, h http.Handler, t config.Timeouts) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: t.ReadHeader,
+		ReadTimeout:       t.Read,
+		WriteTimeout:      t.Write,
+		IdleTimeout:       t.Idle,
+	}
+}
+
+// run serves srv on ln until a signal arrives on stop, then shuts the server
+// down, giving in-flight requests up to grace to complete. Running out of
+// grace time is logged but not reported as an error; the process is exiting
+// either way and the orchestrator has already stopped routing traffic to it.
+// When srv.TLSConfig is set the listener serves HTTPS using its certificates.
+func run(srv *http.Server, ln net.Listener, stop <-chan os.Signal, grace time.Duration) error {
+	tracker := &requestTracker{}
+	srv.Handler = tracker.wrap(srv.Handler)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			serveErr <- srv.ServeTLS(ln, "", "")
+			return
+		}
+		serveErr <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-stop:
+		slog.Info("shutting down", "signal", sig.String(), "grace", grace)
+	}
+
+	inFlight, before := tracker.snapshot()
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	err := srv.Shutdown(ctx)
+	remaining, after := tracker.snapshot()
+	slog.Info("drained in-flight requests", "drained", after-before, "in_flight", inFlight)
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("grace period expired", "still_running", remaining)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// requestTracker counts active and completed requests so shutdown can report
+// how much work it drained.
+type requestTracker struct {
+	active    int64
+	completed int64
+}
+
+// wrap returns next instrumented with the tracker. A nil next means
+// http.DefaultServeMux, matching http.Server semantics.
+func (t *requestTracker) wrap(next http.Handler) http.Handler {
+	if next == nil {
+		next = http.DefaultServeMux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&t.active, 1)
+		defer func() {
+			atomic.AddInt64(&t.active, -1)
+			atomic.AddInt64(&t.completed, 1)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// snapshot returns the number of active requests and the running total of
+// completed ones.
+func (t *requestTracker) snapshot() (active, completed int64) {
+	return atomic.LoadInt64(&t.active), atomic.LoadInt64(&t.completed)
+}
+
+// httpsRedirect permanently redirects every request to the same host and
+// path over HTTPS on the port of tlsAddr.
+func httpsRedirect(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// writeOpenAPI writes doc as indented JSON to path, or to stdout when path
+// is "-".
+func writeOpenAPI(path string, doc *openapi.Document) error {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// openAuditLog opens the audit log sink at path for appending, creating the
+// file if need be, or returns standard output for "-".
+func openAuditLog(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+}
+
+// nopCloser keeps standard output open when the audit log is closed.
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a certificate valid for 127.0.0.1 to dir and
+// returns the cert and key paths plus a pool trusting it.
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	cert, _ := x509.ParseCertificate(der)
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
+func TestRunServesHTTPSWithLoadedCertificate(t *testing.T) {
+	certFile, keyFile, pool := writeSelfSignedCert(t, t.TempDir())
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "secure")
+		}),
+		TLSConfig: certs.TLSConfig(),
+	}
+	stop := make(chan os.Signal, 1)
+	done := make(chan error, 1)
+	go func() { done <- run(srv, ln, stop, time.Second) }()
+	defer func() {
+		stop <- os.Interrupt
+		<-done
+	}()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("HTTPS request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.TLS == nil || string(body) != "secure" {
+		t.Fatalf("TLS=%v body=%q, want a TLS response with body \"secure\"", resp.TLS != nil, body)
+	}
+}
+
+func TestNewCertReloaderFailsFast(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _, _ := writeSelfSignedCert(t, dir)
+	if _, err := newCertReloader(certFile, filepath.Join(dir, "missing.key")); err == nil {
+		t.Fatal("newCertReloader succeeded with a missing key file")
+	}
+}
+
+func TestCertReloaderRotatesCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, oldPool := writeSelfSignedCert(t, dir)
+	certs, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "secure")
+		}),
+		TLSConfig: certs.TLSConfig(),
+	}
+	stop := make(chan os.Signal, 1)
+	done := make(chan error, 1)
+	go func() { done <- run(srv, ln, stop, time.Second) }()
+	defer func() {
+		stop <- os.Interrupt
+		<-done
+	}()
+	url := "https://" + ln.Addr().String() + "/"
+	get := func(client *http.Client) error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+
+	// A keep-alive connection opened before the rotation
+	open := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: oldPool}}}
+	if err := get(open); err != nil {
+		t.Fatalf("request before rotation: %v", err)
+	}
+
+	_, _, newPool := writeSelfSignedCert(t, dir)
+	if err := certs.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	// Trusting only the old certificate, the open connection only succeeds
+	// if it is reused rather than dropped and redialed
+	if err := get(open); err != nil {
+		t.Fatalf("request on the connection opened before rotation: %v", err)
+	}
+	fresh := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: newPool}, DisableKeepAlives: true}}
+	if err := get(fresh); err != nil {
+		t.Fatalf("new handshake does not serve the rotated certificate: %v", err)
+	}
+	stale := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: oldPool}, DisableKeepAlives: true}}
+	if err := get(stale); err == nil {
+		t.Fatal("new handshake still serves the old certificate")
+	}
+
+	// A certificate that fails to parse leaves the rotated one in place
+	os.WriteFile(certFile, []byte("not a certificate"), 0o600)
+	if err := certs.Reload(); err == nil {
+		t.Fatal("Reload succeeded with a corrupt certificate")
+	}
+	if err := get(fresh); err != nil {
+		t.Fatalf("after a failed reload: %v", err)
+	}
+}
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		tlsAddr, host, want string
+	}{
+		{":443", "example.com", "https://example.com/api/data?x=1"},
+		{":443", "example.com:80", "https://example.com/api/data?x=1"},
+		{":8443", "example.com", "https://example.com:8443/api/data?x=1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://"+tt.host+"/api/data?x=1", nil)
+		rec := httptest.NewRecorder()
+		httpsRedirect(tt.tlsAddr).ServeHTTP(rec, r)
+		if rec.Code != http.StatusMovedPermanently {
+			t.Errorf("status = %d, want 301", rec.Code)
+		}
+		if got := rec.Header().Get("Location"); got != tt.want {
+			t.Errorf("Location = %q, want %q", got, tt.want)
+		}
+	}
+}
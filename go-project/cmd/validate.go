@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"go-project/internal/config"
+	"go-project/internal/logging"
+	"go-project/pkg/utils"
+)
+
+// configCheck is one line of the -validate-config report.
+type configCheck struct {
+	name string
+	// detail describes what was checked when it passed.
+	detail string
+	err    error
+}
+
+// checkConfig runs every startup check on the configuration the flags,
+// environment and config file make up, without starting anything. Checks
+// that depend on a setting which is not set pass with a note.
+func checkConfig() []configCheck {
+	var checks []configCheck
+	add := func(name, detail string, err error) {
+		checks = append(checks, configCheck{name: name, detail: detail, err: err})
+	}
+
+	addr := config.ListenAddr()
+	add("listen address", addr, checkAddr(addr))
+
+	level, err := logging.ParseLevel(config.LogLevel())
+	add("log level", level.String(), err)
+
+	cfg, err := config.FromEnv()
+	add("feature flags, timeouts and limits", fmt.Sprintf("rate limit %g/s, burst %d (per user %g/s, burst %d), request timeout %s",
+		cfg.Limits.RateLimitRPS, cfg.Limits.RateLimitBurst, cfg.Limits.UserRateLimitRPS, cfg.Limits.UserRateLimitBurst, cfg.Limits.RequestTimeout), err)
+	add("CORS origins", strings.Join(cfg.Limits.CORSOrigins, ", "), checkOrigins(cfg.Limits.CORSOrigins))
+
+	tlsDetail, tlsErr := checkTLS()
+	add("TLS", tlsDetail, tlsErr)
+	if redirect := config.HTTPRedirectAddr(); redirect != "" {
+		err := checkAddr(redirect)
+		if err == nil && tlsDetail == "not configured" {
+			err = errors.New("HTTP_REDIRECT_ADDR is set but TLS is not configured")
+		}
+		add("HTTP redirect address", redirect, err)
+	}
+
+	urls, secret := config.Webhooks()
+	add("webhooks", fmt.Sprintf("%d endpoint(s)", len(urls)), checkWebhooks(urls, secret))
+
+	seeding, on, err := config.SeedData()
+	seedDetail := "off"
+	if on {
+		seedDetail = fmt.Sprintf("%d users, %d products from seed %d", seeding.Users, seeding.Products, seeding.Seed)
+	}
+	add("seed data", seedDetail, err)
+
+	if path := config.AuditLog(); path != "" {
+		add("audit log", path, checkAuditLog(path))
+	}
+
+	if dir := config.StaticDir(); dir != "" {
+		add("static files", dir, checkDir(dir))
+	}
+	return checks
+}
+
+// writeConfigReport writes the result of each check to w, one per line,
+// and reports whether they all passed.
+func writeConfigReport(w io.Writer, checks []configCheck) bool {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	passed := true
+	for _, c := range checks {
+		if c.err != nil {
+			passed = false
+			// Limit errors are joined one per line; keep the report aligned
+			msg := strings.ReplaceAll(c.err.Error(), "\n", "; ")
+			fmt.Fprintf(tw, "FAIL\t%s\t%s\n", c.name, msg)
+			continue
+		}
+		fmt.Fprintf(tw, "ok\t%s\t%s\n", c.name, c.detail)
+	}
+	tw.Flush()
+	if passed {
+		fmt.Fprintln(w, "configuration is valid")
+	} else {
+		fmt.Fprintln(w, "configuration is invalid")
+	}
+	return passed
+}
+
+// checkAddr checks that addr is a host:port net.Listen accepts, with an
+// optional host and a port from 0 to 65535.
+func checkAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("port %q: want a number from 0 to 65535", port)
+	}
+	return nil
+}
+
+// checkOrigins checks each CORS origin other than "*" with utils.IsValidURL.
+func checkOrigins(origins []string) error {
+	var errs []error
+	for _, o := range origins {
+		if o == "*" {
+			continue
+		}
+		if _, err := utils.IsValidURL(o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkTLS checks that TLS_CERT_FILE and TLS_KEY_FILE are set together,
+// exist and load as a certificate and matching key.
+func checkTLS() (string, error) {
+	certFile, keyFile, ok := config.TLSFiles()
+	switch {
+	case certFile == "" && keyFile == "":
+		return "not configured", nil
+	case !ok && certFile == "":
+		return "", errors.New("TLS_KEY_FILE is set without TLS_CERT_FILE")
+	case !ok:
+		return "", errors.New("TLS_CERT_FILE is set without TLS_KEY_FILE")
+	}
+	var errs []error
+	for _, f := range []struct{ env, path string }{{"TLS_CERT_FILE", certFile}, {"TLS_KEY_FILE", keyFile}} {
+		if _, err := os.Stat(f.path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	if _, err := newCertReloader(certFile, keyFile); err != nil {
+		return "", err
+	}
+	return certFile, nil
+}
+
+// checkWebhooks checks each webhook URL with utils.IsValidURL and that
+// deliveries have a secret to be signed with.
+func checkWebhooks(urls []string, secret string) error {
+	var errs []error
+	if len(urls) > 0 && secret == "" {
+		errs = append(errs, errors.New("WEBHOOK_SECRET must be set with WEBHOOK_URLS"))
+	}
+	for _, u := range urls {
+		if _, err := utils.IsValidURL(u); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkAuditLog checks that the audit log file could be created or
+// appended to, without creating it: its directory must exist, and the path
+// must not be a directory itself.
+func checkAuditLog(path string) error {
+	if path == "-" {
+		return nil
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return fmt.Errorf("AUDIT_LOG: %s is a directory", path)
+	}
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("AUDIT_LOG: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("AUDIT_LOG: %s is not a directory", filepath.Dir(path))
+	}
+	return nil
+}
+
+func checkDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("STATIC_DIR: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("STATIC_DIR: %s is not a directory", dir)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearConfigEnv unsets the variables checkConfig reads that a test does
+// not set itself.
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{
+		"ADDR", "PORT", "LOG_LEVEL", "TLS_CERT_FILE", "TLS_KEY_FILE", "HTTP_REDIRECT_ADDR",
+		"WEBHOOK_URLS", "WEBHOOK_SECRET", "SEED_DATA", "STATIC_DIR", "CONFIG_FILE",
+		"RATE_LIMIT_RPS", "RATE_LIMIT_BURST", "REQUEST_TIMEOUT", "CORS_ALLOWED_ORIGINS",
+		"USER_RATE_LIMIT_RPS", "USER_RATE_LIMIT_BURST",
+	} {
+		t.Setenv(env, "")
+	}
+}
+
+// failedChecks returns the report and the names of the checks that failed.
+func failedChecks(t *testing.T) (string, []string, bool) {
+	t.Helper()
+	checks := checkConfig()
+	var buf bytes.Buffer
+	passed := writeConfigReport(&buf, checks)
+	var failed []string
+	for _, c := range checks {
+		if c.err != nil {
+			failed = append(failed, c.name)
+		}
+	}
+	return buf.String(), failed, passed
+}
+
+func TestValidateConfigPasses(t *testing.T) {
+	clearConfigEnv(t)
+	certFile, keyFile, _ := writeSelfSignedCert(t, t.TempDir())
+	t.Setenv("TLS_CERT_FILE", certFile)
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+
+	report, failed, passed := failedChecks(t)
+	if !passed || len(failed) > 0 {
+		t.Fatalf("failed checks %q:\n%s", failed, report)
+	}
+	if !strings.Contains(report, "configuration is valid") || !strings.Contains(report, certFile) {
+		t.Errorf("report does not show the TLS certificate checked:\n%s", report)
+	}
+}
+
+func TestValidateConfigMissingTLSCert(t *testing.T) {
+	clearConfigEnv(t)
+	dir := t.TempDir()
+	_, keyFile, _ := writeSelfSignedCert(t, dir)
+	missing := filepath.Join(dir, "missing-cert.pem")
+	t.Setenv("TLS_CERT_FILE", missing)
+	t.Setenv("TLS_KEY_FILE", keyFile)
+
+	report, failed, passed := failedChecks(t)
+	if passed {
+		t.Fatalf("want the configuration rejected:\n%s", report)
+	}
+	if len(failed) != 1 || failed[0] != "TLS" {
+		t.Errorf("failed checks = %q, want only TLS", failed)
+	}
+	for _, want := range []string{"FAIL  TLS", "TLS_CERT_FILE", missing, "configuration is invalid"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report does not mention %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestValidateConfigReportsEveryProblem(t *testing.T) {
+	clearConfigEnv(t)
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ADDR", "localhost:http-ish")
+	t.Setenv("RATE_LIMIT_RPS", "0")
+	t.Setenv("TLS_KEY_FILE", "key.pem")
+	t.Setenv("WEBHOOK_URLS", "not a url")
+	t.Setenv("STATIC_DIR", notDir)
+
+	report, failed, _ := failedChecks(t)
+	want := []string{"listen address", "feature flags, timeouts and limits", "TLS", "webhooks", "static files"}
+	if strings.Join(failed, ",") != strings.Join(want, ",") {
+		t.Errorf("failed checks = %q, want %q\n%s", failed, want, report)
+	}
+	if !strings.Contains(report, "TLS_KEY_FILE is set without TLS_CERT_FILE") {
+		t.Errorf("report does not explain the TLS problem:\n%s", report)
+	}
+}
@@ -0,0 +1,245 @@
+// Package audit keeps an append-only record of changes to users and
+// products: who made each one, to which entity, and the fields it changed
+// from what to what, written as one JSON object per line.
+package audit
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-project/internal/auth"
+	"go-project/pkg/utils"
+)
+
+// Actions of an Entry.
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// Anonymous is the actor of changes made without an authenticated user.
+const Anonymous = "anonymous"
+
+// Redacted stands in for both values of a changed field tagged
+// audit:",redact", such as a password hash.
+const Redacted = `"[REDACTED]"`
+
+// Entry is one line of the audit log.
+type Entry struct {
+	At time.Time `json:"at"`
+	// Actor is "user:" followed by the ID auth.UserIDFromContext gives, or
+	// Anonymous.
+	Actor    string `json:"actor"`
+	Action   string `json:"action"`
+	Entity   string `json:"entity"`
+	EntityID int    `json:"entity_id"`
+	// Changes are the fields that differ, by their JSON name; see Diff.
+	Changes map[string]Change `json:"changes"`
+}
+
+// Change is a field's JSON value before and after. Before is left out for
+// a create and After for a delete.
+type Change struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Logger writes an Entry for each change recorded with it to a sink, such
+// as a file opened for appending. It is safe for concurrent use; each entry
+// is written with a single Write. A nil *Logger records nothing, so callers
+// need not check whether auditing is on.
+type Logger struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewLogger returns a Logger writing JSON lines to w.
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{w: w, now: time.Now}
+}
+
+// Record writes an entry for action on the entity of the given type and
+// id, made by the user authenticated in ctx. before and after are the
+// entity, a struct or a pointer to one, as it was and as it is now; before
+// is nil for a create and after for a delete. A failed write is logged,
+// not returned, since the change it records has already been made.
+func (l *Logger) Record(ctx context.Context, action, entity string, id int, before, after any) {
+	if l == nil {
+		return
+	}
+	changes, err := Diff(before, after)
+	if err != nil {
+		slog.ErrorContext(ctx, "audit: diffing entity", "entity", entity, "id", id, "error", err)
+		return
+	}
+	line, err := json.Marshal(Entry{
+		At:       l.now().UTC(),
+		Actor:    actor(ctx),
+		Action:   action,
+		Entity:   entity,
+		EntityID: id,
+		Changes:  changes,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "audit: encoding entry", "entity", entity, "id", id, "error", err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(line, '\n')); err != nil {
+		slog.ErrorContext(ctx, "audit: writing entry", "entity", entity, "id", id, "error", err)
+	}
+}
+
+func actor(ctx context.Context) string {
+	if id, ok := auth.UserIDFromContext(ctx); ok {
+		return "user:" + strconv.Itoa(id)
+	}
+	return Anonymous
+}
+
+// Diff returns the exported fields of before and after whose JSON values
+// differ, keyed by their JSON name; either may be nil, in which case every
+// field of the other is listed. Fields the JSON encoding leaves out, with
+// json:"-", are skipped unless they have an audit tag naming them, and a
+// field tagged audit:"-" is always skipped. A field whose audit tag has the
+// redact option, such as audit:"password_hash,redact", is listed when it
+// changes with both values replaced by Redacted, and a string field with the
+// mask option, such as audit:"email,mask", with both values masked by
+// utils.MaskEmail.
+func Diff(before, after any) (map[string]Change, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]Change)
+	for name, f := range cur {
+		o, ok := old[name]
+		if ok && bytes.Equal(o.value, f.value) {
+			continue
+		}
+		var c Change
+		if ok {
+			c.Before = o.value
+		}
+		c.After = f.value
+		changes[name] = c
+	}
+	for name, o := range old {
+		if _, ok := cur[name]; !ok {
+			changes[name] = Change{Before: o.value}
+		}
+	}
+	for name, c := range changes {
+		switch opt := cmp.Or(cur[name].option, old[name].option); opt {
+		case "redact":
+			changes[name] = redact(c)
+		case "mask":
+			changes[name] = mask(c)
+		}
+	}
+	return changes, nil
+}
+
+func redact(c Change) Change {
+	if c.Before != nil {
+		c.Before = json.RawMessage(Redacted)
+	}
+	if c.After != nil {
+		c.After = json.RawMessage(Redacted)
+	}
+	return c
+}
+
+// mask replaces string values of c with utils.MaskEmail of them.
+func mask(c Change) Change {
+	for _, v := range []*json.RawMessage{&c.Before, &c.After} {
+		var s string
+		if *v == nil || json.Unmarshal(*v, &s) != nil {
+			continue
+		}
+		*v, _ = json.Marshal(utils.MaskEmail(s))
+	}
+	return c
+}
+
+// field is one struct field's JSON encoding and the option of its audit
+// tag, "redact", "mask" or "".
+type field struct {
+	value  json.RawMessage
+	option string
+}
+
+// fields returns the fields Diff compares, or nil for a nil v.
+func fields(v any) (map[string]field, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("audit: %T is not a struct", v)
+	}
+	out := make(map[string]field)
+	t := rv.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, option := fieldName(sf)
+		if name == "" {
+			continue
+		}
+		b, err := json.Marshal(rv.Field(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("audit: field %s: %w", sf.Name, err)
+		}
+		out[name] = field{value: b, option: option}
+	}
+	return out, nil
+}
+
+// fieldName returns the name sf is listed under, or "" to skip it, and the
+// option of its audit tag.
+func fieldName(sf reflect.StructField) (string, string) {
+	tag, hasTag := sf.Tag.Lookup("audit")
+	name, option, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", ""
+	}
+	if name != "" {
+		return name, option
+	}
+	jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch {
+	case jsonName == "-" && !hasTag:
+		return "", ""
+	case jsonName == "-":
+		return strings.ToLower(sf.Name), option
+	case jsonName == "":
+		return sf.Name, option
+	}
+	return jsonName, option
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/internal/auth"
+)
+
+type account struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email"`
+	Hash     string `json:"-" audit:"password_hash,redact"`
+	Internal string `json:"-"`
+	Notes    string `json:"notes,omitempty" audit:"-"`
+}
+
+func TestDiff(t *testing.T) {
+	before := account{ID: 1, Email: "ada@example.com", Hash: "old-hash", Internal: "x", Notes: "a"}
+	after := before
+	after.Email, after.Hash, after.Internal, after.Notes = "ada@example.org", "new-hash", "y", "b"
+
+	got, err := Diff(&before, &after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Change{
+		"email":         {Before: json.RawMessage(`"ada@example.com"`), After: json.RawMessage(`"ada@example.org"`)},
+		"password_hash": {Before: json.RawMessage(Redacted), After: json.RawMessage(Redacted)},
+	}
+	if !equalChanges(got, want) {
+		t.Errorf("Diff = %s, want %s", encode(t, got), encode(t, want))
+	}
+
+	created, err := Diff(nil, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 3 || created["id"].Before != nil || string(created["id"].After) != "1" {
+		t.Errorf("Diff of a create = %s, want every audited field with only an after value", encode(t, created))
+	}
+	deleted, err := Diff(&before, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 3 || string(deleted["email"].Before) != `"ada@example.com"` || deleted["email"].After != nil {
+		t.Errorf("Diff of a delete = %s, want every audited field with only a before value", encode(t, deleted))
+	}
+
+	if _, err := Diff("not a struct", nil); err == nil {
+		t.Error("Diff of a string: want an error")
+	}
+}
+
+func TestLoggerRecord(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return at }
+
+	before := account{ID: 7, Email: "ada@example.com", Hash: "secret-hash"}
+	after := before
+	after.Email = "ada@example.org"
+	l.Record(auth.WithUserID(context.Background(), 3), Update, "account", 7, &before, &after)
+	l.Record(context.Background(), Delete, "account", 7, &after, nil)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if !e.At.Equal(at) || e.Actor != "user:3" || e.Action != Update || e.Entity != "account" || e.EntityID != 7 || len(e.Changes) != 1 {
+		t.Errorf("entry = %+v", e)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Actor != Anonymous || e.Action != Delete {
+		t.Errorf("entry without a user = %+v, want an anonymous delete", e)
+	}
+	if strings.Contains(buf.String(), "secret-hash") {
+		t.Errorf("log leaks the unchanged hash:\n%s", buf.String())
+	}
+
+	var none *Logger
+	none.Record(context.Background(), Create, "account", 1, nil, &after)
+}
+
+func equalChanges(a, b map[string]Change) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, c := range a {
+		if d, ok := b[k]; !ok || !bytes.Equal(c.Before, d.Before) || !bytes.Equal(c.After, d.After) {
+			return false
+		}
+	}
+	return true
+}
+
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
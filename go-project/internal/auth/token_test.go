@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+func setNow(t *testing.T, tm time.Time) {
+	t.Helper()
+	orig := now
+	t.Cleanup(func() { now = orig })
+	now = func() time.Time { return tm }
+}
+
+func TestIssueAndParseToken(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	setNow(t, start)
+	token, err := IssueToken(testSecret, 42, time.Hour)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	setNow(t, start.Add(59*time.Minute))
+	id, err := ParseToken(testSecret, token)
+	if err != nil || id != 42 {
+		t.Fatalf("ParseToken = %d, %v, want 42, nil", id, err)
+	}
+
+	setNow(t, start.Add(time.Hour))
+	if _, err := ParseToken(testSecret, token); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("ParseToken of an expired token err = %v, want ErrExpiredToken", err)
+	}
+}
+
+func TestParseTokenRejectsTampering(t *testing.T) {
+	token, err := IssueToken(testSecret, 42, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	forged, _ := json.Marshal(claims{Sub: "1", Exp: time.Now().Add(time.Hour).Unix()})
+	noneHeader := b64.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := map[string]string{
+		"wrong secret":     mustIssue(t, []byte("other-secret")),
+		"swapped payload":  parts[0] + "." + b64.EncodeToString(forged) + "." + parts[2],
+		"flipped sig byte": parts[0] + "." + parts[1] + "." + flipFirst(parts[2]),
+		"alg none":         noneHeader + "." + parts[1] + ".",
+		"not a jwt":        "abc",
+		"empty":            "",
+	}
+	for name, tok := range tests {
+		if _, err := ParseToken(testSecret, tok); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func mustIssue(t *testing.T, secret []byte) string {
+	t.Helper()
+	tok, err := IssueToken(secret, 42, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+// flipFirst changes the first character, which always alters the decoded
+// bytes (the last one may only carry padding bits).
+func flipFirst(s string) string {
+	if s[0] == 'A' {
+		return "B" + s[1:]
+	}
+	return "A" + s[1:]
+}
+
+func TestUserIDContext(t *testing.T) {
+	if _, ok := UserIDFromContext(context.Background()); ok {
+		t.Fatal("UserIDFromContext on an empty context reported ok")
+	}
+	id, ok := UserIDFromContext(WithUserID(context.Background(), 7))
+	if !ok || id != 7 {
+		t.Fatalf("UserIDFromContext = %d, %v, want 7, true", id, ok)
+	}
+}
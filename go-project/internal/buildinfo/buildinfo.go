@@ -0,0 +1,51 @@
+// Package buildinfo identifies the running build. Version, Commit and
+// BuildDate are meant to be set when linking:
+//
+//	go build -ldflags "\
+//	  -X go-project/internal/buildinfo.Version=1.4.0 \
+//	  -X go-project/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X go-project/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags -X; see the package documentation.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes a build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the running build's Info. A Commit or BuildDate not set at
+// link time is taken from the version control stamp the go command embeds
+// when building inside a repository, or left empty when there is none.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+	if info.Commit != "" && info.BuildDate != "" {
+		return info
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = s.Value
+		case s.Key == "vcs.time" && info.BuildDate == "":
+			info.BuildDate = s.Value
+		}
+	}
+	return info
+}
@@ -0,0 +1,343 @@
+// Package config resolves the server's runtime settings from command-line
+// flags, the environment and an optional JSON config file.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAddr is used when no listen address is configured.
+const DefaultAddr = ":8080"
+
+var addrFlag = flag.String("addr", "", "listen address, e.g. :8080 (overrides ADDR and PORT)")
+
+// ListenAddr returns the address the server should listen on. Sources are
+// consulted in this order, first non-empty value wins:
+//
+//  1. the -addr flag
+//  2. the ADDR environment variable
+//  3. the PORT environment variable, as injected by most PaaS platforms;
+//     a bare port number such as "3000" is turned into ":3000"
+//  4. DefaultAddr
+//
+// Flags must already be parsed when ListenAddr is called.
+func ListenAddr() string {
+	return resolveAddr(*addrFlag, os.Getenv("ADDR"), os.Getenv("PORT"))
+}
+
+func resolveAddr(flagAddr, envAddr, envPort string) string {
+	if addr := strings.TrimSpace(flagAddr); addr != "" {
+		return addr
+	}
+	if addr := strings.TrimSpace(envAddr); addr != "" {
+		return addr
+	}
+	if port := strings.TrimSpace(envPort); port != "" {
+		if strings.Contains(port, ":") {
+			return port
+		}
+		return ":" + port
+	}
+	return DefaultAddr
+}
+
+// TLSFiles returns the certificate and private key paths from the
+// TLS_CERT_FILE and TLS_KEY_FILE environment variables. ok is true only when
+// both are set; otherwise the server should serve plain HTTP.
+func TLSFiles() (certFile, keyFile string, ok bool) {
+	certFile = strings.TrimSpace(os.Getenv("TLS_CERT_FILE"))
+	keyFile = strings.TrimSpace(os.Getenv("TLS_KEY_FILE"))
+	return certFile, keyFile, certFile != "" && keyFile != ""
+}
+
+// HTTPRedirectAddr returns the address of the optional plain-HTTP listener
+// that redirects every request to HTTPS, read from HTTP_REDIRECT_ADDR
+// (typically ":80"). An empty result disables the redirect listener. It only
+// applies when TLS is enabled.
+func HTTPRedirectAddr() string {
+	return strings.TrimSpace(os.Getenv("HTTP_REDIRECT_ADDR"))
+}
+
+// LogLevel returns the minimum level to log from LOG_LEVEL, such as "debug"
+// or "warn". An empty result means info.
+func LogLevel() string {
+	return strings.TrimSpace(os.Getenv("LOG_LEVEL"))
+}
+
+// DevMode reports whether APP_ENV is "development" or "dev", in which case
+// logs are written as text instead of JSON.
+func DevMode() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("APP_ENV"))) {
+	case "development", "dev":
+		return true
+	}
+	return false
+}
+
+// Webhooks returns the endpoints to notify of price changes, read as a
+// comma-separated list from WEBHOOK_URLS, and the secret deliveries are
+// signed with, from WEBHOOK_SECRET. No URLs means webhooks are off.
+func Webhooks() (urls []string, secret string) {
+	for _, u := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls, os.Getenv("WEBHOOK_SECRET")
+}
+
+// JWTSecret returns the secret bearer tokens on the API routes are verified
+// with, read from JWT_SECRET. An empty result serves every API request as
+// anonymous.
+func JWTSecret() string {
+	return os.Getenv("JWT_SECRET")
+}
+
+// AuditLog returns where to append the audit log of changes to users and
+// products, read from AUDIT_LOG: a file path, or - for standard output. An
+// empty result turns auditing off.
+func AuditLog() string {
+	return strings.TrimSpace(os.Getenv("AUDIT_LOG"))
+}
+
+// StaticDir returns the directory of web UI files to serve, read from
+// STATIC_DIR. An empty result disables static file serving.
+func StaticDir() string {
+	return strings.TrimSpace(os.Getenv("STATIC_DIR"))
+}
+
+// Seeding is how much generated data to load at startup; see SeedData.
+type Seeding struct {
+	Users, Products int
+	Seed            uint64
+}
+
+// DefaultSeeding is used for any of SEED_USERS, SEED_PRODUCTS and SEED left
+// unset.
+var DefaultSeeding = Seeding{Users: 20, Products: 50, Seed: 1}
+
+// SeedData reports whether SEED_DATA asks for the repositories to be filled
+// with generated users and products at startup, for local development, and
+// how many of each: SEED_USERS and SEED_PRODUCTS. SEED picks which data is
+// generated; the same seed always gives the same records. Unset counts and
+// seed keep DefaultSeeding. A value that does not parse, or a negative
+// count, is an error.
+func SeedData() (Seeding, bool, error) {
+	s := DefaultSeeding
+	raw := strings.TrimSpace(os.Getenv("SEED_DATA"))
+	if raw == "" {
+		return s, false, nil
+	}
+	on, err := strconv.ParseBool(raw)
+	if err != nil {
+		return s, false, fmt.Errorf("SEED_DATA=%q: want true or false", raw)
+	}
+	if !on {
+		return s, false, nil
+	}
+	var errs []error
+	for _, c := range []struct {
+		env string
+		dst *int
+	}{{"SEED_USERS", &s.Users}, {"SEED_PRODUCTS", &s.Products}} {
+		raw := strings.TrimSpace(os.Getenv(c.env))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("%s=%q: want a whole number of at least 0", c.env, raw))
+			continue
+		}
+		*c.dst = n
+	}
+	if raw := strings.TrimSpace(os.Getenv("SEED")); raw != "" {
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SEED=%q: want a whole number of at least 0", raw))
+		} else {
+			s.Seed = n
+		}
+	}
+	return s, true, errors.Join(errs...)
+}
+
+// Timeouts bounds how long the server spends on each phase of a connection.
+// A zero field means no limit, which lets a slow or stalled client hold a
+// connection and its goroutine open indefinitely.
+type Timeouts struct {
+	// ReadHeader limits reading the request line and headers. It is the
+	// main defence against Slowloris clients, which trickle headers in to
+	// keep many connections open at once.
+	ReadHeader time.Duration
+	// Read limits reading the whole request, headers and body, so a client
+	// cannot stall mid-upload.
+	Read time.Duration
+	// Write limits the time from the end of the request headers to the end
+	// of the response, so a client that stops reading cannot pin a
+	// handler. Streaming handlers lift it for their own responses.
+	Write time.Duration
+	// Idle limits how long a keep-alive connection waits for its next
+	// request before it is closed.
+	Idle time.Duration
+}
+
+// DefaultTimeouts are used for any timeout left unset in the environment.
+var DefaultTimeouts = Timeouts{
+	ReadHeader: 5 * time.Second,
+	Read:       15 * time.Second,
+	Write:      15 * time.Second,
+	Idle:       60 * time.Second,
+}
+
+// ServerTimeouts returns the server timeouts, reading each from its
+// environment variable as a Go duration such as "30s":
+// HTTP_READ_HEADER_TIMEOUT, HTTP_READ_TIMEOUT, HTTP_WRITE_TIMEOUT and
+// HTTP_IDLE_TIMEOUT. Unset variables keep DefaultTimeouts. A value that does
+// not parse or is not positive is an error rather than silently disabling
+// the timeout.
+func ServerTimeouts() (Timeouts, error) {
+	t := DefaultTimeouts
+	for _, v := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", &t.ReadHeader},
+		{"HTTP_READ_TIMEOUT", &t.Read},
+		{"HTTP_WRITE_TIMEOUT", &t.Write},
+		{"HTTP_IDLE_TIMEOUT", &t.Idle},
+	} {
+		raw := strings.TrimSpace(os.Getenv(v.env))
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return Timeouts{}, fmt.Errorf("%s=%q: want a positive duration such as 15s", v.env, raw)
+		}
+		*v.dst = d
+	}
+	return t, nil
+}
+
+// Config is the settings main assembles the server from, as read by
+// FromEnv.
+type Config struct {
+	// RateLimit applies the per-client rate limit to the API routes
+	// (RATE_LIMIT_ENABLED).
+	RateLimit bool
+	// Gzip compresses responses with Brotli, gzip or deflate for clients
+	// that accept one of them (GZIP_ENABLED).
+	Gzip bool
+	// RequestLogging logs a line per request (REQUEST_LOGGING_ENABLED).
+	RequestLogging bool
+	// Metrics records Prometheus metrics and serves /metrics
+	// (METRICS_ENABLED).
+	Metrics bool
+	// Timeouts are the server's connection timeouts; see ServerTimeouts.
+	Timeouts Timeouts
+	// Limits are the middleware thresholds; see ResolveLimits.
+	Limits Limits
+}
+
+// FromEnv reads the Config from the environment, and the Limits also from
+// flags and the config file as ResolveLimits describes. Every feature is on
+// unless its variable turns it off; flags accept the values
+// strconv.ParseBool does, such as "true", "false", "1" and "0". Every
+// problem is reported in the one error: flags that do not parse, invalid
+// timeouts and limits, and variables that look like a misspelt flag, such
+// as GIZP_ENABLED or gzip_enabled, which would otherwise be silently
+// ignored. Other variables ending in _ENABLED, such as CGO_ENABLED, belong
+// to other programs and are left alone.
+func FromEnv() (Config, error) {
+	c := Config{RateLimit: true, Gzip: true, RequestLogging: true, Metrics: true}
+	flags := []struct {
+		env string
+		dst *bool
+	}{
+		{"RATE_LIMIT_ENABLED", &c.RateLimit},
+		{"GZIP_ENABLED", &c.Gzip},
+		{"REQUEST_LOGGING_ENABLED", &c.RequestLogging},
+		{"METRICS_ENABLED", &c.Metrics},
+	}
+	var errs []error
+	known := map[string]bool{}
+	for _, f := range flags {
+		known[f.env] = true
+		raw := strings.TrimSpace(os.Getenv(f.env))
+		if raw == "" {
+			continue
+		}
+		on, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: want true or false", f.env, raw))
+			continue
+		}
+		*f.dst = on
+	}
+	var unknown []string
+	for _, kv := range os.Environ() {
+		env, _, _ := strings.Cut(kv, "=")
+		if !known[env] && nearMiss(env, known) {
+			unknown = append(unknown, env)
+		}
+	}
+	sort.Strings(unknown)
+	for _, env := range unknown {
+		errs = append(errs, fmt.Errorf("unknown feature flag %s", env))
+	}
+
+	var err error
+	if c.Timeouts, err = ServerTimeouts(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Limits, err = ResolveLimits(); err != nil {
+		errs = append(errs, err)
+	}
+	return c, errors.Join(errs...)
+}
+
+// nearMiss reports whether env looks like a typo of one of the known flag
+// names: the same name in another case, or a name ending in _ENABLED whose
+// feature is at most two edits away from a known one's.
+func nearMiss(env string, known map[string]bool) bool {
+	upper := strings.ToUpper(env)
+	feature, ok := strings.CutSuffix(upper, "_ENABLED")
+	if !ok {
+		return false
+	}
+	for name := range known {
+		if upper == name || editDistance(feature, strings.TrimSuffix(name, "_ENABLED")) <= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
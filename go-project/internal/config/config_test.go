@@ -0,0 +1,195 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		flag string
+		addr string
+		port string
+		want string
+	}{
+		{name: "default", want: ":8080"},
+		{name: "port number", port: "3000", want: ":3000"},
+		{name: "port with colon", port: ":3000", want: ":3000"},
+		{name: "addr beats port", addr: "127.0.0.1:9000", port: "3000", want: "127.0.0.1:9000"},
+		{name: "flag beats env", flag: ":7000", addr: ":9000", port: "3000", want: ":7000"},
+		{name: "blank values ignored", flag: " ", addr: "", port: " 4000 ", want: ":4000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADDR", tt.addr)
+			t.Setenv("PORT", tt.port)
+			*addrFlag = tt.flag
+			defer func() { *addrFlag = "" }()
+
+			if got := ListenAddr(); got != tt.want {
+				t.Errorf("ListenAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTLSFiles(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "/etc/tls/tls.crt")
+	t.Setenv("TLS_KEY_FILE", "")
+	if _, _, ok := TLSFiles(); ok {
+		t.Fatal("TLS enabled with only a certificate configured")
+	}
+
+	t.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+	cert, key, ok := TLSFiles()
+	if !ok || cert != "/etc/tls/tls.crt" || key != "/etc/tls/tls.key" {
+		t.Fatalf("TLSFiles() = %q, %q, %v", cert, key, ok)
+	}
+}
+
+func TestStaticDir(t *testing.T) {
+	t.Setenv("STATIC_DIR", " ./web/dist ")
+	if got := StaticDir(); got != "./web/dist" {
+		t.Fatalf("StaticDir() = %q, want ./web/dist", got)
+	}
+}
+
+func TestLogSettings(t *testing.T) {
+	t.Setenv("LOG_LEVEL", " debug ")
+	if got := LogLevel(); got != "debug" {
+		t.Fatalf("LogLevel() = %q, want debug", got)
+	}
+	for env, want := range map[string]bool{"": false, "production": false, "development": true, "Dev": true} {
+		t.Setenv("APP_ENV", env)
+		if got := DevMode(); got != want {
+			t.Errorf("DevMode() with APP_ENV=%q = %v, want %v", env, got, want)
+		}
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	t.Setenv("WEBHOOK_URLS", " https://a.example/hook, ,http://b.example/hook ")
+	t.Setenv("WEBHOOK_SECRET", "s3cret")
+	urls, secret := Webhooks()
+	if len(urls) != 2 || urls[0] != "https://a.example/hook" || urls[1] != "http://b.example/hook" || secret != "s3cret" {
+		t.Fatalf("Webhooks() = %q, %q", urls, secret)
+	}
+	t.Setenv("WEBHOOK_URLS", "")
+	if urls, _ := Webhooks(); urls != nil {
+		t.Fatalf("Webhooks() with no URLs = %q, want none", urls)
+	}
+}
+
+func TestSeedData(t *testing.T) {
+	for _, env := range []string{"SEED_DATA", "SEED_USERS", "SEED_PRODUCTS", "SEED"} {
+		t.Setenv(env, "")
+	}
+	if _, on, err := SeedData(); on || err != nil {
+		t.Fatalf("SeedData() unset = %v, %v; want off", on, err)
+	}
+	t.Setenv("SEED_DATA", "true")
+	t.Setenv("SEED_PRODUCTS", " 200 ")
+	t.Setenv("SEED", "42")
+	got, on, err := SeedData()
+	if err != nil || !on {
+		t.Fatalf("SeedData() = %v, %v; want on", on, err)
+	}
+	if want := (Seeding{Users: 20, Products: 200, Seed: 42}); got != want {
+		t.Fatalf("SeedData() = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("SEED_USERS", "-1")
+	t.Setenv("SEED", "forty-two")
+	_, _, err = SeedData()
+	if err == nil || !strings.Contains(err.Error(), "SEED_USERS") || !strings.Contains(err.Error(), "SEED=") {
+		t.Errorf("bad SEED_USERS and SEED: err = %v, want both reported", err)
+	}
+	t.Setenv("SEED_DATA", "yes please")
+	if _, _, err := SeedData(); err == nil {
+		t.Error(`SEED_DATA="yes please": want an error`)
+	}
+}
+
+func TestServerTimeouts(t *testing.T) {
+	t.Setenv("HTTP_READ_HEADER_TIMEOUT", "")
+	t.Setenv("HTTP_READ_TIMEOUT", " 30s ")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "2m")
+	got, err := ServerTimeouts()
+	if err != nil {
+		t.Fatalf("ServerTimeouts: %v", err)
+	}
+	want := Timeouts{ReadHeader: 5 * time.Second, Read: 30 * time.Second, Write: 15 * time.Second, Idle: 2 * time.Minute}
+	if got != want {
+		t.Fatalf("ServerTimeouts() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"15", "soon", "0s", "-1s"} {
+		t.Setenv("HTTP_WRITE_TIMEOUT", bad)
+		if _, err := ServerTimeouts(); err == nil {
+			t.Errorf("HTTP_WRITE_TIMEOUT=%q: want an error", bad)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	clearLimitsEnv(t)
+	for _, env := range []string{"RATE_LIMIT_ENABLED", "GZIP_ENABLED", "REQUEST_LOGGING_ENABLED", "METRICS_ENABLED"} {
+		t.Setenv(env, "")
+	}
+	cfg, err := FromEnv()
+	if err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	want := Config{RateLimit: true, Gzip: true, RequestLogging: true, Metrics: true, Timeouts: DefaultTimeouts,
+		Limits: Limits{CORSOrigins: []string{"*"}, RateLimitRPS: 10, RateLimitBurst: 20, UserRateLimitRPS: 10, UserRateLimitBurst: 20,
+			RequestTimeout: 10 * time.Second, LogSampleRate: 1}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("defaults = %+v, want %+v", cfg, want)
+	}
+
+	t.Setenv("GZIP_ENABLED", "false")
+	t.Setenv("METRICS_ENABLED", " 0 ")
+	if cfg, err = FromEnv(); err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+	if cfg.Gzip || cfg.Metrics || !cfg.RateLimit || !cfg.RequestLogging {
+		t.Fatalf("with gzip and metrics off: %+v", cfg)
+	}
+}
+
+func TestFromEnvReportsEveryProblem(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "sometimes")
+	t.Setenv("GIZP_ENABLED", "false")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "forever")
+	_, err := FromEnv()
+	if err == nil {
+		t.Fatal("FromEnv: want an error")
+	}
+	for _, want := range []string{"RATE_LIMIT_ENABLED", "unknown feature flag GIZP_ENABLED", "HTTP_IDLE_TIMEOUT"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestFromEnvIgnoresOtherProgramsFlags(t *testing.T) {
+	clearLimitsEnv(t)
+	t.Setenv("CGO_ENABLED", "0")
+	t.Setenv("DOCKER_BUILDKIT_ENABLED", "1")
+	if _, err := FromEnv(); err != nil {
+		t.Fatalf("FromEnv: %v", err)
+	}
+
+	t.Setenv("gzip_enabled", "false")
+	t.Setenv("METRIC_ENABLED", "false")
+	_, err := FromEnv()
+	for _, want := range []string{"gzip_enabled", "METRIC_ENABLED"} {
+		if err == nil || !strings.Contains(err.Error(), "unknown feature flag "+want) {
+			t.Errorf("FromEnv error = %v, want it to report %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the settings in Limits, which handlers.Routes also falls
+// back on for the zero fields of handlers.Services.
+var (
+	DefaultCORSOrigins    = []string{"*"}
+	DefaultRateLimitRPS   = 10.0
+	DefaultRateLimitBurst = 20
+	DefaultRequestTimeout = 10 * time.Second
+	DefaultLogSampleRate  = 1.0
+	// Authenticated users get the same limit as anonymous clients unless
+	// configured otherwise.
+	DefaultUserRateLimitRPS   = 10.0
+	DefaultUserRateLimitBurst = 20
+)
+
+// Limits are the thresholds the middleware is built with. Each can come
+// from a flag, an environment variable or the config file; see
+// ResolveLimits for the order.
+type Limits struct {
+	// CORSOrigins are the origins allowed to make cross-origin requests;
+	// "*" allows any.
+	CORSOrigins []string
+	// RateLimitRPS and RateLimitBurst are the per-client rate limit of the
+	// rate-limited API routes: requests per second, and how many may
+	// arrive at once.
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// UserRateLimitRPS and UserRateLimitBurst are the same limit for
+	// authenticated requests, which are counted per user rather than per
+	// client IP.
+	UserRateLimitRPS   float64
+	UserRateLimitBurst int
+	// RequestTimeout bounds each API request; see
+	// handlers.TimeoutMiddleware.
+	RequestTimeout time.Duration
+	// LogSampleRate is the fraction of successful requests given a log
+	// line, and LogExcludePaths are paths whose successful requests never
+	// are; see handlers.SampleSuccesses and handlers.ExcludePaths. Other
+	// responses are always logged.
+	LogSampleRate   float64
+	LogExcludePaths []string
+}
+
+var (
+	configFlag    = flag.String("config", "", "JSON config file (overrides CONFIG_FILE)")
+	corsFlag      = flag.String("cors-origins", "", "comma-separated origins allowed by CORS, or * (overrides CORS_ALLOWED_ORIGINS)")
+	rpsFlag       = flag.String("rate-limit-rps", "", "requests per second allowed per client (overrides RATE_LIMIT_RPS)")
+	burstFlag     = flag.String("rate-limit-burst", "", "requests a client may make at once (overrides RATE_LIMIT_BURST)")
+	userRPSFlag   = flag.String("user-rate-limit-rps", "", "requests per second allowed per authenticated user (overrides USER_RATE_LIMIT_RPS)")
+	userBurstFlag = flag.String("user-rate-limit-burst", "", "requests an authenticated user may make at once (overrides USER_RATE_LIMIT_BURST)")
+	timeoutFlag   = flag.String("request-timeout", "", "time limit for each API request, e.g. 10s (overrides REQUEST_TIMEOUT)")
+	sampleFlag    = flag.String("log-sample-rate", "", "fraction of successful requests to log, 0 to 1 (overrides LOG_SAMPLE_RATE)")
+	excludeFlag   = flag.String("log-exclude-paths", "", "comma-separated paths whose successful requests are not logged (overrides LOG_EXCLUDE_PATHS)")
+)
+
+// fileLimits is the config file's JSON: an object with any of these keys.
+// Fields left out keep their defaults.
+type fileLimits struct {
+	CORSAllowedOrigins []string `json:"cors_allowed_origins"`
+	RateLimitRPS       *float64 `json:"rate_limit_rps"`
+	RateLimitBurst     *int     `json:"rate_limit_burst"`
+	UserRateLimitRPS   *float64 `json:"user_rate_limit_rps"`
+	UserRateLimitBurst *int     `json:"user_rate_limit_burst"`
+	RequestTimeout     string   `json:"request_timeout"`
+	LogSampleRate      *float64 `json:"log_sample_rate"`
+	LogExcludePaths    []string `json:"log_exclude_paths"`
+}
+
+// limitSetting is one Limits field and where its value can come from.
+type limitSetting struct {
+	key, env, flag string
+	flagValue      *string
+	// fromFile returns the file's value as its flag would spell it, or ""
+	// when the file leaves it out.
+	fromFile func(fileLimits) string
+	parse    func(raw string, l *Limits) error
+}
+
+var limitSettings = []limitSetting{
+	{
+		key: "cors_allowed_origins", env: "CORS_ALLOWED_ORIGINS", flag: "-cors-origins", flagValue: corsFlag,
+		fromFile: func(f fileLimits) string { return strings.Join(f.CORSAllowedOrigins, ",") },
+		parse: func(raw string, l *Limits) (err error) {
+			l.CORSOrigins, err = parseOrigins(raw)
+			return err
+		},
+	},
+	{
+		key: "rate_limit_rps", env: "RATE_LIMIT_RPS", flag: "-rate-limit-rps", flagValue: rpsFlag,
+		fromFile: func(f fileLimits) string { return formatFloat(f.RateLimitRPS) },
+		parse: func(raw string, l *Limits) (err error) {
+			l.RateLimitRPS, err = parseRPS(raw)
+			return err
+		},
+	},
+	{
+		key: "rate_limit_burst", env: "RATE_LIMIT_BURST", flag: "-rate-limit-burst", flagValue: burstFlag,
+		fromFile: func(f fileLimits) string { return formatInt(f.RateLimitBurst) },
+		parse: func(raw string, l *Limits) (err error) {
+			l.RateLimitBurst, err = parseBurst(raw)
+			return err
+		},
+	},
+	{
+		key: "user_rate_limit_rps", env: "USER_RATE_LIMIT_RPS", flag: "-user-rate-limit-rps", flagValue: userRPSFlag,
+		fromFile: func(f fileLimits) string { return formatFloat(f.UserRateLimitRPS) },
+		parse: func(raw string, l *Limits) (err error) {
+			l.UserRateLimitRPS, err = parseRPS(raw)
+			return err
+		},
+	},
+	{
+		key: "user_rate_limit_burst", env: "USER_RATE_LIMIT_BURST", flag: "-user-rate-limit-burst", flagValue: userBurstFlag,
+		fromFile: func(f fileLimits) string { return formatInt(f.UserRateLimitBurst) },
+		parse: func(raw string, l *Limits) (err error) {
+			l.UserRateLimitBurst, err = parseBurst(raw)
+			return err
+		},
+	},
+	{
+		key: "request_timeout", env: "REQUEST_TIMEOUT", flag: "-request-timeout", flagValue: timeoutFlag,
+		fromFile: func(f fileLimits) string { return f.RequestTimeout },
+		parse: func(raw string, l *Limits) error {
+			d, err := time.ParseDuration(raw)
+			if err != nil || d <= 0 {
+				return errors.New("want a positive duration such as 10s")
+			}
+			l.RequestTimeout = d
+			return nil
+		},
+	},
+	{
+		key: "log_sample_rate", env: "LOG_SAMPLE_RATE", flag: "-log-sample-rate", flagValue: sampleFlag,
+		fromFile: func(f fileLimits) string {
+			if f.LogSampleRate == nil {
+				return ""
+			}
+			return strconv.FormatFloat(*f.LogSampleRate, 'g', -1, 64)
+		},
+		parse: func(raw string, l *Limits) error {
+			rate, err := strconv.ParseFloat(raw, 64)
+			if err != nil || !(rate >= 0 && rate <= 1) {
+				return errors.New("want a fraction from 0 to 1")
+			}
+			l.LogSampleRate = rate
+			return nil
+		},
+	},
+	{
+		key: "log_exclude_paths", env: "LOG_EXCLUDE_PATHS", flag: "-log-exclude-paths", flagValue: excludeFlag,
+		fromFile: func(f fileLimits) string { return strings.Join(f.LogExcludePaths, ",") },
+		parse: func(raw string, l *Limits) (err error) {
+			l.LogExcludePaths, err = parsePaths(raw)
+			return err
+		},
+	},
+}
+
+// ResolveLimits returns the Limits. Each setting is taken from the first of
+// these that gives it:
+//
+//  1. its flag, such as -rate-limit-rps
+//  2. its environment variable, such as RATE_LIMIT_RPS
+//  3. the JSON config file named by -config or CONFIG_FILE, under a key
+//     such as "rate_limit_rps"
+//  4. its default, such as DefaultRateLimitRPS
+//
+// A value that does not parse or is out of range is an error naming where
+// it came from, not a fallback to the default; so is a config file that
+// cannot be read or has keys ResolveLimits does not know. Every problem is
+// reported in the one error. Flags must already be parsed.
+func ResolveLimits() (Limits, error) {
+	l := Limits{
+		CORSOrigins:    slices.Clone(DefaultCORSOrigins),
+		RateLimitRPS:   DefaultRateLimitRPS,
+		RateLimitBurst: DefaultRateLimitBurst,
+		RequestTimeout: DefaultRequestTimeout,
+		LogSampleRate:  DefaultLogSampleRate,
+
+		UserRateLimitRPS:   DefaultUserRateLimitRPS,
+		UserRateLimitBurst: DefaultUserRateLimitBurst,
+	}
+	path := strings.TrimSpace(*configFlag)
+	if path == "" {
+		path = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+	}
+	var file fileLimits
+	if path != "" {
+		var err error
+		if file, err = readLimitsFile(path); err != nil {
+			return l, err
+		}
+	}
+
+	var errs []error
+	for _, s := range limitSettings {
+		raw, from := strings.TrimSpace(*s.flagValue), s.flag
+		if raw == "" {
+			raw, from = strings.TrimSpace(os.Getenv(s.env)), s.env
+		}
+		if raw == "" {
+			raw, from = strings.TrimSpace(s.fromFile(file)), s.key+" in "+path
+		}
+		if raw == "" {
+			continue
+		}
+		if err := s.parse(raw, &l); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", from, raw, err))
+		}
+	}
+	return l, errors.Join(errs...)
+}
+
+// readLimitsFile decodes the config file at path, rejecting unknown keys
+// since they are usually typos.
+func readLimitsFile(path string) (fileLimits, error) {
+	var f fileLimits
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, fmt.Errorf("config file: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return f, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// formatFloat and formatInt spell a config file number as its flag would,
+// or "" when the file leaves it out.
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'g', -1, 64)
+}
+
+func formatInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// parseRPS parses a rate limit in requests per second, which must be
+// positive and finite.
+func parseRPS(raw string) (float64, error) {
+	rps, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !(rps > 0) || math.IsInf(rps, 0) {
+		return 0, errors.New("want a positive number of requests per second")
+	}
+	return rps, nil
+}
+
+// parseBurst parses a rate limit burst, a whole number of at least 1.
+func parseBurst(raw string) (int, error) {
+	burst, err := strconv.Atoi(raw)
+	if err != nil || burst < 1 {
+		return 0, errors.New("want a whole number of at least 1")
+	}
+	return burst, nil
+}
+
+// parseOrigins splits a comma-separated origin list. Each entry must be "*"
+// or a scheme and host such as https://app.example.com, with no path.
+func parseOrigins(raw string) ([]string, error) {
+	var origins []string
+	for _, o := range strings.Split(raw, ",") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+		if o != "*" {
+			u, err := url.Parse(o)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+				return nil, fmt.Errorf("origin %q: want * or a scheme and host such as https://app.example.com", o)
+			}
+			o = u.Scheme + "://" + u.Host
+		}
+		origins = append(origins, o)
+	}
+	if len(origins) == 0 {
+		return nil, errors.New("want at least one origin")
+	}
+	return origins, nil
+}
+
+// parsePaths splits a comma-separated list of URL paths, each of which must
+// begin with "/".
+func parsePaths(raw string) ([]string, error) {
+	var paths []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("path %q: want a path beginning with /", p)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearLimitsEnv unsets every variable ResolveLimits reads, and the flags,
+// for the duration of the test.
+func clearLimitsEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for _, s := range limitSettings {
+		t.Setenv(s.env, "")
+	}
+	setLimitFlag(t, configFlag, "")
+	for _, s := range limitSettings {
+		setLimitFlag(t, s.flagValue, "")
+	}
+}
+
+// setLimitFlag sets a flag's value as if it had been parsed from the
+// command line, restoring it afterwards.
+func setLimitFlag(t *testing.T, f *string, v string) {
+	t.Helper()
+	old := *f
+	*f = v
+	t.Cleanup(func() { *f = old })
+}
+
+func writeConfigFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveLimitsDefaults(t *testing.T) {
+	clearLimitsEnv(t)
+	l, err := ResolveLimits()
+	if err != nil {
+		t.Fatalf("ResolveLimits: %v", err)
+	}
+	want := Limits{CORSOrigins: []string{"*"}, RateLimitRPS: 10, RateLimitBurst: 20, UserRateLimitRPS: 10, UserRateLimitBurst: 20,
+		RequestTimeout: 10 * time.Second, LogSampleRate: 1}
+	if !reflect.DeepEqual(l, want) {
+		t.Fatalf("defaults = %+v, want %+v", l, want)
+	}
+}
+
+func TestResolveLimitsPrecedence(t *testing.T) {
+	clearLimitsEnv(t)
+	t.Setenv("CONFIG_FILE", writeConfigFile(t, `{
+		"cors_allowed_origins": ["https://app.example.com", "https://admin.example.com/"],
+		"rate_limit_rps": 2.5,
+		"rate_limit_burst": 5,
+		"user_rate_limit_rps": 30,
+		"user_rate_limit_burst": 60,
+		"request_timeout": "3s",
+		"log_sample_rate": 0.25,
+		"log_exclude_paths": ["/healthz", "/metrics"]
+	}`))
+	t.Setenv("RATE_LIMIT_BURST", "7")
+	t.Setenv("REQUEST_TIMEOUT", "4s")
+	t.Setenv("LOG_EXCLUDE_PATHS", "/readyz")
+	t.Setenv("USER_RATE_LIMIT_BURST", "80")
+	setLimitFlag(t, timeoutFlag, "5s")
+	setLimitFlag(t, userBurstFlag, "90")
+
+	l, err := ResolveLimits()
+	if err != nil {
+		t.Fatalf("ResolveLimits: %v", err)
+	}
+	want := Limits{
+		CORSOrigins:        []string{"https://app.example.com", "https://admin.example.com"}, // file
+		RateLimitRPS:       2.5,                                                              // file
+		RateLimitBurst:     7,                                                                // env over file
+		UserRateLimitRPS:   30,                                                               // file
+		UserRateLimitBurst: 90,                                                               // flag over env and file
+		RequestTimeout:     5 * time.Second,                                                  // flag over env and file
+		LogSampleRate:      0.25,                                                             // file
+		LogExcludePaths:    []string{"/readyz"},                                              // env over file
+	}
+	if !reflect.DeepEqual(l, want) {
+		t.Fatalf("limits = %+v, want %+v", l, want)
+	}
+
+	// -config names a different file than CONFIG_FILE
+	setLimitFlag(t, configFlag, writeConfigFile(t, `{"rate_limit_rps": 50}`))
+	if l, err = ResolveLimits(); err != nil {
+		t.Fatalf("ResolveLimits: %v", err)
+	}
+	if l.RateLimitRPS != 50 || !reflect.DeepEqual(l.CORSOrigins, []string{"*"}) {
+		t.Errorf("with -config: %+v, want rps 50 and the default origins", l)
+	}
+}
+
+func TestResolveLimitsRejectsBadValues(t *testing.T) {
+	tests := []struct {
+		name, env, value string
+	}{
+		{"negative rps", "RATE_LIMIT_RPS", "-1"},
+		{"zero rps", "RATE_LIMIT_RPS", "0"},
+		{"rps not a number", "RATE_LIMIT_RPS", "NaN"},
+		{"zero burst", "RATE_LIMIT_BURST", "0"},
+		{"fractional burst", "RATE_LIMIT_BURST", "1.5"},
+		{"zero user rps", "USER_RATE_LIMIT_RPS", "0"},
+		{"zero user burst", "USER_RATE_LIMIT_BURST", "0"},
+		{"zero timeout", "REQUEST_TIMEOUT", "0s"},
+		{"timeout without unit", "REQUEST_TIMEOUT", "10"},
+		{"origin with a path", "CORS_ALLOWED_ORIGINS", "https://app.example.com/ui"},
+		{"origin without scheme", "CORS_ALLOWED_ORIGINS", "app.example.com"},
+		{"no origins", "CORS_ALLOWED_ORIGINS", " , "},
+		{"sample rate above 1", "LOG_SAMPLE_RATE", "1.5"},
+		{"negative sample rate", "LOG_SAMPLE_RATE", "-0.1"},
+		{"relative excluded path", "LOG_EXCLUDE_PATHS", "/healthz,metrics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearLimitsEnv(t)
+			t.Setenv(tt.env, tt.value)
+			_, err := ResolveLimits()
+			if err == nil || !strings.Contains(err.Error(), tt.env) {
+				t.Fatalf("%s=%q: err = %v, want an error naming %s", tt.env, tt.value, err, tt.env)
+			}
+		})
+	}
+}
+
+func TestResolveLimitsReportsFileProblems(t *testing.T) {
+	clearLimitsEnv(t)
+	path := writeConfigFile(t, `{"rate_limit_burst": 0, "rate_limit_rps": -3}`)
+	t.Setenv("CONFIG_FILE", path)
+	_, err := ResolveLimits()
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, want := range []string{"rate_limit_burst in " + path, "rate_limit_rps in " + path} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	for name, body := range map[string]string{
+		"unknown key": `{"rate_limit": 5}`,
+		"not JSON":    `rate_limit_rps: 5`,
+	} {
+		t.Setenv("CONFIG_FILE", writeConfigFile(t, body))
+		if _, err := ResolveLimits(); err == nil || !strings.Contains(err.Error(), "config file") {
+			t.Errorf("%s: err = %v, want a config file error", name, err)
+		}
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := ResolveLimits(); err == nil {
+		t.Error("missing file: want an error")
+	}
+}
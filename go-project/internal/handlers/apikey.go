@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// APIKeyHeader carries the key checked by APIKeyMiddleware.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware authenticates server-to-server calls by the X-API-Key
+// header. validate decides whether a key is acceptable so keys can come from
+// anywhere; StaticAPIKeys covers a fixed list. A missing or rejected key
+// gets 401, and a validate error gets 500.
+func APIKeyMiddleware(validate func(key string) (bool, error)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				writeJSONError(w, http.StatusUnauthorized, "missing API key")
+				return
+			}
+			ok, err := validate(key)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			if !ok {
+				writeJSONError(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// compareKeys is subtle.ConstantTimeCompare; tests swap it to count calls.
+var compareKeys = subtle.ConstantTimeCompare
+
+// StaticAPIKeys returns a validator for APIKeyMiddleware that accepts any of
+// keys. Keys are compared as SHA-256 digests in constant time, and every
+// key is checked on each call, so timing reveals neither which key matched
+// nor how much of a guess was right.
+func StaticAPIKeys(keys ...string) func(key string) (bool, error) {
+	digests := make([][32]byte, len(keys))
+	for i, k := range keys {
+		digests[i] = sha256.Sum256([]byte(k))
+	}
+	return func(key string) (bool, error) {
+		d := sha256.Sum256([]byte(key))
+		match := 0
+		for i := range digests {
+			match |= compareKeys(d[:], digests[i][:])
+		}
+		return match == 1, nil
+	}
+}
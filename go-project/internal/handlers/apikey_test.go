@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	validate := StaticAPIKeys("key-one", "key-two")
+	failing := func(string) (bool, error) { return false, errors.New("db down") }
+
+	tests := []struct {
+		name       string
+		validate   func(string) (bool, error)
+		key        string
+		wantStatus int
+	}{
+		{"first key", validate, "key-one", http.StatusOK},
+		{"second key", validate, "key-two", http.StatusOK},
+		{"empty header", validate, "", http.StatusUnauthorized},
+		{"wrong key", validate, "key-three", http.StatusUnauthorized},
+		{"prefix of a key", validate, "key-", http.StatusUnauthorized},
+		{"validator error", failing, "key-one", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := APIKeyMiddleware(tt.validate)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestStaticAPIKeysChecksEveryKey(t *testing.T) {
+	calls := 0
+	orig := compareKeys
+	t.Cleanup(func() { compareKeys = orig })
+	compareKeys = func(x, y []byte) int {
+		calls++
+		return orig(x, y)
+	}
+
+	validate := StaticAPIKeys("a", "b", "c", "d")
+	for _, key := range []string{"a", "d", "nope"} {
+		calls = 0
+		validate(key)
+		if calls != 4 {
+			t.Errorf("validate(%q) made %d comparisons, want 4 (no early return)", key, calls)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-project/internal/repository"
+)
+
+// CategoryHandlers serves the /api/categories endpoints. Products are
+// listed from the product repository, filtered by category.
+type CategoryHandlers struct {
+	Repo     repository.CategoryRepository
+	Products repository.ProductRepository
+}
+
+// NewCategoryHandlers returns handlers backed by repo and products.
+func NewCategoryHandlers(repo repository.CategoryRepository, products repository.ProductRepository) *CategoryHandlers {
+	return &CategoryHandlers{Repo: repo, Products: products}
+}
+
+// List handles GET /api/categories, returning every category ordered by
+// name.
+func (h *CategoryHandlers) List(w http.ResponseWriter, r *http.Request) {
+	cs, err := h.Repo.List(r.Context())
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+	writeJSON(w, http.StatusOK, derefAll(cs))
+}
+
+// ListProducts handles GET /api/categories/{slug}/products. It answers 404 for
+// an unknown slug and otherwise accepts the query parameters of
+// GET /api/products, with the category fixed by the path.
+func (h *CategoryHandlers) ListProducts(w http.ResponseWriter, r *http.Request) {
+	c, err := h.Repo.Get(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+	p, err := parsePageParams(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f, err := parseProductFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f.Category, f.Limit, f.Offset = c.Slug, p.Limit, p.Offset
+	total, err := h.Products.Count(r.Context(), f)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	products, err := h.Products.List(r.Context(), f)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(derefAll(products), p, total))
+}
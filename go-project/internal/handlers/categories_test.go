@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+// newCategoryTestRouter serves the category routes and product creation
+// and updates from the same repositories, seeded with the Books and Garden categories.
+func newCategoryTestRouter(products repository.ProductRepository) *Router {
+	categories := repository.NewInMemoryCategoryRepo(models.NewCategory("Garden"), models.NewCategory("Books"))
+	h := NewCategoryHandlers(categories, products)
+	ph := NewProductHandlers(products)
+	ph.Categories = categories
+	rt := NewRouter()
+	rt.Get("/api/categories", http.HandlerFunc(h.List))
+	rt.Get("/api/categories/{slug}/products", http.HandlerFunc(h.ListProducts))
+	rt.Post("/api/products", http.HandlerFunc(ph.Create))
+	rt.Post("/api/products/bulk", http.HandlerFunc(ph.Bulk))
+	rt.Put("/api/products/{id}", http.HandlerFunc(ph.Update))
+	rt.Patch("/api/products/{id}", http.HandlerFunc(ph.Patch))
+	return rt
+}
+
+func seedCategorized(t *testing.T, repo repository.ProductRepository, name, category string) {
+	t.Helper()
+	p := models.NewProduct(name, usd("1"))
+	p.Category = category
+	if err := repo.Create(context.Background(), p); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+}
+
+func TestCategoryHandlersList(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newCategoryTestRouter(repository.NewInMemoryProductRepo()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got []models.Category
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || got[0].Slug != "books" || got[1].Slug != "garden" {
+		t.Fatalf("categories = %+v, want books then garden", got)
+	}
+}
+
+func TestCategoryHandlersListProducts(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	seedCategorized(t, repo, "Rake", "garden")
+	seedCategorized(t, repo, "Novel", "books")
+	seedCategorized(t, repo, "Hose", "garden")
+	seedCategorized(t, repo, "Mystery", "")
+	rt := newCategoryTestRouter(repo)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantNames  []string
+	}{
+		{"category", "/api/categories/garden/products", http.StatusOK, []string{"Rake", "Hose"}},
+		{"with search", "/api/categories/garden/products?q=ho", http.StatusOK, []string{"Hose"}},
+		{"path wins over query", "/api/categories/books/products?category=garden", http.StatusOK, []string{"Novel"}},
+		{"empty category", "/api/categories/books/products?q=rake", http.StatusOK, []string{}},
+		{"unknown category", "/api/categories/toys/products", http.StatusNotFound, nil},
+		{"bad sort", "/api/categories/books/products?sort=size", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantNames == nil {
+				decodeErrorEnvelope(t, rec)
+				return
+			}
+			var page pageResponse[models.Product]
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			names := []string{}
+			for _, p := range page.Data {
+				names = append(names, p.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") || page.Pagination.Total != len(tt.wantNames) {
+				t.Fatalf("products = %v (total %d), want %v", names, page.Pagination.Total, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestProductHandlersCreateChecksCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"known category", `{"name":"Atlas","price":9,"category":"books"}`, http.StatusCreated},
+		{"no category", `{"name":"Atlas","price":9}`, http.StatusCreated},
+		{"unknown category", `{"name":"Atlas","price":9,"category":"toys"}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/products", strings.NewReader(tt.body))
+			newCategoryTestRouter(repository.NewInMemoryProductRepo()).ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code == http.StatusUnprocessableEntity {
+				if env := decodeErrorEnvelope(t, rec); len(env.Fields) != 1 || env.Fields[0].Field != "category" {
+					t.Fatalf("fields = %+v, want only category", env.Fields)
+				}
+			}
+		})
+	}
+}
+
+func TestProductHandlersBulkChecksCategory(t *testing.T) {
+	rec := httptest.NewRecorder()
+	body := `[{"name":"Atlas","price":9,"category":"books"},{"name":"","price":1,"category":"toys"}]`
+	newCategoryTestRouter(repository.NewInMemoryProductRepo()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/products/bulk", strings.NewReader(body)))
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusMultiStatus)
+	}
+	var resp bulkProductResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Created != 1 || resp.Failed != 1 {
+		t.Fatalf("created %d, failed %d; want 1 and 1", resp.Created, resp.Failed)
+	}
+	if fields := resp.Results[1].Error.Fields; len(fields) != 2 || fields[1].Field != "category" {
+		t.Fatalf("fields = %+v, want name then category", fields)
+	}
+}
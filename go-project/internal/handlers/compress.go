@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressMinSize is the smallest response body CompressMiddleware
+// compresses. Below it the encoding's framing costs more than it saves.
+const compressMinSize = 1024
+
+// incompressibleTypes are Content-Type prefixes that are already compressed.
+var incompressibleTypes = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/gzip", "application/x-gzip", "application/zip",
+	"application/zstd", "application/x-bzip2", "application/x-7z-compressed",
+}
+
+// identity is the Accept-Encoding name for an uncompressed body.
+const identity = "identity"
+
+// encoder is what the compress/gzip, compress/zlib and brotli writers have
+// in common.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// encodings are the content codings CompressMiddleware can apply, in the
+// order it prefers them when a client rates several equally. Each pools
+// its writers, which are costly to allocate.
+var encodings = []struct {
+	name string
+	pool *sync.Pool
+}{
+	{"br", &sync.Pool{New: func() any { return brotli.NewWriter(io.Discard) }}},
+	{"gzip", &sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}},
+	// HTTP's "deflate" is the zlib format, not a raw deflate stream
+	{"deflate", &sync.Pool{New: func() any { return zlib.NewWriter(io.Discard) }}},
+}
+
+// CompressMiddleware compresses response bodies with the encoding
+// NegotiateEncoding picks from the request's Accept-Encoding: Brotli, gzip
+// or deflate. Bodies are buffered until compressMinSize bytes have been
+// written, so small responses and already-compressed content types pass
+// through unchanged. Calling Flush forces the decision early, which keeps
+// streaming responses working.
+func CompressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		coding := NegotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if coding == identity {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressResponseWriter{ResponseWriter: w, status: http.StatusOK, coding: coding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// NegotiateEncoding returns the content coding to send a response in given
+// the request's Accept-Encoding header: whichever of "br", "gzip" and
+// "deflate" the client gives the highest q-value, by name or through "*".
+// A coding named explicitly takes its own q-value over the wildcard's, and
+// q=0 rules it out. Ties go to the order of that list. When the client
+// accepts none of them, sends no header, or rates "identity" at least as
+// high, the answer is "identity".
+func NegotiateEncoding(accept string) string {
+	named := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.ToLower(strings.TrimSpace(k)) != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && f >= 0 && f <= 1 {
+				q = f
+			}
+		}
+		if coding == "*" {
+			wildcard = q
+		} else {
+			named[coding] = q
+		}
+	}
+
+	// A client may rank an uncompressed body above the codings it lists
+	best, bestQ := identity, named[identity]
+	for _, e := range encodings {
+		q, ok := named[e.name]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = e.name, q
+		}
+	}
+	return best
+}
+
+// compressResponseWriter holds back the response until it knows whether to
+// compress it: once compressMinSize bytes are buffered, on Flush, or when
+// the handler returns.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	coding  string // as chosen by NegotiateEncoding
+	buf     []byte
+	decided bool
+	enc     encoder // nil when passing through
+}
+
+func (g *compressResponseWriter) WriteHeader(status int) {
+	if g.decided {
+		g.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if status >= 100 && status < 200 {
+		g.ResponseWriter.WriteHeader(status)
+		return
+	}
+	g.status = status
+}
+
+func (g *compressResponseWriter) Write(b []byte) (int, error) {
+	if !g.decided {
+		g.buf = append(g.buf, b...)
+		if len(g.buf) < compressMinSize {
+			return len(b), nil
+		}
+		if err := g.decide(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if g.enc != nil {
+		return g.enc.Write(b)
+	}
+	return g.ResponseWriter.Write(b)
+}
+
+// decide picks compression or pass-through based on what has been buffered,
+// sends the header and drains the buffer.
+func (g *compressResponseWriter) decide() error {
+	g.decided = true
+	h := g.Header()
+	if h.Get("Content-Type") == "" && len(g.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(g.buf))
+	}
+	if g.compressible() {
+		h.Set("Content-Encoding", g.coding)
+		h.Del("Content-Length")
+		g.enc = pool(g.coding).Get().(encoder)
+		g.enc.Reset(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+	buf := g.buf
+	g.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if g.enc != nil {
+		_, err = g.enc.Write(buf)
+	} else {
+		_, err = g.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (g *compressResponseWriter) compressible() bool {
+	h := g.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	if g.status < 200 || g.status == http.StatusNoContent || g.status == http.StatusNotModified {
+		return false
+	}
+	ct := strings.ToLower(h.Get("Content-Type"))
+	for _, prefix := range incompressibleTypes {
+		if strings.HasPrefix(ct, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// Flush implements http.Flusher, compressing from here on if the response
+// qualifies, so streamed responses reach the client as they are written.
+func (g *compressResponseWriter) Flush() {
+	if !g.decided {
+		if err := g.decide(); err != nil {
+			return
+		}
+	}
+	if g.enc != nil {
+		g.enc.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer supports it.
+func (g *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := g.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("handlers: %T does not support hijacking", g.ResponseWriter)
+	}
+	g.decided = true
+	return h.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (g *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// close sends anything still buffered uncompressed, since it is below
+// compressMinSize, or finishes the compressed stream.
+func (g *compressResponseWriter) close() {
+	if !g.decided {
+		g.decided = true
+		g.ResponseWriter.WriteHeader(g.status)
+		if len(g.buf) > 0 {
+			g.ResponseWriter.Write(g.buf)
+		}
+		return
+	}
+	if g.enc != nil {
+		g.enc.Close()
+		g.enc.Reset(io.Discard)
+		pool(g.coding).Put(g.enc)
+		g.enc = nil
+	}
+}
+
+// pool returns the writer pool for a coding NegotiateEncoding chose.
+func pool(coding string) *sync.Pool {
+	for _, e := range encodings {
+		if e.name == coding {
+			return e.pool
+		}
+	}
+	panic("handlers: no encoder for " + coding)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct{ accept, want string }{
+		{"gzip;q=0.5, br;q=0.9", "br"},
+		{"br;q=0.2, gzip;q=0.8, deflate;q=0.5", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, deflate", "gzip"},
+		{"deflate, gzip, br", "br"}, // equal q-values: server preference
+		{"GZIP;Q=0.7", "gzip"},
+		{"*", "br"},
+		{"*;q=0.5, br;q=0", "gzip"},
+		{"gzip;q=0.4, identity;q=0.9", "identity"},
+		{"", "identity"},
+		{"zstd, compress", "identity"},
+		{"gzip;q=0, br;q=0", "identity"},
+		{"*;q=0", "identity"},
+		{"gzip;q=abc", "gzip"}, // an unparsable q-value counts as 1
+	}
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.accept); got != tt.want {
+			t.Errorf("NegotiateEncoding(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// decoders undo each encoding CompressMiddleware may apply.
+var decoders = map[string]func(io.Reader) (io.Reader, error){
+	"gzip":    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+	"deflate": func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	"br":      func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"name":"widget"},`, 200)
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantEncoding   string
+	}{
+		{"gzip client, large JSON", "gzip, deflate", "application/json", large, "gzip"},
+		{"brotli by q-value", "br;q=1.0, gzip;q=0.5", "application/json", large, "br"},
+		{"deflate only", "deflate", "application/json", large, "deflate"},
+		{"wildcard", "*", "application/json", large, "br"},
+		{"no Accept-Encoding", "", "application/json", large, ""},
+		{"gzip refused", "gzip;q=0", "application/json", large, ""},
+		{"small payload", "gzip", "application/json", `{"ok":true}`, ""},
+		{"image", "br, gzip", "image/png", large, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Set("Content-Length", "12345")
+				w.WriteHeader(http.StatusTeapot)
+				// Write in pieces to exercise buffering across the threshold.
+				for _, chunk := range strings.SplitAfter(tt.body, ",") {
+					io.WriteString(w, chunk)
+				}
+			}))
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != http.StatusTeapot {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusTeapot)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			body := rec.Body.String()
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if tt.wantEncoding != "" {
+				if got := rec.Header().Get("Content-Length"); got != "" {
+					t.Errorf("Content-Length = %q, want it removed", got)
+				}
+				zr, err := decoders[tt.wantEncoding](rec.Body)
+				if err != nil {
+					t.Fatalf("%s reader: %v", tt.wantEncoding, err)
+				}
+				b, err := io.ReadAll(zr)
+				if err != nil {
+					t.Fatalf("decompress: %v", err)
+				}
+				body = string(b)
+			}
+			if body != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestCompressMiddlewareFlush(t *testing.T) {
+	flushed := make(chan struct{})
+	proceed := make(chan struct{})
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		close(flushed)
+		<-proceed
+		io.WriteString(w, "data: second\n\n")
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	// The default transport asks for gzip and decompresses transparently.
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	<-flushed
+	if !resp.Uncompressed {
+		t.Fatal("response was not gzip encoded")
+	}
+
+	// The first event must be readable before the handler finishes.
+	first := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		t.Fatalf("read first event: %v", err)
+	}
+	if string(first) != "data: first\n\n" {
+		t.Fatalf("first event = %q", first)
+	}
+	close(proceed)
+	rest, _ := io.ReadAll(resp.Body)
+	if string(rest) != "data: second\n\n" {
+		t.Fatalf("rest = %q", rest)
+	}
+}
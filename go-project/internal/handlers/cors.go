@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSOptions configures CORSMiddleware.
+type CORSOptions struct {
+	// AllowedOrigins lists origins allowed to make cross-origin requests.
+	// "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods is returned on preflight. Defaults to GET, POST, HEAD.
+	AllowedMethods []string
+	// AllowedHeaders is returned on preflight. Defaults to Content-Type.
+	AllowedHeaders []string
+	// AllowCredentials permits cookies and Authorization headers. When set,
+	// the matching origin is echoed back instead of "*", as browsers reject
+	// credentialed responses with a wildcard origin.
+	AllowCredentials bool
+}
+
+// CORSMiddleware adds Access-Control-* headers for allowed origins and
+// answers preflight OPTIONS requests with 204 without calling the next
+// handler. Requests from disallowed origins are passed through without CORS
+// headers, leaving the browser to block them.
+func CORSMiddleware(opts CORSOptions) Middleware {
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodPost, http.MethodHead}
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = []string{"Content-Type"}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+
+	wildcard := false
+	allowed := make(map[string]bool, len(opts.AllowedOrigins))
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" {
+			wildcard = true
+			continue
+		}
+		allowed[strings.ToLower(o)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if wildcard || allowed[strings.ToLower(origin)] {
+				if wildcard && !opts.AllowCredentials {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+				}
+				if opts.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+				if preflight {
+					h.Set("Access-Control-Allow-Methods", allowMethods)
+					h.Set("Access-Control-Allow-Headers", allowHeaders)
+				}
+			}
+
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveCORS(opts CORSOptions, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	Chain(http.HandlerFunc(DataHandler), CORSMiddleware(opts)).ServeHTTP(rec, r)
+	return rec
+}
+
+func TestCORSPreflight(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/api/data", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	rec := serveCORS(CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}, r)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+	}
+	for k, v := range want {
+		if got := rec.Header().Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("preflight reached the handler: body %q", rec.Body.String())
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	rec := serveCORS(CORSOptions{AllowedOrigins: []string{"https://app.example.com"}}, r)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want empty", got)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+	r.Header.Set("Origin", "https://anyone.example.com")
+	rec := serveCORS(CORSOptions{AllowedOrigins: []string{"*"}}, r)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
+
+func TestCORSCredentialedRequestNeverUsesWildcard(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	rec := serveCORS(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true}, r)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want echoed origin", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Fatalf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxBodyBytes caps the size of JSON request bodies read by handlers in this
+// package.
+var MaxBodyBytes int64 = 1 << 20
+
+// formMediaType is the Content-Type of HTML form posts and curl -d.
+const formMediaType = "application/x-www-form-urlencoded"
+
+// DecodeJSON decodes a single JSON value from r's body into dst for handlers
+// that accept JSON. It answers 415 when the request declares a Content-Type
+// other than application/json (a missing header is treated as JSON), 413
+// when the body is over MaxBodyBytes, and 400 naming the problem, such as an
+// unknown or mistyped field or trailing data, otherwise. On failure it
+// returns false and dst must be ignored.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if mt := mediaType(r); mt != "" && mt != "application/json" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+	return decodeJSONBody(w, http.MaxBytesReader(w, r.Body, MaxBodyBytes), dst)
+}
+
+// DecodeRequest is DecodeJSON for handlers that also take form-encoded
+// bodies, such as HTML form posts, into dst, a pointer to a struct. With a
+// Content-Type of application/x-www-form-urlencoded each form field is
+// decoded into the struct field whose json tag names it, exactly as the
+// same value in a JSON body would be, so the two encodings fail and
+// validate alike; a number or boolean that does not parse is reported as a
+// mistyped field. Repeating a field is a 400. Any other Content-Type but
+// JSON answers 415.
+func DecodeRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	switch mediaType(r) {
+	case "", "application/json":
+		return DecodeJSON(w, r, dst)
+	case formMediaType:
+		if t := reflect.TypeOf(dst); t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+			return decodeForm(w, r, t.Elem(), dst)
+		}
+	}
+	writeJSONError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json or "+formMediaType)
+	return false
+}
+
+// mediaType returns the media type of r's Content-Type, "" when there is
+// none, or the header itself when it does not parse so it matches nothing.
+func mediaType(r *http.Request) string {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ct
+	}
+	return mt
+}
+
+// decodeJSONBody decodes the single JSON value in body into dst, answering
+// as DecodeJSON does when it cannot.
+func decodeJSONBody(w http.ResponseWriter, body io.Reader, dst any) bool {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(dst)
+	if err == nil {
+		if dec.Decode(&struct{}{}) != io.EOF {
+			err = errors.New("body must contain a single JSON object")
+		}
+	}
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.As(err, new(*http.MaxBytesError)) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		WriteError(w, status, CodeInvalidRequest, jsonErrorMessage(err))
+		return false
+	}
+	return true
+}
+
+// decodeForm decodes r's form-encoded body into dst, a pointer to a struct
+// of type t, by rewriting it as the equivalent JSON object.
+func decodeForm(w http.ResponseWriter, r *http.Request, t reflect.Type, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		if errors.As(err, new(*http.MaxBytesError)) {
+			WriteError(w, http.StatusRequestEntityTooLarge, CodeInvalidRequest, jsonErrorMessage(err))
+			return false
+		}
+		WriteError(w, http.StatusBadRequest, CodeInvalidRequest, "malformed form body: "+err.Error())
+		return false
+	}
+	obj, err := formObject(r.PostForm, t)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return false
+	}
+	body, err := json.Marshal(obj)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return false
+	}
+	return decodeJSONBody(w, bytes.NewReader(body), dst)
+}
+
+// formObject returns the JSON object form describes for a struct of type
+// t. Values for number and boolean fields that parse as such become JSON
+// numbers and booleans; everything else stays a string, which leaves
+// types with their own UnmarshalJSON, such as models.Money, to parse it.
+// Fields t does not have are kept so decoding rejects them by name.
+func formObject(form url.Values, t reflect.Type) (map[string]any, error) {
+	fields := jsonFields(t)
+	obj := make(map[string]any, len(form))
+	for name, values := range form {
+		if len(values) > 1 {
+			return nil, fmt.Errorf("field %q must be given once", name)
+		}
+		v := values[0]
+		obj[name] = v
+		ft, ok := fields[name]
+		if !ok {
+			continue
+		}
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch jsonTypeName(ft) {
+		case "a boolean":
+			if b, err := strconv.ParseBool(v); err == nil {
+				obj[name] = b
+			}
+		case "an integer", "a number":
+			var n json.Number
+			if json.Unmarshal([]byte(v), &n) == nil {
+				obj[name] = n
+			}
+		}
+	}
+	return obj, nil
+}
+
+// jsonFields maps the JSON names of t's exported fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// jsonErrorMessage turns a decoding error into a message for the client:
+// where a syntax error is, which field has the wrong type and what it
+// should be, or that the body is empty or cut short. Types are described in
+// JSON terms rather than Go ones.
+func jsonErrorMessage(err error) string {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		maxErr    *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &maxErr):
+		return fmt.Sprintf("request body must not be larger than %d bytes", maxErr.Limit)
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("malformed JSON at offset %d: %s", syntaxErr.Offset, strings.TrimPrefix(syntaxErr.Error(), "json: "))
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "malformed JSON: body ends before the value is complete"
+	case errors.Is(err, io.EOF):
+		return "request body must not be empty"
+	case errors.As(err, &typeErr):
+		want, got := jsonTypeName(typeErr.Type), typeErr.Value
+		if typeErr.Field != "" {
+			return fmt.Sprintf("field %q must be %s, got %s", typeErr.Field, want, got)
+		}
+		return fmt.Sprintf("body must be %s, got %s", want, got)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for this case.
+		return "unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	default:
+		return err.Error()
+	}
+}
+
+// jsonTypeName describes the JSON value that decodes into t.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "a different type"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a different type"
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPostExampleHandlerRejectsBadBodies(t *testing.T) {
+	defer func(n int64) { MaxBodyBytes = n }(MaxBodyBytes)
+	MaxBodyBytes = 64
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"unknown field", "", `{"id":1,"naem":"typo"}`, http.StatusBadRequest, `unknown field "naem"`},
+		{"oversized body", "application/json", `{"id":1,"name":"` + strings.Repeat("x", 100) + `"}`,
+			http.StatusRequestEntityTooLarge, "must not be larger than 64 bytes"},
+		{"truncated JSON", "", `{"id":1,"name":`, http.StatusBadRequest, "malformed JSON: body ends before the value is complete"},
+		{"syntax error", "", `{"id":1 "name":"x"}`, http.StatusBadRequest, "malformed JSON at offset 9: invalid character"},
+		{"wrong type", "", `{"id":"one"}`, http.StatusBadRequest, `field "id" must be an integer, got string`},
+		{"trailing data", "", `{"id":1}{"id":2}`, http.StatusBadRequest, "single JSON object"},
+		{"empty body", "", ``, http.StatusBadRequest, "must not be empty"},
+		{"text body", "text/plain", `{"id":1}`, http.StatusUnsupportedMediaType, "must be application/json"},
+		{"form body", "application/x-www-form-urlencoded", `id=1`, http.StatusUnsupportedMediaType, "must be application/json"},
+		{"bad content type", "application/", `{"id":1}`, http.StatusUnsupportedMediaType, "must be application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			PostExampleHandler(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			got := decodeErrorEnvelope(t, rec)
+			if !strings.Contains(got.Message, tt.wantMessage) {
+				t.Fatalf("message = %q, want it to contain %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestPostExampleHandlerAcceptsKnownFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	PostExampleHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader(`{"id":7,"name":"ok"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"name":"ok"`) {
+		t.Fatalf("body = %s", rec.Body.String())
+	}
+}
+
+func TestPostExampleHandlerAcceptsJSONContentTypes(t *testing.T) {
+	for _, ct := range []string{"application/json", "application/json; charset=utf-8", "Application/JSON"} {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"id":7,"name":"ok"}`))
+		req.Header.Set("Content-Type", ct)
+		rec := httptest.NewRecorder()
+		PostExampleHandler(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Content-Type %q: status = %d, want 201", ct, rec.Code)
+		}
+	}
+}
+
+func TestDecodeJSONDescribesTypeErrors(t *testing.T) {
+	type item struct {
+		Price float64 `json:"price"`
+	}
+	type payload struct {
+		Name   string         `json:"name"`
+		Count  *int           `json:"count"`
+		Active bool           `json:"active"`
+		Tags   []string       `json:"tags"`
+		Item   item           `json:"item"`
+		Meta   map[string]any `json:"meta"`
+	}
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"name":42}`, `field "name" must be a string, got number`},
+		{`{"count":1.5}`, `field "count" must be an integer, got number 1.5`},
+		{`{"active":"yes"}`, `field "active" must be a boolean, got string`},
+		{`{"tags":"a,b"}`, `field "tags" must be an array, got string`},
+		{`{"item":{"price":"1"}}`, `field "item.price" must be a number, got string`},
+		{`{"meta":[]}`, `field "meta" must be an object, got array`},
+		{`[]`, `body must be an object, got array`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			var dst payload
+			if DecodeJSON(rec, httptest.NewRequest("POST", "/", strings.NewReader(tt.body)), &dst) {
+				t.Fatal("DecodeJSON succeeded, want a type error")
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			if got := decodeErrorEnvelope(t, rec); got.Message != tt.want {
+				t.Fatalf("message = %q, want %q", got.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeRequestReadsForms(t *testing.T) {
+	type payload struct {
+		Name   string  `json:"name"`
+		Count  *int    `json:"count"`
+		Active bool    `json:"active"`
+		Ratio  float64 `json:"ratio,omitempty"`
+	}
+	decode := func(contentType, body string) (payload, *httptest.ResponseRecorder, bool) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		var dst payload
+		ok := DecodeRequest(rec, req, &dst)
+		return dst, rec, ok
+	}
+
+	got, rec, ok := decode("application/x-www-form-urlencoded; charset=utf-8", "name=Ada+L&count=3&active=true&ratio=0.5")
+	if !ok {
+		t.Fatalf("DecodeRequest failed: %s", rec.Body.String())
+	}
+	if got.Name != "Ada L" || got.Count == nil || *got.Count != 3 || !got.Active || got.Ratio != 0.5 {
+		t.Fatalf("decoded %+v", got)
+	}
+	if _, rec, ok := decode("application/json", `{"name":"Ada"}`); !ok {
+		t.Fatalf("JSON body: %s", rec.Body.String())
+	}
+
+	tests := []struct {
+		name, contentType, body string
+		wantStatus              int
+		wantMessage             string
+	}{
+		{"mistyped number", "application/x-www-form-urlencoded", "count=three", http.StatusBadRequest, `field "count" must be an integer, got string`},
+		{"fractional integer", "application/x-www-form-urlencoded", "count=1.5", http.StatusBadRequest, `field "count" must be an integer, got number 1.5`},
+		{"mistyped boolean", "application/x-www-form-urlencoded", "active=maybe", http.StatusBadRequest, `field "active" must be a boolean, got string`},
+		{"unknown field", "application/x-www-form-urlencoded", "naem=typo", http.StatusBadRequest, `unknown field "naem"`},
+		{"repeated field", "application/x-www-form-urlencoded", "name=a&name=b", http.StatusBadRequest, `field "name" must be given once`},
+		{"malformed form", "application/x-www-form-urlencoded", "name=%zz", http.StatusBadRequest, "malformed form body"},
+		{"text body", "text/plain", "name=Ada", http.StatusUnsupportedMediaType, "must be application/json or application/x-www-form-urlencoded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rec, ok := decode(tt.contentType, tt.body)
+			if ok || rec.Code != tt.wantStatus {
+				t.Fatalf("ok = %v, status = %d, want %d (body %s)", ok, rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := decodeErrorEnvelope(t, rec); !strings.Contains(got.Message, tt.wantMessage) {
+				t.Fatalf("message = %q, want it to contain %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-project/internal/models"
+)
+
+// Machine-readable error codes used in the error envelope. Clients should
+// branch on these rather than on messages, which may change.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeUnauthorized   = "unauthorized"
+	CodeNotFound       = "not_found"
+	CodeConflict       = "conflict"
+	CodeInternal       = "internal"
+)
+
+// errorBody is the object under "error" in every error response. Fields is
+// only set for validation failures.
+type errorBody struct {
+	Code    string              `json:"code"`
+	Message string              `json:"message"`
+	Fields  []models.FieldError `json:"fields,omitempty"`
+}
+
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+// WriteError writes the standard error envelope
+// {"error":{"code":"...","message":"..."}} as JSON with the given status.
+func WriteError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorEnvelope{Error: errorBody{Code: code, Message: message}})
+}
+
+// codeForStatus picks the error code for status when a handler has nothing
+// more specific to say.
+func codeForStatus(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return CodeUnauthorized
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusConflict || status == http.StatusPreconditionFailed:
+		return CodeConflict
+	case status >= 500:
+		return CodeInternal
+	default:
+		return CodeInvalidRequest
+	}
+}
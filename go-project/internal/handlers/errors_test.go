@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-project/internal/repository"
+)
+
+// decodeErrorEnvelope checks that rec holds exactly the standard error
+// envelope and returns it.
+func decodeErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder) errorBody {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	var raw map[string]map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("body %s is not an error envelope: %v", rec.Body.String(), err)
+	}
+	if len(raw) != 1 || raw["error"] == nil {
+		t.Fatalf("body %s: want a single top-level \"error\" object", rec.Body.String())
+	}
+	var env errorEnvelope
+	json.Unmarshal(rec.Body.Bytes(), &env)
+	if env.Error.Code == "" || env.Error.Message == "" {
+		t.Fatalf("body %s: code and message must both be set", rec.Body.String())
+	}
+	return env.Error
+}
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, http.StatusUnauthorized, CodeUnauthorized, "no entry")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+	if got := decodeErrorEnvelope(t, rec); got.Code != CodeUnauthorized || got.Message != "no entry" {
+		t.Fatalf("error = %+v", got)
+	}
+	if strings.Contains(rec.Body.String(), "fields") {
+		t.Fatalf("body %s has a fields key outside validation errors", rec.Body.String())
+	}
+}
+
+func TestErrorEnvelopeFromHandlers(t *testing.T) {
+	t.Run("400 from PostExampleHandler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		PostExampleHandler(rec, httptest.NewRequest("POST", "/", strings.NewReader("{not json")))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", rec.Code)
+		}
+		if got := decodeErrorEnvelope(t, rec); got.Code != CodeInvalidRequest {
+			t.Fatalf("code = %q, want %q", got.Code, CodeInvalidRequest)
+		}
+	})
+	t.Run("404 for an unknown user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newUserTestRouter(repository.NewInMemoryUserRepo()).ServeHTTP(rec, httptest.NewRequest("GET", "/api/users/99", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404", rec.Code)
+		}
+		if got := decodeErrorEnvelope(t, rec); got.Code != CodeNotFound || got.Message != "user not found" {
+			t.Fatalf("error = %+v, want not_found / user not found", got)
+		}
+	})
+	t.Run("422 keeps the field list", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newUserTestRouter(repository.NewInMemoryUserRepo()).ServeHTTP(rec, httptest.NewRequest("POST", "/api/users", strings.NewReader(`{}`)))
+		got := decodeErrorEnvelope(t, rec)
+		if got.Code != CodeInvalidRequest || len(got.Fields) != 2 {
+			t.Fatalf("error = %+v, want invalid_request with two fields", got)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// weakETag returns a weak entity tag for v, derived from a hash of its JSON
+// encoding. It is weak because the same resource may be sent as JSON or
+// XML; both representations share the tag.
+func weakETag(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match or If-Match header value
+// lists etag or is "*". Tags are compared ignoring the W/ prefix, since
+// every tag this package issues is weak.
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// respondCached sets the ETag of v and answers 304 Not Modified without a
+// body when the request's If-None-Match already names it. Otherwise it
+// hands off to Respond.
+func respondCached(w http.ResponseWriter, r *http.Request, v any) {
+	etag := weakETag(v)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	Respond(w, r, http.StatusOK, v)
+}
+
+// checkIfMatch answers 412 and returns false when the request has an
+// If-Match header that does not name current's ETag, meaning the client is
+// about to overwrite a version it has not seen. Requests without If-Match
+// are let through.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, current any) bool {
+	im := r.Header.Get("If-Match")
+	if im == "" || etagMatches(im, weakETag(current)) {
+		return true
+	}
+	writeJSONError(w, http.StatusPreconditionFailed, "resource has changed; fetch it again before updating")
+	return false
+}
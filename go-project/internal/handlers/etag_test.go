@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-project/internal/repository"
+)
+
+func TestETagMatches(t *testing.T) {
+	const etag = `W/"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`*`, true},
+		{`W/"xyz"`, false},
+		{`abc`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// conditional sends a request with the given conditional header to rt.
+func conditional(rt http.Handler, method, path, header, etag, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if etag != "" {
+		req.Header.Set(header, etag)
+	}
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetUserNotModified(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	u := seedUser(t, repo, "Ada", "ada@example.com")
+	rt := newUserTestRouter(repo)
+	path := "/api/users/" + strconv.Itoa(u.ID)
+
+	first := conditional(rt, http.MethodGet, path, "", "", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("status = %d, ETag = %q; want 200 with a weak ETag", first.Code, etag)
+	}
+
+	rec := conditional(rt, http.MethodGet, path, "If-None-Match", etag, "")
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match with the current ETag: status = %d, want 304", rec.Code)
+	}
+	if rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+		t.Errorf("304 has body %q and ETag %q; want no body and %q", rec.Body, rec.Header().Get("ETag"), etag)
+	}
+
+	if rec := conditional(rt, http.MethodPatch, path, "", "", `{"name":"Ada Lovelace"}`); rec.Code != http.StatusOK {
+		t.Fatalf("PATCH: status = %d", rec.Code)
+	}
+	rec = conditional(rt, http.MethodGet, path, "If-None-Match", etag, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("after a change: status = %d, ETag = %q; want 200 with a new ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestGetProductNotModified(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	p := seedProduct(t, repo, "Widget", "5")
+	rt := newProductTestRouter(repo)
+	path := "/api/products/" + strconv.Itoa(p.ID)
+
+	etag := conditional(rt, http.MethodGet, path, "", "", "").Header().Get("ETag")
+	if rec := conditional(rt, http.MethodGet, path, "If-None-Match", etag, ""); rec.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", rec.Code)
+	}
+}
+
+func TestUpdateUserIfMatch(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	u := seedUser(t, repo, "Ada", "ada@example.com")
+	rt := newUserTestRouter(repo)
+	path := "/api/users/" + strconv.Itoa(u.ID)
+	stale := conditional(rt, http.MethodGet, path, "", "", "").Header().Get("ETag")
+
+	// Someone else changes the user after the client read it.
+	changed := conditional(rt, http.MethodPut, path, "If-Match", stale, `{"email":"ada@other.example.com"}`)
+	if changed.Code != http.StatusOK {
+		t.Fatalf("update with the current ETag: status = %d (body %s)", changed.Code, changed.Body)
+	}
+	current := changed.Header().Get("ETag")
+	if current == "" || current == stale {
+		t.Fatalf("ETag after update = %q, want a new one", current)
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch} {
+		rec := conditional(rt, method, path, "If-Match", stale, `{"email":"ada@stale.example.com"}`)
+		if rec.Code != http.StatusPreconditionFailed {
+			t.Fatalf("%s with a stale ETag: status = %d, want 412 (body %s)", method, rec.Code, rec.Body)
+		}
+		if got := decodeErrorEnvelope(t, rec); got.Code != CodeConflict {
+			t.Errorf("%s 412 code = %q, want %q", method, got.Code, CodeConflict)
+		}
+	}
+	if got, _ := repo.Get(context.Background(), u.ID); got.Email != "ada@other.example.com" {
+		t.Fatalf("email after stale updates = %q, want it unchanged", got.Email)
+	}
+
+	if rec := conditional(rt, http.MethodPut, path, "", "", `{"email":"ada@new.example.com"}`); rec.Code != http.StatusOK {
+		t.Fatalf("update without If-Match: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestUpdateProductIfMatch(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	p := seedProduct(t, repo, "Widget", "5")
+	rt := newProductTestRouter(repo)
+	path := "/api/products/" + strconv.Itoa(p.ID)
+	etag := conditional(rt, http.MethodGet, path, "", "", "").Header().Get("ETag")
+
+	if rec := conditional(rt, http.MethodPut, path, "If-Match", etag, `{"name":"Widget","price":6}`); rec.Code != http.StatusOK {
+		t.Fatalf("update with the current ETag: status = %d (body %s)", rec.Code, rec.Body)
+	}
+	if rec := conditional(rt, http.MethodPut, path, "If-Match", etag, `{"name":"Widget","price":7}`); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("update with a stale ETag: status = %d, want 412", rec.Code)
+	}
+	if rec := conditional(rt, http.MethodPatch, path, "If-Match", etag, `{"price":8}`); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("patch with a stale ETag: status = %d, want 412", rec.Code)
+	}
+	if got, _ := repo.Get(context.Background(), p.ID); got.Price != usd("6") {
+		t.Errorf("price = %s, want 6.00", got.Price)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-project/internal/models"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before further events are dropped for it.
+const subscriberBuffer = 16
+
+// sseHeartbeat is how often an idle event stream gets a comment line, so
+// proxies do not close it.
+var sseHeartbeat = 15 * time.Second
+
+// PriceBroker fans price changes out to event-stream subscribers. It is
+// safe for concurrent use. Publish never blocks: a subscriber whose buffer
+// is full misses the event.
+type PriceBroker struct {
+	mu   sync.Mutex
+	subs map[chan models.PriceChange]struct{}
+}
+
+// NewPriceBroker returns a broker with no subscribers.
+func NewPriceBroker() *PriceBroker {
+	return &PriceBroker{subs: make(map[chan models.PriceChange]struct{})}
+}
+
+// Subscribe returns a channel of future price changes and a function that
+// unsubscribes and closes it. The function may be called more than once.
+func (b *PriceBroker) Subscribe() (<-chan models.PriceChange, func()) {
+	ch := make(chan models.PriceChange, subscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends c to every current subscriber. Call it from the observer
+// registered with models.OnPriceChange to stream every saved price change.
+func (b *PriceBroker) Publish(c models.PriceChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- c:
+		default:
+		}
+	}
+}
+
+func (b *PriceBroker) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// ProductEventsHandler handles GET /api/products/events. It streams each
+// price change published to b as a server-sent "data:" frame holding the
+// models.PriceChange JSON, until the client disconnects. Do not put it
+// behind TimeoutMiddleware, which buffers the response.
+func ProductEventsHandler(b *PriceBroker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		// The stream outlives the server's WriteTimeout by design, so lift
+		// it for this response; heartbeats notice a client that goes away.
+		rc.SetWriteDeadline(time.Time{})
+		events, unsubscribe := b.Subscribe()
+		defer unsubscribe()
+
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		// The opening comment tells the client the subscription is live.
+		fmt.Fprint(w, ": subscribed\n\n")
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			case c := <-events:
+				data, err := json.Marshal(c)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "data: %s\n\n", data)
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/internal/models"
+)
+
+func TestProductEventsHandlerStreamsPriceChanges(t *testing.T) {
+	b := NewPriceBroker()
+	models.OnPriceChange(func(_ context.Context, c models.PriceChange) { b.Publish(c) })
+	t.Cleanup(func() { models.OnPriceChange(nil) })
+
+	srv := httptest.NewServer(ProductEventsHandler(b))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	if !lines.Scan() || lines.Text() != ": subscribed" {
+		t.Fatalf("first line = %q, want the subscription comment", lines.Text())
+	}
+
+	p := models.NewProduct("Widget", usd("5"))
+	p.ID = 3
+	c, _ := p.UpdatePrice(usd("7.50"))
+	models.NotifyPriceChange(context.Background(), c)
+
+	for lines.Scan() {
+		data, ok := strings.CutPrefix(lines.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var c models.PriceChange
+		if err := json.Unmarshal([]byte(data), &c); err != nil {
+			t.Fatalf("decode %q: %v", data, err)
+		}
+		if c.ProductID != 3 || c.OldPrice != usd("5") || c.NewPrice != usd("7.50") {
+			t.Fatalf("event = %+v", c)
+		}
+		return
+	}
+	t.Fatalf("stream ended without an event: %v", lines.Err())
+}
+
+func TestProductEventsHandlerUnsubscribesOnDisconnect(t *testing.T) {
+	b := NewPriceBroker()
+	srv := httptest.NewServer(ProductEventsHandler(b))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	bufio.NewReader(resp.Body).ReadString('\n')
+	if n := b.subscribers(); n != 1 {
+		t.Fatalf("subscribers while connected = %d, want 1", n)
+	}
+	resp.Body.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for b.subscribers() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("handler did not unsubscribe after the client went away")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProductEventsHandlerOutlivesWriteTimeout(t *testing.T) {
+	defer func(d time.Duration) { sseHeartbeat = d }(sseHeartbeat)
+	sseHeartbeat = 20 * time.Millisecond
+
+	// Through wrapping middleware, the deadline must still reach the
+	// connection.
+	srv := httptest.NewUnstartedServer(Chain(ProductEventsHandler(NewPriceBroker()), LoggingMiddleware(nil), CompressMiddleware))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	lines := bufio.NewScanner(resp.Body)
+	end := time.Now().Add(4 * srv.Config.WriteTimeout)
+	for time.Now().Before(end) {
+		if !lines.Scan() {
+			t.Fatalf("stream closed after the write timeout: %v", lines.Err())
+		}
+	}
+}
+
+func TestPriceBrokerDropsForSlowSubscribers(t *testing.T) {
+	b := NewPriceBroker()
+	events, unsubscribe := b.Subscribe()
+	for i := 0; i < subscriberBuffer+5; i++ {
+		b.Publish(models.PriceChange{ProductID: i})
+	}
+	if len(events) != subscriberBuffer {
+		t.Fatalf("buffered %d events, want %d", len(events), subscriberBuffer)
+	}
+	unsubscribe()
+	unsubscribe()
+	if b.subscribers() != 0 {
+		t.Fatal("unsubscribe did not remove the subscriber")
+	}
+}
@@ -1,31 +1,52 @@
 package handlers
 
 import (
-    "encoding/json"
-    "net/http"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
 )
 
 // Example data structure
 type Example struct {
-    ID   int    `json:"id"`
-    Name string `json:"name"`
+	XMLName xml.Name `json:"-" xml:"example"`
+	ID      int      `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name"`
+}
+
+// HomeHandler handles requests to the root path
+func HomeHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "Welcome to the Go Project API"})
+}
+
+// DataHandler handles GET requests for the example API data; other methods
+// get 405
+func DataHandler(w http.ResponseWriter, r *http.Request) {
+	GetExampleHandler(w, r)
 }
 
 // GetExampleHandler handles GET requests for example data
 func GetExampleHandler(w http.ResponseWriter, r *http.Request) {
-    example := Example{ID: 1, Name: "Example Name"}
-    w.Header().Set("Content-Type", "application/json")
-    json.NewEncoder(w).Encode(example)
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	example := Example{ID: 1, Name: "Example Name"}
+	Respond(w, r, http.StatusOK, example)
 }
 
 // PostExampleHandler handles POST requests to create example data
 func PostExampleHandler(w http.ResponseWriter, r *http.Request) {
-    var example Example
-    if err := json.NewDecoder(r.Body).Decode(&example); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
-    }
-    // Here you would typically save the example to a database
-    w.WriteHeader(http.StatusCreated)
-    json.NewEncoder(w).Encode(example)
-}
\ No newline at end of file
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var example Example
+	if !DecodeJSON(w, r, &example) {
+		return
+	}
+	// Here you would typically save the example to a database
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(example)
+}
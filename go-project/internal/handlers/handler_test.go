@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExampleHandlersRejectOtherMethods(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		method    string
+		wantAllow string
+	}{
+		{"POST to DataHandler", DataHandler, http.MethodPost, "GET, HEAD"},
+		{"DELETE to HomeHandler", HomeHandler, http.MethodDelete, "GET, HEAD"},
+		{"PUT to GetExampleHandler", GetExampleHandler, http.MethodPut, "GET, HEAD"},
+		{"GET to PostExampleHandler", PostExampleHandler, http.MethodGet, "POST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(tt.method, "/", nil))
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("status = %d, want 405", rec.Code)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Fatalf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if got := decodeErrorEnvelope(t, rec); got.Code != CodeInvalidRequest {
+				t.Fatalf("code = %q, want %q", got.Code, CodeInvalidRequest)
+			}
+		})
+	}
+}
+
+func TestDataHandlerAllowsHead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DataHandler(rec, httptest.NewRequest(http.MethodHead, "/api/data", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestRouteTableRejectsPostToGetOnlyRoute(t *testing.T) {
+	rt := NewRouter()
+	Register(rt, Routes(testServices()))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/data", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "GET, HEAD" {
+		t.Fatalf("Allow = %q, want %q", got, "GET, HEAD")
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go-project/internal/repository"
+)
+
+// readinessTimeout bounds how long /readyz waits for all checks.
+const readinessTimeout = 2 * time.Second
+
+var readiness = struct {
+	sync.RWMutex
+	checks map[string]func(ctx context.Context) error
+}{checks: make(map[string]func(ctx context.Context) error)}
+
+// RegisterReadinessCheck adds a named check consulted by ReadyHandler.
+// Registering a name again replaces the earlier check.
+func RegisterReadinessCheck(name string, fn func(ctx context.Context) error) {
+	readiness.Lock()
+	defer readiness.Unlock()
+	readiness.checks[name] = fn
+}
+
+// RepositoryCheck is the name of the readiness check added by
+// RegisterRepositoryCheck.
+const RepositoryCheck = "repository"
+
+// RegisterRepositoryCheck makes ReadyHandler ping repo, so the service is
+// reported unready while its store is unreachable.
+func RegisterRepositoryCheck(repo repository.Pinger) {
+	RegisterReadinessCheck(RepositoryCheck, repo.Ping)
+}
+
+// HealthHandler is the liveness probe: it always reports ok while the
+// process can serve HTTP.
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// ReadyHandler is the readiness probe: it returns 200 when every registered
+// check passes and 503 listing the failing check names, and under "errors"
+// what each of them reported, otherwise.
+func ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	readiness.RLock()
+	checks := make(map[string]func(ctx context.Context) error, len(readiness.checks))
+	names := make([]string, 0, len(readiness.checks))
+	for name, fn := range readiness.checks {
+		checks[name] = fn
+		names = append(names, name)
+	}
+	readiness.RUnlock()
+	sort.Strings(names)
+
+	failed := []string{}
+	errs := map[string]string{}
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			failed = append(failed, name)
+			errs[name] = err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(failed) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]any{"status": "unavailable", "failed": failed, "errors": errs})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-project/internal/repository"
+)
+
+func resetReadinessChecks() {
+	readiness.Lock()
+	defer readiness.Unlock()
+	readiness.checks = make(map[string]func(ctx context.Context) error)
+}
+
+func TestHealthHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HealthHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "{\"status\":\"ok\"}\n" {
+		t.Fatalf("body = %q", rec.Body.String())
+	}
+}
+
+func TestReadyHandlerFailingCheck(t *testing.T) {
+	defer resetReadinessChecks()
+	RegisterReadinessCheck("cache", func(ctx context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status with passing checks = %d, want 200", rec.Code)
+	}
+
+	RegisterReadinessCheck("database", func(ctx context.Context) error { return errors.New("connection refused") })
+	rec = httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status with failing check = %d, want 503", rec.Code)
+	}
+	var body struct {
+		Status string   `json:"status"`
+		Failed []string `json:"failed"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Failed) != 1 || body.Failed[0] != "database" {
+		t.Fatalf("failed = %v, want [database]", body.Failed)
+	}
+}
+
+// unreachableUserRepo is a UserRepository whose store cannot be reached.
+type unreachableUserRepo struct {
+	repository.UserRepository
+}
+
+func (unreachableUserRepo) Ping(ctx context.Context) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func TestReadyHandlerRepositoryCheck(t *testing.T) {
+	defer resetReadinessChecks()
+	RegisterRepositoryCheck(repository.NewInMemoryUserRepo())
+	rec := httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status with the in-memory repo = %d, want 200", rec.Code)
+	}
+
+	RegisterRepositoryCheck(unreachableUserRepo{})
+	rec = httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status with an unreachable repo = %d, want 503", rec.Code)
+	}
+	var body struct {
+		Failed []string          `json:"failed"`
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Failed) != 1 || body.Failed[0] != "repository" {
+		t.Fatalf("failed = %v, want [repository]", body.Failed)
+	}
+	if body.Errors["repository"] != "dial tcp 10.0.0.5:5432: connection refused" {
+		t.Fatalf("errors = %v, want the ping error under repository", body.Errors)
+	}
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-project/internal/auth"
+)
+
+// IdempotencyKeyHeader lets clients retry a POST safely: requests to the
+// same route with the same key get the first response replayed.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on replayed responses.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyTTL is how long a key's response is kept for replay.
+const idempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKeys bounds how many keys a store holds. Past it, the
+// stored response closest to expiry makes room for a new key.
+const maxIdempotencyKeys = 10000
+
+// IdempotencyMiddleware honors the Idempotency-Key header. The first request
+// with a key runs normally and its response is kept for ttl; a retry with
+// the same key and body gets that response again, marked with
+// Idempotent-Replayed, without running the handler. Reusing a key with a
+// different body, or while the first request is still running, answers
+// 409. Keys are scoped to the route pattern and the caller: the user from
+// auth.UserIDFromContext, so authentication must run before it, or else
+// the client IP. Requests without the header and 5xx responses are not
+// stored, so failed requests can be retried. Expired keys are evicted in
+// the background until ctx is done, and at most maxIdempotencyKeys are
+// kept.
+func IdempotencyMiddleware(ctx context.Context, ttl time.Duration) Middleware {
+	s := newIdempotencyStore(ttl)
+	go s.janitor(ctx)
+	return s.middleware
+}
+
+type idempotencyStore struct {
+	ttl time.Duration
+	max int
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// idempotencyEntry is a key's request fingerprint and, once done, the
+// response to replay.
+type idempotencyEntry struct {
+	bodyHash [sha256.Size]byte
+	done     bool
+	expires  time.Time
+
+	status int
+	header http.Header
+	body   []byte
+}
+
+func newIdempotencyStore(ttl time.Duration) *idempotencyStore {
+	return &idempotencyStore{ttl: ttl, max: maxIdempotencyKeys, now: time.Now, entries: make(map[string]*idempotencyEntry)}
+}
+
+// begin looks up key, claiming it for the caller when it is unused or
+// expired. It returns the existing entry otherwise.
+func (s *idempotencyStore) begin(key string, hash [sha256.Size]byte) (existing *idempotencyEntry, claimed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if ok && (!e.done || s.now().Before(e.expires)) {
+		copied := *e
+		return &copied, false
+	}
+	if !ok && len(s.entries) >= s.max {
+		s.makeRoom()
+	}
+	s.entries[key] = &idempotencyEntry{bodyHash: hash}
+	return nil, true
+}
+
+// makeRoom drops the stored response closest to expiry, leaving requests
+// still in progress alone. s.mu must be held.
+func (s *idempotencyStore) makeRoom() {
+	var oldest string
+	for key, e := range s.entries {
+		if e.done && (oldest == "" || e.expires.Before(s.entries[oldest].expires)) {
+			oldest = key
+		}
+	}
+	if oldest != "" {
+		delete(s.entries, oldest)
+	}
+}
+
+// finish stores the response for a claimed key, or releases the key when
+// the response should not be replayed.
+func (s *idempotencyStore) finish(key string, status int, header http.Header, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	if status >= 500 {
+		delete(s.entries, key)
+		return
+	}
+	e.done, e.expires = true, s.now().Add(s.ttl)
+	e.status, e.header, e.body = status, header, body
+}
+
+// evictExpired drops stored responses past their TTL.
+func (s *idempotencyStore) evictExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entries {
+		if e.done && !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// janitor evicts expired entries every quarter of the TTL until ctx is
+// done.
+func (s *idempotencyStore) janitor(ctx context.Context) {
+	t := time.NewTicker(s.ttl / 4)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			s.evictExpired(now)
+		}
+	}
+}
+
+func (s *idempotencyStore) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !validRequestID(key) {
+			writeJSONError(w, http.StatusBadRequest, "invalid "+IdempotencyKeyHeader+" header")
+			return
+		}
+
+		// Fingerprint the body, leaving it readable for the handler. An
+		// oversized body is passed through for DecodeJSON to reject.
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes+1))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "could not read request body")
+			return
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		if int64(len(body)) > MaxBodyBytes {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		storeKey := r.Pattern + "\x00" + idempotencyCaller(r) + "\x00" + key
+		hash := sha256.Sum256(body)
+		e, claimed := s.begin(storeKey, hash)
+		switch {
+		case claimed:
+		case !e.done:
+			writeJSONError(w, http.StatusConflict, "a request with this "+IdempotencyKeyHeader+" is still in progress")
+			return
+		case e.bodyHash != hash:
+			writeJSONError(w, http.StatusConflict, IdempotencyKeyHeader+" was already used with a different request body")
+			return
+		default:
+			for name, values := range e.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(e.status)
+			w.Write(e.body)
+			return
+		}
+
+		// Release the key if the handler panics, so the client can retry.
+		finished := false
+		defer func() {
+			if !finished {
+				s.finish(storeKey, http.StatusInternalServerError, nil, nil)
+			}
+		}()
+		rec := &captureWriter{statusRecorder: newStatusRecorder(w)}
+		next.ServeHTTP(rec, r)
+		header := w.Header().Clone()
+		// The request ID belongs to each request, not to the replay.
+		header.Del(RequestIDHeader)
+		s.finish(storeKey, rec.status, header, rec.body.Bytes())
+		finished = true
+	})
+}
+
+// idempotencyCaller names whose keys r's belongs with, so callers who pick
+// the same key do not get each other's responses.
+func idempotencyCaller(r *http.Request) string {
+	if id, ok := auth.UserIDFromContext(r.Context()); ok {
+		return "user:" + strconv.Itoa(id)
+	}
+	return "ip:" + clientIP(r)
+}
+
+// captureWriter is a statusRecorder that also keeps a copy of the body.
+type captureWriter struct {
+	*statusRecorder
+	body bytes.Buffer
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	n, err := c.statusRecorder.Write(b)
+	c.body.Write(b[:n])
+	return n, err
+}
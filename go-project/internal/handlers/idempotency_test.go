@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/internal/auth"
+	"go-project/internal/repository"
+)
+
+// newIdempotentTestRouter serves the user and product create routes behind
+// one idempotency store, returned so tests can move its clock.
+func newIdempotentTestRouter(users repository.UserRepository) (*Router, *idempotencyStore) {
+	s := newIdempotencyStore(time.Hour)
+	rt := NewRouter()
+	Register(rt, []Route{
+		{Method: http.MethodPost, Pattern: "/api/users", Handler: NewUserHandlers(users).Create},
+		{Method: http.MethodPost, Pattern: "/api/products", Handler: NewProductHandlers(repository.NewInMemoryProductRepo()).Create},
+	}, s.middleware)
+	return rt, s
+}
+
+func postWithKey(rt http.Handler, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+	return rec
+}
+
+func countUsers(t *testing.T, repo repository.UserRepository) int {
+	t.Helper()
+	n, err := repo.Count(context.Background())
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	return n
+}
+
+func TestIdempotencyReplaysCreate(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	rt, _ := newIdempotentTestRouter(repo)
+	body := `{"name":"Ada","email":"ada@example.com"}`
+
+	first := postWithKey(rt, "/api/users", "key-1", body)
+	second := postWithKey(rt, "/api/users", "key-1", body)
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("statuses = %d, %d; want both %d (second body %s)", first.Code, second.Code, http.StatusCreated, second.Body)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Errorf("replayed body = %s, want %s", second.Body, first.Body)
+	}
+	if got, want := second.Header().Get("Location"), first.Header().Get("Location"); got != want {
+		t.Errorf("replayed Location = %q, want %q", got, want)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("first response is marked as replayed")
+	}
+	if got := second.Header().Get(IdempotentReplayedHeader); got != "true" {
+		t.Errorf("%s = %q, want true", IdempotentReplayedHeader, got)
+	}
+	if n := countUsers(t, repo); n != 1 {
+		t.Errorf("users created = %d, want 1", n)
+	}
+}
+
+func TestIdempotencyKeyReuse(t *testing.T) {
+	tests := []struct {
+		name       string
+		path, key  string
+		body       string
+		wantStatus int
+	}{
+		{"different body", "/api/users", "key-1", `{"name":"Bob","email":"bob@example.com"}`, http.StatusConflict},
+		{"same key on another route", "/api/products", "key-1", `{"name":"Widget","price":"1.00"}`, http.StatusCreated},
+		{"new key", "/api/users", "key-2", `{"name":"Bob","email":"bob@example.com"}`, http.StatusCreated},
+		{"invalid key", "/api/users", "bad key\n", `{"name":"Bob","email":"bob@example.com"}`, http.StatusBadRequest},
+		{"no key", "/api/users", "", `{"name":"Bob","email":"bob@example.com"}`, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, _ := newIdempotentTestRouter(repository.NewInMemoryUserRepo())
+			if rec := postWithKey(rt, "/api/users", "key-1", `{"name":"Ada","email":"ada@example.com"}`); rec.Code != http.StatusCreated {
+				t.Fatalf("first request: status = %d, body %s", rec.Code, rec.Body)
+			}
+			rec := postWithKey(rt, tt.path, tt.key, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code >= 400 {
+				decodeErrorEnvelope(t, rec)
+			}
+			if rec.Header().Get(IdempotentReplayedHeader) != "" {
+				t.Errorf("response is marked as replayed")
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyExpires(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	rt, s := newIdempotentTestRouter(repo)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	if rec := postWithKey(rt, "/api/users", "key-1", `{"name":"Ada","email":"ada@example.com"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("first request: status = %d", rec.Code)
+	}
+	now = now.Add(2 * time.Hour)
+	rec := postWithKey(rt, "/api/users", "key-1", `{"name":"Bob","email":"bob@example.com"}`)
+	if rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("after expiry: status = %d, replayed = %q; want a fresh 201", rec.Code, rec.Header().Get(IdempotentReplayedHeader))
+	}
+	if n := countUsers(t, repo); n != 2 {
+		t.Errorf("users created = %d, want 2", n)
+	}
+
+	s.evictExpired(now.Add(2 * time.Hour))
+	if len(s.entries) != 0 {
+		t.Errorf("entries after eviction = %d, want 0", len(s.entries))
+	}
+}
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	s := newIdempotencyStore(time.Hour)
+	calls := 0
+	h := s.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeJSONError(w, http.StatusServiceUnavailable, "try again")
+	}))
+	for range 2 {
+		if rec := postWithKey(h, "/api/users", "key-1", `{}`); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyKeysAreScopedToTheCaller(t *testing.T) {
+	rt, _ := newIdempotentTestRouter(repository.NewInMemoryUserRepo())
+	post := func(userID int, remote, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.RemoteAddr = remote
+		if userID != 0 {
+			req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		}
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The same key and body from different callers, all behind one NAT
+	// except the last
+	body := `{"name":"Ada","email":"ada@example.com"}`
+	first := post(1, "203.0.113.9:4000", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("user 1: status = %d, body %s", first.Code, first.Body)
+	}
+	for _, tc := range []struct {
+		name   string
+		userID int
+		remote string
+	}{
+		{"another user", 2, "203.0.113.9:4000"},
+		{"an anonymous client", 0, "203.0.113.9:4000"},
+	} {
+		rec := post(tc.userID, tc.remote, body)
+		if rec.Header().Get(IdempotentReplayedHeader) != "" || rec.Body.String() == first.Body.String() {
+			t.Fatalf("%s got user 1's response replayed: %d %s", tc.name, rec.Code, rec.Body)
+		}
+	}
+	if rec := post(0, "198.51.100.1:4000", `{"name":"Bob","email":"bob@example.com"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("anonymous client on another IP: status = %d, want %d (body %s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+}
+
+func TestIdempotencyStoreIsCapped(t *testing.T) {
+	rt, s := newIdempotentTestRouter(repository.NewInMemoryUserRepo())
+	s.max = 2
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	for i, key := range []string{"key-1", "key-2", "key-3"} {
+		now = now.Add(time.Minute)
+		body := `{"name":"User","email":"user` + key + `@example.com"}`
+		if rec := postWithKey(rt, "/api/users", key, body); rec.Code != http.StatusCreated {
+			t.Fatalf("request %d: status = %d, body %s", i+1, rec.Code, rec.Body)
+		}
+	}
+	if len(s.entries) != 2 {
+		t.Fatalf("entries = %d, want the cap of 2", len(s.entries))
+	}
+	// The oldest key made room
+	rec := postWithKey(rt, "/api/users", "key-3", `{"name":"User","email":"userkey-3@example.com"}`)
+	if rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("newest key was not replayed: %d %s", rec.Code, rec.Body)
+	}
+	for key := range s.entries {
+		if strings.HasSuffix(key, "key-1") {
+			t.Errorf("oldest key %q is still stored", key)
+		}
+	}
+}
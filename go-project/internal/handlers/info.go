@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-project/internal/buildinfo"
+)
+
+// infoResponse is the body of GET /info.
+type infoResponse struct {
+	buildinfo.Info
+	UptimeSeconds float64 `json:"uptime_seconds"`
+}
+
+// InfoHandler handles GET /info, reporting which build is running and how
+// long ago, at started, the process came up. It only ever reports the
+// build and the uptime, never configuration, so it is safe to leave
+// unauthenticated.
+func InfoHandler(started time.Time) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, infoResponse{Info: buildinfo.Get(), UptimeSeconds: time.Since(started).Seconds()})
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/internal/buildinfo"
+	"go-project/internal/repository"
+)
+
+// setBuildInfo stands in for -ldflags -X for the duration of the test.
+func setBuildInfo(t *testing.T, version, commit, date string) {
+	t.Helper()
+	old := []string{buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate}
+	buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate = version, commit, date
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit, buildinfo.BuildDate = old[0], old[1], old[2] })
+}
+
+func getInfo(t *testing.T, h http.Handler) (infoResponse, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/info", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var body infoResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return body, rec.Body.String()
+}
+
+func TestInfoHandler(t *testing.T) {
+	setBuildInfo(t, "1.4.0-test", "abc123", "2026-10-01T12:00:00Z")
+	t.Setenv("WEBHOOK_SECRET", "do-not-leak-me")
+
+	rt := NewRouter()
+	Register(rt, Routes(Services{
+		Users:    repository.NewInMemoryUserRepo(),
+		Products: repository.NewInMemoryProductRepo(),
+		Prices:   NewPriceBroker(),
+		Started:  time.Now().Add(-time.Minute),
+	}))
+
+	first, raw := getInfo(t, rt)
+	want := buildinfo.Info{Version: "1.4.0-test", Commit: "abc123", BuildDate: "2026-10-01T12:00:00Z", GoVersion: runtime.Version()}
+	if first.Info != want {
+		t.Errorf("build info = %+v, want %+v", first.Info, want)
+	}
+	if first.UptimeSeconds < 60 {
+		t.Errorf("uptime_seconds = %v, want at least 60", first.UptimeSeconds)
+	}
+	if strings.Contains(raw, "do-not-leak-me") {
+		t.Errorf("response leaks an environment secret: %s", raw)
+	}
+	for _, key := range []string{`"version"`, `"commit"`, `"build_date"`, `"go_version"`, `"uptime_seconds"`} {
+		if !strings.Contains(raw, key) {
+			t.Errorf("response %s has no %s", raw, key)
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if second, _ := getInfo(t, rt); second.UptimeSeconds <= first.UptimeSeconds {
+		t.Errorf("uptime went from %v to %v, want it to increase", first.UptimeSeconds, second.UptimeSeconds)
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-project/internal/auth"
+)
+
+// JWTMiddleware requires an "Authorization: Bearer <token>" header carrying
+// a token issued by auth.IssueToken with secret. The token's user ID is
+// stored in the request context for auth.UserIDFromContext. Missing,
+// malformed, tampered and expired tokens get 401 with a WWW-Authenticate
+// challenge.
+func JWTMiddleware(secret []byte) Middleware {
+	return jwtMiddleware(secret, false)
+}
+
+// OptionalJWTMiddleware is JWTMiddleware for routes that also serve
+// anonymous clients: a request without an Authorization header passes
+// through with no user ID, while a bad token is still rejected with 401
+// rather than quietly served as anonymous.
+func OptionalJWTMiddleware(secret []byte) Middleware {
+	return jwtMiddleware(secret, true)
+}
+
+func jwtMiddleware(secret []byte, optional bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if optional && r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				writeJSONError(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+			id, err := auth.ParseToken(secret, token)
+			if err != nil {
+				msg := "invalid token"
+				if errors.Is(err, auth.ErrExpiredToken) {
+					msg = "token expired"
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token", error_description="`+msg+`"`)
+				writeJSONError(w, http.StatusUnauthorized, msg)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), id)))
+		})
+	}
+}
+
+// bearerToken extracts the token from an Authorization: Bearer header. The
+// scheme is matched case-insensitively.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
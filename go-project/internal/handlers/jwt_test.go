@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/internal/auth"
+)
+
+func TestJWTMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	valid, err := auth.IssueToken(secret, 42, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := auth.IssueToken(secret, 42, -time.Minute)
+	otherKey, _ := auth.IssueToken([]byte("other-secret"), 42, time.Hour)
+
+	h := JWTMiddleware(secret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := auth.UserIDFromContext(r.Context())
+		if !ok {
+			t.Error("no user ID in context")
+		}
+		io.WriteString(w, strconv.Itoa(id))
+	}))
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"valid", "Bearer " + valid, http.StatusOK, "42"},
+		{"lowercase scheme", "bearer " + valid, http.StatusOK, "42"},
+		{"missing", "", http.StatusUnauthorized, "missing bearer token"},
+		{"wrong scheme", "Basic " + valid, http.StatusUnauthorized, "missing bearer token"},
+		{"expired", "Bearer " + expired, http.StatusUnauthorized, "token expired"},
+		{"tampered", "Bearer " + valid[:len(valid)-5] + "abcde", http.StatusUnauthorized, "invalid token"},
+		{"signed with another key", "Bearer " + otherKey, http.StatusUnauthorized, "invalid token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %q does not contain %q", rec.Body.String(), tt.wantBody)
+			}
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if tt.wantStatus == http.StatusUnauthorized && !strings.HasPrefix(challenge, "Bearer ") {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge", challenge)
+			}
+		})
+	}
+}
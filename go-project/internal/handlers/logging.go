@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-project/pkg/utils"
+)
+
+// LogOption adjusts which requests LoggingMiddleware logs.
+type LogOption func(*logOptions)
+
+type logOptions struct {
+	// sampleRate is the fraction of successful requests logged.
+	sampleRate float64
+	exclude    map[string]bool
+	// sample reports whether to log a successful request; tests replace it.
+	sample func(rate float64) bool
+}
+
+// SampleSuccesses logs only about rate, between 0 and 1, of the requests
+// answered with a 2xx status, chosen at random. Every other response is
+// still logged, so sampling never hides an error.
+func SampleSuccesses(rate float64) LogOption {
+	return func(o *logOptions) { o.sampleRate = min(max(rate, 0), 1) }
+}
+
+// ExcludePaths stops logging of successful requests to exactly these paths,
+// such as /healthz and /metrics, which are hit often and uneventfully.
+// Their non-2xx responses are still logged.
+func ExcludePaths(paths ...string) LogOption {
+	return func(o *logOptions) {
+		for _, p := range paths {
+			o.exclude[p] = true
+		}
+	}
+}
+
+// logged reports whether a request to path answered with status is logged.
+func (o *logOptions) logged(path string, status int) bool {
+	if status < 200 || status >= 300 {
+		return true
+	}
+	if o.exclude[path] {
+		return false
+	}
+	return o.sampleRate >= 1 || o.sample(o.sampleRate)
+}
+
+func randomSample(rate float64) bool {
+	return rand.Float64() < rate
+}
+
+// LoggingMiddleware logs one structured line per request with the method,
+// path, response status, bytes written, and duration, plus the request ID
+// when RequestIDMiddleware runs before it. Email addresses in the path are
+// masked with utils.MaskEmail. A nil logger uses slog.Default(). Every
+// request is logged unless SampleSuccesses or ExcludePaths say otherwise.
+func LoggingMiddleware(logger *slog.Logger, opts ...LogOption) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	o := &logOptions{sampleRate: 1, exclude: map[string]bool{}, sample: randomSample}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newStatusRecorder(w)
+			next.ServeHTTP(rec, r)
+			if !o.logged(r.URL.Path, rec.status) {
+				return
+			}
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", maskPath(r.URL.Path)),
+				slog.Int("status", rec.status),
+				slog.Int("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if id, ok := RequestIDFromContext(r.Context()); ok {
+				attrs = append(attrs, slog.String("request_id", id))
+			}
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "http request", attrs...)
+		})
+	}
+}
+
+// maskPath masks every path segment that looks like an email address, such
+// as the one in /api/users/by-email/ada@example.com.
+func maskPath(path string) string {
+	if !strings.Contains(path, "@") {
+		return path
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.Contains(seg, "@") {
+			segments[i] = utils.MaskEmail(seg)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// emailPattern matches the email addresses maskEmails masks.
+var emailPattern = regexp.MustCompile(`[^\s@/"'<>()\[\],;:=]+@[^\s@/"'<>()\[\],;:=]+`)
+
+// maskEmails masks every email address in free text that is about to be
+// logged, such as a panic value or an error from the repository.
+func maskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, utils.MaskEmail)
+}
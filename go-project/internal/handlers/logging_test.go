@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoggingMiddlewareRecordsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	}), LoggingMiddleware(logger))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/data", nil))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v\n%s", err, buf.String())
+	}
+	if entry["method"] != "POST" || entry["path"] != "/api/data" {
+		t.Errorf("method/path = %v %v", entry["method"], entry["path"])
+	}
+	if entry["status"] != float64(http.StatusTeapot) {
+		t.Errorf("status = %v, want %d", entry["status"], http.StatusTeapot)
+	}
+	if entry["bytes"] != float64(len("short and stout")) {
+		t.Errorf("bytes = %v", entry["bytes"])
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("duration missing from log entry")
+	}
+}
+
+func TestLoggingMiddlewareDefaultsStatusTo200(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	Chain(http.HandlerFunc(DataHandler), LoggingMiddleware(logger)).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/data", nil))
+
+	var entry map[string]any
+	json.Unmarshal(buf.Bytes(), &entry)
+	if entry["status"] != float64(http.StatusOK) {
+		t.Fatalf("status = %v, want 200", entry["status"])
+	}
+}
+
+func TestLoggingMiddlewareMasksEmailsInPath(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	Chain(http.HandlerFunc(DataHandler), LoggingMiddleware(logger)).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/by-email/jason@example.com", nil))
+
+	var entry map[string]any
+	json.Unmarshal(buf.Bytes(), &entry)
+	if want := "/api/users/by-email/j***n@example.com"; entry["path"] != want {
+		t.Fatalf("path = %v, want %q", entry["path"], want)
+	}
+}
+
+func TestLoggingMiddlewareSampling(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	status := http.StatusOK
+	sampled := 0
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}), LoggingMiddleware(logger, SampleSuccesses(0.5), ExcludePaths("/healthz", "/metrics"),
+		func(o *logOptions) {
+			// Keep every other successful request
+			o.sample = func(rate float64) bool {
+				if rate != 0.5 {
+					t.Errorf("sampled at rate %v, want 0.5", rate)
+				}
+				sampled++
+				return sampled%2 == 0
+			}
+		}))
+	// logged sends n requests to path and returns how many were logged.
+	logged := func(path string, n int) int {
+		buf.Reset()
+		for range n {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		return bytes.Count(buf.Bytes(), []byte("\n"))
+	}
+
+	if got := logged("/healthz", 10); got != 0 {
+		t.Errorf("logged %d successful /healthz requests, want none", got)
+	}
+	if got := logged("/api/data", 10); got != 5 {
+		t.Errorf("logged %d of 10 successful requests at rate 0.5, want 5", got)
+	}
+	for _, status = range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusNotFound, http.StatusFound} {
+		for _, path := range []string{"/api/data", "/healthz"} {
+			if got := logged(path, 3); got != 3 {
+				t.Errorf("logged %d of 3 %s responses from %s, want every one", got, http.StatusText(status), path)
+			}
+		}
+	}
+}
+
+func TestLoggingMiddlewareSampleRateZero(t *testing.T) {
+	var buf bytes.Buffer
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}), LoggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil)), SampleSuccesses(0)))
+	for _, path := range []string{"/ok", "/fail", "/ok"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("want exactly the one 500 logged, got %q", buf.String())
+	}
+	if entry["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("logged status %v, want 500", entry["status"])
+	}
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	return nil, nil, nil
+}
+
+func TestStatusRecorderPreservesFlusherAndHijacker(t *testing.T) {
+	inner := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("wrapped writer is not an http.Flusher")
+		}
+		f.Flush()
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("wrapped writer is not an http.Hijacker")
+		}
+		if _, _, err := hj.Hijack(); err != nil {
+			t.Fatalf("Hijack: %v", err)
+		}
+	}), LoggingMiddleware(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))))
+
+	h.ServeHTTP(inner, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if !inner.Flushed {
+		t.Error("Flush was not forwarded")
+	}
+	if !inner.hijacked {
+		t.Error("Hijack was not forwarded")
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-project/internal/metrics"
+)
+
+// MetricsMiddleware records http_requests_total and
+// http_request_duration_seconds for every request. The path label is the
+// Router pattern that matched, so /api/users/1 and /api/users/2 share the
+// "/api/users/{id}" series instead of creating one per ID. It must run inside
+// the Router (as part of a route's chain) for the pattern to be known.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		path := routePattern(r)
+		if path == "" {
+			path = "unmatched"
+		}
+		metrics.RequestsTotal.Inc(r.Method, path, strconv.Itoa(rec.status))
+		metrics.RequestDuration.Observe(time.Since(start).Seconds(), r.Method, path)
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-project/internal/metrics"
+)
+
+// scrapeSample returns the value of the sample line starting with series, or
+// 0 when the series is absent.
+func scrapeSample(t *testing.T, url, series string) float64 {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, series+" ") {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(line, series+" "), 64)
+			if err != nil {
+				t.Fatalf("parse %q: %v", line, err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func TestMetricsMiddlewareCountsRequests(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/api/users/{id}", Chain(http.HandlerFunc(DataHandler), MetricsMiddleware))
+	rt.Get("/metrics", metrics.Handler())
+	srv := httptest.NewServer(rt)
+	defer srv.Close()
+
+	const series = `http_requests_total{method="GET",path="/api/users/{id}",status="200"}`
+	before := scrapeSample(t, srv.URL+"/metrics", series)
+
+	for _, id := range []string{"1", "2"} {
+		resp, err := http.Get(srv.URL + "/api/users/" + id)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if after := scrapeSample(t, srv.URL+"/metrics", series); after-before != 2 {
+		t.Fatalf("%s went from %v to %v, want +2", series, before, after)
+	}
+	hist := `http_request_duration_seconds_count{method="GET",path="/api/users/{id}"}`
+	if got := scrapeSample(t, srv.URL+"/metrics", hist); got < 2 {
+		t.Fatalf("%s = %v, want >= 2", hist, got)
+	}
+}
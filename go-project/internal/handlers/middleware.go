@@ -0,0 +1,16 @@
+package handlers
+
+import "net/http"
+
+// Middleware wraps an http.Handler to add behaviour before and/or after it.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with mw in declaration order: the first middleware is the
+// outermost and sees the request first. Chain(h) with no middleware returns
+// h unchanged.
+func Chain(h http.Handler, mw ...Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func tagMiddleware(tag string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Order", tag)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChainAppliesMiddlewareInDeclarationOrder(t *testing.T) {
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Order", "handler")
+	})
+	h := Chain(final, tagMiddleware("first"), tagMiddleware("second"))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"first", "second", "handler"}
+	if got := rec.Header().Values("X-Order"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("X-Order = %v, want %v", got, want)
+	}
+}
+
+func TestChainWithoutMiddlewareReturnsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Chain(http.HandlerFunc(DataHandler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
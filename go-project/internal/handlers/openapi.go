@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go-project/internal/models"
+	"go-project/internal/openapi"
+)
+
+// APIVersion is the version reported in the OpenAPI document.
+const APIVersion = "1.0.0"
+
+// operationDoc is what the route table does not say about an operation.
+// Request and Response are zero values of the types the handler decodes and
+// encodes, so their schemas are derived from the same structs.
+type operationDoc struct {
+	ID       string
+	Summary  string
+	Query    []openapi.Parameter
+	Request  any // nil when the operation takes no body
+	Status   int
+	Response any // nil when the success response has no body
+	// Produces is the media type of a success body that is not JSON, such
+	// as text/csv; Response is nil for these.
+	Produces string
+	Errors   []int
+	// Forms is set for bodies decoded with DecodeRequest, which may also be
+	// form-encoded.
+	Forms bool
+}
+
+// Errors every body decoded with DecodeJSON or DecodeRequest can produce.
+var bodyErrors = []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType}
+
+var pageQuery = []openapi.Parameter{
+	queryParam("limit", "integer", "Page size, 1 to 100 (default 20)"),
+	queryParam("offset", "integer", "Number of items to skip"),
+}
+
+// cursorQuery pages by cursor, on endpoints that recommend it over offset.
+var cursorQuery = slices.Concat([]openapi.Parameter{
+	queryParam("cursor", "string", "next_cursor of the previous page; the pagination object then has page_size and next_cursor in place of limit, offset and next_offset"),
+	queryParam("page_size", "integer", "Page size when paging by cursor, 1 to 100 (default 20)"),
+}, pageQuery)
+
+// searchFilters filter and sort products; GET /api/products adds a
+// category filter that the per-category listing takes from its path.
+var searchFilters = []openapi.Parameter{
+	queryParam("q", "string", "Case-insensitive name substring"),
+	queryParam("min_price", "string", "Lowest price, a decimal amount in currency"),
+	queryParam("max_price", "string", "Highest price, a decimal amount in currency"),
+	queryParam("currency", "string", "ISO 4217 code the price bounds are in (default USD)"),
+	queryParam("sort", "string", "name or price, optionally suffixed with :asc or :desc"),
+}
+
+var searchQuery = slices.Concat(searchFilters, pageQuery)
+
+var productFilters = slices.Concat([]openapi.Parameter{
+	queryParam("category", "string", "Slug of the category products must be in"),
+}, searchFilters)
+
+var productQuery = slices.Concat(productFilters, pageQuery)
+
+// operationDocs documents the API routes, keyed by "METHOD pattern" as in
+// the route table. Routes without an entry are left out of the document;
+// TestOpenAPIDocumentsEveryAPIRoute keeps the two in step.
+var operationDocs = map[string]operationDoc{
+	"POST /api/users": {ID: "createUser", Summary: "Create a user", Request: createUserRequest{}, Forms: true,
+		Status: http.StatusCreated, Response: models.User{},
+		Errors: append([]int{http.StatusConflict, http.StatusUnprocessableEntity}, bodyErrors...)},
+	"GET /api/users": {ID: "listUsers", Summary: "List users", Query: cursorQuery,
+		Status: http.StatusOK, Response: pageResponse[models.PublicUser]{}, Errors: []int{http.StatusBadRequest}},
+	"POST /api/users/batch": {ID: "getUsers", Summary: "Get up to MaxBatchUsers users by ID", Request: batchUsersRequest{},
+		Status: http.StatusOK, Response: batchUsersResponse{}, Errors: bodyErrors},
+	"GET /api/users/{id}": {ID: "getUser", Summary: "Get a user; only the user themself sees the email",
+		Status: http.StatusOK, Response: models.User{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	"PUT /api/users/{id}": {ID: "updateUser", Summary: "Change a user's email", Request: updateUserRequest{},
+		Status: http.StatusOK, Response: models.User{},
+		Errors: append([]int{http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed, http.StatusUnprocessableEntity}, bodyErrors...)},
+	"PATCH /api/users/{id}": {ID: "patchUser", Summary: "Change the given fields of a user", Request: patchUserRequest{},
+		Status: http.StatusOK, Response: models.User{},
+		Errors: append([]int{http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed, http.StatusUnprocessableEntity}, bodyErrors...)},
+	"DELETE /api/users/{id}": {ID: "deleteUser", Summary: "Delete a user",
+		Status: http.StatusNoContent, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	"POST /api/products": {ID: "createProduct", Summary: "Create a product", Request: createProductRequest{}, Forms: true,
+		Status: http.StatusCreated, Response: models.Product{},
+		Errors: append([]int{http.StatusUnprocessableEntity}, bodyErrors...)},
+	"POST /api/products/bulk": {ID: "createProducts", Summary: "Create up to MaxBulkProducts products",
+		Request: []createProductRequest{}, Status: http.StatusMultiStatus, Response: bulkProductResponse{}, Errors: bodyErrors},
+	"GET /api/products": {ID: "listProducts", Summary: "Search products", Query: productQuery,
+		Status: http.StatusOK, Response: pageResponse[models.Product]{}, Errors: []int{http.StatusBadRequest}},
+	"GET /api/products.csv": {ID: "exportProducts", Summary: "Download the matching products as CSV", Query: productFilters,
+		Status: http.StatusOK, Produces: "text/csv", Errors: []int{http.StatusBadRequest}},
+	"GET /api/products.json": {ID: "exportProductsJSON", Summary: "Download the matching products as one JSON array", Query: productFilters,
+		Status: http.StatusOK, Response: []models.Product{}, Errors: []int{http.StatusBadRequest}},
+	"GET /api/products/{id}": {ID: "getProduct", Summary: "Get a product",
+		Status: http.StatusOK, Response: models.Product{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	"PUT /api/products/{id}": {ID: "updateProduct", Summary: "Replace a product", Request: replaceProductRequest{},
+		Status: http.StatusOK, Response: models.Product{},
+		Errors: append([]int{http.StatusNotFound, http.StatusPreconditionFailed, http.StatusUnprocessableEntity}, bodyErrors...)},
+	"PATCH /api/products/{id}": {ID: "patchProduct", Summary: "Change the given fields of a product", Request: patchProductRequest{},
+		Status: http.StatusOK, Response: models.Product{},
+		Errors: append([]int{http.StatusNotFound, http.StatusPreconditionFailed, http.StatusUnprocessableEntity}, bodyErrors...)},
+
+	"GET /api/categories": {ID: "listCategories", Summary: "List product categories",
+		Status: http.StatusOK, Response: []models.Category{}},
+	"GET /api/categories/{slug}/products": {ID: "listCategoryProducts", Summary: "Search the products in a category",
+		Query: searchQuery, Status: http.StatusOK, Response: pageResponse[models.Product]{},
+		Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+}
+
+// moneySchema is the wire form written by models.Money.MarshalJSON.
+var moneySchema = &openapi.Schema{
+	Type: "object",
+	Properties: map[string]*openapi.Schema{
+		"amount":   {Type: "string", Description: `Decimal amount, e.g. "12.34"`},
+		"currency": {Type: "string", Description: "ISO 4217 code"},
+	},
+	Required:    []string{"amount", "currency"},
+	Description: "Requests may also give a bare decimal number or string in USD.",
+}
+
+// OpenAPI describes the documented routes among routes as an OpenAPI 3.0
+// document, deriving request and response schemas from the handlers' types.
+func OpenAPI(routes []Route) *openapi.Document {
+	g := openapi.NewGenerator()
+	g.Override(reflect.TypeFor[models.Money](), moneySchema)
+	errorRef := g.SchemaFor(reflect.TypeFor[errorEnvelope]())
+
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info:    openapi.Info{Title: "go-project API", Version: APIVersion},
+		Paths:   map[string]*openapi.PathItem{},
+	}
+	for _, r := range routes {
+		d, ok := operationDocs[r.Method+" "+r.Pattern]
+		if !ok {
+			continue
+		}
+		op := &openapi.Operation{
+			OperationID: d.ID,
+			Summary:     d.Summary,
+			Parameters:  append(pathParams(r.Pattern), d.Query...),
+			Responses:   map[string]*openapi.Response{},
+		}
+		if d.Request != nil {
+			schema := g.SchemaFor(reflect.TypeOf(d.Request))
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: jsonContent(schema)}
+			if d.Forms {
+				op.RequestBody.Content[formMediaType] = openapi.MediaType{Schema: schema}
+			}
+		}
+		success := &openapi.Response{Description: http.StatusText(d.Status)}
+		if d.Response != nil {
+			success.Content = jsonContent(g.SchemaFor(reflect.TypeOf(d.Response)))
+		}
+		if d.Produces != "" {
+			success.Content = map[string]openapi.MediaType{d.Produces: {Schema: &openapi.Schema{Type: "string"}}}
+		}
+		op.Responses[strconv.Itoa(d.Status)] = success
+		for _, status := range d.Errors {
+			op.Responses[strconv.Itoa(status)] = &openapi.Response{Description: http.StatusText(status), Content: jsonContent(errorRef)}
+		}
+
+		item := doc.Paths[r.Pattern]
+		if item == nil {
+			item = &openapi.PathItem{}
+			doc.Paths[r.Pattern] = item
+		}
+		item.SetOperation(r.Method, op)
+	}
+	doc.Components.Schemas = g.Schemas()
+	return doc
+}
+
+// OpenAPIHandler serves doc as JSON. The document is encoded once up front.
+func OpenAPIHandler(doc *openapi.Document) http.HandlerFunc {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		panic("handlers: encoding OpenAPI document: " + err.Error())
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// pathParams declares the {name} wildcards of a Router pattern. Wildcards
+// named id hold record IDs and are typed as integers; others are strings.
+func pathParams(pattern string) []openapi.Parameter {
+	var params []openapi.Parameter
+	for _, seg := range strings.Split(pattern, "/") {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") || seg == "{$}" {
+			continue
+		}
+		name := strings.TrimSuffix(strings.Trim(seg, "{}"), "...")
+		typ := "string"
+		if name == "id" {
+			typ = "integer"
+		}
+		params = append(params, openapi.Parameter{Name: name, In: "path", Required: true, Schema: &openapi.Schema{Type: typ}})
+	}
+	return params
+}
+
+func queryParam(name, typ, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &openapi.Schema{Type: typ}}
+}
+
+func jsonContent(s *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: s}}
+}
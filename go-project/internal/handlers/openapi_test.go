@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIServedAndValid(t *testing.T) {
+	rt := NewRouter()
+	Register(rt, Routes(testServices()))
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("document is not JSON: %v", err)
+	}
+	for _, err := range validateOpenAPI(doc) {
+		t.Error(err)
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	users, _ := paths["/api/users"].(map[string]any)
+	if users["get"] == nil || users["post"] == nil {
+		t.Fatalf("/api/users = %v, want get and post operations", users)
+	}
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	user := schemas["User"].(map[string]any)["properties"].(map[string]any)
+	if user["email"] == nil || user["created_at"] == nil {
+		t.Errorf("User properties = %v, want email and created_at", user)
+	}
+	for _, hidden := range []string{"PasswordHash", "XMLName"} {
+		if _, ok := user[hidden]; ok {
+			t.Errorf("User schema exposes %s", hidden)
+		}
+	}
+}
+
+func TestOpenAPIDocumentsEveryAPIRoute(t *testing.T) {
+	routes := map[string]bool{}
+	for _, r := range Routes(testServices()) {
+		key := r.Method + " " + r.Pattern
+		routes[key] = true
+		// The event stream is not JSON and is left out on purpose, as are
+		// the example routes such as /api/data.
+		resource := strings.HasPrefix(r.Pattern, "/api/users") || strings.HasPrefix(r.Pattern, "/api/products") || strings.HasPrefix(r.Pattern, "/api/categories")
+		if resource && r.Pattern != "/api/products/events" {
+			if _, ok := operationDocs[key]; !ok {
+				t.Errorf("route %q has no entry in operationDocs", key)
+			}
+		}
+	}
+	for key := range operationDocs {
+		if !routes[key] {
+			t.Errorf("operationDocs entry %q matches no route", key)
+		}
+	}
+}
+
+// validateOpenAPI checks doc against the rules of the OpenAPI 3.0 schema
+// that a generated document can get wrong: required fields, status code
+// keys, declared path parameters and resolvable references.
+func validateOpenAPI(doc map[string]any) []error {
+	var errs []error
+	fail := func(format string, args ...any) { errs = append(errs, fmt.Errorf(format, args...)) }
+
+	if v, _ := doc["openapi"].(string); !regexp.MustCompile(`^3\.0\.\d+$`).MatchString(v) {
+		fail("openapi = %q, want 3.0.x", v)
+	}
+	info, _ := doc["info"].(map[string]any)
+	if info["title"] == "" || info["title"] == nil || info["version"] == "" || info["version"] == nil {
+		fail("info = %v, want title and version", info)
+	}
+	schemas, _ := doc["components"].(map[string]any)["schemas"].(map[string]any)
+
+	var walkRefs func(where string, v any)
+	walkRefs = func(where string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok {
+				name, found := strings.CutPrefix(ref, "#/components/schemas/")
+				if !found || schemas[name] == nil {
+					fail("%s: unresolved $ref %q", where, ref)
+				}
+				if len(v) != 1 {
+					fail("%s: $ref %q has sibling keys", where, ref)
+				}
+			}
+			for k, child := range v {
+				walkRefs(where+"."+k, child)
+			}
+		case []any:
+			for i, child := range v {
+				walkRefs(fmt.Sprintf("%s[%d]", where, i), child)
+			}
+		}
+	}
+
+	paths, ok := doc["paths"].(map[string]any)
+	if !ok || len(paths) == 0 {
+		fail("paths missing")
+	}
+	statusKey := regexp.MustCompile(`^([1-5]\d\d|[1-5]XX|default)$`)
+	template := regexp.MustCompile(`\{([^}]+)\}`)
+	for path, item := range paths {
+		if !strings.HasPrefix(path, "/") {
+			fail("path %q does not start with /", path)
+		}
+		for method, v := range item.(map[string]any) {
+			where := method + " " + path
+			op := v.(map[string]any)
+			responses, _ := op["responses"].(map[string]any)
+			if len(responses) == 0 {
+				fail("%s: no responses", where)
+			}
+			for code, r := range responses {
+				if !statusKey.MatchString(code) {
+					fail("%s: invalid response key %q", where, code)
+				}
+				if d, _ := r.(map[string]any)["description"].(string); d == "" {
+					fail("%s %s: response without description", where, code)
+				}
+			}
+			declared := map[string]bool{}
+			params, _ := op["parameters"].([]any)
+			for _, p := range params {
+				p := p.(map[string]any)
+				in, _ := p["in"].(string)
+				if p["name"] == nil || p["schema"] == nil || !strings.Contains(" query header path cookie ", " "+in+" ") {
+					fail("%s: invalid parameter %v", where, p)
+				}
+				if in == "path" {
+					if p["required"] != true {
+						fail("%s: path parameter %v is not required", where, p["name"])
+					}
+					declared[p["name"].(string)] = true
+				}
+			}
+			for _, m := range template.FindAllStringSubmatch(path, -1) {
+				if !declared[m[1]] {
+					fail("%s: path parameter %q is not declared", where, m[1])
+				}
+			}
+			if body, ok := op["requestBody"].(map[string]any); ok {
+				if content, _ := body["content"].(map[string]any); len(content) == 0 {
+					fail("%s: requestBody without content", where)
+				}
+			}
+			walkRefs(where, op)
+		}
+	}
+	walkRefs("components", schemas)
+	return errs
+}
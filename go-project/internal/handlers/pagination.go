@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Bounds for the limit query parameter of paginated list endpoints.
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// pageParams is the window requested through the limit and offset query
+// parameters.
+type pageParams struct {
+	Limit  int
+	Offset int
+}
+
+// pagination is the metadata returned alongside a page of results.
+// NextOffset is nil on the last page. Endpoints that also page by cursor
+// set NextCursor to the cursor of the same next page, and Notice to
+// recommend it.
+type pagination struct {
+	Total      int     `json:"total"`
+	Count      int     `json:"count"`
+	Limit      int     `json:"limit"`
+	Offset     int     `json:"offset"`
+	NextOffset *int    `json:"next_offset"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	Notice     string  `json:"notice,omitempty"`
+}
+
+// offsetNotice is the Notice of pages fetched by offset from endpoints
+// that also page by cursor.
+const offsetNotice = "offset pagination can skip or repeat items added or deleted between requests; pass next_cursor as cursor instead"
+
+// pageResponse is the envelope for paginated list endpoints.
+type pageResponse[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination pagination `json:"pagination"`
+}
+
+// parsePageParams reads limit and offset from the query string. A missing
+// limit defaults to defaultPageLimit and larger values are capped at
+// maxPageLimit. Non-numeric values, a limit below one and a negative offset
+// are errors.
+func parsePageParams(r *http.Request) (pageParams, error) {
+	p := pageParams{Limit: defaultPageLimit}
+	q := r.URL.Query()
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return p, errors.New("limit must be a positive integer")
+		}
+		p.Limit = min(n, maxPageLimit)
+	}
+	if s := q.Get("offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return p, errors.New("offset must be a non-negative integer")
+		}
+		p.Offset = n
+	}
+	return p, nil
+}
+
+// newPageResponse wraps one page of data with its pagination metadata.
+func newPageResponse[T any](data []T, p pageParams, total int) pageResponse[T] {
+	meta := pagination{Total: total, Count: len(data), Limit: p.Limit, Offset: p.Offset}
+	if next := p.Offset + len(data); len(data) > 0 && next < total {
+		meta.NextOffset = &next
+	}
+	return pageResponse[T]{Data: data, Pagination: meta}
+}
+
+// cursorParams is the page requested through the cursor and page_size
+// query parameters: up to Size items with an ID greater than After.
+type cursorParams struct {
+	Size  int
+	After int
+}
+
+// cursorPagination is the metadata returned alongside a page fetched by
+// cursor. NextCursor is nil on the last page.
+type cursorPagination struct {
+	Total      int     `json:"total"`
+	Count      int     `json:"count"`
+	PageSize   int     `json:"page_size"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// cursorPageResponse is the envelope for pages fetched by cursor.
+type cursorPageResponse[T any] struct {
+	Data       []T              `json:"data"`
+	Pagination cursorPagination `json:"pagination"`
+}
+
+// usesCursor reports whether r asks for a page by cursor rather than by
+// offset, by having a cursor or page_size query parameter.
+func usesCursor(r *http.Request) bool {
+	q := r.URL.Query()
+	return q.Has("cursor") || q.Has("page_size")
+}
+
+// parseCursorParams reads cursor and page_size from the query string. A
+// missing cursor starts at the first item, and page_size is bounded as
+// limit is by parsePageParams. A cursor that was not returned as a
+// next_cursor, and limit or offset alongside, are errors.
+func parseCursorParams(r *http.Request) (cursorParams, error) {
+	c := cursorParams{Size: defaultPageLimit}
+	q := r.URL.Query()
+	if q.Has("limit") || q.Has("offset") {
+		return c, errors.New("limit and offset cannot be combined with cursor or page_size")
+	}
+	if s := q.Get("page_size"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return c, errors.New("page_size must be a positive integer")
+		}
+		c.Size = min(n, maxPageLimit)
+	}
+	if s := q.Get("cursor"); s != "" {
+		after, err := decodeCursor(s)
+		if err != nil {
+			return c, errors.New("cursor is not valid; use a next_cursor from a previous page")
+		}
+		c.After = after
+	}
+	return c, nil
+}
+
+// encodeCursor returns the opaque cursor of the page after the item with
+// the given ID: the ID in base64.
+func encodeCursor(id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(id)))
+}
+
+func decodeCursor(s string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(string(b))
+	if err != nil || id < 0 {
+		return 0, errors.New("cursor does not hold an ID")
+	}
+	return id, nil
+}
+
+// newCursorPageResponse wraps one page of data fetched by cursor, where
+// more is whether items follow it and lastID is the ID of its last item.
+func newCursorPageResponse[T any](data []T, c cursorParams, total int, more bool, lastID int) cursorPageResponse[T] {
+	meta := cursorPagination{Total: total, Count: len(data), PageSize: c.Size}
+	if more {
+		next := encodeCursor(lastID)
+		meta.NextCursor = &next
+	}
+	return cursorPageResponse[T]{Data: data, Pagination: meta}
+}
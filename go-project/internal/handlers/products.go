@@ -0,0 +1,447 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-project/internal/audit"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+	"go-project/pkg/utils"
+)
+
+// ProductHandlers serves the /api/products endpoints from a
+// ProductRepository. Categories is consulted to check the category of new
+// products; when it is nil, products cannot be given a category. Every
+// change is recorded with Audit, if set.
+type ProductHandlers struct {
+	Repo       repository.ProductRepository
+	Categories repository.CategoryRepository
+	Audit      *audit.Logger
+}
+
+// NewProductHandlers returns handlers backed by repo.
+func NewProductHandlers(repo repository.ProductRepository) *ProductHandlers {
+	return &ProductHandlers{Repo: repo}
+}
+
+type createProductRequest struct {
+	Name  string       `json:"name"`
+	Price models.Money `json:"price"`
+	Stock int          `json:"stock" openapi:"optional"`
+	// Category is the slug of an existing category.
+	Category string `json:"category,omitempty"`
+}
+
+// product returns the new, unsaved product req describes, with any markup
+// removed from its name.
+func (req createProductRequest) product() *models.Product {
+	p := models.NewProduct(utils.SanitizeText(req.Name), req.Price)
+	p.Stock = req.Stock
+	p.Category = strings.TrimSpace(req.Category)
+	return p
+}
+
+// replaceProductRequest is the body of PUT /api/products/{id}: the whole
+// product, as for Create. Price is a pointer so that leaving it out is
+// reported rather than read as free.
+type replaceProductRequest struct {
+	Name  string        `json:"name"`
+	Price *models.Money `json:"price" openapi:"required"`
+	Stock int           `json:"stock" openapi:"optional"`
+	// Category is the slug of an existing category; leaving it out files
+	// the product under none.
+	Category string `json:"category,omitempty"`
+}
+
+// patchProductRequest is the body of PATCH /api/products/{id}. Fields left
+// out, or null, are not changed; an empty category removes the product
+// from its category.
+type patchProductRequest struct {
+	Name     *string       `json:"name"`
+	Price    *models.Money `json:"price"`
+	Stock    *int          `json:"stock"`
+	Category *string       `json:"category"`
+}
+
+// categoryOf returns the category slug a patch sets, or "" when it leaves
+// the category alone, in the form knownCategories takes.
+func (req patchProductRequest) categoryOf() createProductRequest {
+	if req.Category == nil {
+		return createProductRequest{}
+	}
+	return createProductRequest{Category: *req.Category}
+}
+
+// knownCategories returns the slugs of every category, or nil without
+// querying when none of reqs names a category.
+func (h *ProductHandlers) knownCategories(ctx context.Context, reqs ...createProductRequest) (map[string]bool, error) {
+	needed := false
+	for _, req := range reqs {
+		needed = needed || strings.TrimSpace(req.Category) != ""
+	}
+	if !needed || h.Categories == nil {
+		return nil, nil
+	}
+	cs, err := h.Categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(cs))
+	for _, c := range cs {
+		known[c.Slug] = true
+	}
+	return known, nil
+}
+
+// validateProduct is Validate(p), also reporting a category missing from
+// known in the same *models.ValidationError.
+func validateProduct(p *models.Product, known map[string]bool) error {
+	err := Validate(p)
+	if p.Category == "" || known[p.Category] {
+		return err
+	}
+	return addFieldError(err, "category", "does not exist")
+}
+
+// addFieldError adds a failing field to err, a *models.ValidationError or
+// nil.
+func addFieldError(err error, field, message string) error {
+	v := &models.ValidationError{}
+	errors.As(err, &v)
+	v.Add(field, message)
+	return v
+}
+
+// Create handles POST /api/products. The body may be JSON or form-encoded,
+// with the price as a decimal amount in USD.
+func (h *ProductHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req createProductRequest
+	if !DecodeRequest(w, r, &req) {
+		return
+	}
+	known, err := h.knownCategories(r.Context(), req)
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+	p := req.product()
+	if err := validateProduct(p, known); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := h.Repo.Create(r.Context(), p); err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	h.Audit.Record(r.Context(), audit.Create, "product", p.ID, nil, p)
+	w.Header().Set("Location", "/api/products/"+strconv.Itoa(p.ID))
+	writeJSON(w, http.StatusCreated, p)
+}
+
+// MaxBulkProducts caps the number of items accepted by POST
+// /api/products/bulk; larger batches are rejected with 413.
+var MaxBulkProducts = 1000
+
+// bulkProductResult reports what happened to one item of a bulk create, in
+// request order: the new ID on success, the validation error otherwise.
+type bulkProductResult struct {
+	Index  int        `json:"index"`
+	Status int        `json:"status"`
+	ID     int        `json:"id,omitempty"`
+	Error  *errorBody `json:"error,omitempty"`
+}
+
+type bulkProductResponse struct {
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Results []bulkProductResult `json:"results"`
+}
+
+// Bulk handles POST /api/products/bulk. The body is a JSON array of the
+// objects accepted by Create. Each item is validated on its own and the
+// valid ones are stored in a single CreateMany call, so one bad item does
+// not fail the batch; the response is 207 with a result per item. A body
+// that is not a well-formed array of products is rejected as a whole.
+func (h *ProductHandlers) Bulk(w http.ResponseWriter, r *http.Request) {
+	var reqs []createProductRequest
+	if !DecodeJSON(w, r, &reqs) {
+		return
+	}
+	if len(reqs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "body must contain at least one product")
+		return
+	}
+	if len(reqs) > MaxBulkProducts {
+		writeJSONError(w, http.StatusRequestEntityTooLarge,
+			"at most "+strconv.Itoa(MaxBulkProducts)+" products may be created at once")
+		return
+	}
+
+	known, err := h.knownCategories(r.Context(), reqs...)
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+
+	resp := bulkProductResponse{Results: make([]bulkProductResult, len(reqs))}
+	valid := make([]*models.Product, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		p := req.product()
+		if err := validateProduct(p, known); err != nil {
+			body := validationErrorBody(err)
+			resp.Results[i] = bulkProductResult{Index: i, Status: http.StatusUnprocessableEntity, Error: &body}
+			resp.Failed++
+			continue
+		}
+		valid = append(valid, p)
+		indexes = append(indexes, i)
+	}
+	if len(valid) > 0 {
+		if err := h.Repo.CreateMany(r.Context(), valid); err != nil {
+			writeRepoError(w, r, err, "product")
+			return
+		}
+	}
+	for n, p := range valid {
+		i := indexes[n]
+		resp.Results[i] = bulkProductResult{Index: i, Status: http.StatusCreated, ID: p.ID}
+		resp.Created++
+		h.Audit.Record(r.Context(), audit.Create, "product", p.ID, nil, p)
+	}
+	writeJSON(w, http.StatusMultiStatus, resp)
+}
+
+// Get handles GET /api/products/{id}, as JSON or XML depending on the
+// Accept header, with an ETag honored by If-None-Match as for users.
+func (h *ProductHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "product")
+	if !ok {
+		return
+	}
+	p, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	respondCached(w, r, p)
+}
+
+// Update handles PUT /api/products/{id}, replacing the whole product with
+// the body: name and price are required, as for Create, and a stock or
+// category left out is reset to zero or none rather than kept. Use PATCH to
+// change some fields only. If-Match is honored as for users, so a client
+// that read the product first can make sure nobody changed it in between.
+func (h *ProductHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "product")
+	if !ok {
+		return
+	}
+	var req replaceProductRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	p, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	if !checkIfMatch(w, r, p) {
+		return
+	}
+	known, err := h.knownCategories(r.Context(), createProductRequest{Category: req.Category})
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+	// A placeholder price keeps a missing one from also failing on its
+	// currency
+	next := createProductRequest{Name: req.Name, Price: models.NewMoney(0, models.DefaultCurrency), Stock: req.Stock, Category: req.Category}
+	if req.Price != nil {
+		next.Price = *req.Price
+	}
+	replacement := next.product()
+	err = validateProduct(replacement, known)
+	if req.Price == nil {
+		err = addFieldError(err, "price", "is required")
+	}
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	before := *p
+	p.Name, p.Stock, p.Category = replacement.Name, replacement.Stock, replacement.Category
+	change, changed := p.UpdatePrice(replacement.Price)
+	if h.save(w, r, &before, p) && changed {
+		models.NotifyPriceChange(r.Context(), change)
+	}
+}
+
+// Patch handles PATCH /api/products/{id}, changing only the fields present
+// in the body. The result is validated as a whole, and If-Match is honored
+// as for Update.
+func (h *ProductHandlers) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "product")
+	if !ok {
+		return
+	}
+	var req patchProductRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	p, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	if !checkIfMatch(w, r, p) {
+		return
+	}
+	if req == (patchProductRequest{}) {
+		writeJSON(w, http.StatusOK, p)
+		return
+	}
+	known, err := h.knownCategories(r.Context(), req.categoryOf())
+	if err != nil {
+		writeRepoError(w, r, err, "category")
+		return
+	}
+	next := *p
+	if req.Name != nil {
+		next.Name = utils.SanitizeText(*req.Name)
+	}
+	if req.Stock != nil {
+		next.Stock = *req.Stock
+	}
+	if req.Category != nil {
+		next.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.Price != nil {
+		next.Price = *req.Price
+	}
+	// The category is only checked when it changes, so a product whose
+	// category has since gone can still be edited
+	err = Validate(&next)
+	if req.Category != nil {
+		err = validateProduct(&next, known)
+	}
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	before := *p
+	p.Name, p.Stock, p.Category = next.Name, next.Stock, next.Category
+	change, changed := p.UpdatePrice(next.Price)
+	if h.save(w, r, &before, p) && changed {
+		models.NotifyPriceChange(r.Context(), change)
+	}
+}
+
+// save stores p, changed from before, and responds with it and its new
+// ETag. It reports whether p was stored.
+func (h *ProductHandlers) save(w http.ResponseWriter, r *http.Request, before, p *models.Product) bool {
+	if err := h.Repo.Update(r.Context(), p); err != nil {
+		writeRepoError(w, r, err, "product")
+		return false
+	}
+	h.Audit.Record(r.Context(), audit.Update, "product", p.ID, before, p)
+	w.Header().Set("ETag", weakETag(p))
+	writeJSON(w, http.StatusOK, p)
+	return true
+}
+
+// List handles GET /api/products. It accepts q (case-insensitive name
+// substring), category (a category slug), min_price and max_price (decimal amounts in currency, default
+// USD), sort (name or price, optionally suffixed with :asc or :desc) and the
+// usual limit and offset.
+func (h *ProductHandlers) List(w http.ResponseWriter, r *http.Request) {
+	p, err := parsePageParams(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f, err := parseProductFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f.Limit, f.Offset = p.Limit, p.Offset
+	total, err := h.Repo.Count(r.Context(), f)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	products, err := h.Repo.List(r.Context(), f)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(derefAll(products), p, total))
+}
+
+// parseProductFilter reads the search and sort query parameters of
+// GET /api/products.
+func parseProductFilter(r *http.Request) (repository.ProductFilter, error) {
+	q := r.URL.Query()
+	f := repository.ProductFilter{Query: strings.TrimSpace(q.Get("q")), Category: strings.TrimSpace(q.Get("category"))}
+
+	currency := strings.ToUpper(strings.TrimSpace(q.Get("currency")))
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	var err error
+	if f.MinPrice, err = parsePrice(q.Get("min_price"), currency, "min_price"); err != nil {
+		return f, err
+	}
+	if f.MaxPrice, err = parsePrice(q.Get("max_price"), currency, "max_price"); err != nil {
+		return f, err
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && f.MinPrice.Amount > f.MaxPrice.Amount {
+		return f, errors.New("min_price must not be greater than max_price")
+	}
+
+	if s := q.Get("sort"); s != "" {
+		field, dir, _ := strings.Cut(s, ":")
+		switch repository.ProductSort(field) {
+		case repository.SortByName, repository.SortByPrice:
+			f.Sort = repository.ProductSort(field)
+		default:
+			return f, errors.New("sort must be name or price")
+		}
+		switch dir {
+		case "", "asc":
+		case "desc":
+			f.Desc = true
+		default:
+			return f, errors.New("sort direction must be asc or desc")
+		}
+	}
+	return f, nil
+}
+
+// parsePrice parses an optional decimal price query parameter in currency.
+// It returns nil when s is empty.
+func parsePrice(s, currency, name string) (*models.Money, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m, err := models.ParseMoney(s, currency)
+	if err != nil {
+		return nil, errors.New(name + " must be a decimal amount")
+	}
+	return &m, nil
+}
+
+// derefAll copies the values behind ps into a slice that is never nil, so an
+// empty result encodes as [] rather than null.
+func derefAll[T any](ps []*T) []T {
+	out := make([]T, len(ps))
+	for i, p := range ps {
+		out[i] = *p
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-project/internal/logging"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+// exportBatch is how many products ExportCSV and ExportJSON fetch from the
+// repository at a time. Only one batch is held in memory however large the
+// catalog.
+const exportBatch = 500
+
+// exportBatchWriteTimeout is how long the client gets to take each batch.
+// The server's WriteTimeout would otherwise cut off large exports, so the
+// exports push the write deadline back before every batch instead.
+const exportBatchWriteTimeout = 15 * time.Second
+
+// productCSVHeader names the columns written by ExportCSV.
+var productCSVHeader = []string{"id", "name", "price", "currency", "stock", "category", "created_at", "updated_at"}
+
+// ExportCSV handles GET /api/products.csv. It writes every product matching
+// the same filter and sort parameters as List, ignoring limit and offset, as
+// a CSV file with a header row. Rows are streamed in batches of
+// exportBatch, so do not put it behind TimeoutMiddleware, which buffers
+// the response.
+//
+// A repository error on the first batch gets the usual JSON error. Once
+// rows have been sent the status can no longer change, so a later error is
+// logged and the file ends early.
+func (h *ProductHandlers) ExportCSV(w http.ResponseWriter, r *http.Request) {
+	f, err := parseProductFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f.Limit = exportBatch
+	batch, err := h.Repo.List(r.Context(), f)
+	if err != nil {
+		writeRepoError(w, r, err, "product")
+		return
+	}
+
+	hdr := w.Header()
+	hdr.Set("Content-Type", "text/csv; charset=utf-8")
+	hdr.Set("Content-Disposition", `attachment; filename="products.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	cw := csv.NewWriter(w)
+	cw.Write(productCSVHeader)
+	for {
+		rc.SetWriteDeadline(time.Now().Add(exportBatchWriteTimeout))
+		for _, p := range batch {
+			cw.Write(productCSVRecord(p))
+		}
+		cw.Flush()
+		if cw.Error() != nil {
+			// The client went away.
+			return
+		}
+		rc.Flush()
+		if len(batch) < exportBatch {
+			return
+		}
+		f.Offset += len(batch)
+		if batch, err = h.Repo.List(r.Context(), f); err != nil {
+			logging.With(r.Context()).ErrorContext(r.Context(), "product export cut short",
+				slog.Int("rows", f.Offset),
+				slog.String("error", maskEmails(err.Error())),
+			)
+			return
+		}
+	}
+}
+
+// ExportJSON handles GET /api/products.json. It writes every product
+// matching the same filter and sort parameters as List, ignoring limit and
+// offset, as one JSON array. Like ExportCSV it streams the products in
+// batches of exportBatch rather than building the array in memory, so it
+// must not be put behind TimeoutMiddleware either.
+func (h *ProductHandlers) ExportJSON(w http.ResponseWriter, r *http.Request) {
+	f, err := parseProductFilter(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	streamJSONArray(w, r, repository.EachProduct(r.Context(), h.Repo, f, exportBatch), "product")
+}
+
+// streamJSONArray writes the items of seq as a JSON array, encoding each as
+// it is yielded and flushing every exportBatch items. An error before the
+// first item gets the usual JSON error for resource. A later one is logged
+// and the response aborted with http.ErrAbortHandler: closing the array
+// would pass a cut-short list off as complete, whereas a broken chunked
+// body fails in the client's JSON parser or HTTP library.
+func streamJSONArray[T any](w http.ResponseWriter, r *http.Request, seq iter.Seq2[T, error], resource string) {
+	next, stop := iter.Pull2(seq)
+	defer stop()
+	item, err, more := next()
+	if err != nil {
+		writeRepoError(w, r, err, resource)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	for n := 0; more; n++ {
+		if n%exportBatch == 0 {
+			rc.SetWriteDeadline(time.Now().Add(exportBatchWriteTimeout))
+		}
+		if n > 0 {
+			bw.WriteByte(',')
+		}
+		b, err := json.Marshal(item)
+		if err != nil {
+			streamFailed(r, n, err)
+		}
+		bw.Write(b)
+		if n%exportBatch == exportBatch-1 {
+			if bw.Flush() != nil {
+				// The client went away.
+				return
+			}
+			rc.Flush()
+		}
+		if item, err, more = next(); err != nil {
+			bw.Flush()
+			streamFailed(r, n+1, err)
+		}
+	}
+	bw.WriteString("]\n")
+	bw.Flush()
+}
+
+// streamFailed logs why streamJSONArray gave up after sent items and
+// aborts the response.
+func streamFailed(r *http.Request, sent int, err error) {
+	logging.With(r.Context()).ErrorContext(r.Context(), "JSON stream cut short",
+		slog.String("path", maskPath(r.URL.Path)),
+		slog.Int("items", sent),
+		slog.String("error", maskEmails(err.Error())),
+	)
+	panic(http.ErrAbortHandler)
+}
+
+// productCSVRecord formats p as a row under productCSVHeader.
+func productCSVRecord(p *models.Product) []string {
+	return []string{
+		strconv.Itoa(p.ID),
+		csvText(p.Name),
+		p.Price.Decimal(),
+		p.Price.Currency,
+		strconv.Itoa(p.Stock),
+		csvText(p.Category),
+		p.CreatedAt.UTC().Format(time.RFC3339),
+		p.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// csvText guards a free-text cell against spreadsheet formula injection:
+// a value starting with = + - @ or a tab or carriage return would be
+// evaluated as a formula when the file is opened, so it is prefixed with a
+// single quote. encoding/csv takes care of commas, quotes and newlines.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
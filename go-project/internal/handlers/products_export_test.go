@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func exportCSV(t *testing.T, repo repository.ProductRepository, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	rt := NewRouter()
+	rt.Get("/api/products.csv", http.HandlerFunc(NewProductHandlers(repo).ExportCSV))
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/products.csv"+query, nil))
+	return rec
+}
+
+// readCSV parses rec's body, failing the test if it is not valid CSV.
+func readCSV(t *testing.T, rec *httptest.ResponseRecorder) [][]string {
+	t.Helper()
+	rows, err := csv.NewReader(strings.NewReader(rec.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("body is not CSV: %v\n%s", err, rec.Body)
+	}
+	return rows
+}
+
+func TestExportProductsCSV(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	widget := seedProduct(t, repo, "Widget, large", "12.5")
+	seedProduct(t, repo, `Gadget "Pro"`, "3")
+	seedProduct(t, repo, "=HYPERLINK(\"http://evil.example\")", "1")
+
+	rec := exportCSV(t, repo, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "products.csv") {
+		t.Errorf("Content-Disposition = %q, want an attachment named products.csv", cd)
+	}
+	if !strings.Contains(rec.Body.String(), `"Widget, large"`) {
+		t.Errorf("name with a comma is not quoted:\n%s", rec.Body)
+	}
+
+	rows := readCSV(t, rec)
+	if len(rows) != 4 || !reflect.DeepEqual(rows[0], productCSVHeader) {
+		t.Fatalf("rows = %q, want the header and 3 products", rows)
+	}
+	want := []string{strconv.Itoa(widget.ID), "Widget, large", "12.50", "USD", "0", ""}
+	if got := rows[1][:len(want)]; !reflect.DeepEqual(got, want) {
+		t.Errorf("first row = %q, want it to start %q", rows[1], want)
+	}
+	if rows[2][1] != `Gadget "Pro"` {
+		t.Errorf("name with quotes = %q", rows[2][1])
+	}
+	if !strings.HasPrefix(rows[3][1], "'=") {
+		t.Errorf("formula-like name = %q, want it prefixed with a quote", rows[3][1])
+	}
+}
+
+func TestExportProductsCSVRespectsFilters(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	seedProduct(t, repo, "Red widget", "5")
+	seedProduct(t, repo, "Blue widget", "20")
+	seedProduct(t, repo, "Gadget", "7")
+
+	rows := readCSV(t, exportCSV(t, repo, "?q=widget&sort=price:desc&limit=1"))
+	var names []string
+	for _, row := range rows[1:] {
+		names = append(names, row[1])
+	}
+	if want := []string{"Blue widget", "Red widget"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %q, want %q (limit is ignored)", names, want)
+	}
+
+	rec := exportCSV(t, repo, "?min_price=abc")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad filter: status = %d, want 400", rec.Code)
+	}
+	if got := decodeErrorEnvelope(t, rec); got.Code != CodeInvalidRequest {
+		t.Errorf("bad filter code = %q, want %q", got.Code, CodeInvalidRequest)
+	}
+}
+
+// windowRecorder records the windows List was asked for.
+type windowRecorder struct {
+	repository.ProductRepository
+	calls, maxLimit int
+	unbounded       bool
+}
+
+func (w *windowRecorder) List(ctx context.Context, f repository.ProductFilter) ([]*models.Product, error) {
+	w.calls++
+	w.unbounded = w.unbounded || f.Limit <= 0
+	w.maxLimit = max(w.maxLimit, f.Limit)
+	return w.ProductRepository.List(ctx, f)
+}
+
+func TestExportProductsCSVStreamsInBatches(t *testing.T) {
+	inner := repository.NewInMemoryProductRepo()
+	n := 2*exportBatch + 3
+	for i := range n {
+		seedProduct(t, inner, "Product "+strconv.Itoa(i), "1")
+	}
+	repo := &windowRecorder{ProductRepository: inner}
+
+	rows := readCSV(t, exportCSV(t, repo, ""))
+	if len(rows) != n+1 {
+		t.Fatalf("got %d rows, want the header and %d products", len(rows), n)
+	}
+	seen := map[string]bool{}
+	for _, row := range rows[1:] {
+		if seen[row[0]] {
+			t.Fatalf("product %s exported twice", row[0])
+		}
+		seen[row[0]] = true
+	}
+	if repo.unbounded || repo.maxLimit != exportBatch || repo.calls != 3 {
+		t.Errorf("List called %d times with limits up to %d, want 3 batches of at most %d", repo.calls, repo.maxLimit, exportBatch)
+	}
+}
+
+func exportJSON(t *testing.T, repo repository.ProductRepository, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	rt := NewRouter()
+	rt.Get("/api/products.json", http.HandlerFunc(NewProductHandlers(repo).ExportJSON))
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/products.json"+query, nil))
+	return rec
+}
+
+func TestExportProductsJSONMatchesBufferedList(t *testing.T) {
+	inner := repository.NewInMemoryProductRepo()
+	n := 2*exportBatch + 3
+	for i := range n {
+		seedProduct(t, inner, "Product \""+strconv.Itoa(i)+"\"", "1.5")
+	}
+	repo := &windowRecorder{ProductRepository: inner}
+
+	rec := exportJSON(t, repo, "?sort=name:desc")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	all, _ := inner.List(context.Background(), repository.ProductFilter{Sort: repository.SortByName, Desc: true})
+	buffered, _ := json.Marshal(derefAll(all))
+
+	var got, want []any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("streamed body is not JSON: %v", err)
+	}
+	json.Unmarshal(buffered, &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed array of %d products differs from the buffered array of %d", len(got), len(want))
+	}
+	if repo.unbounded || repo.maxLimit != exportBatch || repo.calls != 3 {
+		t.Errorf("List called %d times with limits up to %d, want 3 batches of at most %d", repo.calls, repo.maxLimit, exportBatch)
+	}
+
+	if body := exportJSON(t, repository.NewInMemoryProductRepo(), "").Body.String(); body != "[]\n" {
+		t.Errorf("empty catalog body = %q, want an empty array", body)
+	}
+}
+
+// flakyProductRepo is a ProductRepository whose List fails after ok calls.
+type flakyProductRepo struct {
+	repository.ProductRepository
+	ok, calls int
+}
+
+func (r *flakyProductRepo) List(ctx context.Context, f repository.ProductFilter) ([]*models.Product, error) {
+	if r.calls++; r.calls > r.ok {
+		return nil, errors.New("connection reset by peer")
+	}
+	return r.ProductRepository.List(ctx, f)
+}
+
+func TestExportProductsJSONRepositoryErrors(t *testing.T) {
+	inner := repository.NewInMemoryProductRepo()
+	for i := range exportBatch + 1 {
+		seedProduct(t, inner, "Product "+strconv.Itoa(i), "1")
+	}
+
+	// Before anything is sent the failure gets a JSON error
+	rec := exportJSON(t, &flakyProductRepo{ProductRepository: inner}, "")
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	decodeErrorEnvelope(t, rec)
+
+	// Afterwards the response is cut off rather than closed as if complete
+	srv := httptest.NewServer(http.HandlerFunc(NewProductHandlers(&flakyProductRepo{ProductRepository: inner, ok: 1}).ExportJSON))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		t.Fatalf("body read completely after a mid-stream error, want a broken stream; body ends %q", body[max(0, len(body)-20):])
+	}
+	if json.Valid(body) {
+		t.Error("cut-off body is valid JSON, want an unterminated array")
+	}
+	if !strings.HasPrefix(string(body), `[{"id":`) {
+		t.Errorf("body starts %q, want the products sent before the error", body[:min(len(body), 20)])
+	}
+}
+
+// generatedProducts is a ProductRepository of n products made up on each
+// List, so benchmarks measure the encoding rather than a store.
+type generatedProducts struct {
+	repository.ProductRepository
+	n int
+}
+
+func (g generatedProducts) List(ctx context.Context, f repository.ProductFilter) ([]*models.Product, error) {
+	end := g.n
+	if f.Limit > 0 {
+		end = min(end, f.Offset+f.Limit)
+	}
+	var ps []*models.Product
+	for i := f.Offset; i < end; i++ {
+		p := models.NewProduct("Product "+strconv.Itoa(i), models.Money{Amount: int64(i), Currency: "USD"})
+		p.ID = i + 1
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// discardResponse is a flushable http.ResponseWriter that drops the body.
+type discardResponse struct{ header http.Header }
+
+func (d discardResponse) Header() http.Header         { return d.header }
+func (d discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (d discardResponse) WriteHeader(int)             {}
+func (d discardResponse) Flush()                      {}
+
+// BenchmarkExportProductsJSON compares ExportJSON with marshaling the whole
+// catalog at once, as List does with a page. Run with -benchmem: the
+// buffered version allocates the full slice and the full encoded body,
+// which grow with the catalog, while the streamed one holds a batch.
+func BenchmarkExportProductsJSON(b *testing.B) {
+	repo := generatedProducts{n: 100_000}
+	r := httptest.NewRequest(http.MethodGet, "/api/products.json", nil)
+
+	b.Run("buffered", func(b *testing.B) {
+		for range b.N {
+			all, _ := repo.List(r.Context(), repository.ProductFilter{})
+			writeJSON(discardResponse{http.Header{}}, http.StatusOK, derefAll(all))
+		}
+	})
+	b.Run("streamed", func(b *testing.B) {
+		h := NewProductHandlers(repo)
+		for range b.N {
+			h.ExportJSON(discardResponse{http.Header{}}, r)
+		}
+	})
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func newProductTestRouter(repo repository.ProductRepository) *Router {
+	h := NewProductHandlers(repo)
+	rt := NewRouter()
+	rt.Post("/api/products", http.HandlerFunc(h.Create))
+	rt.Post("/api/products/bulk", http.HandlerFunc(h.Bulk))
+	rt.Get("/api/products", http.HandlerFunc(h.List))
+	rt.Get("/api/products/{id}", http.HandlerFunc(h.Get))
+	rt.Put("/api/products/{id}", http.HandlerFunc(h.Update))
+	rt.Patch("/api/products/{id}", http.HandlerFunc(h.Patch))
+	return rt
+}
+
+func usd(s string) models.Money {
+	m, err := models.ParseMoney(s, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func seedProduct(t *testing.T, repo repository.ProductRepository, name string, price string) *models.Product {
+	t.Helper()
+	p := models.NewProduct(name, usd(price))
+	if err := repo.Create(context.Background(), p); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return p
+}
+
+func TestProductHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		wantStatus   int
+		wantBody     string
+	}{
+		{name: "create", method: "POST", path: "/api/products", body: `{"name":"Gadget","price":3.5}`,
+			wantStatus: http.StatusCreated, wantBody: `"name":"Gadget"`},
+		{name: "create strips markup from name", method: "POST", path: "/api/products", body: `{"name":"<img src=x onerror=alert(1)>Gadget","price":3.5}`,
+			wantStatus: http.StatusCreated, wantBody: `"name":"Gadget"`},
+		{name: "create negative price", method: "POST", path: "/api/products", body: `{"name":"Gadget","price":-1}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"price"`},
+		{name: "get", method: "GET", path: "/api/products/1", wantStatus: http.StatusOK, wantBody: `"name":"Widget"`},
+		{name: "get unknown", method: "GET", path: "/api/products/99", wantStatus: http.StatusNotFound,
+			wantBody: `"product not found"`},
+		{name: "get bad id", method: "GET", path: "/api/products/abc", wantStatus: http.StatusBadRequest,
+			wantBody: `"invalid product id"`},
+		{name: "update", method: "PUT", path: "/api/products/1", body: `{"name":"Widget","price":"11.00","stock":4}`,
+			wantStatus: http.StatusOK, wantBody: `"price":{"amount":"11.00","currency":"USD"},"stock":4`},
+		{name: "update unknown", method: "PUT", path: "/api/products/99", body: `{"name":"Widget","price":1}`,
+			wantStatus: http.StatusNotFound, wantBody: `"product not found"`},
+		{name: "update negative price", method: "PUT", path: "/api/products/1", body: `{"name":"Widget","price":-1}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"price"`},
+		{name: "update without price", method: "PUT", path: "/api/products/1", body: `{"name":"Widget","stock":4}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"fields":[{"field":"price","message":"is required"}]`},
+		{name: "update without name or price", method: "PUT", path: "/api/products/1", body: `{}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"field":"name","message":"is required"},{"field":"price","message":"is required"}`},
+		{name: "update null price", method: "PUT", path: "/api/products/1", body: `{"name":"Widget","price":null}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"price","message":"is required"`},
+		{name: "patch price", method: "PATCH", path: "/api/products/1", body: `{"price":"4.25"}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Widget","price":{"amount":"4.25","currency":"USD"},"stock":3`},
+		{name: "patch name", method: "PATCH", path: "/api/products/1", body: `{"name":"Widget Pro"}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Widget Pro","price":{"amount":"9.99","currency":"USD"}`},
+		{name: "patch nothing", method: "PATCH", path: "/api/products/1", body: `{}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Widget"`},
+		{name: "patch empty name", method: "PATCH", path: "/api/products/1", body: `{"name":""}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"name"`},
+		{name: "patch negative stock", method: "PATCH", path: "/api/products/1", body: `{"stock":-2}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"stock"`},
+		{name: "patch unknown", method: "PATCH", path: "/api/products/99", body: `{"price":1}`,
+			wantStatus: http.StatusNotFound, wantBody: `"product not found"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewInMemoryProductRepo()
+			p := seedProduct(t, repo, "Widget", "9.99")
+			p.Stock = 3
+			repo.Update(context.Background(), p)
+			rec := httptest.NewRecorder()
+			newProductTestRouter(repo).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %s does not contain %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestProductPutReplacesAndPatchMerges(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	rt := newCategoryTestRouter(repo)
+	reset := func(t *testing.T) *models.Product {
+		t.Helper()
+		p, _ := repo.Get(context.Background(), 1)
+		if p == nil {
+			p = models.NewProduct("", models.Money{})
+		}
+		p.Name, p.Price, p.Stock, p.Category = "Widget", usd("9.99"), 7, "books"
+		if p.ID == 0 {
+			if err := repo.Create(context.Background(), p); err != nil {
+				t.Fatal(err)
+			}
+		} else if err := repo.Update(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	send := func(t *testing.T, method, body string) *models.Product {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(method, "/api/products/1", strings.NewReader(body)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: status = %d (body %s)", method, body, rec.Code, rec.Body)
+		}
+		p, _ := repo.Get(context.Background(), 1)
+		return p
+	}
+
+	t.Run("PUT resets fields left out", func(t *testing.T) {
+		reset(t)
+		p := send(t, http.MethodPut, `{"name":"Widget 2","price":"12.00"}`)
+		if p.Name != "Widget 2" || p.Price != usd("12.00") || p.Stock != 0 || p.Category != "" {
+			t.Errorf("after PUT: %+v, want the new name and price with no stock or category", p)
+		}
+	})
+	t.Run("PUT without price changes nothing", func(t *testing.T) {
+		before := reset(t)
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/products/1", strings.NewReader(`{"name":"Widget 2","stock":1}`)))
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status = %d, want 422", rec.Code)
+		}
+		if p, _ := repo.Get(context.Background(), 1); p.Name != before.Name || p.Price != before.Price || p.Stock != before.Stock {
+			t.Errorf("rejected PUT changed the product: %+v", p)
+		}
+	})
+	t.Run("PATCH price leaves the rest", func(t *testing.T) {
+		reset(t)
+		p := send(t, http.MethodPatch, `{"price":"8.50"}`)
+		if p.Name != "Widget" || p.Price != usd("8.50") || p.Stock != 7 || p.Category != "books" {
+			t.Errorf("after PATCH: %+v, want only the price changed", p)
+		}
+	})
+	t.Run("PATCH category", func(t *testing.T) {
+		reset(t)
+		if p := send(t, http.MethodPatch, `{"category":"garden"}`); p.Category != "garden" || p.Stock != 7 {
+			t.Errorf("after moving category: %+v", p)
+		}
+		if p := send(t, http.MethodPatch, `{"category":""}`); p.Category != "" {
+			t.Errorf("empty category did not clear it: %+v", p)
+		}
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/api/products/1", strings.NewReader(`{"category":"toys"}`)))
+		if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"field":"category"`) {
+			t.Errorf("unknown category: status = %d (body %s), want 422", rec.Code, rec.Body)
+		}
+	})
+}
+
+// failingUpdateRepo is a ProductRepository whose Update fails with err.
+type failingUpdateRepo struct {
+	repository.ProductRepository
+	err error
+}
+
+func (r failingUpdateRepo) Update(ctx context.Context, p *models.Product) error {
+	return r.err
+}
+
+func TestProductPriceChangeIsPublishedOnlyOnceSaved(t *testing.T) {
+	var events []models.PriceChange
+	models.OnPriceChange(func(_ context.Context, c models.PriceChange) { events = append(events, c) })
+	t.Cleanup(func() { models.OnPriceChange(nil) })
+
+	inner := repository.NewInMemoryProductRepo()
+	seedProduct(t, inner, "Widget", "5")
+	send := func(t *testing.T, repo repository.ProductRepository, method, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/products/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		newProductTestRouter(repo).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	failing := failingUpdateRepo{ProductRepository: inner, err: repository.ErrUnavailable}
+	if code := send(t, failing, http.MethodPut, `{"name":"Widget","price":"6"}`); code != http.StatusServiceUnavailable {
+		t.Fatalf("PUT with a failing save: status = %d, want 503", code)
+	}
+	if code := send(t, failing, http.MethodPatch, `{"price":"7"}`); code != http.StatusServiceUnavailable {
+		t.Fatalf("PATCH with a failing save: status = %d, want 503", code)
+	}
+	if len(events) != 0 {
+		t.Fatalf("price changes that were never saved were published: %+v", events)
+	}
+
+	if code := send(t, inner, http.MethodPatch, `{"price":"7"}`); code != http.StatusOK {
+		t.Fatalf("PATCH: status = %d, want 200", code)
+	}
+	if len(events) != 1 || events[0].NewPrice != usd("7") || events[0].OldPrice != usd("5") {
+		t.Fatalf("events = %+v, want one change from 5.00 to 7.00", events)
+	}
+}
+
+func TestProductHandlersSearch(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	seedProduct(t, repo, "Blue Widget", "9.99") // 1
+	seedProduct(t, repo, "gadget", "25")        // 2
+	seedProduct(t, repo, "Red widget", "4.50")  // 3
+	seedProduct(t, repo, "Widget Deluxe", "25") // 4
+	rt := newProductTestRouter(repo)
+
+	tests := []struct {
+		query     string
+		wantIDs   []int
+		wantTotal int
+	}{
+		{"", []int{1, 2, 3, 4}, 4},
+		{"?q=WIDGET", []int{1, 3, 4}, 3},
+		{"?min_price=5&max_price=25", []int{1, 2, 4}, 3},
+		{"?q=widget&max_price=9.99", []int{1, 3}, 2},
+		{"?sort=price", []int{3, 1, 2, 4}, 4},
+		{"?sort=price:desc", []int{2, 4, 1, 3}, 4},
+		{"?sort=name:asc", []int{1, 2, 3, 4}, 4},
+		{"?sort=name:desc&limit=2", []int{4, 3}, 4},
+		{"?q=sprocket", []int{}, 0},
+		{"?min_price=100", []int{}, 0},
+		{"?min_price=1&currency=eur", []int{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/products"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+			}
+			var page pageResponse[models.Product]
+			if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			ids := []int{}
+			for _, p := range page.Data {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			if page.Data == nil {
+				t.Error("data is null, want an empty array")
+			}
+			if page.Pagination.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", page.Pagination.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProductHandlersSearchRejectsBadParams(t *testing.T) {
+	rt := newProductTestRouter(repository.NewInMemoryProductRepo())
+	for _, q := range []string{
+		"min_price=10&max_price=5",
+		"min_price=cheap",
+		"max_price=NaN",
+		"max_price=9.999",
+		"sort=rating",
+		"sort=price:sideways",
+		"limit=-1",
+	} {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/products?"+q, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("?%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestProductHandlersBulkMixedBatch(t *testing.T) {
+	repo := repository.NewInMemoryProductRepo()
+	seedProduct(t, repo, "Widget", "9.99") // 1
+	body := `[
+		{"name":"Gadget","price":3.5,"stock":2},
+		{"name":"","price":1},
+		{"name":"Sprocket","price":0.25},
+		{"name":"Gizmo","price":-1}
+	]`
+	rec := httptest.NewRecorder()
+	newProductTestRouter(repo).ServeHTTP(rec, httptest.NewRequest("POST", "/api/products/bulk", strings.NewReader(body)))
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207 (body %s)", rec.Code, rec.Body.String())
+	}
+	var resp bulkProductResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Created != 2 || resp.Failed != 2 {
+		t.Errorf("created, failed = %d, %d, want 2, 2", resp.Created, resp.Failed)
+	}
+	want := []struct {
+		status int
+		id     int
+		field  string
+	}{
+		{http.StatusCreated, 2, ""},
+		{http.StatusUnprocessableEntity, 0, "name"},
+		{http.StatusCreated, 3, ""},
+		{http.StatusUnprocessableEntity, 0, "price"},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(want))
+	}
+	for i, w := range want {
+		got := resp.Results[i]
+		if got.Index != i || got.Status != w.status || got.ID != w.id {
+			t.Errorf("result %d = %+v, want index %d status %d id %d", i, got, i, w.status, w.id)
+		}
+		if w.field == "" {
+			if got.Error != nil {
+				t.Errorf("result %d has error %+v", i, got.Error)
+			}
+			continue
+		}
+		if got.Error == nil || len(got.Error.Fields) == 0 || got.Error.Fields[0].Field != w.field {
+			t.Errorf("result %d error = %+v, want a %q field error", i, got.Error, w.field)
+		}
+	}
+
+	if p, err := repo.Get(context.Background(), 2); err != nil || p.Name != "Gadget" || p.Stock != 2 {
+		t.Errorf("product 2 = %+v, %v, want the stored Gadget", p, err)
+	}
+	if n, _ := repo.Count(context.Background(), repository.ProductFilter{}); n != 3 {
+		t.Errorf("stored products = %d, want 3", n)
+	}
+}
+
+func TestProductHandlersBulkRejectsBatch(t *testing.T) {
+	defer func(n int) { MaxBulkProducts = n }(MaxBulkProducts)
+	MaxBulkProducts = 2
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"too many", `[{"name":"A","price":1},{"name":"B","price":1},{"name":"C","price":1}]`, http.StatusRequestEntityTooLarge},
+		{"empty", `[]`, http.StatusBadRequest},
+		{"object", `{"name":"A","price":1}`, http.StatusBadRequest},
+		{"unknown field", `[{"name":"A","price":1,"colour":"red"}]`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewInMemoryProductRepo()
+			rec := httptest.NewRecorder()
+			newProductTestRouter(repo).ServeHTTP(rec, httptest.NewRequest("POST", "/api/products/bulk", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if n, _ := repo.Count(context.Background(), repository.ProductFilter{}); n != 0 {
+				t.Errorf("stored %d products from a rejected batch", n)
+			}
+		})
+	}
+}
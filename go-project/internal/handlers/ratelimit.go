@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"go-project/internal/auth"
+)
+
+// limiterTTL is how long a client's bucket is kept after its last request.
+const limiterTTL = 3 * time.Minute
+
+// RateLimitMiddleware limits each client IP to rps requests per second with
+// bursts of up to burst requests. Clients over the limit get 429 Too Many
+// Requests with a Retry-After header. Idle buckets are evicted in the
+// background so memory stays bounded by the number of recently active IPs;
+// eviction stops once ctx is done, such as on shutdown.
+func RateLimitMiddleware(ctx context.Context, rps float64, burst int) Middleware {
+	l := newIPRateLimiter(rate.Limit(rps), burst, limiterTTL)
+	go l.janitor(ctx)
+	return l.middleware
+}
+
+// UserRateLimitMiddleware is RateLimitMiddleware keyed on identity, run
+// around authenticate, such as OptionalJWTMiddleware: a request that comes
+// out of it with a user ID from auth.UserIDFromContext gets that user's
+// bucket of userRPS and userBurst, so users behind one NAT do not share a
+// limit, while anonymous requests share their client IP's bucket of rps
+// and burst. Requests authenticate rejects, such as guessed tokens, are
+// counted against their IP's bucket too; once it is empty, every request
+// from the IP is refused before authentication, users' included. As with RateLimitMiddleware, idle buckets are evicted until
+// ctx is done.
+func UserRateLimitMiddleware(ctx context.Context, authenticate Middleware, rps float64, burst int, userRPS float64, userBurst int) Middleware {
+	l := newUserRateLimiter(rate.Limit(rps), burst, rate.Limit(userRPS), userBurst)
+	go l.anonymous.janitor(ctx)
+	go l.users.janitor(ctx)
+	return l.around(authenticate)
+}
+
+type ipRateLimiter struct {
+	limit rate.Limit
+	burst int
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*clientLimiter
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newIPRateLimiter(limit rate.Limit, burst int, ttl time.Duration) *ipRateLimiter {
+	return &ipRateLimiter{
+		limit:   limit,
+		burst:   burst,
+		ttl:     ttl,
+		clients: make(map[string]*clientLimiter),
+	}
+}
+
+func (l *ipRateLimiter) get(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter
+}
+
+// evictIdle drops buckets not used within the TTL.
+func (l *ipRateLimiter) evictIdle(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > l.ttl {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// janitor evicts idle buckets every third of the TTL until ctx is done.
+func (l *ipRateLimiter) janitor(ctx context.Context) {
+	t := time.NewTicker(l.ttl / 3)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			l.evictIdle(now)
+		}
+	}
+}
+
+func (l *ipRateLimiter) middleware(next http.Handler) http.Handler {
+	return limitBy(next, func(r *http.Request, now time.Time) *rate.Limiter {
+		return l.get(clientIP(r), now)
+	})
+}
+
+// userRateLimiter keeps a bucket per authenticated user apart from the
+// per-IP buckets of anonymous clients.
+type userRateLimiter struct {
+	anonymous, users *ipRateLimiter
+}
+
+func newUserRateLimiter(limit rate.Limit, burst int, userLimit rate.Limit, userBurst int) *userRateLimiter {
+	return &userRateLimiter{
+		anonymous: newIPRateLimiter(limit, burst, limiterTTL),
+		users:     newIPRateLimiter(userLimit, userBurst, limiterTTL),
+	}
+}
+
+// countedKey is the context key of the flag middleware sets once it has
+// counted a request against a bucket.
+type countedKey struct{}
+
+// around limits requests through authenticate as UserRateLimitMiddleware
+// describes.
+func (l *userRateLimiter) around(authenticate Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		return l.guard(authenticate(l.middleware(next)))
+	}
+}
+
+// guard refuses requests before they are authenticated while the client
+// IP's bucket is empty, and counts those authenticate rejected, which never
+// reach middleware, against it afterwards.
+func (l *userRateLimiter) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		lim := l.anonymous.get(clientIP(r), now)
+		if lim.TokensAt(now) < 1 {
+			w.Header().Set("Retry-After", retryAfterSeconds(lim, now))
+			writeJSONError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		counted := new(bool)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), countedKey{}, counted)))
+		if !*counted {
+			lim.Allow()
+		}
+	})
+}
+
+// middleware counts an authenticated request against its user's bucket,
+// and an anonymous one against its IP's.
+func (l *userRateLimiter) middleware(next http.Handler) http.Handler {
+	return limitBy(next, func(r *http.Request, now time.Time) *rate.Limiter {
+		if counted, ok := r.Context().Value(countedKey{}).(*bool); ok {
+			*counted = true
+		}
+		if id, ok := auth.UserIDFromContext(r.Context()); ok {
+			return l.users.get(strconv.Itoa(id), now)
+		}
+		return l.anonymous.get(clientIP(r), now)
+	})
+}
+
+// limitBy answers 429 with a Retry-After header when the bucket picked for
+// a request has no token left, and passes it to next otherwise.
+func limitBy(next http.Handler, bucket func(r *http.Request, now time.Time) *rate.Limiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		lim := bucket(r, now)
+		if !lim.AllowN(now, 1) {
+			w.Header().Set("Retry-After", retryAfterSeconds(lim, now))
+			writeJSONError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// retryAfterSeconds returns how long until lim has a token, rounded up to
+// whole seconds as Retry-After requires.
+func retryAfterSeconds(lim *rate.Limiter, now time.Time) string {
+	res := lim.ReserveN(now, 1)
+	delay := res.DelayFrom(now)
+	res.CancelAt(now)
+	secs := int(math.Ceil(delay.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.Itoa(secs)
+}
+
+// clientIP returns the first hop of X-Forwarded-For when present, otherwise
+// the host part of RemoteAddr.
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		first, _, _ := strings.Cut(xff, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
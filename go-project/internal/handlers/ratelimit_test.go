@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"go-project/internal/auth"
+)
+
+func TestRateLimitMiddlewareReturns429AfterBurst(t *testing.T) {
+	const burst = 5
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := Chain(http.HandlerFunc(DataHandler), RateLimitMiddleware(ctx, 0.1, burst))
+
+	for i := 0; i < burst; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request %d: status = %d, want 429", burst+1, rec.Code)
+	}
+	secs, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || secs < 1 {
+		t.Fatalf("Retry-After = %q, want positive seconds", rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimitMiddlewareKeysByClientIP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := Chain(http.HandlerFunc(DataHandler), RateLimitMiddleware(ctx, 0.01, 1))
+
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+		r.Header.Set("X-Forwarded-For", ip+", 10.0.0.1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("first request from %s: status = %d, want 200", ip, rec.Code)
+		}
+	}
+}
+
+func TestUserRateLimitMiddlewareKeysByUser(t *testing.T) {
+	// One request for anonymous clients per IP, two for each user
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := Chain(http.HandlerFunc(DataHandler), UserRateLimitMiddleware(ctx, passThrough, 0.01, 1, 0.01, 2))
+	get := func(userID int) int {
+		r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+		r.RemoteAddr = "203.0.113.9:4000" // the same NAT for everyone
+		if userID != 0 {
+			r = r.WithContext(auth.WithUserID(r.Context(), userID))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	for _, id := range []int{1, 2} {
+		for i := range 2 {
+			if code := get(id); code != http.StatusOK {
+				t.Fatalf("request %d of user %d: status = %d, want 200 from the user's own bucket", i+1, id, code)
+			}
+		}
+	}
+	if code := get(1); code != http.StatusTooManyRequests {
+		t.Fatalf("third request of user 1: status = %d, want 429", code)
+	}
+
+	// The users did not use up the IP's anonymous bucket, which anonymous
+	// requests share
+	if code := get(0); code != http.StatusOK {
+		t.Fatalf("first anonymous request: status = %d, want 200", code)
+	}
+	if code := get(0); code != http.StatusTooManyRequests {
+		t.Fatalf("second anonymous request from the same IP: status = %d, want 429", code)
+	}
+}
+
+// passThrough stands in for authentication that has already happened.
+func passThrough(next http.Handler) http.Handler { return next }
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		xff, remote, want string
+	}{
+		{remote: "192.0.2.10:5555", want: "192.0.2.10"},
+		{xff: "198.51.100.7", remote: "10.0.0.1:80", want: "198.51.100.7"},
+		{xff: " 198.51.100.7 , 10.0.0.2", remote: "10.0.0.1:80", want: "198.51.100.7"},
+		{remote: "[2001:db8::1]:443", want: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remote
+		if tt.xff != "" {
+			r.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if got := clientIP(r); got != tt.want {
+			t.Errorf("clientIP(xff=%q, remote=%q) = %q, want %q", tt.xff, tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestIPRateLimiterEvictsIdleClients(t *testing.T) {
+	l := newIPRateLimiter(rate.Limit(1), 1, time.Minute)
+	start := time.Now()
+	l.get("a", start)
+	l.get("b", start.Add(50*time.Second))
+
+	l.evictIdle(start.Add(90 * time.Second))
+
+	if _, ok := l.clients["a"]; ok {
+		t.Error("idle client a was not evicted")
+	}
+	if _, ok := l.clients["b"]; !ok {
+		t.Error("recent client b was evicted")
+	}
+}
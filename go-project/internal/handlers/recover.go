@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware turns a panic in a downstream handler into a 500
+// response so one bad request cannot take the process down. The panic value
+// and stack trace are logged. http.ErrAbortHandler is re-panicked so net/http
+// can abort the response as documented. A nil logger uses slog.Default().
+func RecoverMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger.ErrorContext(r.Context(), "panic serving request",
+					slog.String("method", r.Method),
+					slog.String("path", maskPath(r.URL.Path)),
+					slog.String("panic", maskEmails(fmt.Sprint(v))),
+					slog.String("stack", string(debug.Stack())),
+				)
+				writeJSONError(w, http.StatusInternalServerError, "internal server error")
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverMiddlewareReturns500AndKeepsServing(t *testing.T) {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.HandleFunc("/boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("something broke")
+	})
+	mux.HandleFunc("/ok", DataHandler)
+	srv := httptest.NewServer(Chain(mux, RecoverMiddleware(slog.New(slog.NewTextHandler(&logs, nil)))))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/boom")
+	if err != nil {
+		t.Fatalf("GET /boom: %v", err)
+	}
+	var body errorEnvelope
+	json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+	if body.Error.Code != CodeInternal || body.Error.Message != "internal server error" {
+		t.Fatalf("body = %v", body)
+	}
+	if !strings.Contains(logs.String(), "something broke") || !strings.Contains(logs.String(), "goroutine") {
+		t.Errorf("panic value or stack missing from log: %s", logs.String())
+	}
+
+	resp, err = http.Get(srv.URL + "/ok")
+	if err != nil {
+		t.Fatalf("server did not survive the panic: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("follow-up status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestRecoverMiddlewareMasksEmails(t *testing.T) {
+	var logs bytes.Buffer
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("no user ada@example.com")
+	}), RecoverMiddleware(slog.New(slog.NewJSONHandler(&logs, nil))))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/by-email/ada@example.com", nil))
+
+	if strings.Contains(logs.String(), "ada@") {
+		t.Fatalf("log leaks the email: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "a*a@example.com") {
+		t.Errorf("log does not have the masked email: %s", logs.String())
+	}
+}
+
+func TestRecoverMiddlewareRepanicsErrAbortHandler(t *testing.T) {
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), RecoverMiddleware(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("recovered %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
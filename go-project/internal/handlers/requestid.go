@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go-project/internal/logging"
+	"go-project/pkg/utils"
+)
+
+// RequestIDHeader carries the request correlation ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen caps client-supplied IDs so they cannot bloat log lines.
+const maxRequestIDLen = 128
+
+// RequestIDMiddleware makes sure every request has a correlation ID. An
+// incoming X-Request-ID is reused when it is a reasonable token; otherwise a
+// fresh one is generated with utils.GenerateID. The ID is stored in the
+// request context, where logging.With picks it up, and echoed in the
+// response header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = utils.GenerateID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// RequestIDFromContext returns the ID stored by RequestIDMiddleware.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	return logging.RequestIDFromContext(ctx)
+}
+
+// validRequestID accepts non-empty printable ASCII without spaces, which
+// keeps client-supplied IDs from injecting content into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func echoRequestID(w http.ResponseWriter, r *http.Request) {
+	id, _ := RequestIDFromContext(r.Context())
+	w.Write([]byte(id))
+}
+
+func TestRequestIDMiddlewarePassesThroughIncomingID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	RequestIDMiddleware(http.HandlerFunc(echoRequestID)).ServeHTTP(rec, r)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("response header = %q, want abc-123", got)
+	}
+	if rec.Body.String() != "abc-123" {
+		t.Errorf("context ID = %q, want abc-123", rec.Body.String())
+	}
+}
+
+func TestRequestIDMiddlewareGeneratesMissingID(t *testing.T) {
+	for _, incoming := range []string{"", "has spaces\nand newlines"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if incoming != "" {
+			r.Header.Set(RequestIDHeader, incoming)
+		}
+		rec := httptest.NewRecorder()
+		RequestIDMiddleware(http.HandlerFunc(echoRequestID)).ServeHTTP(rec, r)
+
+		id := rec.Header().Get(RequestIDHeader)
+		if _, err := uuid.Parse(id); err != nil {
+			t.Fatalf("generated ID %q is not a UUID: %v", id, err)
+		}
+		if rec.Body.String() != id {
+			t.Fatalf("context ID = %q, header ID = %q", rec.Body.String(), id)
+		}
+	}
+}
+
+func TestRequestIDFromContextWithoutMiddleware(t *testing.T) {
+	if _, ok := RequestIDFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Fatal("RequestIDFromContext reported an ID on a bare request")
+	}
+}
+
+func TestLoggingMiddlewareIncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	h := Chain(http.HandlerFunc(DataHandler),
+		RequestIDMiddleware, LoggingMiddleware(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/data", nil)
+	r.Header.Set(RequestIDHeader, "req-42")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	var entry map[string]any
+	json.Unmarshal(buf.Bytes(), &entry)
+	if entry["request_id"] != "req-42" {
+		t.Fatalf("request_id = %v, want req-42", entry["request_id"])
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-project/internal/logging"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+	"go-project/pkg/utils"
+)
+
+// writeJSON encodes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Media types Respond can produce. The first is used when the client has no
+// preference.
+var respondTypes = []string{"application/json", "application/xml", "text/xml"}
+
+// Respond writes v with the given status in the encoding the request's
+// Accept header prefers: JSON by default, or XML for application/xml and
+// text/xml. When the Accept header rules out every supported type it
+// answers 406 instead. v must be encodable by encoding/xml if XML can be
+// chosen; an encoding failure answers 500.
+func Respond(w http.ResponseWriter, r *http.Request, status int, v any) {
+	ct := negotiateContentType(r.Header.Get("Accept"), respondTypes)
+	switch ct {
+	case "":
+		writeJSONError(w, http.StatusNotAcceptable, "not acceptable")
+	case "application/xml", "text/xml":
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		w.Header().Set("Content-Type", ct+"; charset=utf-8")
+		w.WriteHeader(status)
+		w.Write(buf.Bytes())
+	default:
+		writeJSON(w, status, v)
+	}
+}
+
+// negotiateContentType picks the offer the Accept header ranks highest,
+// preferring earlier offers on ties. An empty header accepts the first
+// offer. It returns "" when no offer is acceptable.
+func negotiateContentType(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := acceptQuality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q-value the Accept header gives mediaType,
+// taken from its most specific matching range. Unmatched types get 0.
+func acceptQuality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		rng, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch {
+		case rng == mediaType:
+			s = 2
+		case rng == typ+"/*":
+			s = 1
+		case rng == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && f <= 1 {
+				q = f
+			}
+		}
+	}
+	return q
+}
+
+// writeJSONError writes the error envelope with the given status, choosing
+// the code from the status.
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	WriteError(w, status, codeForStatus(status), message)
+}
+
+// writeValidationError answers 422 with the error envelope listing each
+// invalid field when err is a *models.ValidationError, and 400 with the error
+// text otherwise.
+func writeValidationError(w http.ResponseWriter, err error) {
+	if !errors.As(err, new(*models.ValidationError)) {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, errorEnvelope{Error: validationErrorBody(err)})
+}
+
+// validationErrorBody describes err as it appears under "error", listing
+// the invalid fields when err is a *models.ValidationError.
+func validationErrorBody(err error) errorBody {
+	var v *models.ValidationError
+	if !errors.As(err, &v) {
+		return errorBody{Code: CodeInvalidRequest, Message: err.Error()}
+	}
+	return errorBody{Code: CodeInvalidRequest, Message: "validation failed", Fields: v.Fields}
+}
+
+// repoRetryAfter is the Retry-After sent when the repository backend is
+// unreachable.
+const repoRetryAfter = 5 * time.Second
+
+// errorStatus maps a repository error to an HTTP status: 404 for
+// repository.ErrNotFound, 409 for repository.ErrDuplicateEmail, 503 when the
+// backend is unreachable or the request's context was canceled or timed
+// out, and 500 for anything else.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrDuplicateEmail):
+		return http.StatusConflict
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), unavailable(err):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// unavailable reports whether err means the repository backend could not
+// be reached, as opposed to rejecting the query: repository.ErrUnavailable,
+// a broken or closed database/sql connection, or a network failure. A
+// canceled or timed-out request is not, even though
+// context.DeadlineExceeded satisfies net.Error.
+func unavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, repository.ErrUnavailable) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, new(net.Error))
+}
+
+// writeRepoError answers with the status errorStatus picks. The body never
+// carries err's text, which may come from the database driver: 404 says
+// "<resource> not found", 503 for an unreachable backend adds Retry-After,
+// and a 500 is logged with the request's correlation ID, generating one
+// when RequestIDMiddleware did not run, so the generic response can be
+// matched to its cause.
+func writeRepoError(w http.ResponseWriter, r *http.Request, err error, resource string) {
+	switch status := errorStatus(err); {
+	case status == http.StatusNotFound:
+		writeJSONError(w, status, resource+" not found")
+	case status == http.StatusConflict:
+		writeJSONError(w, status, "email is already in use")
+	case status == http.StatusServiceUnavailable && unavailable(err):
+		logging.With(r.Context()).WarnContext(r.Context(), "repository unavailable",
+			slog.String("method", r.Method),
+			slog.String("path", maskPath(r.URL.Path)),
+			slog.String("error", maskEmails(err.Error())),
+		)
+		w.Header().Set("Retry-After", strconv.Itoa(int(repoRetryAfter/time.Second)))
+		writeJSONError(w, status, "service temporarily unavailable")
+	case status == http.StatusServiceUnavailable:
+		writeJSONError(w, status, "request canceled")
+	default:
+		logger := logging.With(r.Context())
+		if _, ok := RequestIDFromContext(r.Context()); !ok {
+			id := utils.GenerateID()
+			w.Header().Set(RequestIDHeader, id)
+			logger = logger.With(slog.String("request_id", id))
+		}
+		logger.ErrorContext(r.Context(), "repository error",
+			slog.String("method", r.Method),
+			slog.String("path", maskPath(r.URL.Path)),
+			slog.String("error", maskEmails(err.Error())),
+		)
+		writeJSONError(w, status, "internal server error")
+	}
+}
+
+// allowMethods reports whether r uses one of methods. Otherwise it answers
+// 405 with an Allow header listing them, sorted and with HEAD implied by GET
+// as the Router does, and returns false. Handlers call it so they reject
+// other methods even when mounted on a pattern without a method.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	allowed := slices.Clone(methods)
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	if slices.Contains(allowed, r.Method) {
+		return true
+	}
+	slices.Sort(allowed)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeJSONError(w, http.StatusMethodNotAllowed, "method "+r.Method+" not allowed")
+	return false
+}
+
+// pathID parses the {id} path value, answering 400 "invalid <resource> id"
+// when it is not an integer.
+func pathID(w http.ResponseWriter, r *http.Request, resource string) (int, bool) {
+	id, err := strconv.Atoi(PathValue(r, "id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid "+resource+" id")
+		return 0, false
+	}
+	return id, true
+}
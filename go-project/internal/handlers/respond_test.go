@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func TestRespondNegotiatesEncoding(t *testing.T) {
+	tests := []struct {
+		accept     string
+		wantStatus int
+		wantType   string
+	}{
+		{"", http.StatusOK, "application/json"},
+		{"*/*", http.StatusOK, "application/json"},
+		{"application/json", http.StatusOK, "application/json"},
+		{"application/xml", http.StatusOK, "application/xml; charset=utf-8"},
+		{"text/xml", http.StatusOK, "text/xml; charset=utf-8"},
+		{"application/*", http.StatusOK, "application/json"},
+		{"application/json;q=0.5, application/xml", http.StatusOK, "application/xml; charset=utf-8"},
+		{"application/xml;q=0.9, */*;q=0.1", http.StatusOK, "application/xml; charset=utf-8"},
+		{"text/html, application/xhtml+xml", http.StatusNotAcceptable, "application/json"},
+		{"application/json;q=0, application/xml;q=0", http.StatusNotAcceptable, "application/json"},
+		{"*/*, application/json;q=0", http.StatusOK, "application/xml; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			Respond(rec, r, http.StatusOK, Example{ID: 1, Name: "x"})
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestRespondSamePayloadInBothEncodings(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	payloads := []any{
+		Example{ID: 1, Name: "Example Name"},
+		models.User{ID: 2, Name: "Ada", Email: "ada@example.com", PasswordHash: "secret", CreatedAt: created, UpdatedAt: created},
+		models.Product{ID: 3, Name: "Widget", Price: usd("9.99"), CreatedAt: created, UpdatedAt: created},
+	}
+	for _, want := range payloads {
+		typ := reflect.TypeOf(want)
+		t.Run(typ.Name(), func(t *testing.T) {
+			for _, accept := range []string{"application/json", "application/xml"} {
+				r := httptest.NewRequest("GET", "/", nil)
+				r.Header.Set("Accept", accept)
+				rec := httptest.NewRecorder()
+				Respond(rec, r, http.StatusCreated, want)
+				if rec.Code != http.StatusCreated {
+					t.Fatalf("%s: status = %d, want 201", accept, rec.Code)
+				}
+				body := rec.Body.String()
+				if strings.Contains(body, "secret") {
+					t.Fatalf("%s: body leaks the password hash: %s", accept, body)
+				}
+
+				got := reflect.New(typ)
+				var err error
+				if accept == "application/xml" {
+					if !strings.HasPrefix(body, xml.Header) {
+						t.Errorf("XML body has no declaration: %s", body)
+					}
+					err = xml.Unmarshal(rec.Body.Bytes(), got.Interface())
+				} else {
+					err = json.Unmarshal(rec.Body.Bytes(), got.Interface())
+				}
+				if err != nil {
+					t.Fatalf("%s: decode %s: %v", accept, body, err)
+				}
+				// PasswordHash is never encoded and XMLName is only set by
+				// the XML decoder; clear both before comparing.
+				wantV := reflect.New(typ).Elem()
+				wantV.Set(reflect.ValueOf(want))
+				for _, v := range []reflect.Value{wantV, got.Elem()} {
+					for _, f := range []string{"PasswordHash", "XMLName"} {
+						if fv := v.FieldByName(f); fv.IsValid() {
+							fv.Set(reflect.Zero(fv.Type()))
+						}
+					}
+				}
+				if !reflect.DeepEqual(got.Elem().Interface(), wantV.Interface()) {
+					t.Errorf("%s: decoded %+v, want %+v", accept, got.Elem().Interface(), wantV.Interface())
+				}
+			}
+		})
+	}
+}
+
+func TestUserGetAsXML(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	r := httptest.NewRequest("GET", "/api/users/1", nil)
+	r.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+	newUserTestRouter(repo).ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "<user><id>1</id><name>Ada</name>") {
+		t.Fatalf("body = %s, want a <user> element", body)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: &net.AddrError{Err: "connection refused", Addr: "10.0.0.5:5432"}}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", fmt.Errorf("get user 7: %w", repository.ErrNotFound), http.StatusNotFound},
+		{"duplicate email", repository.ErrDuplicateEmail, http.StatusConflict},
+		{"canceled", context.Canceled, http.StatusServiceUnavailable},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusServiceUnavailable},
+		{"unavailable", fmt.Errorf("users: %w", repository.ErrUnavailable), http.StatusServiceUnavailable},
+		{"bad driver connection", driver.ErrBadConn, http.StatusServiceUnavailable},
+		{"closed connection", sql.ErrConnDone, http.StatusServiceUnavailable},
+		{"network error", refused, http.StatusServiceUnavailable},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), http.StatusServiceUnavailable},
+		{"query error", errors.New(`pq: relation "users" does not exist`), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorStatus(tt.err); got != tt.want {
+				t.Fatalf("errorStatus(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// failingUserRepo is a UserRepository whose reads fail with err.
+type failingUserRepo struct {
+	repository.UserRepository
+	err error
+}
+
+func (r failingUserRepo) Get(ctx context.Context, id int) (*models.User, error) {
+	return nil, r.err
+}
+
+// captureDefaultLog sends slog.Default to a buffer for the test.
+func captureDefaultLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestWriteRepoErrorHidesDriverMessages(t *testing.T) {
+	const secret = "10.0.0.5:5432"
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{"unreachable backend", fmt.Errorf("dial tcp %s: %w", secret, syscall.ECONNREFUSED), http.StatusServiceUnavailable, "5"},
+		{"query failure", fmt.Errorf(`pq: password authentication failed for "app" at %s`, secret), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureDefaultLog(t)
+			rec := httptest.NewRecorder()
+			newUserTestRouter(failingUserRepo{err: tt.err}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			decodeErrorEnvelope(t, rec)
+			if body := rec.Body.String(); strings.Contains(body, secret) || strings.Contains(body, "pq:") {
+				t.Fatalf("body %s leaks the repository error", body)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if !strings.Contains(logs.String(), secret) {
+				t.Errorf("log %q does not record the repository error", logs)
+			}
+		})
+	}
+}
+
+func TestWriteRepoErrorCanceledRequest(t *testing.T) {
+	for name, err := range map[string]error{
+		"deadline": fmt.Errorf("query: %w", context.DeadlineExceeded),
+		"canceled": fmt.Errorf("query: %w", context.Canceled),
+	} {
+		t.Run(name, func(t *testing.T) {
+			logs := captureDefaultLog(t)
+			rec := httptest.NewRecorder()
+			newUserTestRouter(failingUserRepo{err: err}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+
+			if rec.Code != http.StatusServiceUnavailable {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+			}
+			if body := decodeErrorEnvelope(t, rec); body.Message != "request canceled" {
+				t.Errorf("message = %q, want %q", body.Message, "request canceled")
+			}
+			if got := rec.Header().Get("Retry-After"); got != "" {
+				t.Errorf("Retry-After = %q, want none for a canceled request", got)
+			}
+			if strings.Contains(logs.String(), "repository unavailable") {
+				t.Errorf("log %q reports the repository unavailable", logs)
+			}
+		})
+	}
+}
+
+func TestWriteRepoErrorMasksEmails(t *testing.T) {
+	for name, err := range map[string]error{
+		"unavailable": fmt.Errorf("lookup ada@example.com: %w", repository.ErrUnavailable),
+		"internal":    errors.New(`pq: duplicate key value (email)=(ada@example.com)`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			logs := captureDefaultLog(t)
+			writeRepoError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/by-email/ada@example.com", nil), err, "user")
+			if strings.Contains(logs.String(), "ada@") {
+				t.Fatalf("log leaks the email: %s", logs)
+			}
+			if !strings.Contains(logs.String(), "a*a@example.com") {
+				t.Errorf("log does not have the masked email: %s", logs)
+			}
+		})
+	}
+}
+
+func TestWriteRepoErrorLogsCorrelationID(t *testing.T) {
+	internal := failingUserRepo{err: errors.New("pq: syntax error")}
+	t.Run("from RequestIDMiddleware", func(t *testing.T) {
+		logs := captureDefaultLog(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/users/1", nil)
+		req.Header.Set(RequestIDHeader, "req-42")
+		rec := httptest.NewRecorder()
+		Chain(newUserTestRouter(internal), RequestIDMiddleware).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusInternalServerError || rec.Header().Get(RequestIDHeader) != "req-42" {
+			t.Fatalf("status = %d, %s = %q", rec.Code, RequestIDHeader, rec.Header().Get(RequestIDHeader))
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("log %q: %v", logs, err)
+		}
+		if entry["request_id"] != "req-42" || entry["level"] != "ERROR" {
+			t.Fatalf("log entry = %v, want an ERROR with request_id req-42", entry)
+		}
+	})
+	t.Run("generated", func(t *testing.T) {
+		logs := captureDefaultLog(t)
+		rec := httptest.NewRecorder()
+		newUserTestRouter(internal).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/1", nil))
+
+		id := rec.Header().Get(RequestIDHeader)
+		if id == "" {
+			t.Fatalf("no %s on a 500 without RequestIDMiddleware", RequestIDHeader)
+		}
+		if !strings.Contains(logs.String(), `"request_id":"`+id+`"`) {
+			t.Fatalf("log %q does not carry the generated ID %q", logs, id)
+		}
+	})
+}
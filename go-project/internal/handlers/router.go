@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Router dispatches requests by method and path pattern. It is a thin layer
+// over http.ServeMux, so patterns use its syntax: "/api/users/{id}" captures
+// a path segment, "{$}" anchors the end of the path, and a request whose path
+// matches but whose method does not gets 405 with an Allow header.
+type Router struct {
+	mux *http.ServeMux
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// Handle registers h for method and pattern. An empty method matches any
+// method.
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	if method != "" {
+		pattern = method + " " + pattern
+	}
+	rt.mux.Handle(pattern, h)
+}
+
+// Get registers h for GET (and therefore HEAD) requests matching pattern.
+func (rt *Router) Get(pattern string, h http.Handler) { rt.Handle(http.MethodGet, pattern, h) }
+
+// Post registers h for POST requests matching pattern.
+func (rt *Router) Post(pattern string, h http.Handler) { rt.Handle(http.MethodPost, pattern, h) }
+
+// Put registers h for PUT requests matching pattern.
+func (rt *Router) Put(pattern string, h http.Handler) { rt.Handle(http.MethodPut, pattern, h) }
+
+// Patch registers h for PATCH requests matching pattern.
+func (rt *Router) Patch(pattern string, h http.Handler) { rt.Handle(http.MethodPatch, pattern, h) }
+
+// Delete registers h for DELETE requests matching pattern.
+func (rt *Router) Delete(pattern string, h http.Handler) { rt.Handle(http.MethodDelete, pattern, h) }
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+// PathValue returns the value of the named wildcard in the pattern that
+// matched r, or "" when there is none.
+func PathValue(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// routePattern returns the path part of the pattern that matched r, without
+// the method prefix, or "" when r was not dispatched by a Router.
+func routePattern(r *http.Request) string {
+	p := r.Pattern
+	if i := strings.IndexByte(p, ' '); i >= 0 {
+		p = p[i+1:]
+	}
+	return p
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterExtractsPathValue(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/api/users/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(PathValue(r, "id")))
+	}))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/users/42", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if rec.Body.String() != "42" {
+		t.Fatalf("id = %q, want 42", rec.Body.String())
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/api/users/{id}", http.HandlerFunc(DataHandler))
+	rt.Delete("/api/users/{id}", http.HandlerFunc(DataHandler))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/users/42", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "DELETE, GET, HEAD" {
+		t.Fatalf("Allow = %q, want %q", got, "DELETE, GET, HEAD")
+	}
+}
+
+func TestRouterUnknownPath(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/{$}", http.HandlerFunc(HomeHandler))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
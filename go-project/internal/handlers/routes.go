@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"go-project/internal/audit"
+	"go-project/internal/config"
+	"go-project/internal/lifecycle"
+	"go-project/internal/repository"
+)
+
+// Route is one entry in the route table: the method and Router pattern it is
+// registered under, its handler, and any middleware specific to it. The
+// server's shared middleware is applied around Middleware when registering.
+type Route struct {
+	Method     string
+	Pattern    string
+	Handler    http.HandlerFunc
+	Middleware []Middleware
+}
+
+// Services holds the dependencies the route handlers are built from, and
+// the options that change which middleware they get.
+type Services struct {
+	Users    repository.UserRepository
+	Products repository.ProductRepository
+	// Categories are the categories products may be filed under. Nil
+	// leaves the category routes out and rejects products with a category.
+	Categories repository.CategoryRepository
+	Prices     *PriceBroker
+	// Audit records every change made through the user and product routes.
+	// Nil records none.
+	Audit *audit.Logger
+	// UI is the web UI served under /static/ with an SPA fallback on /.
+	// Nil leaves those routes out.
+	UI fs.FS
+	// JWTSecret verifies the bearer tokens of requests to the /api routes,
+	// issued by auth.IssueToken, so their user ID reaches the per-user rate
+	// limit and the audit log. Requests without a token are served as
+	// anonymous. Nil serves every request as anonymous.
+	JWTSecret []byte
+	// NoRateLimit serves the /api routes without the per-client limit.
+	NoRateLimit bool
+	// RateLimitRPS and RateLimitBurst set that limit: requests per second
+	// per client, and how many may arrive at once. Zero keeps
+	// config.DefaultRateLimitRPS and config.DefaultRateLimitBurst.
+	RateLimitRPS   float64
+	RateLimitBurst int
+	// UserRateLimitRPS and UserRateLimitBurst set the limit of
+	// authenticated requests, which are counted per user instead of per
+	// client IP. Zero keeps config.DefaultUserRateLimitRPS and
+	// config.DefaultUserRateLimitBurst.
+	UserRateLimitRPS   float64
+	UserRateLimitBurst int
+	// RequestTimeout bounds each API request; zero keeps
+	// config.DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// Started is when the process started, for the uptime in /info. Zero
+	// counts from the call to Routes.
+	Started time.Time
+	// Lifecycle runs the background janitors of the rate limiter and the
+	// idempotency store, so shutdown stops them. Routes registers them, so
+	// it must be called before Lifecycle starts. Nil runs them for the life
+	// of the process.
+	Lifecycle *lifecycle.Manager
+}
+
+// Routes returns the application's route table. New endpoints are added
+// here, next to their handlers, rather than in main.
+func Routes(s Services) []Route {
+	rps, burst, timeout := s.RateLimitRPS, s.RateLimitBurst, s.RequestTimeout
+	if rps == 0 {
+		rps = config.DefaultRateLimitRPS
+	}
+	if burst == 0 {
+		burst = config.DefaultRateLimitBurst
+	}
+	userRPS, userBurst := s.UserRateLimitRPS, s.UserRateLimitBurst
+	if userRPS == 0 {
+		userRPS = config.DefaultUserRateLimitRPS
+	}
+	if userBurst == 0 {
+		userBurst = config.DefaultUserRateLimitBurst
+	}
+	if timeout == 0 {
+		timeout = config.DefaultRequestTimeout
+	}
+	started := s.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+	// The rate limit runs around authentication, so it can count users
+	// rather than IPs and still count rejected tokens against their IP
+	apiAuth := func(next http.Handler) http.Handler { return next }
+	if s.JWTSecret != nil {
+		apiAuth = OptionalJWTMiddleware(s.JWTSecret)
+	}
+	if !s.NoRateLimit {
+		limiter := newUserRateLimiter(rate.Limit(rps), burst, rate.Limit(userRPS), userBurst)
+		s.background("rate limiter janitor", limiter.anonymous.janitor)
+		s.background("user rate limiter janitor", limiter.users.janitor)
+		apiAuth = limiter.around(apiAuth)
+	}
+	apiTimeout := TimeoutMiddleware(timeout)
+	replays := newIdempotencyStore(idempotencyTTL)
+	s.background("idempotency janitor", replays.janitor)
+	idempotent := replays.middleware
+	// Every /api route authenticates first, then applies its own middleware
+	api := func(mw ...Middleware) []Middleware {
+		return append([]Middleware{apiAuth}, mw...)
+	}
+	users := NewUserHandlers(s.Users)
+	users.Audit = s.Audit
+	products := NewProductHandlers(s.Products)
+	products.Categories = s.Categories
+	products.Audit = s.Audit
+
+	routes := []Route{
+		{Method: http.MethodGet, Pattern: "/{$}", Handler: HomeHandler},
+		{Method: http.MethodGet, Pattern: "/healthz", Handler: HealthHandler},
+		{Method: http.MethodGet, Pattern: "/readyz", Handler: ReadyHandler},
+		{Method: http.MethodGet, Pattern: "/info", Handler: InfoHandler(started)},
+		{Method: http.MethodGet, Pattern: "/api/data", Handler: DataHandler, Middleware: api()},
+
+		{Method: http.MethodPost, Pattern: "/api/users", Handler: users.Create, Middleware: api(apiTimeout, idempotent)},
+		{Method: http.MethodGet, Pattern: "/api/users", Handler: users.List, Middleware: api(apiTimeout)},
+		{Method: http.MethodPost, Pattern: "/api/users/batch", Handler: users.Batch, Middleware: api(apiTimeout)},
+		{Method: http.MethodGet, Pattern: "/api/users/{id}", Handler: users.Get, Middleware: api(apiTimeout)},
+		{Method: http.MethodPut, Pattern: "/api/users/{id}", Handler: users.Update, Middleware: api(apiTimeout)},
+		{Method: http.MethodPatch, Pattern: "/api/users/{id}", Handler: users.Patch, Middleware: api(apiTimeout)},
+		{Method: http.MethodDelete, Pattern: "/api/users/{id}", Handler: users.Delete, Middleware: api(apiTimeout)},
+
+		{Method: http.MethodPost, Pattern: "/api/products", Handler: products.Create, Middleware: api(apiTimeout, idempotent)},
+		{Method: http.MethodPost, Pattern: "/api/products/bulk", Handler: products.Bulk, Middleware: api(apiTimeout, idempotent)},
+		{Method: http.MethodGet, Pattern: "/api/products", Handler: products.List, Middleware: api(apiTimeout)},
+		{Method: http.MethodGet, Pattern: "/api/products/{id}", Handler: products.Get, Middleware: api(apiTimeout)},
+		{Method: http.MethodPut, Pattern: "/api/products/{id}", Handler: products.Update, Middleware: api(apiTimeout)},
+		{Method: http.MethodPatch, Pattern: "/api/products/{id}", Handler: products.Patch, Middleware: api(apiTimeout)},
+		// No timeout since the exports are streamed in batches
+		{Method: http.MethodGet, Pattern: "/api/products.csv", Handler: products.ExportCSV, Middleware: api()},
+		{Method: http.MethodGet, Pattern: "/api/products.json", Handler: products.ExportJSON, Middleware: api()},
+		// No timeout since the event stream stays open
+		{Method: http.MethodGet, Pattern: "/api/products/events", Handler: ProductEventsHandler(s.Prices), Middleware: api()},
+	}
+	if s.Categories != nil {
+		categories := NewCategoryHandlers(s.Categories, s.Products)
+		routes = append(routes,
+			Route{Method: http.MethodGet, Pattern: "/api/categories", Handler: categories.List, Middleware: api(apiTimeout)},
+			Route{Method: http.MethodGet, Pattern: "/api/categories/{slug}/products", Handler: categories.ListProducts, Middleware: api(apiTimeout)},
+		)
+	}
+	// The contract for everything above, generated from the handlers' types
+	routes = append(routes, Route{Method: http.MethodGet, Pattern: "/openapi.json", Handler: OpenAPIHandler(OpenAPI(routes))})
+	if s.UI != nil {
+		routes = append(routes,
+			Route{Method: http.MethodGet, Pattern: "/static/", Handler: http.StripPrefix("/static", StaticHandler(s.UI)).ServeHTTP},
+			Route{Method: http.MethodGet, Pattern: "/", Handler: SPAHandler(s.UI).ServeHTTP},
+		)
+	}
+	return routes
+}
+
+// Register adds every route to rt, wrapping each handler in its own
+// middleware and then in shared, outermost first.
+func Register(rt *Router, routes []Route, shared ...Middleware) {
+	for _, r := range routes {
+		rt.Handle(r.Method, r.Pattern, Chain(Chain(r.Handler, r.Middleware...), shared...))
+	}
+}
+
+// background runs work for the life of the process: as a worker of
+// s.Lifecycle when it is set, otherwise in a goroutine of its own.
+func (s Services) background(name string, work func(ctx context.Context)) {
+	if s.Lifecycle == nil {
+		go work(context.Background())
+		return
+	}
+	s.Lifecycle.Register(name, work, nil)
+}
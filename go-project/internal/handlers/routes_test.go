@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"go-project/internal/audit"
+	"go-project/internal/auth"
+	"go-project/internal/repository"
+)
+
+func testServices() Services {
+	return Services{
+		Users:      repository.NewInMemoryUserRepo(),
+		Products:   repository.NewInMemoryProductRepo(),
+		Categories: repository.NewInMemoryCategoryRepo(),
+		Prices:     NewPriceBroker(),
+		UI:         fstest.MapFS{"index.html": {Data: []byte("<html></html>")}},
+	}
+}
+
+func TestRoutesHaveUniqueMethodAndPattern(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range Routes(testServices()) {
+		key := r.Method + " " + r.Pattern
+		if seen[key] {
+			t.Errorf("route %q is registered twice", key)
+		}
+		seen[key] = true
+		if r.Handler == nil {
+			t.Errorf("route %q has no handler", key)
+		}
+	}
+}
+
+func TestRoutesIncludeExampleRoutes(t *testing.T) {
+	routes := map[string]bool{}
+	for _, r := range Routes(testServices()) {
+		routes[r.Method+" "+r.Pattern] = true
+	}
+	for _, want := range []string{"GET /{$}", "GET /api/data", "GET /healthz", "POST /api/users"} {
+		if !routes[want] {
+			t.Errorf("route table is missing %q", want)
+		}
+	}
+}
+
+func TestRoutesOmitUIWithoutFS(t *testing.T) {
+	s := testServices()
+	s.UI = nil
+	for _, r := range Routes(s) {
+		if r.Pattern == "/" || r.Pattern == "/static/" {
+			t.Errorf("route %s %s registered without a UI filesystem", r.Method, r.Pattern)
+		}
+	}
+}
+
+func TestRegisterServesRoutesThroughMiddleware(t *testing.T) {
+	rt := NewRouter()
+	routes := []Route{{
+		Method:     http.MethodGet,
+		Pattern:    "/api/data",
+		Handler:    DataHandler,
+		Middleware: []Middleware{tagMiddleware("route")},
+	}}
+	Register(rt, routes, tagMiddleware("shared"))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	want := []string{"shared", "route"}
+	if got := rec.Header().Values("X-Order"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("X-Order = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterAllRoutesDoesNotPanic(t *testing.T) {
+	// http.ServeMux panics on conflicting patterns, so registering the full
+	// table proves the patterns are compatible with each other.
+	Register(NewRouter(), Routes(testServices()))
+}
+
+func TestRoutesNoRateLimit(t *testing.T) {
+	s := testServices()
+	s.NoRateLimit = true
+	rt := NewRouter()
+	Register(rt, Routes(s))
+	// The limit allows a burst of 20 before answering 429.
+	for i := 0; i < 30; i++ {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestRoutesRateLimitEachUserSeparately(t *testing.T) {
+	secret := []byte("test-secret")
+	s := testServices()
+	s.JWTSecret = secret
+	// One request per bucket, refilled far slower than the test runs
+	s.RateLimitRPS, s.RateLimitBurst = 0.001, 1
+	s.UserRateLimitRPS, s.UserRateLimitBurst = 0.001, 1
+	rt := NewRouter()
+	Register(rt, Routes(s))
+
+	token := func(userID int) string {
+		tok, err := auth.IssueToken(secret, userID, time.Hour)
+		if err != nil {
+			t.Fatalf("IssueToken: %v", err)
+		}
+		return "Bearer " + tok
+	}
+	alice, bob := token(1), token(2)
+	// Every request comes from httptest's one client IP
+	for i, tc := range []struct {
+		name, path, authorization string
+		want                      int
+	}{
+		{"alice", "/api/users", alice, http.StatusOK},
+		{"alice again", "/api/products", alice, http.StatusTooManyRequests},
+		{"bob", "/api/users", bob, http.StatusOK},
+		{"anonymous", "/api/data", "", http.StatusOK},
+		{"anonymous again", "/api/users", "", http.StatusTooManyRequests},
+		{"bob again", "/api/data", bob, http.StatusTooManyRequests},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("request %d (%s): status = %d, want %d", i+1, tc.name, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestRoutesRateLimitInvalidTokens(t *testing.T) {
+	secret := []byte("test-secret")
+	s := testServices()
+	s.JWTSecret = secret
+	s.RateLimitRPS, s.RateLimitBurst = 0.001, 3
+	rt := NewRouter()
+	Register(rt, Routes(s))
+
+	// Guessed tokens use up the IP's bucket like anonymous requests
+	for i := range s.RateLimitBurst + 2 {
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		req.Header.Set("Authorization", "Bearer guess-"+strconv.Itoa(i))
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		want := http.StatusUnauthorized
+		if i >= s.RateLimitBurst {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Fatalf("guess %d: status = %d, want %d", i+1, rec.Code, want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("anonymous request after the guesses: status = %d, want 429", rec.Code)
+	}
+}
+
+func TestRoutesAuditAuthenticatedUser(t *testing.T) {
+	secret := []byte("test-secret")
+	var log bytes.Buffer
+	s := testServices()
+	s.JWTSecret = secret
+	s.Audit = audit.NewLogger(&log)
+	rt := NewRouter()
+	Register(rt, Routes(s))
+
+	tok, err := auth.IssueToken(secret, 7, time.Hour)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(`{"name":"Bob","email":"bob@example.com"}`))
+	req.Header.Set("Authorization", "Bearer "+tok)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	if !strings.Contains(log.String(), `"actor":"user:7"`) {
+		t.Fatalf("audit log = %s, want the change made by user:7", log.String())
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// StaticHandler serves the files in fsys with http.FileServerFS. Mount it
+// with http.StripPrefix, e.g. under /static/. Directory listings are
+// disabled: a directory is only served when it has an index.html, otherwise
+// the request gets 404. Paths are cleaned before lookup and fs.FS refuses
+// names containing "..", so requests cannot escape fsys.
+func StaticHandler(fsys fs.FS) http.Handler {
+	return http.FileServerFS(noListingFS{fsys})
+}
+
+// noListingFS hides directories that have no index.html so FileServer
+// answers 404 instead of listing them.
+type noListingFS struct {
+	fs.FS
+}
+
+func (n noListingFS) Open(name string) (fs.File, error) {
+	f, err := n.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		if _, err := fs.Stat(n.FS, path.Join(name, "index.html")); err != nil {
+			f.Close()
+			return nil, fs.ErrNotExist
+		}
+	}
+	return f, nil
+}
+
+// SPAHandler serves index.html from fsys for every request except those
+// under /api/, so a single-page app can handle its own client-side routes.
+// Register it as the catch-all route; unknown /api/ paths still get the
+// JSON 404.
+func SPAHandler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+			WriteError(w, http.StatusNotFound, CodeNotFound, "not found")
+			return
+		}
+		index, err := fs.ReadFile(fsys, "index.html")
+		if errors.Is(err, fs.ErrNotExist) {
+			WriteError(w, http.StatusNotFound, CodeNotFound, "not found")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(index)
+	})
+}
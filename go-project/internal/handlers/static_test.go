@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/static
+var testStatic embed.FS
+
+func staticRoot(t *testing.T) fs.FS {
+	t.Helper()
+	sub, err := fs.Sub(testStatic, "testdata/static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sub
+}
+
+func TestStaticHandler(t *testing.T) {
+	h := http.StripPrefix("/static", StaticHandler(staticRoot(t)))
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/static/css/app.css", http.StatusOK, "margin: 0"},
+		{"/static/", http.StatusOK, "<title>Dashboard</title>"},
+		{"/static/missing.js", http.StatusNotFound, ""},
+		{"/static/empty/", http.StatusNotFound, ""},
+		{"/static/css/", http.StatusNotFound, ""},
+		{"/static/../static_test.go", http.StatusNotFound, ""},
+		{"/static/../../../../etc/passwd", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.URL.Path = tt.path // bypass httptest's own path parsing
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %q does not contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSPAHandler(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/api/data", http.HandlerFunc(DataHandler))
+	rt.Get("/", SPAHandler(staticRoot(t)))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantType   string
+	}{
+		{"/dashboard/products/7", http.StatusOK, "text/html; charset=utf-8"},
+		{"/settings", http.StatusOK, "text/html; charset=utf-8"},
+		{"/api/data", http.StatusOK, "application/json"},
+		{"/api/nope", http.StatusNotFound, "application/json"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.wantStatus || rec.Header().Get("Content-Type") != tt.wantType {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, rec.Code, rec.Header().Get("Content-Type"), tt.wantStatus, tt.wantType)
+		}
+	}
+}
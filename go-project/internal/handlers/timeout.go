@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TimeoutMiddleware gives each request d to complete. The handler runs with
+// a context that is canceled after d; if it has not returned by then the
+// client gets 503 with the error envelope
+// {"error":{"code":"internal","message":"request timeout"}} and anything
+// the handler writes afterwards is discarded (Write returns
+// http.ErrHandlerTimeout).
+//
+// Handlers behind it must watch r.Context().Done() and stop work when it
+// fires; the middleware cannot preempt a handler that ignores its context,
+// which keeps running in the background until it returns.
+//
+// Responses are buffered until the handler returns, so this middleware is
+// not suitable for streaming endpoints.
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{header: make(http.Header), status: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				// Re-panic on the serving goroutine so RecoverMiddleware and
+				// net/http see it.
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, v := range tw.header {
+					dst[k] = v
+				}
+				w.WriteHeader(tw.status)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				tw.timedOut = true
+				tw.mu.Unlock()
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					writeJSONError(w, http.StatusServiceUnavailable, "request timeout")
+				}
+			}
+		})
+	}
+}
+
+// timeoutWriter buffers a response so TimeoutMiddleware can drop it if the
+// deadline passes first. It is safe for the handler goroutine and the
+// middleware to use concurrently.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.header }
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.status = status
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.buf.Write(b)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeoutMiddlewareExpires(t *testing.T) {
+	handlerErr := make(chan error, 1)
+	served := make(chan struct{})
+	h := TimeoutMiddleware(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+			io.WriteString(w, "too late")
+		case <-r.Context().Done():
+		}
+		<-served
+		_, err := io.WriteString(w, "after deadline")
+		handlerErr <- err
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	close(served)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Body.String(); got != `{"error":{"code":"internal","message":"request timeout"}}`+"\n" {
+		t.Fatalf("body = %q", got)
+	}
+	select {
+	case err := <-handlerErr:
+		if !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Fatalf("late Write err = %v, want http.ErrHandlerTimeout", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not observe context cancellation")
+	}
+}
+
+func TestTimeoutMiddlewareUnderLimit(t *testing.T) {
+	h := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("handler context has no deadline")
+		}
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, "done")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusCreated || rec.Body.String() != "done" || rec.Header().Get("X-Test") != "yes" {
+		t.Fatalf("response = %d %q %v, want 201 \"done\" with X-Test", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestTimeoutMiddlewarePanicReachesRecover(t *testing.T) {
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), RecoverMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil))), TimeoutMiddleware(time.Second))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
+
+func TestTimeoutMiddlewareClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+	if rec.Body.Len() != 0 {
+		t.Fatalf("body = %q, want nothing written for a canceled request", rec.Body.String())
+	}
+}
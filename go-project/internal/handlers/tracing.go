@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-project/internal/tracing"
+)
+
+// TracingMiddleware wraps each request in a server span. The span continues
+// the trace named by the request's traceparent header, or starts a new one
+// without it, and is stored in the request context so repository calls
+// and webhook deliveries made with that context become its children. Like
+// MetricsMiddleware it names spans after the Router pattern, so it must run
+// inside the Router.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routePattern(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Tracer().Start(tracing.Extract(r.Context(), r.Header), r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+			),
+		)
+		defer span.End()
+
+		rec := newStatusRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
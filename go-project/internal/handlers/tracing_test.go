@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingSpanID  = "00f067aa0ba902b7"
+	traceparent     = "00-" + incomingTraceID + "-" + incomingSpanID + "-01"
+)
+
+// recordSpans installs a TracerProvider that keeps finished spans for the
+// test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	prev := otel.GetTracerProvider()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return rec
+}
+
+// tracedRouter serves GET /api/things/{id} behind TracingMiddleware and
+// reports the span context each request's handler saw.
+func tracedRouter(seen *trace.SpanContext) *Router {
+	rt := NewRouter()
+	Register(rt, []Route{{Method: http.MethodGet, Pattern: "/api/things/{id}", Handler: func(w http.ResponseWriter, r *http.Request) {
+		*seen = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}}}, TracingMiddleware)
+	return rt
+}
+
+func TestTracingMiddlewareContinuesIncomingTrace(t *testing.T) {
+	spans := recordSpans(t)
+	var seen trace.SpanContext
+	req := httptest.NewRequest(http.MethodGet, "/api/things/7", nil)
+	req.Header.Set("traceparent", traceparent)
+	tracedRouter(&seen).ServeHTTP(httptest.NewRecorder(), req)
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans = %d, want 1", len(ended))
+	}
+	span := ended[0]
+	if got := span.SpanContext().TraceID().String(); got != incomingTraceID {
+		t.Errorf("trace ID = %s, want the incoming %s", got, incomingTraceID)
+	}
+	if got := span.Parent().SpanID().String(); got != incomingSpanID || !span.Parent().IsRemote() {
+		t.Errorf("parent = %s (remote %v), want the remote %s", got, span.Parent().IsRemote(), incomingSpanID)
+	}
+	if span.Name() != "GET /api/things/{id}" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %q of kind %v, want a server span named after the route", span.Name(), span.SpanKind())
+	}
+	if !hasAttribute(span.Attributes(), attribute.Int("http.response.status_code", http.StatusTeapot)) {
+		t.Errorf("attributes %v lack the response status", span.Attributes())
+	}
+	if seen.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("handler saw span %s, want the middleware's %s", seen.SpanID(), span.SpanContext().SpanID())
+	}
+}
+
+func TestTracingMiddlewareStartsNewTrace(t *testing.T) {
+	spans := recordSpans(t)
+	var seen trace.SpanContext
+	tracedRouter(&seen).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/things/7", nil))
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans = %d, want 1", len(ended))
+	}
+	if !ended[0].SpanContext().TraceID().IsValid() || ended[0].Parent().IsValid() {
+		t.Fatalf("span %v with parent %v, want the root of a new trace", ended[0].SpanContext(), ended[0].Parent())
+	}
+}
+
+func TestTracingMiddlewareWithoutProvider(t *testing.T) {
+	// Without tracing.Setup the global provider is a no-op, but the
+	// incoming trace context must still reach the handler to be passed on.
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	var seen trace.SpanContext
+	req := httptest.NewRequest(http.MethodGet, "/api/things/7", nil)
+	req.Header.Set("traceparent", traceparent)
+	tracedRouter(&seen).ServeHTTP(httptest.NewRecorder(), req)
+	if seen.TraceID().String() != incomingTraceID || seen.SpanID().String() != incomingSpanID {
+		t.Fatalf("handler saw %v, want the incoming trace context", seen)
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
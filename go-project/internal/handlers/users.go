@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-project/internal/audit"
+	"go-project/internal/auth"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+	"go-project/pkg/utils"
+)
+
+// UserHandlers serves the /api/users endpoints from a UserRepository.
+// Every change is recorded with Audit, if set.
+type UserHandlers struct {
+	Repo  repository.UserRepository
+	Audit *audit.Logger
+}
+
+// NewUserHandlers returns handlers backed by repo.
+func NewUserHandlers(repo repository.UserRepository) *UserHandlers {
+	return &UserHandlers{Repo: repo}
+}
+
+type createUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type updateUserRequest struct {
+	Email string `json:"email"`
+}
+
+// patchUserRequest uses pointers so an absent (or null) field, which is left
+// alone, can be told apart from an explicit empty string, which is applied
+// and then fails validation.
+type patchUserRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+// Create handles POST /api/users. The body may be JSON or form-encoded.
+func (h *UserHandlers) Create(w http.ResponseWriter, r *http.Request) {
+	var req createUserRequest
+	if !DecodeRequest(w, r, &req) {
+		return
+	}
+	// Names are shown in the dashboard, so no markup is stored.
+	u := models.NewUser(utils.SanitizeText(req.Name), req.Email)
+	if err := Validate(u); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := h.Repo.Create(r.Context(), u); err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	h.Audit.Record(r.Context(), audit.Create, "user", u.ID, nil, u)
+	w.Header().Set("Location", "/api/users/"+strconv.Itoa(u.ID))
+	writeJSON(w, http.StatusCreated, u)
+}
+
+// Get handles GET /api/users/{id}, as JSON or XML depending on the
+// Accept header. Only the user themself sees the full record with the
+// email; anyone else gets the public form, as in List. The response
+// carries an ETag, and a request whose If-None-Match names it gets 304 Not
+// Modified.
+func (h *UserHandlers) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	u, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	respondCached(w, r, userView(r, u))
+}
+
+// userView returns u as the caller may see it: the full record for the
+// authenticated user with u's ID, otherwise its public form. ETags are
+// taken from the view, so If-Match is compared against the same one.
+func userView(r *http.Request, u *models.User) any {
+	if id, ok := auth.UserIDFromContext(r.Context()); ok && id == u.ID {
+		return u
+	}
+	return u.Public()
+}
+
+// Update handles PUT /api/users/{id}, changing the user's email. With an
+// If-Match header naming an ETag other than the user's current one it
+// answers 412 and leaves the user alone.
+func (h *UserHandlers) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	var req updateUserRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	u, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	if !checkIfMatch(w, r, userView(r, u)) {
+		return
+	}
+	before := *u
+	u.UpdateEmail(req.Email)
+	if err := Validate(u); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := h.Repo.Update(r.Context(), u); err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	h.Audit.Record(r.Context(), audit.Update, "user", u.ID, &before, u)
+	view := userView(r, u)
+	w.Header().Set("ETag", weakETag(view))
+	writeJSON(w, http.StatusOK, view)
+}
+
+// Patch handles PATCH /api/users/{id}, changing only the fields present in
+// the body. A body with no fields returns the user unchanged. If-Match is
+// honored as in Update.
+func (h *UserHandlers) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	var req patchUserRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	u, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	if !checkIfMatch(w, r, userView(r, u)) {
+		return
+	}
+	before := *u
+	if req.Name == nil && req.Email == nil {
+		writeJSON(w, http.StatusOK, userView(r, u))
+		return
+	}
+	if req.Name != nil {
+		if err := u.UpdateName(utils.SanitizeText(*req.Name)); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+	}
+	if req.Email != nil {
+		u.UpdateEmail(*req.Email)
+	}
+	if err := Validate(u); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := h.Repo.Update(r.Context(), u); err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	h.Audit.Record(r.Context(), audit.Update, "user", u.ID, &before, u)
+	view := userView(r, u)
+	w.Header().Set("ETag", weakETag(view))
+	writeJSON(w, http.StatusOK, view)
+}
+
+// Delete handles DELETE /api/users/{id}.
+func (h *UserHandlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	// Read first so the audit entry has what was deleted
+	u, err := h.Repo.Get(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	if err := h.Repo.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	h.Audit.Record(r.Context(), audit.Delete, "user", id, u, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List handles GET /api/users, ordered by ID. Users are listed in their
+// public form, without email addresses; a user sees their own from Get.
+//
+// Pages are fetched by cursor, with ?page_size= and then each page's
+// next_cursor as ?cursor=, so users added or deleted in between are
+// neither skipped nor repeated. The older ?limit=&offset= is still
+// accepted; those pages carry the next_cursor too, and a notice
+// recommending it.
+func (h *UserHandlers) List(w http.ResponseWriter, r *http.Request) {
+	if usesCursor(r) {
+		h.listByCursor(w, r)
+		return
+	}
+	p, err := parsePageParams(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	total, err := h.Repo.Count(r.Context())
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	users, err := h.Repo.List(r.Context(), p.Limit, p.Offset)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	resp := newPageResponse(publicUsers(users), p, total)
+	resp.Pagination.Notice = offsetNotice
+	if resp.Pagination.NextOffset != nil {
+		next := encodeCursor(users[len(users)-1].ID)
+		resp.Pagination.NextCursor = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// listByCursor serves List for a cursor and page_size: the users after the
+// cursor's ID, read one past the page to tell whether another follows.
+func (h *UserHandlers) listByCursor(w http.ResponseWriter, r *http.Request) {
+	c, err := parseCursorParams(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	total, err := h.Repo.Count(r.Context())
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	users, err := h.Repo.List(r.Context(), c.Size+1, 0, repository.After(c.After))
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	more := len(users) > c.Size
+	if more {
+		users = users[:c.Size]
+	}
+	lastID := 0
+	if len(users) > 0 {
+		lastID = users[len(users)-1].ID
+	}
+	writeJSON(w, http.StatusOK, newCursorPageResponse(publicUsers(users), c, total, more, lastID))
+}
+
+func publicUsers(users []*models.User) []models.PublicUser {
+	public := make([]models.PublicUser, len(users))
+	for i := range users {
+		public[i] = users[i].Public()
+	}
+	return public
+}
+
+// MaxBatchUsers caps the ids accepted by POST /api/users/batch.
+var MaxBatchUsers = 100
+
+type batchUsersRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// batchUsersResponse lists the users found, in their public form, and the
+// requested ids that matched no user, both in request order.
+type batchUsersResponse struct {
+	Data    []models.PublicUser `json:"data"`
+	Missing []int               `json:"missing"`
+}
+
+// Batch handles POST /api/users/batch, fetching up to MaxBatchUsers users
+// with one GetMany call so a client rendering many authors need not make a
+// request per user. Repeated ids are answered once.
+func (h *UserHandlers) Batch(w http.ResponseWriter, r *http.Request) {
+	var req batchUsersRequest
+	if !DecodeJSON(w, r, &req) {
+		return
+	}
+	if len(req.IDs) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "ids must contain at least one id")
+		return
+	}
+	if len(req.IDs) > MaxBatchUsers {
+		writeJSONError(w, http.StatusBadRequest,
+			"at most "+strconv.Itoa(MaxBatchUsers)+" users may be fetched at once")
+		return
+	}
+	users, err := h.Repo.GetMany(r.Context(), req.IDs)
+	if err != nil {
+		writeRepoError(w, r, err, "user")
+		return
+	}
+	resp := batchUsersResponse{Data: make([]models.PublicUser, len(users)), Missing: []int{}}
+	found := make(map[int]bool, len(users))
+	for i, u := range users {
+		resp.Data[i] = u.Public()
+		found[u.ID] = true
+	}
+	for _, id := range req.IDs {
+		if !found[id] {
+			resp.Missing = append(resp.Missing, id)
+			found[id] = true // report each missing id once
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// userID parses the {id} path value, answering 400 when it is not an integer.
+func userID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	return pathID(w, r, "user")
+}
@@ -0,0 +1,554 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-project/internal/audit"
+	"go-project/internal/auth"
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func newUserTestRouter(repo repository.UserRepository) *Router {
+	h := NewUserHandlers(repo)
+	rt := NewRouter()
+	rt.Post("/api/users", http.HandlerFunc(h.Create))
+	rt.Get("/api/users", http.HandlerFunc(h.List))
+	rt.Post("/api/users/batch", http.HandlerFunc(h.Batch))
+	rt.Get("/api/users/{id}", http.HandlerFunc(h.Get))
+	rt.Put("/api/users/{id}", http.HandlerFunc(h.Update))
+	rt.Patch("/api/users/{id}", http.HandlerFunc(h.Patch))
+	rt.Delete("/api/users/{id}", http.HandlerFunc(h.Delete))
+	return rt
+}
+
+func seedUser(t *testing.T, repo repository.UserRepository, name, email string) *models.User {
+	t.Helper()
+	u := models.NewUser(name, email)
+	if err := repo.Create(context.Background(), u); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return u
+}
+
+func TestUserHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		wantStatus   int
+		wantLocation string
+		wantBody     string
+	}{
+		{name: "create", method: "POST", path: "/api/users", body: `{"name":"Bob","email":"bob@example.com"}`,
+			wantStatus: http.StatusCreated, wantLocation: "/api/users/2", wantBody: `"email":"bob@example.com"`},
+		{name: "create invalid email", method: "POST", path: "/api/users", body: `{"name":"Bob","email":"bob"}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"field":"email","message":"is not a valid email address"}`},
+		{name: "create missing name and email", method: "POST", path: "/api/users", body: `{}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"fields":[{"field":"name","message":"is required"},{"field":"email","message":"is required"}]`},
+		{name: "create normalizes email", method: "POST", path: "/api/users", body: `{"name":"Bob","email":" Bob@EXAMPLE.com "}`,
+			wantStatus: http.StatusCreated, wantLocation: "/api/users/2", wantBody: `"email":"Bob@example.com"`},
+		{name: "create duplicate email", method: "POST", path: "/api/users", body: `{"name":"Imposter","email":"ADA@example.COM"}`,
+			wantStatus: http.StatusConflict, wantBody: `"code":"conflict"`},
+		{name: "create strips markup from name", method: "POST", path: "/api/users", body: `{"name":"<b>Bob</b><script>alert(1)</script>","email":"bob@example.com"}`,
+			wantStatus: http.StatusCreated, wantLocation: "/api/users/2", wantBody: `"name":"Bob",`},
+		{name: "create name of only markup", method: "POST", path: "/api/users", body: `{"name":"<script>alert(1)</script>","email":"bob@example.com"}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"field":"name","message":"is required"}`},
+		{name: "create malformed body", method: "POST", path: "/api/users", body: `{`,
+			wantStatus: http.StatusBadRequest},
+		{name: "get", method: "GET", path: "/api/users/1", wantStatus: http.StatusOK, wantBody: `"name":"Ada"`},
+		{name: "get unknown", method: "GET", path: "/api/users/99", wantStatus: http.StatusNotFound},
+		{name: "get bad id", method: "GET", path: "/api/users/abc", wantStatus: http.StatusBadRequest},
+		{name: "update", method: "PUT", path: "/api/users/1", body: `{"email":"ada@new.example.com"}`,
+			wantStatus: http.StatusOK, wantBody: `"email":"ada@new.example.com"`},
+		{name: "update invalid email", method: "PUT", path: "/api/users/1", body: `{"email":"nope"}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `"field":"email"`},
+		{name: "update unknown", method: "PUT", path: "/api/users/99", body: `{"email":"x@example.com"}`,
+			wantStatus: http.StatusNotFound},
+		{name: "patch name", method: "PATCH", path: "/api/users/1", body: `{"name":"Ada Lovelace"}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Ada Lovelace","email":"ada@example.com"`},
+		{name: "patch name strips markup", method: "PATCH", path: "/api/users/1", body: `{"name":"<i>Ada</i> Lovelace"}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Ada Lovelace","email":"ada@example.com"`},
+		{name: "patch empty name", method: "PATCH", path: "/api/users/1", body: `{"name":""}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"field":"name","message":"is required"}`},
+		{name: "patch empty email", method: "PATCH", path: "/api/users/1", body: `{"email":""}`,
+			wantStatus: http.StatusUnprocessableEntity, wantBody: `{"field":"email","message":"is required"}`},
+		{name: "patch no fields", method: "PATCH", path: "/api/users/1", body: `{}`,
+			wantStatus: http.StatusOK, wantBody: `"name":"Ada"`},
+		{name: "patch unknown field", method: "PATCH", path: "/api/users/1", body: `{"nmae":"x"}`,
+			wantStatus: http.StatusBadRequest},
+		{name: "patch unknown", method: "PATCH", path: "/api/users/99", body: `{"name":"x"}`,
+			wantStatus: http.StatusNotFound},
+		{name: "delete", method: "DELETE", path: "/api/users/1", wantStatus: http.StatusNoContent},
+		{name: "delete unknown", method: "DELETE", path: "/api/users/99", wantStatus: http.StatusNotFound},
+		{name: "list", method: "GET", path: "/api/users", wantStatus: http.StatusOK, wantBody: `"name":"Ada"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewInMemoryUserRepo()
+			seedUser(t, repo, "Ada", "ada@example.com")
+			rt := newUserTestRouter(repo)
+
+			// As Ada, who may see her own email
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %s does not contain %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestUserHandlersCreateFromForm(t *testing.T) {
+	create := func(contentType, body string) (int, map[string]any) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		newUserTestRouter(repository.NewInMemoryUserRepo()).ServeHTTP(rec, req)
+		var got map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+		}
+		delete(got, "created_at")
+		delete(got, "updated_at")
+		return rec.Code, got
+	}
+	tests := []struct {
+		name       string
+		json, form string
+		wantStatus int
+	}{
+		{"valid", `{"name":"Bob <b>Smith</b>","email":" Bob@EXAMPLE.com "}`, "name=Bob+%3Cb%3ESmith%3C%2Fb%3E&email=+Bob%40EXAMPLE.com+", http.StatusCreated},
+		{"invalid", `{"name":"","email":"bob"}`, "name=&email=bob", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonStatus, fromJSON := create("application/json", tt.json)
+			formStatus, fromForm := create("application/x-www-form-urlencoded", tt.form)
+			if jsonStatus != tt.wantStatus || formStatus != tt.wantStatus {
+				t.Fatalf("status from JSON = %d, from form = %d, want %d", jsonStatus, formStatus, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(fromJSON, fromForm) {
+				t.Fatalf("from form = %v, want the same as from JSON, %v", fromForm, fromJSON)
+			}
+		})
+	}
+}
+
+func TestUserHandlersPatchEmailKeepsName(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	rt := newUserTestRouter(repo)
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("PATCH", "/api/users/1", strings.NewReader(`{"email":"ada@new.example.com"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+
+	u, err := repo.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if u.Name != "Ada" || u.Email != "ada@new.example.com" {
+		t.Fatalf("stored user = %q <%s>, want Ada <ada@new.example.com>", u.Name, u.Email)
+	}
+}
+
+func TestUserHandlersPatchValidationLeavesUserUnchanged(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	rt := newUserTestRouter(repo)
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("PATCH", "/api/users/1", strings.NewReader(`{"name":"Ada L","email":"nope"}`)))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+
+	u, _ := repo.Get(context.Background(), 1)
+	if u.Name != "Ada" || u.Email != "ada@example.com" {
+		t.Fatalf("stored user = %q <%s>, want it unchanged", u.Name, u.Email)
+	}
+}
+
+func TestUserHandlersDeleteRemovesUser(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	seedUser(t, repo, "Grace", "grace@example.com")
+	rt := newUserTestRouter(repo)
+
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/api/users/1", nil))
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users", nil))
+	var page pageResponse[models.PublicUser]
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].Name != "Grace" {
+		t.Fatalf("users after delete = %+v, want only Grace", page.Data)
+	}
+}
+
+func TestUserHandlersAuditEmailChange(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	u := models.NewUser("Ada", "ada@example.com")
+	u.PasswordHash = "$2a$10$secrethash"
+	if err := repo.Create(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+	var log bytes.Buffer
+	h := NewUserHandlers(repo)
+	h.Audit = audit.NewLogger(&log)
+
+	req := httptest.NewRequest("PUT", "/api/users/1", strings.NewReader(`{"email":"ada@new.example.com"}`))
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+	h.Update(rec, req.WithContext(auth.WithUserID(req.Context(), 9)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(log.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("audit log has %d entries, want 1:\n%s", len(lines), log.String())
+	}
+	var e audit.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Actor != "user:9" || e.Action != audit.Update || e.Entity != "user" || e.EntityID != 1 {
+		t.Errorf("entry = %+v, want user 9 updating user 1", e)
+	}
+	email := e.Changes["email"]
+	if string(email.Before) != `"a*a@example.com"` || string(email.After) != `"a*a@new.example.com"` {
+		t.Errorf("email change = %s -> %s, want the old and new address masked", email.Before, email.After)
+	}
+	if strings.Contains(log.String(), "ada@") {
+		t.Errorf("audit entry has an unmasked email: %s", log.String())
+	}
+	if strings.Contains(log.String(), "password") || strings.Contains(log.String(), "secrethash") {
+		t.Errorf("audit entry has password data: %s", log.String())
+	}
+}
+
+func TestUserHandlersUpdateToTakenEmailConflicts(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	seedUser(t, repo, "Grace", "grace@example.com")
+	rt := newUserTestRouter(repo)
+
+	for _, req := range []struct{ method, body string }{
+		{"PUT", `{"email":"Ada@Example.com"}`},
+		{"PATCH", `{"email":"ada@example.com"}`},
+	} {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(req.method, "/api/users/2", strings.NewReader(req.body)))
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("%s: status = %d, want 409 (body %s)", req.method, rec.Code, rec.Body.String())
+		}
+	}
+	if u, _ := repo.Get(context.Background(), 2); u.Email != "grace@example.com" {
+		t.Fatalf("email after conflicting updates = %q, want it unchanged", u.Email)
+	}
+}
+
+// cancelingUserRepo cancels the request's context as the handler reaches the
+// repository, as if the client went away mid-request.
+type cancelingUserRepo struct {
+	repository.UserRepository
+	cancel context.CancelFunc
+}
+
+func (r cancelingUserRepo) Create(ctx context.Context, u *models.User) error {
+	r.cancel()
+	return r.UserRepository.Create(ctx, u)
+}
+
+func TestUserHandlersCreateCanceledRequest(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, "POST", "/api/users", strings.NewReader(`{"name":"Ada","email":"ada@example.com"}`))
+	rec := httptest.NewRecorder()
+	newUserTestRouter(cancelingUserRepo{repo, cancel}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 (body %s)", rec.Code, rec.Body.String())
+	}
+	if n, _ := repo.Count(context.Background()); n != 0 {
+		t.Fatalf("canceled request stored %d users", n)
+	}
+}
+
+func TestUserHandlersEmailVisibility(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	seedUser(t, repo, "Ada", "ada@example.com")
+	seedUser(t, repo, "Grace", "grace@example.com")
+	rt := newUserTestRouter(repo)
+
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users", nil))
+	var list pageResponse[map[string]any]
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Data) != 2 {
+		t.Fatalf("listed %d users, want 2", len(list.Data))
+	}
+	for _, u := range list.Data {
+		if _, ok := u["email"]; ok {
+			t.Errorf("list entry %v has an email key", u)
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		asUser    int
+		wantEmail any
+	}{
+		{"anonymous", 0, nil},
+		{"another user", 2, nil},
+		{"the user themself", 1, "ada@example.com"},
+	} {
+		req := httptest.NewRequest("GET", "/api/users/1", nil)
+		if tc.asUser != 0 {
+			req = req.WithContext(auth.WithUserID(req.Context(), tc.asUser))
+		}
+		rec = httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		var detail map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+			t.Fatalf("%s: decode detail: %v", tc.name, err)
+		}
+		if detail["email"] != tc.wantEmail {
+			t.Errorf("%s: detail email = %v, want %v", tc.name, detail["email"], tc.wantEmail)
+		}
+	}
+
+	// Changing the name must not reveal the email either
+	rec = httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("PATCH", "/api/users/1", strings.NewReader(`{"name":"Ada Lovelace"}`)))
+	if strings.Contains(rec.Body.String(), "ada@example.com") {
+		t.Errorf("anonymous PATCH response %s has the email", rec.Body)
+	}
+}
+
+func TestUserHandlersListPagination(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	for i := 0; i < 150; i++ {
+		seedUser(t, repo, fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i))
+	}
+	rt := newUserTestRouter(repo)
+	intp := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		query     string
+		wantCount int
+		wantFirst int
+		wantLimit int
+		wantNext  *int
+	}{
+		{"defaults", "", 20, 1, 20, intp(20)},
+		{"limit and offset", "?limit=5&offset=10", 5, 11, 5, intp(15)},
+		{"limit capped", "?limit=1000", 100, 1, 100, intp(100)},
+		{"last page", "?limit=100&offset=100", 50, 101, 100, nil},
+		{"offset past end", "?offset=500", 0, 0, 20, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+			}
+			var page pageResponse[models.PublicUser]
+			if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			meta := page.Pagination
+			if len(page.Data) != tt.wantCount || meta.Count != tt.wantCount {
+				t.Fatalf("returned %d users (count %d), want %d", len(page.Data), meta.Count, tt.wantCount)
+			}
+			if tt.wantCount > 0 && page.Data[0].ID != tt.wantFirst {
+				t.Errorf("first ID = %d, want %d", page.Data[0].ID, tt.wantFirst)
+			}
+			if page.Data == nil {
+				t.Error("data is null, want an empty array")
+			}
+			if meta.Total != 150 || meta.Limit != tt.wantLimit {
+				t.Errorf("total, limit = %d, %d, want 150, %d", meta.Total, meta.Limit, tt.wantLimit)
+			}
+			if (meta.NextOffset == nil) != (tt.wantNext == nil) || (meta.NextOffset != nil && *meta.NextOffset != *tt.wantNext) {
+				t.Errorf("next_offset = %v, want %v", meta.NextOffset, tt.wantNext)
+			}
+		})
+	}
+}
+
+// scriptedIDs hands out the given IDs in order.
+type scriptedIDs struct {
+	mu  sync.Mutex
+	ids []int
+}
+
+func (s *scriptedIDs) NextID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+	return id
+}
+
+func TestUserHandlersListByCursor(t *testing.T) {
+	// 15 is created after the first page is read, among the IDs before it
+	repo := repository.NewInMemoryUserRepo(repository.WithIDs(&scriptedIDs{ids: []int{10, 20, 30, 40, 50, 15, 60}}))
+	for i := range 5 {
+		seedUser(t, repo, fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i))
+	}
+	rt := newUserTestRouter(repo)
+	fetch := func(query string) cursorPageResponse[models.PublicUser] {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users?"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("?%s: status = %d, want 200 (body %s)", query, rec.Code, rec.Body.String())
+		}
+		var page cursorPageResponse[models.PublicUser]
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return page
+	}
+
+	var seen []int
+	page := fetch("page_size=2")
+	for pages := 1; ; pages++ {
+		for _, u := range page.Data {
+			seen = append(seen, u.ID)
+		}
+		if pages == 1 {
+			seedUser(t, repo, "early", "early@example.com")
+			seedUser(t, repo, "late", "late@example.com")
+		}
+		if page.Pagination.PageSize != 2 || page.Pagination.Count != len(page.Data) {
+			t.Errorf("page %d: pagination = %+v, want page_size 2 and its count", pages, page.Pagination)
+		}
+		if page.Pagination.NextCursor == nil {
+			break
+		}
+		if pages > 5 {
+			t.Fatal("pagination does not end")
+		}
+		page = fetch("page_size=2&cursor=" + *page.Pagination.NextCursor)
+	}
+	if want := []int{10, 20, 30, 40, 50, 60}; !slices.Equal(seen, want) {
+		t.Errorf("users seen across pages = %v, want %v: each one once, and the one added after the cursor", seen, want)
+	}
+
+	// Offset pages point at the same next page by cursor
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users?limit=2", nil))
+	var legacy pageResponse[models.PublicUser]
+	if err := json.NewDecoder(rec.Body).Decode(&legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Pagination.NextCursor == nil || legacy.Pagination.Notice == "" {
+		t.Fatalf("offset pagination = %+v, want a next_cursor and a notice", legacy.Pagination)
+	}
+	if next := fetch("cursor=" + *legacy.Pagination.NextCursor); len(next.Data) == 0 || next.Data[0].ID != 20 {
+		t.Errorf("page after the offset page's cursor starts at %v, want user 20", next.Data)
+	}
+}
+
+func TestUserHandlersListRejectsBadPageParams(t *testing.T) {
+	rt := newUserTestRouter(repository.NewInMemoryUserRepo())
+	for _, q := range []string{"limit=-1", "limit=0", "limit=ten", "offset=-5", "offset=1.5",
+		"page_size=0", "cursor=!!", "cursor=abc", "cursor=" + encodeCursor(2) + "&offset=2", "page_size=5&limit=5"} {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users?"+q, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("?%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestUserHandlersBatch(t *testing.T) {
+	repo := repository.NewInMemoryUserRepo()
+	ada := seedUser(t, repo, "Ada", "ada@example.com")
+	grace := seedUser(t, repo, "Grace", "grace@example.com")
+	rt := newUserTestRouter(repo)
+
+	body := fmt.Sprintf(`{"ids":[%d,404,%d,%d,404]}`, grace.ID, ada.ID, grace.ID)
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/users/batch", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "@example.com") {
+		t.Fatalf("body %s exposes email addresses", rec.Body)
+	}
+	var resp batchUsersResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var names []string
+	for _, u := range resp.Data {
+		names = append(names, u.Name)
+	}
+	if want := []string{"Grace", "Ada"}; !slices.Equal(names, want) {
+		t.Errorf("users = %v, want %v in request order", names, want)
+	}
+	if want := []int{404}; !slices.Equal(resp.Missing, want) {
+		t.Errorf("missing = %v, want %v", resp.Missing, want)
+	}
+}
+
+func TestUserHandlersBatchRejectsBadRequests(t *testing.T) {
+	tooMany := make([]string, MaxBatchUsers+1)
+	for i := range tooMany {
+		tooMany[i] = strconv.Itoa(i + 1)
+	}
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no ids", `{"ids":[]}`},
+		{"over the cap", `{"ids":[` + strings.Join(tooMany, ",") + `]}`},
+		{"ids are not integers", `{"ids":["1"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newUserTestRouter(repository.NewInMemoryUserRepo()).ServeHTTP(rec,
+				httptest.NewRequest(http.MethodPost, "/api/users/batch", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400 (body %s)", rec.Code, rec.Body)
+			}
+			decodeErrorEnvelope(t, rec)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import "go-project/internal/models"
+
+// Validate checks v, a model or decoded request body, against its validate
+// struct tags, or with its own Validate method when it has one; the models'
+// Validate methods check their tags first and then the rules tags cannot
+// express. Every failing field is reported together in a
+// *models.ValidationError, which writeValidationError answers with 422.
+func Validate(v any) error {
+	if m, ok := v.(interface{ Validate() error }); ok {
+		return m.Validate()
+	}
+	return models.ValidateStruct(v)
+}
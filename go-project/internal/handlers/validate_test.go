@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func TestValidateReportsEveryFailingTag(t *testing.T) {
+	type signup struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
+		Age   int    `json:"age" validate:"gte=0"`
+	}
+	tests := []struct {
+		name string
+		v    any
+		want []models.FieldError
+	}{
+		{"user", &models.User{Email: "not-an-email"}, []models.FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "email", Message: "is not a valid email address"},
+		}},
+		{"request body", signup{Email: "ada@", Age: -1}, []models.FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "email", Message: "is not a valid email address"},
+			{Field: "age", Message: "must not be negative"},
+		}},
+		{"product", &models.Product{Price: models.NewMoney(-1, "usd")}, []models.FieldError{
+			{Field: "name", Message: "is required"},
+			{Field: "price", Message: "must not be negative"},
+			{Field: "price.currency", Message: "must be a three-letter ISO 4217 code"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			rec := httptest.NewRecorder()
+			writeValidationError(rec, err)
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("status = %d, want 422 for %v", rec.Code, err)
+			}
+			var env struct {
+				Error struct {
+					Code   string              `json:"code"`
+					Fields []models.FieldError `json:"fields"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+				t.Fatal(err)
+			}
+			if env.Error.Code != CodeInvalidRequest || !reflect.DeepEqual(env.Error.Fields, tt.want) {
+				t.Fatalf("error = %+v, want code %s with fields %+v", env.Error, CodeInvalidRequest, tt.want)
+			}
+		})
+	}
+
+	if err := Validate(&models.User{Name: "Ada", Email: "ada@example.com"}); err != nil {
+		t.Fatalf("valid user: %v", err)
+	}
+	if err := Validate("not a struct"); err == nil {
+		t.Fatal("Validate of a string: want an error")
+	}
+}
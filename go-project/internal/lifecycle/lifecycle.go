@@ -0,0 +1,133 @@
+// Package lifecycle starts the service's background workers together and
+// stops them together on shutdown, so none is left running, or cut off
+// mid-task, when the process exits.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Manager runs registered workers. Register every worker, call Start once
+// on boot and Shutdown once on the way out. It is safe for concurrent use.
+type Manager struct {
+	mu       sync.Mutex
+	workers  []*worker
+	cancel   context.CancelFunc
+	started  bool
+	shutdown bool
+}
+
+type worker struct {
+	name  string
+	start func(ctx context.Context)
+	stop  func(ctx context.Context) error
+	done  chan struct{}
+}
+
+// New returns a Manager with no workers.
+func New() *Manager {
+	return &Manager{}
+}
+
+// Register adds a worker known by name in logs and errors. start runs in
+// its own goroutine from Start and should return once its context is
+// canceled; stop is called once by Shutdown, after that cancellation, to
+// finish anything start handed off, such as flushing a buffer. Either may
+// be nil. Register panics when called after Start.
+func (m *Manager) Register(name string, start func(ctx context.Context), stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		panic("lifecycle: Register of " + name + " after Start")
+	}
+	m.workers = append(m.workers, &worker{name: name, start: start, stop: stop, done: make(chan struct{})})
+}
+
+// Start runs the start function of every registered worker with a context
+// derived from ctx that Shutdown cancels. Calls after the first do
+// nothing.
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started {
+		return
+	}
+	m.started = true
+	ctx, m.cancel = context.WithCancel(ctx)
+	for _, w := range m.workers {
+		if w.start == nil {
+			close(w.done)
+			continue
+		}
+		go func() {
+			defer close(w.done)
+			w.start(ctx)
+		}()
+	}
+}
+
+// Shutdown cancels the workers' context, then calls every worker's stop
+// function at once and waits for it and the worker's start function to
+// return, giving up on a worker once ctx is done. Workers that fail or do
+// not finish in time are reported together in the returned error, in the
+// order they were registered. Calls after the first do nothing and return
+// nil.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.shutdown {
+		m.mu.Unlock()
+		return nil
+	}
+	m.shutdown = true
+	if m.cancel != nil {
+		m.cancel()
+	}
+	started, workers := m.started, m.workers
+	m.mu.Unlock()
+
+	errs := make([]error, len(workers))
+	var wg sync.WaitGroup
+	for i, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.shutdown(ctx, started); err != nil {
+				errs[i] = fmt.Errorf("lifecycle: %s: %w", w.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// shutdown stops w and waits for it, returning ctx's error when it does
+// not finish in time. A worker that never started has only its stop
+// function to wait for.
+func (w *worker) shutdown(ctx context.Context, started bool) error {
+	stopped := make(chan error, 1)
+	go func() {
+		if w.stop == nil {
+			stopped <- nil
+			return
+		}
+		stopped <- w.stop(ctx)
+	}()
+	var err error
+	select {
+	case err = <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if !started {
+		return err
+	}
+	select {
+	case <-w.done:
+		return err
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+}
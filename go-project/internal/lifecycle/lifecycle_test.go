@@ -0,0 +1,103 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownStopsEachWorkerOnce(t *testing.T) {
+	m := New()
+	var stops atomic.Int32
+	exited := make(chan struct{})
+	m.Register("ticker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(exited)
+	}, func(ctx context.Context) error {
+		stops.Add(1)
+		return nil
+	})
+	m.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Fatal("Shutdown returned before the worker's start func did")
+	}
+	if n := stops.Load(); n != 1 {
+		t.Fatalf("stop called %d times, want once", n)
+	}
+}
+
+func TestShutdownTimesOutOnHangingWorker(t *testing.T) {
+	m := New()
+	release := make(chan struct{})
+	defer close(release)
+	m.Register("stuck", func(ctx context.Context) {
+		<-release // ignores cancellation
+	}, nil)
+	var stopped atomic.Bool
+	m.Register("well-behaved", func(ctx context.Context) { <-ctx.Done() }, func(ctx context.Context) error {
+		stopped.Store(true)
+		return nil
+	})
+	m.Register("slow stop", nil, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	m.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := m.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Shutdown took %v, want it to give up at the deadline", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want a deadline error", err)
+	}
+	for _, name := range []string{"stuck", "slow stop"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %q", err, name)
+		}
+	}
+	if strings.Contains(err.Error(), "well-behaved") {
+		t.Errorf("error %q names a worker that stopped", err)
+	}
+	if !stopped.Load() {
+		t.Error("a worker registered alongside the hanging one was not stopped")
+	}
+}
+
+func TestShutdownReportsStopErrors(t *testing.T) {
+	m := New()
+	boom := errors.New("flush failed")
+	m.Register("exporter", nil, func(context.Context) error { return boom })
+	m.Start(context.Background())
+	if err := m.Shutdown(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("Shutdown = %v, want %v", err, boom)
+	}
+}
+
+func TestRegisterAfterStartPanics(t *testing.T) {
+	m := New()
+	m.Start(context.Background())
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Register after Start did not panic")
+		}
+	}()
+	m.Register("late", nil, nil)
+}
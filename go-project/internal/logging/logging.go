@@ -0,0 +1,83 @@
+// Package logging configures the server's structured logger on top of
+// log/slog and carries the request ID that every request-scoped line
+// includes.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go-project/pkg/utils"
+)
+
+// New returns a logger writing to w that drops records below level. It
+// emits text lines when dev is set, which read better in a terminal, and
+// JSON lines otherwise, for log collectors in production. Attributes are
+// passed through Redact.
+func New(w io.Writer, dev bool, level slog.Leveler) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: Redact}
+	if dev {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Redacted replaces the value of secret attributes.
+const Redacted = "[REDACTED]"
+
+// Redact is a slog.HandlerOptions.ReplaceAttr that keeps personal data out
+// of the logs wherever it is logged from, including inside groups: a string
+// attribute whose key ends in "email" is masked with utils.MaskEmail, and
+// one whose key contains "password" is replaced with Redacted.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindString {
+		return a
+	}
+	key := strings.ToLower(a.Key)
+	switch {
+	case strings.Contains(key, "password"):
+		return slog.String(a.Key, Redacted)
+	case strings.HasSuffix(key, "email"):
+		return slog.String(a.Key, utils.MaskEmail(a.Value.String()))
+	}
+	return a
+}
+
+// ParseLevel parses debug, info, warn or error, in any case and optionally
+// with an offset such as "info+2". An empty string means info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s = strings.TrimSpace(s); s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: want debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// With returns slog.Default() with a request_id attribute when ctx carries
+// one, so every line logged for a request can be correlated.
+func With(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id, ok := RequestIDFromContext(ctx); ok {
+		logger = logger.With(slog.String("request_id", id))
+	}
+	return logger
+}
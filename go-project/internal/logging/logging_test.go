@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func TestNewJSONEmitsLevelAndMessage(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, false, slog.LevelInfo).Info("server started", "addr", ":8080")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v\n%s", err, buf.String())
+	}
+	if entry["level"] != "INFO" || entry["msg"] != "server started" || entry["addr"] != ":8080" {
+		t.Fatalf("entry = %v", entry)
+	}
+}
+
+func TestNewDevEmitsText(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, true, slog.LevelInfo).Info("server started")
+	if got := buf.String(); !strings.Contains(got, "level=INFO") || !strings.Contains(got, `msg="server started"`) {
+		t.Fatalf("text line = %q", got)
+	}
+}
+
+func TestNewFiltersBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, false, slog.LevelInfo)
+	logger.Debug("noisy detail")
+	if buf.Len() != 0 {
+		t.Fatalf("debug line logged at info level: %s", buf.String())
+	}
+	logger.Warn("disk almost full")
+	if !strings.Contains(buf.String(), "disk almost full") {
+		t.Fatalf("warn line missing: %q", buf.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want slog.Level
+	}{
+		{"", slog.LevelInfo},
+		{"debug", slog.LevelDebug},
+		{" WARN ", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"info+2", slog.LevelInfo + 2},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(\"verbose\") succeeded")
+	}
+}
+
+func TestWithAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(l *slog.Logger) { slog.SetDefault(l) }(slog.Default())
+	slog.SetDefault(New(&buf, false, slog.LevelInfo))
+
+	With(WithRequestID(context.Background(), "req-42")).Info("handled")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+	if entry["request_id"] != "req-42" {
+		t.Fatalf("request_id = %v, want req-42", entry["request_id"])
+	}
+
+	buf.Reset()
+	With(context.Background()).Info("no request")
+	if strings.Contains(buf.String(), "request_id") {
+		t.Fatalf("request_id logged without one in the context: %s", buf.String())
+	}
+}
+
+func TestNewRedactsUsers(t *testing.T) {
+	u := &models.User{ID: 7, Name: "Ada", Email: "ada.lovelace@example.com", PasswordHash: "$2a$10$secrethash"}
+	for _, dev := range []bool{false, true} {
+		var buf bytes.Buffer
+		New(&buf, dev, slog.LevelInfo).Info("user changed",
+			"user", u,
+			slog.Group("request", "email", "grace@example.com", "password", "hunter22"),
+		)
+		out := buf.String()
+		for _, leak := range []string{"ada.lovelace@", "grace@", "secrethash", "hunter22"} {
+			if strings.Contains(out, leak) {
+				t.Errorf("dev=%v: log line leaks %q: %s", dev, leak, out)
+			}
+		}
+		for _, want := range []string{"a**********e@example.com", "g***e@example.com", Redacted} {
+			if !strings.Contains(out, want) {
+				t.Errorf("dev=%v: log line lacks %q: %s", dev, want, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package metrics defines the metrics exported by the server and renders
+// them in the Prometheus text exposition format. Counters and histograms
+// are implemented here on sync/atomic rather than with the Prometheus
+// client library, so the server can be built where that library is not
+// allowed.
+package metrics
+
+var (
+	// RequestsTotal counts handled HTTP requests. The path label is the
+	// matched route pattern, never the raw URL, to keep cardinality bounded.
+	RequestsTotal = NewCounter("http_requests_total",
+		"Total number of HTTP requests handled, by method, route and status code.",
+		"method", "path", "status")
+
+	// RequestDuration observes request latency in seconds.
+	RequestDuration = NewHistogram("http_request_duration_seconds",
+		"HTTP request latency in seconds, by method and route.",
+		DefBuckets, "method", "path")
+)
@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxSeries caps the label combinations kept per metric. Once a metric has
+// this many, samples with new combinations are recorded under OtherLabel
+// for every label instead, so a label fed from user input cannot grow
+// memory and scrape size without bound.
+var MaxSeries = 1000
+
+// OtherLabel is the label value samples fall back to past MaxSeries.
+const OtherLabel = "other"
+
+// DefBuckets are the default histogram upper bounds, in seconds, suited to
+// request latencies. They match the Prometheus client's defaults.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry holds metrics to be rendered together. It is safe for concurrent
+// use.
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]metric)}
+}
+
+// Default is the registry NewCounter and NewHistogram add to and Handler
+// renders.
+var Default = NewRegistry()
+
+// metric is a named family of series.
+type metric interface {
+	write(w io.Writer)
+}
+
+// register adds m under name, panicking on a duplicate since metric names
+// are fixed at compile time.
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[name]; ok {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.metrics[name] = m
+}
+
+// WriteText renders every metric in the Prometheus text exposition format,
+// sorted by name.
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	ms := make([]metric, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		ms[i] = r.metrics[name]
+	}
+	r.mu.Unlock()
+	for _, m := range ms {
+		m.write(w)
+	}
+}
+
+// Handler serves the registry for Prometheus to scrape.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// Handler serves the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// family holds the series of one metric, keyed by their label values.
+type family[S any] struct {
+	name, help string
+	labels     []string
+	newSeries  func() *S
+
+	mu     sync.RWMutex
+	series map[string]*labeledSeries[S]
+}
+
+type labeledSeries[S any] struct {
+	values []string
+	s      *S
+}
+
+func newFamily[S any](name, help string, labels []string, newSeries func() *S) *family[S] {
+	return &family[S]{name: name, help: help, labels: labels, newSeries: newSeries, series: make(map[string]*labeledSeries[S])}
+}
+
+// get returns the series for values, creating it if there is room.
+func (f *family[S]) get(values []string) *S {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.RLock()
+	ls, ok := f.series[key]
+	f.mu.RUnlock()
+	if ok {
+		return ls.s
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ls, ok := f.series[key]; ok {
+		return ls.s
+	}
+	if len(f.series) >= MaxSeries {
+		values = make([]string, len(f.labels))
+		for i := range values {
+			values[i] = OtherLabel
+		}
+		key = strings.Join(values, "\xff")
+		if ls, ok := f.series[key]; ok {
+			return ls.s
+		}
+	}
+	ls = &labeledSeries[S]{values: append([]string(nil), values...), s: f.newSeries()}
+	f.series[key] = ls
+	return ls.s
+}
+
+// sorted returns the series ordered by label values, for stable output.
+func (f *family[S]) sorted() []*labeledSeries[S] {
+	f.mu.RLock()
+	out := make([]*labeledSeries[S], 0, len(f.series))
+	for _, ls := range f.series {
+		out = append(out, ls)
+	}
+	f.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		return strings.Join(out[i].values, "\xff") < strings.Join(out[j].values, "\xff")
+	})
+	return out
+}
+
+func (f *family[S]) writeHeader(w io.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, typ)
+}
+
+// labelString renders names and values as {a="x",b="y"}, or "" without
+// labels. extra is appended as one more pair when non-empty.
+func labelString(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name + `="` + escapeLabel(values[i]) + `"`)
+	}
+	if len(extra) == 2 {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(extra[0] + `="` + escapeLabel(extra[1]) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter is a monotonically increasing count, optionally split by labels.
+type Counter struct {
+	f *family[atomic.Uint64]
+}
+
+// NewCounter registers a counter in Default. labels name the label values
+// Inc and Add take, in order.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewCounter registers a counter in r.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{f: newFamily(name, help, labels, func() *atomic.Uint64 { return new(atomic.Uint64) })}
+	r.register(name, c)
+	return c
+}
+
+// Inc adds one to the series for labelValues.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds n to the series for labelValues.
+func (c *Counter) Add(n uint64, labelValues ...string) {
+	c.f.get(labelValues).Add(n)
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.f.writeHeader(w, "counter")
+	for _, ls := range c.f.sorted() {
+		fmt.Fprintf(w, "%s%s %d\n", c.f.name, labelString(c.f.labels, ls.values), ls.s.Load())
+	}
+}
+
+// Histogram counts observations into cumulative buckets and tracks their
+// sum, optionally split by labels.
+type Histogram struct {
+	f       *family[histogramSeries]
+	buckets []float64
+}
+
+type histogramSeries struct {
+	counts  []atomic.Uint64 // per bucket, plus +Inf last; not cumulative
+	sumBits atomic.Uint64   // math.Float64bits of the sum
+}
+
+// NewHistogram registers a histogram in Default with the given bucket upper
+// bounds, which must be sorted; nil means DefBuckets.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// NewHistogram registers a histogram in r.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic("metrics: buckets of " + name + " are not sorted")
+	}
+	h := &Histogram{buckets: append([]float64(nil), buckets...)}
+	h.f = newFamily(name, help, labels, func() *histogramSeries {
+		return &histogramSeries{counts: make([]atomic.Uint64, len(h.buckets)+1)}
+	})
+	r.register(name, h)
+	return h
+}
+
+// Observe records v in the series for labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	s := h.f.get(labelValues)
+	s.counts[sort.SearchFloat64s(h.buckets, v)].Add(1)
+	for {
+		old := s.sumBits.Load()
+		if s.sumBits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.f.writeHeader(w, "histogram")
+	for _, ls := range h.f.sorted() {
+		var cumulative uint64
+		for i := range ls.s.counts {
+			cumulative += ls.s.counts[i].Load()
+			le := math.Inf(1)
+			if i < len(h.buckets) {
+				le = h.buckets[i]
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.f.name, labelString(h.f.labels, ls.values, "le", formatFloat(le)), cumulative)
+		}
+		labels := labelString(h.f.labels, ls.values)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.f.name, labels, formatFloat(math.Float64frombits(ls.s.sumBits.Load())))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.f.name, labels, cumulative)
+	}
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// parseText reads the exposition format into the declared type of each
+// metric and the value of each sample, keyed by the sample's full series
+// name as written, e.g. `requests_total{code="200"}`.
+func parseText(t *testing.T, text string) (types map[string]string, samples map[string]float64) {
+	t.Helper()
+	types, samples = map[string]string{}, map[string]float64{}
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		line := sc.Text()
+		if rest, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			name, typ, _ := strings.Cut(rest, " ")
+			types[name] = typ
+			continue
+		}
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		v, err := strconv.ParseFloat(line[i+1:], 64)
+		if i < 0 || err != nil {
+			t.Fatalf("malformed sample line %q", line)
+		}
+		samples[line[:i]] = v
+	}
+	return types, samples
+}
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func TestRegistryText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounter("requests_total", "Requests handled.", "method", "code")
+	jobs := r.NewCounter("jobs_total", "Jobs run.")
+	latency := r.NewHistogram("latency_seconds", "Request latency.", []float64{0.1, 1}, "method")
+
+	requests.Inc("GET", "200")
+	requests.Inc("GET", "200")
+	requests.Inc("POST", "500")
+	jobs.Add(5)
+	for _, v := range []float64{0.05, 0.1, 0.5, 3} {
+		latency.Observe(v, "GET")
+	}
+
+	types, samples := parseText(t, scrape(t, r))
+	wantTypes := map[string]string{"requests_total": "counter", "jobs_total": "counter", "latency_seconds": "histogram"}
+	for name, typ := range wantTypes {
+		if types[name] != typ {
+			t.Errorf("TYPE of %s = %q, want %q", name, types[name], typ)
+		}
+	}
+	want := map[string]float64{
+		`requests_total{method="GET",code="200"}`:  2,
+		`requests_total{method="POST",code="500"}`: 1,
+		`jobs_total`: 5,
+		`latency_seconds_bucket{method="GET",le="0.1"}`:  2,
+		`latency_seconds_bucket{method="GET",le="1"}`:    3,
+		`latency_seconds_bucket{method="GET",le="+Inf"}`: 4,
+		`latency_seconds_sum{method="GET"}`:              3.65,
+		`latency_seconds_count{method="GET"}`:            4,
+	}
+	for series, v := range want {
+		if got, ok := samples[series]; !ok || got != v {
+			t.Errorf("%s = %v (present %v), want %v", series, got, ok, v)
+		}
+	}
+	if len(samples) != len(want) {
+		t.Errorf("got %d samples, want %d: %v", len(samples), len(want), samples)
+	}
+}
+
+func TestRegistryEscapesLabelValues(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("paths_total", "Paths.", "path")
+	c.Inc("/a\"b\\c\nd")
+	if text := scrape(t, r); !strings.Contains(text, `paths_total{path="/a\"b\\c\nd"} 1`) {
+		t.Fatalf("label value not escaped:\n%s", text)
+	}
+}
+
+func TestRegistryCapsSeries(t *testing.T) {
+	defer func(n int) { MaxSeries = n }(MaxSeries)
+	MaxSeries = 3
+	r := NewRegistry()
+	c := r.NewCounter("users_total", "Per-user counter.", "user", "kind")
+	for _, user := range []string{"a", "b", "c", "d", "e"} {
+		c.Inc(user, "x")
+	}
+	c.Inc("a", "x")
+
+	_, samples := parseText(t, scrape(t, r))
+	if len(samples) != MaxSeries+1 {
+		t.Fatalf("series = %d, want %d: %v", len(samples), MaxSeries+1, samples)
+	}
+	if got := samples[`users_total{user="a",kind="x"}`]; got != 2 {
+		t.Errorf("existing series kept counting: got %v, want 2", got)
+	}
+	if got := samples[`users_total{user="other",kind="other"}`]; got != 2 {
+		t.Errorf("overflow series = %v, want 2", got)
+	}
+}
+
+func TestRegistryPanicsOnMisuse(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("dup_total", "First.", "a")
+	for name, fn := range map[string]func(){
+		"duplicate name":    func() { r.NewCounter("dup_total", "Second.") },
+		"wrong label count": func() { c.Inc("x", "y") },
+		"unsorted buckets":  func() { r.NewHistogram("h", "Unsorted.", []float64{1, 0.5}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: want a panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
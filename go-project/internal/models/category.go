@@ -0,0 +1,34 @@
+package models
+
+import (
+	"strings"
+
+	"go-project/pkg/utils"
+)
+
+// Category groups products. Products refer to it by Slug, which is derived
+// from the name and used in URLs.
+type Category struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+	Slug string `json:"slug" xml:"slug"`
+}
+
+// NewCategory creates a new Category with its slug made by utils.Slugify.
+// Its ID is left zero for the repository to assign.
+func NewCategory(name string) *Category {
+	name = strings.TrimSpace(name)
+	return &Category{Name: name, Slug: utils.Slugify(name)}
+}
+
+// Validate checks that the Category has a name that yields a slug. All
+// failing fields are reported together in a *ValidationError.
+func (c *Category) Validate() error {
+	var v ValidationError
+	if c.Name == "" {
+		v.Add("name", "is required")
+	} else if c.Slug == "" {
+		v.Add("name", "must contain a letter or digit")
+	}
+	return v.errOrNil()
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCategory(t *testing.T) {
+	c := NewCategory("  Home & Garden ")
+	if c.Name != "Home & Garden" || c.Slug != "home-garden" {
+		t.Fatalf("NewCategory = %+v, want name %q and slug %q", c, "Home & Garden", "home-garden")
+	}
+}
+
+func TestCategoryValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		category *Category
+		want     []string
+	}{
+		{"valid", NewCategory("Books"), nil},
+		{"missing name", NewCategory(" "), []string{"name"}},
+		{"no slug", NewCategory("!!!"), []string{"name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failingFields(t, tt.category.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("failing fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
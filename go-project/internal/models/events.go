@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// PriceChange describes a Product price update.
+type PriceChange struct {
+	ProductID int       `json:"product_id"`
+	Name      string    `json:"name"`
+	OldPrice  Money     `json:"old_price"`
+	NewPrice  Money     `json:"new_price"`
+	At        time.Time `json:"at"`
+}
+
+var priceObserver atomic.Pointer[func(context.Context, PriceChange)]
+
+// OnPriceChange registers fn to be called by NotifyPriceChange with each
+// change it is given and its context, such as that of the request making
+// the change. fn runs synchronously, so it must not block. It replaces any
+// earlier observer; nil removes it.
+func OnPriceChange(fn func(context.Context, PriceChange)) {
+	if fn == nil {
+		priceObserver.Store(nil)
+		return
+	}
+	priceObserver.Store(&fn)
+}
+
+// NotifyPriceChange tells the observer registered with OnPriceChange about
+// c, a change returned by Product.UpdatePrice. Call it once the change has
+// been stored, so observers never hear of one that failed to save.
+func NotifyPriceChange(ctx context.Context, c PriceChange) {
+	if fn := priceObserver.Load(); fn != nil {
+		(*fn)(ctx, c)
+	}
+}
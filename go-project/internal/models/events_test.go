@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOnPriceChange(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeClock(t, start, 0)
+	var got []PriceChange
+	OnPriceChange(func(_ context.Context, c PriceChange) { got = append(got, c) })
+	t.Cleanup(func() { OnPriceChange(nil) })
+
+	p := NewProduct("Widget", usd("5"))
+	p.ID = 7
+	c, changed := p.UpdatePrice(usd("6"))
+	want := PriceChange{ProductID: 7, Name: "Widget", OldPrice: usd("5"), NewPrice: usd("6"), At: start}
+	if !changed || c != want {
+		t.Fatalf("UpdatePrice = %+v, %v; want %+v, true", c, changed, want)
+	}
+	if len(got) != 0 {
+		t.Fatal("UpdatePrice notified the observer before the change was saved")
+	}
+	if _, changed := p.UpdatePrice(usd("6")); changed {
+		t.Fatal("UpdatePrice to the same price reported a change")
+	}
+
+	NotifyPriceChange(context.Background(), c)
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("events = %+v, want [%+v]", got, want)
+	}
+
+	OnPriceChange(nil)
+	NotifyPriceChange(context.Background(), c)
+	if len(got) != 1 {
+		t.Fatal("observer still called after being removed")
+	}
+}
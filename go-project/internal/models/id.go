@@ -0,0 +1,29 @@
+package models
+
+import "sync/atomic"
+
+// ID is the set of types a record's identifier can have. Users, products
+// and orders all have int IDs, which the routes, the SQL schema and API
+// clients depend on; a string key such as a UUID is left for a repository
+// whose records carry one.
+type ID interface {
+	~int | ~int64 | ~string
+}
+
+// IDGenerator assigns the identifiers of new records. Constructors such as
+// NewUser leave the ID zero; the repository storing a record asks its
+// generator for one. Implementations must be safe for concurrent use.
+type IDGenerator[T ID] interface {
+	NextID() T
+}
+
+// Sequence is an IDGenerator handing out increasing ints from 1. The zero
+// value is ready to use.
+type Sequence struct {
+	last atomic.Int64
+}
+
+// NextID returns the next int in the sequence.
+func (s *Sequence) NextID() int {
+	return int(s.last.Add(1))
+}
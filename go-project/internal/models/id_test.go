@@ -0,0 +1,35 @@
+package models
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSequenceIsIncreasingAndUnique(t *testing.T) {
+	var s Sequence
+	for want := 1; want <= 3; want++ {
+		if got := s.NextID(); got != want {
+			t.Fatalf("NextID = %d, want %d", got, want)
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		seen = map[int]bool{}
+		wg   sync.WaitGroup
+	)
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := s.NextID()
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[id] {
+				t.Errorf("ID %d handed out twice", id)
+			}
+			seen[id] = true
+		}()
+	}
+	wg.Wait()
+}
@@ -1,43 +1,297 @@
 package models
 
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"go-project/pkg/utils"
+)
+
+// now is the clock used for timestamps. Tests replace it to get
+// deterministic values.
+var now = time.Now
+
+// timestamp returns the current time in UTC, matching what a JSON
+// round-trip produces.
+func timestamp() time.Time {
+	return now().UTC()
+}
+
 // User represents a user in the application.
 type User struct {
-    ID    int    `json:"id"`
-    Name  string `json:"name"`
-    Email string `json:"email"`
+	XMLName xml.Name `json:"-" xml:"user"`
+	ID      int      `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name" validate:"required"`
+	// Email is masked in logs and audit entries.
+	Email string `json:"email" xml:"email" validate:"required,email" audit:",mask"`
+	// PasswordHash is the bcrypt hash of the user's password. It is never
+	// serialized, and audit entries only note that it changed.
+	PasswordHash string     `json:"-" xml:"-" audit:"password_hash,redact"`
+	CreatedAt    time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" xml:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" xml:"deleted_at,omitempty"`
+}
+
+// LogValue logs a User with its email masked by utils.MaskEmail and without
+// its password hash, so the address never reaches the logs in full
+// whichever logger is used.
+func (u User) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", u.ID),
+		slog.String("name", u.Name),
+		slog.String("email", utils.MaskEmail(u.Email)),
+		slog.Bool("has_password", u.PasswordHash != ""),
+	)
 }
 
-// NewUser creates a new User instance.
-func NewUser(id int, name string, email string) *User {
-    return &User{
-        ID:    id,
-        Name:  name,
-        Email: email,
-    }
+// NewUser creates a new User instance. Its ID is left zero for the
+// repository to assign.
+func NewUser(name string, email string) *User {
+	t := timestamp()
+	return &User{
+		Name:      name,
+		Email:     utils.NormalizeEmail(email),
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
 }
 
-// UpdateEmail updates the email of the User.
+// UpdateEmail updates the email of the User, normalized with
+// utils.NormalizeEmail.
 func (u *User) UpdateEmail(newEmail string) {
-    u.Email = newEmail
+	u.Email = utils.NormalizeEmail(newEmail)
+	u.UpdatedAt = timestamp()
+}
+
+// UpdateName renames the User. A blank name is rejected with a
+// *ValidationError for the "name" field and leaves the User unchanged.
+func (u *User) UpdateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		var v ValidationError
+		v.Add("name", "is required")
+		return v.errOrNil()
+	}
+	u.Name = name
+	u.UpdatedAt = timestamp()
+	return nil
+}
+
+// PublicUser is the view of a User that is safe to show to anyone. It
+// leaves out the email address and anything else personal.
+type PublicUser struct {
+	XMLName   xml.Name  `json:"-" xml:"user"`
+	ID        int       `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+// Public returns the PublicUser view of u.
+func (u *User) Public() PublicUser {
+	return PublicUser{
+		ID:        u.ID,
+		Name:      u.Name,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// MinPasswordLength is the shortest password the default PasswordPolicy
+// accepts.
+const MinPasswordLength = 8
+
+// PasswordPolicy is the policy SetPassword enforces: by default at least
+// MinPasswordLength characters and at most utils.MaxPasswordBytes bytes,
+// with no required character classes. Set it once at startup to tighten
+// it.
+var PasswordPolicy = utils.PasswordPolicy{MinLength: MinPasswordLength}
+
+// ErrPasswordTooShort matches, with errors.Is, the error SetPassword returns
+// for passwords shorter than PasswordPolicy allows.
+var ErrPasswordTooShort = utils.ErrPasswordTooShort
+
+// SetPassword hashes plain and stores it as the User's password. It fails
+// with a *utils.PasswordPolicyError listing every rule of PasswordPolicy
+// that plain breaks.
+func (u *User) SetPassword(plain string) error {
+	if err := utils.IsValidPassword(plain, PasswordPolicy); err != nil {
+		return err
+	}
+	hash, err := utils.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = hash
+	u.UpdatedAt = timestamp()
+	return nil
+}
+
+// CheckPassword reports whether plain matches the stored password. A User
+// without a password never matches.
+func (u *User) CheckPassword(plain string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+	return utils.ComparePassword(u.PasswordHash, plain) == nil
+}
+
+// Delete soft-deletes the User by stamping DeletedAt. Deleting an already
+// deleted User keeps the original timestamp.
+func (u *User) Delete() {
+	if u.DeletedAt == nil {
+		t := timestamp()
+		u.DeletedAt = &t
+	}
+}
+
+// Undelete restores a soft-deleted User.
+func (u *User) Undelete() {
+	u.DeletedAt = nil
+}
+
+// IsDeleted reports whether the User has been soft-deleted.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// Validate checks the User against its validate tags: it needs a name and
+// a valid email address. All failing fields are reported together in a
+// *ValidationError.
+func (u *User) Validate() error {
+	return ValidateStruct(u)
 }
 
 // Product represents a product in the application.
 type Product struct {
-    ID    int     `json:"id"`
-    Name  string  `json:"name"`
-    Price float64 `json:"price"`
+	XMLName xml.Name `json:"-" xml:"product"`
+	ID      int      `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name" validate:"required"`
+	Price   Money    `json:"price" xml:"price" validate:"gte=0"`
+	Stock   int      `json:"stock" xml:"stock" validate:"gte=0"`
+	// Category is the slug of the product's Category, empty when it has
+	// none.
+	Category  string     `json:"category,omitempty" xml:"category,omitempty"`
+	CreatedAt time.Time  `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" xml:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" xml:"deleted_at,omitempty"`
+}
+
+// NewProduct creates a new Product instance. Its ID is left zero for the
+// repository to assign.
+func NewProduct(name string, price Money) *Product {
+	t := timestamp()
+	return &Product{
+		Name:      name,
+		Price:     price,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
+}
+
+// UpdatePrice updates the price of the Product. When the price changed it
+// returns the PriceChange and true, for the caller to pass to
+// NotifyPriceChange after saving the Product.
+func (p *Product) UpdatePrice(newPrice Money) (PriceChange, bool) {
+	old := p.Price
+	p.Price = newPrice
+	p.UpdatedAt = timestamp()
+	if newPrice == old {
+		return PriceChange{}, false
+	}
+	return PriceChange{ProductID: p.ID, Name: p.Name, OldPrice: old, NewPrice: newPrice, At: p.UpdatedAt}, true
 }
 
-// NewProduct creates a new Product instance.
-func NewProduct(id int, name string, price float64) *Product {
-    return &Product{
-        ID:    id,
-        Name:  name,
-        Price: price,
-    }
+// ApplyDiscount returns the Product's price reduced by percent, which must
+// be between 0 and 100, rounded half away from zero to the currency's minor
+// unit. Price itself is not changed.
+func (p *Product) ApplyDiscount(percent float64) (Money, error) {
+	if !(percent >= 0 && percent <= 100) {
+		return Money{}, fmt.Errorf("discount percent must be between 0 and 100, got %v", percent)
+	}
+	amount := math.Round(float64(p.Price.Amount) * (100 - percent) / 100)
+	return Money{Amount: int64(amount), Currency: p.Price.Currency}, nil
 }
 
-// UpdatePrice updates the price of the Product.
-func (p *Product) UpdatePrice(newPrice float64) {
-    p.Price = newPrice
-}
\ No newline at end of file
+// ApplyCoupon returns the Product's price less a fixed amount, floored at
+// zero. The coupon must not be negative and must be in the price's
+// currency, or ErrCurrencyMismatch is returned. Price itself is not
+// changed.
+func (p *Product) ApplyCoupon(amount Money) (Money, error) {
+	if amount.IsNegative() {
+		return Money{}, fmt.Errorf("coupon amount must not be negative, got %s", amount.Decimal())
+	}
+	discounted, err := p.Price.Sub(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	if discounted.IsNegative() {
+		discounted.Amount = 0
+	}
+	return discounted, nil
+}
+
+// ErrInsufficientStock is returned when reserving more units than a
+// Product has available.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// Reserve takes n units out of the Product's available stock, failing with
+// ErrInsufficientStock when fewer than n remain. Like the other methods it
+// is not safe for concurrent use; the repository serializes reservations on
+// stored products.
+func (p *Product) Reserve(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("reserve quantity must be positive, got %d", n)
+	}
+	if n > p.Stock {
+		return fmt.Errorf("%w: product %d has %d, want %d", ErrInsufficientStock, p.ID, p.Stock, n)
+	}
+	p.Stock -= n
+	p.UpdatedAt = timestamp()
+	return nil
+}
+
+// Release returns n previously reserved units to stock. Non-positive n is
+// ignored.
+func (p *Product) Release(n int) {
+	if n <= 0 {
+		return
+	}
+	p.Stock += n
+	p.UpdatedAt = timestamp()
+}
+
+// Delete soft-deletes the Product by stamping DeletedAt. Deleting an already
+// deleted Product keeps the original timestamp.
+func (p *Product) Delete() {
+	if p.DeletedAt == nil {
+		t := timestamp()
+		p.DeletedAt = &t
+	}
+}
+
+// Undelete restores a soft-deleted Product.
+func (p *Product) Undelete() {
+	p.DeletedAt = nil
+}
+
+// IsDeleted reports whether the Product has been soft-deleted.
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// Validate checks that the Product has a name, a non-negative price and
+// non-negative stock, by its validate tags, and that the price is in a
+// three-letter currency. All failing fields are reported together in a
+// *ValidationError.
+func (p *Product) Validate() error {
+	v := mustCheckTags(p)
+	if !validCurrency(p.Price.Currency) {
+		v.Add("price.currency", "must be a three-letter ISO 4217 code")
+	}
+	return v.errOrNil()
+}
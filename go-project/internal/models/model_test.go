@@ -0,0 +1,327 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-project/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeClock makes now return t and advance by step on every call. It
+// restores the real clock when the test ends.
+func fakeClock(t *testing.T, start time.Time, step time.Duration) {
+	t.Helper()
+	current := start
+	now = func() time.Time {
+		v := current
+		current = current.Add(step)
+		return v
+	}
+	t.Cleanup(func() { now = time.Now })
+}
+
+// failingFields returns the field names reported by err, or nil when err is
+// nil.
+func failingFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var v *ValidationError
+	if !errors.As(err, &v) {
+		t.Fatalf("error %v is not a *ValidationError", err)
+	}
+	fields := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		fields[i] = f.Field
+	}
+	return fields
+}
+
+func TestUserValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want []string
+	}{
+		{"valid", User{Name: "Ada", Email: "ada@example.com"}, nil},
+		{"missing name", User{Email: "ada@example.com"}, []string{"name"}},
+		{"missing email", User{Name: "Ada"}, []string{"email"}},
+		{"invalid email", User{Name: "Ada", Email: "not-an-email"}, []string{"email"}},
+		{"everything wrong", User{Email: "nope"}, []string{"name", "email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failingFields(t, tt.user.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("failing fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		product Product
+		want    []string
+	}{
+		{"valid", Product{Name: "Widget", Price: usd("9.99")}, nil},
+		{"free is fine", Product{Name: "Sample", Price: usd("0")}, nil},
+		{"missing name", Product{Price: usd("1")}, []string{"name"}},
+		{"negative price", Product{Name: "Widget", Price: usd("-1")}, []string{"price"}},
+		{"missing currency", Product{Name: "Widget", Price: Money{Amount: 100}}, []string{"price.currency"}},
+		{"lowercase currency", Product{Name: "Widget", Price: NewMoney(100, "usd")}, []string{"price.currency"}},
+		{"negative stock", Product{Name: "Widget", Price: usd("1"), Stock: -1}, []string{"stock"}},
+		{"everything wrong", Product{Price: usd("-5")}, []string{"name", "price"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failingFields(t, tt.product.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("failing fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserEmailIsNormalized(t *testing.T) {
+	u := NewUser("Ada", "  Ada@Example.COM ")
+	if u.Email != "Ada@example.com" {
+		t.Fatalf("NewUser email = %q, want %q", u.Email, "Ada@example.com")
+	}
+	u.UpdateEmail("ada@NEW.example.com\n")
+	if u.Email != "ada@new.example.com" {
+		t.Fatalf("UpdateEmail email = %q, want %q", u.Email, "ada@new.example.com")
+	}
+}
+
+func TestUserUpdateName(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeClock(t, start, time.Minute)
+	u := NewUser("Ada", "ada@example.com")
+
+	if err := u.UpdateName("Ada Lovelace"); err != nil {
+		t.Fatalf("UpdateName: %v", err)
+	}
+	if u.Name != "Ada Lovelace" || !u.UpdatedAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("after UpdateName: name %q, updated %v", u.Name, u.UpdatedAt)
+	}
+
+	for _, name := range []string{"", "   "} {
+		err := u.UpdateName(name)
+		var v *ValidationError
+		if !errors.As(err, &v) || len(v.Fields) != 1 || v.Fields[0].Field != "name" {
+			t.Fatalf("UpdateName(%q) = %v, want a name ValidationError", name, err)
+		}
+		if u.Name != "Ada Lovelace" {
+			t.Fatalf("UpdateName(%q) changed the name to %q", name, u.Name)
+		}
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeClock(t, start, time.Minute)
+
+	u := NewUser("Ada", "ada@example.com")
+	if !u.CreatedAt.Equal(start) || !u.UpdatedAt.Equal(start) {
+		t.Fatalf("new user timestamps = %v / %v, want %v", u.CreatedAt, u.UpdatedAt, start)
+	}
+	u.UpdateEmail("ada@new.example.com")
+	if !u.CreatedAt.Equal(start) || !u.UpdatedAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("after UpdateEmail: created %v, updated %v", u.CreatedAt, u.UpdatedAt)
+	}
+
+	p := NewProduct("Widget", usd("9.99"))
+	p.UpdatePrice(usd("12.50"))
+	if !p.UpdatedAt.After(p.CreatedAt) {
+		t.Fatalf("UpdatePrice did not bump UpdatedAt: created %v, updated %v", p.CreatedAt, p.UpdatedAt)
+	}
+}
+
+func TestTimestampsRoundTripAsRFC3339(t *testing.T) {
+	fakeClock(t, time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.FixedZone("IST", 5*3600+1800)), 0)
+	u := NewUser("Ada", "ada@example.com")
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"created_at":"2026-01-01T21:34:05.123456789Z"`) {
+		t.Fatalf("created_at not RFC3339 UTC: %s", b)
+	}
+	var back User
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !back.CreatedAt.Equal(u.CreatedAt) || !back.UpdatedAt.Equal(u.UpdatedAt) {
+		t.Fatalf("round trip changed timestamps: %v -> %v", u.CreatedAt, back.CreatedAt)
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeClock(t, start, time.Minute)
+
+	u := NewUser("Ada", "ada@example.com")
+	u.Delete()
+	if !u.IsDeleted() {
+		t.Fatal("user not marked deleted")
+	}
+	first := *u.DeletedAt
+	u.Delete()
+	if !u.DeletedAt.Equal(first) {
+		t.Fatalf("second Delete moved DeletedAt from %v to %v", first, *u.DeletedAt)
+	}
+	u.Undelete()
+	if u.IsDeleted() {
+		t.Fatal("user still deleted after Undelete")
+	}
+
+	p := NewProduct("Widget", usd("1"))
+	p.Delete()
+	p.Delete()
+	if !p.IsDeleted() {
+		t.Fatal("product not marked deleted")
+	}
+	if b, _ := json.Marshal(NewProduct("Gadget", usd("2"))); strings.Contains(string(b), "deleted_at") {
+		t.Fatalf("live product serialized deleted_at: %s", b)
+	}
+}
+
+func TestUserPassword(t *testing.T) {
+	defer func(c int) { utils.BcryptCost = c }(utils.BcryptCost)
+	utils.BcryptCost = bcrypt.MinCost
+
+	u := NewUser("Ada", "ada@example.com")
+	if u.CheckPassword("") {
+		t.Fatal("user without a password matched the empty string")
+	}
+	if err := u.SetPassword("short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Fatalf("SetPassword(short) = %v, want ErrPasswordTooShort", err)
+	}
+	if err := u.SetPassword("analytical-engine"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	hash := u.PasswordHash
+	if err := u.SetPassword(strings.Repeat("x", utils.MaxPasswordBytes+1)); !errors.Is(err, utils.ErrPasswordTooLong) {
+		t.Fatalf("SetPassword(73 bytes) = %v, want utils.ErrPasswordTooLong", err)
+	}
+	if u.PasswordHash != hash {
+		t.Fatal("rejected password replaced the stored hash")
+	}
+	if !u.CheckPassword("analytical-engine") {
+		t.Fatal("correct password did not match")
+	}
+	if u.CheckPassword("difference-engine") {
+		t.Fatal("wrong password matched")
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), u.PasswordHash) || strings.Contains(strings.ToLower(string(b)), "password") {
+		t.Fatalf("password hash leaked into JSON: %s", b)
+	}
+}
+
+func TestUserPublic(t *testing.T) {
+	u := NewUser("Ada", "ada@example.com")
+	u.ID = 3
+	p := u.Public()
+	if p.ID != 3 || p.Name != "Ada" || !p.CreatedAt.Equal(u.CreatedAt) || !p.UpdatedAt.Equal(u.UpdatedAt) {
+		t.Fatalf("Public() = %+v, want fields copied from %+v", p, u)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "email") {
+		t.Fatalf("PublicUser JSON %s contains email", b)
+	}
+}
+
+func TestProductStock(t *testing.T) {
+	p := NewProduct("Widget", usd("1"))
+	p.Stock = 5
+
+	if err := p.Reserve(3); err != nil {
+		t.Fatalf("Reserve(3): %v", err)
+	}
+	if err := p.Reserve(3); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("overselling err = %v, want ErrInsufficientStock", err)
+	}
+	if p.Stock != 2 {
+		t.Fatalf("stock after failed reserve = %d, want 2", p.Stock)
+	}
+	if err := p.Reserve(0); err == nil || errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Reserve(0) err = %v, want a quantity error", err)
+	}
+
+	p.Release(3)
+	if p.Stock != 5 {
+		t.Fatalf("stock after release = %d, want 5", p.Stock)
+	}
+	p.Release(-1)
+	if p.Stock != 5 {
+		t.Fatalf("negative release changed stock to %d", p.Stock)
+	}
+}
+
+func TestProductApplyDiscount(t *testing.T) {
+	p := NewProduct("Widget", usd("19.99"))
+	tests := []struct {
+		percent float64
+		want    Money
+		wantErr bool
+	}{
+		{percent: 0, want: usd("19.99")},
+		{percent: 100, want: usd("0")},
+		{percent: 15, want: usd("16.99")}, // 16.9915 rounds down
+		{percent: 50, want: usd("10.00")}, // 9.995 rounds half away from zero
+		{percent: -1, wantErr: true},
+		{percent: 100.5, wantErr: true},
+		{percent: math.NaN(), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := p.ApplyDiscount(tt.percent)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ApplyDiscount(%v) = %v, want an error", tt.percent, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ApplyDiscount(%v) = %v, %v; want %v", tt.percent, got, err, tt.want)
+		}
+	}
+	if p.Price != usd("19.99") {
+		t.Fatalf("price changed to %v", p.Price)
+	}
+}
+
+func TestProductApplyCoupon(t *testing.T) {
+	p := NewProduct("Widget", usd("5.00"))
+	if got, err := p.ApplyCoupon(usd("1.25")); err != nil || got != usd("3.75") {
+		t.Fatalf("ApplyCoupon(1.25) = %v, %v; want 3.75", got, err)
+	}
+	if got, err := p.ApplyCoupon(usd("20")); err != nil || got != usd("0") {
+		t.Fatalf("coupon larger than price = %v, %v; want 0", got, err)
+	}
+	if _, err := p.ApplyCoupon(NewMoney(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("coupon in EUR err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := p.ApplyCoupon(usd("-1")); err == nil {
+		t.Fatal("negative coupon: want an error")
+	}
+	if p.Price != usd("5.00") {
+		t.Fatalf("price changed to %v", p.Price)
+	}
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed when a price is given without a currency.
+const DefaultCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when combining Money in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// zeroDecimalCurrencies have no minor unit; every other currency is
+// treated as having two decimal places.
+var zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true, "VND": true, "CLP": true, "ISK": true}
+
+var currencySymbols = map[string]string{"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "INR": "₹"}
+
+// Money is an exact amount of a currency, stored as an integer number of
+// minor units (cents for USD) so arithmetic never rounds.
+type Money struct {
+	// Amount is in minor units: Money{Amount: 1234, Currency: "USD"} is $12.34.
+	Amount int64
+	// Currency is an ISO 4217 code such as "USD".
+	Currency string
+}
+
+// NewMoney returns amount minor units of currency.
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "12.34" or "-0.5" in
+// currency. It fails when s has more decimal places than the currency's
+// minor unit allows.
+func ParseMoney(s, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	digits := minorDigits(currency)
+	if whole == "" || len(frac) > digits || !allDigits(whole) || !allDigits(frac) {
+		return Money{}, fmt.Errorf("money: invalid %s amount %q", currency, s)
+	}
+	frac += strings.Repeat("0", digits-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("money: invalid %s amount %q", currency, s)
+	}
+	if neg {
+		n = -n
+	}
+	return Money{Amount: n, Currency: currency}, nil
+}
+
+// validCurrency reports whether code looks like an ISO 4217 code.
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func minorDigits(currency string) int {
+	if zeroDecimalCurrencies[currency] {
+		return 0
+	}
+	return 2
+}
+
+// Add returns m+o, or ErrCurrencyMismatch.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns m-o, or ErrCurrencyMismatch.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by n, e.g. a unit price times a quantity.
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1. It
+// returns ErrCurrencyMismatch for different currencies.
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// IsNegative reports whether m is below zero.
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// Decimal formats the amount without a currency, e.g. "12.34".
+func (m Money) Decimal() string {
+	digits := minorDigits(m.Currency)
+	a := m.Amount
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+	s := strconv.FormatInt(a, 10)
+	if digits == 0 {
+		return sign + s
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// String formats m for display: "$12.34" for currencies with a known
+// symbol, otherwise "12.34 CHF".
+func (m Money) String() string {
+	d := m.Decimal()
+	sym, ok := currencySymbols[m.Currency]
+	if !ok {
+		return d + " " + m.Currency
+	}
+	if neg, found := strings.CutPrefix(d, "-"); found {
+		return "-" + sym + neg
+	}
+	return sym + d
+}
+
+// moneyJSON is the wire form of Money: the amount as a decimal string so no
+// precision is lost in clients that parse numbers as floats.
+type moneyJSON struct {
+	Amount   string `json:"amount" xml:"amount"`
+	Currency string `json:"currency" xml:"currency"`
+}
+
+// MarshalJSON encodes m as {"amount":"12.34","currency":"USD"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts the object form written by MarshalJSON, or a bare
+// decimal string or number in DefaultCurrency.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		var v moneyJSON
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		if v.Currency == "" {
+			v.Currency = DefaultCurrency
+		}
+		parsed, err := ParseMoney(v.Amount, v.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalXML encodes m as <amount> and <currency> child elements.
+func (m Money) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(moneyJSON{Amount: m.Decimal(), Currency: m.Currency}, start)
+}
+
+// UnmarshalXML decodes the form written by MarshalXML.
+func (m *Money) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v moneyJSON
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	if v.Currency == "" {
+		v.Currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func usd(s string) Money {
+	m, err := ParseMoney(s, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestMoneyAddIsExact(t *testing.T) {
+	sum, err := usd("0.10").Add(usd("0.20"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != usd("0.30") {
+		t.Fatalf("0.10 + 0.20 = %v, want exactly 0.30", sum)
+	}
+	diff, _ := usd("1.00").Sub(usd("0.99"))
+	if diff != usd("0.01") {
+		t.Fatalf("1.00 - 0.99 = %v, want 0.01", diff)
+	}
+	if got := usd("0.33").Mul(3); got != usd("0.99") {
+		t.Fatalf("0.33 * 3 = %v, want 0.99", got)
+	}
+}
+
+func TestMoneyMixedCurrenciesError(t *testing.T) {
+	eur := NewMoney(100, "EUR")
+	if _, err := usd("1").Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Add err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd("1").Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Sub err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd("1").Cmp(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Cmp err = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{NewMoney(1234, "USD"), "$12.34"},
+		{NewMoney(5, "USD"), "$0.05"},
+		{NewMoney(-250, "USD"), "-$2.50"},
+		{NewMoney(1000, "EUR"), "€10.00"},
+		{NewMoney(500, "JPY"), "¥500"},
+		{NewMoney(1999, "CHF"), "19.99 CHF"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{"12.34", "USD", 1234, false},
+		{"12", "USD", 1200, false},
+		{"0.5", "USD", 50, false},
+		{"-3.10", "USD", -310, false},
+		{"500", "JPY", 500, false},
+		{"1.234", "USD", 0, true},
+		{"1.5", "JPY", 0, true},
+		{"1e3", "USD", 0, true},
+		{".5", "USD", 0, true},
+		{"", "USD", 0, true},
+		{"abc", "USD", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in, tt.currency)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMoney(%q, %s) err = %v, wantErr %v", tt.in, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Amount != tt.want {
+			t.Errorf("ParseMoney(%q, %s) = %d, want %d", tt.in, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	b, err := json.Marshal(usd("12.30"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"amount":"12.30","currency":"USD"}` {
+		t.Fatalf("Marshal = %s", b)
+	}
+	for _, in := range []string{`{"amount":"12.30","currency":"USD"}`, `"12.30"`, `12.3`, `{"amount":"12.3"}`} {
+		var m Money
+		if err := json.Unmarshal([]byte(in), &m); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", in, err)
+		}
+		if m != usd("12.30") {
+			t.Errorf("Unmarshal(%s) = %#v, want $12.30", in, m)
+		}
+	}
+	var m Money
+	if err := json.Unmarshal([]byte(`0.001`), &m); err == nil {
+		t.Error("Unmarshal accepted sub-cent precision")
+	}
+}
+
+func TestMoneyXML(t *testing.T) {
+	p := Product{Name: "Widget", Price: NewMoney(999, "EUR")}
+	b, err := xml.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "<price><amount>9.99</amount><currency>EUR</currency></price>") {
+		t.Fatalf("XML = %s", b)
+	}
+	var back Product
+	if err := xml.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Price != p.Price {
+		t.Fatalf("round trip price = %#v, want %#v", back.Price, p.Price)
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderItem is a single line of an Order.
+type OrderItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// Order records the products a User bought.
+type Order struct {
+	ID        int         `json:"id"`
+	UserID    int         `json:"user_id"`
+	Items     []OrderItem `json:"items"`
+	CreatedAt time.Time   `json:"created_at"`
+	// IdempotencyKey, when set, identifies the client's attempt to place
+	// this order, so a retry with the same key returns the order already
+	// placed instead of placing it again.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// NewOrder creates a new Order for userID. The ID is left zero for the
+// repository to assign.
+func NewOrder(userID int, items ...OrderItem) *Order {
+	return &Order{
+		UserID:    userID,
+		Items:     append([]OrderItem(nil), items...),
+		CreatedAt: timestamp(),
+	}
+}
+
+// Total sums Quantity*price over the Order's items, using price to look up
+// each product's unit price, in exact minor units. Every price must be in
+// the same currency, or Total fails with ErrCurrencyMismatch. An Order
+// without items totals zero DefaultCurrency.
+func (o *Order) Total(price func(productID int) Money) (Money, error) {
+	if len(o.Items) == 0 {
+		return NewMoney(0, DefaultCurrency), nil
+	}
+	var total Money
+	for i, it := range o.Items {
+		line := price(it.ProductID).Mul(int64(it.Quantity))
+		if i == 0 {
+			total = line
+			continue
+		}
+		var err error
+		if total, err = total.Add(line); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Validate checks that the Order has at least one item and that every item
+// has a positive quantity. All failing fields are reported together in a
+// *ValidationError.
+func (o *Order) Validate() error {
+	var v ValidationError
+	if len(o.Items) == 0 {
+		v.Add("items", "must not be empty")
+	}
+	for i, it := range o.Items {
+		if it.Quantity <= 0 {
+			v.Add(fmt.Sprintf("items[%d].quantity", i), "must be positive")
+		}
+	}
+	return v.errOrNil()
+}
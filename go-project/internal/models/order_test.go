@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewOrder(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeClock(t, start, 0)
+
+	items := []OrderItem{{ProductID: 1, Quantity: 2}}
+	o := NewOrder(7, items...)
+	items[0].Quantity = 99
+
+	if o.UserID != 7 || !o.CreatedAt.Equal(start) {
+		t.Fatalf("NewOrder = %+v", o)
+	}
+	if o.Items[0].Quantity != 2 {
+		t.Fatal("NewOrder shares the caller's items slice")
+	}
+}
+
+func TestOrderTotal(t *testing.T) {
+	prices := map[int]Money{1: NewMoney(250, "USD"), 2: NewMoney(1000, "USD"), 3: NewMoney(99, "USD"), 4: NewMoney(10, "USD")}
+	o := NewOrder(1,
+		OrderItem{ProductID: 1, Quantity: 4},
+		OrderItem{ProductID: 2, Quantity: 1},
+		OrderItem{ProductID: 3, Quantity: 100},
+		// 0.1 * 3 is not 0.3 in float64; in cents it is exact
+		OrderItem{ProductID: 4, Quantity: 3},
+	)
+	got, err := o.Total(func(id int) Money { return prices[id] })
+	if err != nil {
+		t.Fatalf("Total: %v", err)
+	}
+	if want := NewMoney(1000+1000+9900+30, "USD"); got != want {
+		t.Fatalf("Total = %v, want %v", got, want)
+	}
+	if got, err := NewOrder(1).Total(func(int) Money { return NewMoney(1, "USD") }); err != nil || got != NewMoney(0, DefaultCurrency) {
+		t.Fatalf("Total of an empty order = %v, %v; want 0 %s", got, err, DefaultCurrency)
+	}
+
+	prices[2] = NewMoney(1000, "EUR")
+	if _, err := o.Total(func(id int) Money { return prices[id] }); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Total with mixed currencies: err = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestOrderValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		order *Order
+		want  []string
+	}{
+		{"valid", NewOrder(1, OrderItem{ProductID: 1, Quantity: 1}), nil},
+		{"no items", NewOrder(1), []string{"items"}},
+		{"zero quantity", NewOrder(1, OrderItem{ProductID: 1, Quantity: 0}), []string{"items[0].quantity"}},
+		{"negative quantities", NewOrder(1,
+			OrderItem{ProductID: 1, Quantity: 1},
+			OrderItem{ProductID: 2, Quantity: -1},
+			OrderItem{ProductID: 3, Quantity: -5},
+		), []string{"items[1].quantity", "items[2].quantity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failingFields(t, tt.order.Validate()); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("failing fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
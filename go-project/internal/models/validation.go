@@ -0,0 +1,129 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"go-project/pkg/utils"
+)
+
+// FieldError describes why a single field failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field that failed validation.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+// Add records a failing field.
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + " " + f.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// errOrNil returns e as an error only when at least one field failed, so a
+// nil *ValidationError never masquerades as a non-nil error.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// structValidator checks the validate struct tags of models and request
+// bodies. Fields are reported by their JSON name, and the email rule is
+// utils.IsValidEmail so every check in the app agrees on which addresses
+// are valid.
+var structValidator = newStructValidator()
+
+func newStructValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("email", func(fl validator.FieldLevel) bool {
+		ok, _ := utils.IsValidEmail(fl.Field().String())
+		return ok
+	})
+	// Money compares by its amount in minor units, so gte=0 on a price
+	// rejects negative ones
+	v.RegisterCustomTypeFunc(func(f reflect.Value) any {
+		return f.Interface().(Money).Amount
+	}, Money{})
+	return v
+}
+
+// ValidateStruct checks s, a struct or a pointer to one, against its
+// validate tags, reporting every failing field together in a
+// *ValidationError. Rules the tags cannot express are left to the type's
+// own Validate method, which calls this first.
+func ValidateStruct(s any) error {
+	v, err := checkTags(s)
+	if err != nil {
+		return err
+	}
+	return v.errOrNil()
+}
+
+// checkTags returns the fields of s failing their validate tags. The error
+// is only for an s that is not a struct.
+func checkTags(s any) (ValidationError, error) {
+	var v ValidationError
+	err := structValidator.Struct(s)
+	var failed validator.ValidationErrors
+	if !errors.As(err, &failed) {
+		return v, err
+	}
+	for _, fe := range failed {
+		// The namespace starts with the struct's type name
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		v.Add(field, tagMessage(fe))
+	}
+	return v, nil
+}
+
+// mustCheckTags is checkTags for the models' own Validate methods, whose
+// receiver is always a struct.
+func mustCheckTags(s any) ValidationError {
+	v, err := checkTags(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// tagMessage describes a failed tag in the words the hand-written rules
+// use.
+func tagMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "is not a valid email address"
+	case "gte":
+		if fe.Param() == "0" {
+			return "must not be negative"
+		}
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " check"
+}
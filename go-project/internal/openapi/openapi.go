@@ -0,0 +1,275 @@
+// Package openapi models the subset of OpenAPI 3.0 the service describes
+// itself with, and derives JSON schemas from Go types by reflection so the
+// published contract follows the structs the handlers actually encode.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Version is the OpenAPI version documents are written in.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations on one path.
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+// SetOperation stores op under the HTTP method, reporting false for methods
+// PathItem has no field for.
+func (p *PathItem) SetOperation(method string, op *Operation) bool {
+	switch strings.ToUpper(method) {
+	case "GET":
+		p.Get = op
+	case "PUT":
+		p.Put = op
+	case "POST":
+		p.Post = op
+	case "DELETE":
+		p.Delete = op
+	case "PATCH":
+		p.Patch = op
+	default:
+		return false
+	}
+	return true
+}
+
+// Operation describes one method on a path. Responses is keyed by status
+// code.
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's body by media type.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes one response, with its body by media type.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType gives the schema of a body in one media type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas referenced from operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema as used by OpenAPI 3.0. A schema with Ref set
+// points at a named schema in Components and has no other fields.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// RefPrefix starts every Schema.Ref produced by a Generator.
+const RefPrefix = "#/components/schemas/"
+
+// Generator turns Go types into schemas, collecting a named schema for every
+// struct type it meets. Struct fields are read the way encoding/json reads
+// them: the json tag names the property, "-" skips it, and fields that are
+// neither omitempty nor pointers are required. A field tagged
+// openapi:"optional" is never required, for request fields with a useful
+// zero value, and a pointer tagged openapi:"required" is required and not
+// nullable, for request fields a handler must tell apart from their zero
+// value.
+type Generator struct {
+	schemas   map[string]*Schema
+	overrides map[reflect.Type]*Schema
+}
+
+// NewGenerator returns a Generator that knows time.Time encodes as an
+// RFC 3339 string.
+func NewGenerator() *Generator {
+	g := &Generator{schemas: map[string]*Schema{}, overrides: map[reflect.Type]*Schema{}}
+	g.Override(reflect.TypeFor[time.Time](), &Schema{Type: "string", Format: "date-time"})
+	return g
+}
+
+// Override makes t use s instead of a derived schema. It is meant for types
+// with custom JSON encoding, whose struct fields say nothing about the wire
+// format.
+func (g *Generator) Override(t reflect.Type, s *Schema) {
+	g.overrides[t] = s
+}
+
+// Schemas returns the named schemas collected so far, keyed by SchemaName.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// SchemaFor returns the schema of values of type t. Named struct types are
+// added to Schemas and referenced rather than inlined.
+func (g *Generator) SchemaFor(t reflect.Type) *Schema {
+	if s, ok := g.overrides[t]; ok {
+		copied := *s
+		return &copied
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.SchemaFor(t.Elem())
+		if s.Ref != "" {
+			// OpenAPI 3.0 ignores siblings of $ref, so wrap it.
+			return &Schema{Nullable: true, AllOf: []*Schema{s}}
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Uint:
+		return &Schema{Type: "integer"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.SchemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.SchemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := SchemaName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = &Schema{} // placeholder so recursive types terminate
+			g.schemas[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: RefPrefix + name}
+	default:
+		// Interfaces and other kinds can hold anything.
+		return &Schema{}
+	}
+}
+
+// structSchema describes the JSON object encoding/json writes for t,
+// including the promoted fields of embedded structs.
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *Generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		required := f.Tag.Get("openapi") == "required" && f.Type.Kind() == reflect.Pointer
+		ft := f.Type
+		if required {
+			ft = ft.Elem()
+		}
+		prop := g.SchemaFor(ft)
+		if strings.Contains(","+opts+",", ",string,") && prop.Type != "string" {
+			prop = &Schema{Type: "string", Description: "JSON-encoded " + prop.Type}
+		}
+		s.Properties[name] = prop
+		optional := strings.Contains(","+opts+",", ",omitempty,") || f.Tag.Get("openapi") == "optional"
+		if required || !optional && f.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// SchemaName is the component name used for the struct type t: its Go name
+// with the first letter upper-cased and the names of any type arguments
+// appended, so pageResponse[models.User] becomes PageResponseUser.
+func SchemaName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	var b strings.Builder
+	b.WriteString(upperFirst(name))
+	if generic {
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			// Arguments are package-qualified, e.g. go-project/internal/models.User.
+			arg = arg[strings.LastIndexAny(arg, "./")+1:]
+			b.WriteString(upperFirst(arg))
+		}
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
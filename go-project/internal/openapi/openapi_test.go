@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Base struct {
+	ID int `json:"id"`
+}
+
+type node struct {
+	Base
+	Name     string            `json:"name"`
+	Note     string            `json:"note,omitempty"`
+	Secret   string            `json:"-"`
+	Parent   *node             `json:"parent"`
+	Children []node            `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	Count    *int64            `json:"count"`
+	Stock    int               `json:"stock" openapi:"optional"`
+	Limit    *int              `json:"limit" openapi:"required"`
+	Created  time.Time         `json:"created_at"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Untagged bool
+	hidden   string
+}
+
+type page[T any] struct {
+	Data []T `json:"data"`
+}
+
+func TestSchemaForStruct(t *testing.T) {
+	g := NewGenerator()
+	ref := g.SchemaFor(reflect.TypeFor[node]())
+	if ref.Ref != RefPrefix+"Node" {
+		t.Fatalf("ref = %+v, want a reference to Node", ref)
+	}
+	s := g.Schemas()["Node"]
+	if s == nil || s.Type != "object" {
+		t.Fatalf("Node schema = %+v", s)
+	}
+
+	want := map[string]Schema{
+		"id":         {Type: "integer"},
+		"name":       {Type: "string"},
+		"note":       {Type: "string"},
+		"parent":     {Nullable: true, AllOf: []*Schema{{Ref: RefPrefix + "Node"}}},
+		"children":   {Type: "array", Items: &Schema{Ref: RefPrefix + "Node"}},
+		"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"count":      {Type: "integer", Format: "int64", Nullable: true},
+		"stock":      {Type: "integer"},
+		"limit":      {Type: "integer"},
+		"created_at": {Type: "string", Format: "date-time"},
+		"raw":        {Type: "string", Format: "byte"},
+		"Untagged":   {Type: "boolean"},
+	}
+	if len(s.Properties) != len(want) {
+		t.Errorf("properties = %v, want %d of them", keys(s.Properties), len(want))
+	}
+	for name, w := range want {
+		got, ok := s.Properties[name]
+		if !ok {
+			t.Errorf("property %q missing", name)
+			continue
+		}
+		if !reflect.DeepEqual(*got, w) {
+			t.Errorf("property %q = %+v, want %+v", name, *got, w)
+		}
+	}
+	wantRequired := []string{"id", "name", "children", "labels", "limit", "created_at", "Untagged"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Errorf("required = %v, want %v", s.Required, wantRequired)
+	}
+}
+
+func TestSchemaForOverrideAndGenerics(t *testing.T) {
+	g := NewGenerator()
+	g.Override(reflect.TypeFor[Base](), &Schema{Type: "string"})
+	ref := g.SchemaFor(reflect.TypeFor[page[Base]]())
+	if ref.Ref != RefPrefix+"PageBase" {
+		t.Fatalf("ref = %q, want %sPageBase", ref.Ref, RefPrefix)
+	}
+	items := g.Schemas()["PageBase"].Properties["data"].Items
+	if items.Type != "string" || items.Ref != "" {
+		t.Fatalf("items = %+v, want the override", items)
+	}
+	if _, ok := g.Schemas()["Base"]; ok {
+		t.Fatal("overridden type was also added as a component")
+	}
+}
+
+func TestPathItemSetOperation(t *testing.T) {
+	var p PathItem
+	op := &Operation{Summary: "x"}
+	if !p.SetOperation("patch", op) || p.Patch != op {
+		t.Fatalf("SetOperation(patch) did not set Patch")
+	}
+	if p.SetOperation("OPTIONS", op) {
+		t.Fatal("SetOperation(OPTIONS) reported success")
+	}
+}
+
+func keys(m map[string]*Schema) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
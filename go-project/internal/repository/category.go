@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"go-project/internal/models"
+)
+
+// ErrDuplicateCategory is returned by CategoryRepository.Create when a
+// category with the same slug already exists.
+var ErrDuplicateCategory = errors.New("repository: category already exists")
+
+// CategoryRepository stores product categories, which are looked up by
+// slug.
+type CategoryRepository interface {
+	// Create stores c and assigns its ID, failing with
+	// ErrDuplicateCategory when its slug is taken.
+	Create(ctx context.Context, c *models.Category) error
+	// Get returns the category with slug, or ErrNotFound.
+	Get(ctx context.Context, slug string) (*models.Category, error)
+	// List returns every category ordered by name.
+	List(ctx context.Context) ([]*models.Category, error)
+}
+
+// InMemoryCategoryRepo is a CategoryRepository backed by a map keyed by
+// slug. It is safe for concurrent use and copies categories in and out.
+type InMemoryCategoryRepo struct {
+	ids models.Sequence
+
+	mu         sync.RWMutex
+	categories map[string]models.Category
+}
+
+// NewInMemoryCategoryRepo returns a repository holding the given
+// categories, with IDs assigned in order. It panics on a duplicate slug,
+// since the seed data is fixed at compile time.
+func NewInMemoryCategoryRepo(seed ...*models.Category) *InMemoryCategoryRepo {
+	r := &InMemoryCategoryRepo{categories: make(map[string]models.Category)}
+	for _, c := range seed {
+		if err := r.Create(context.Background(), c); err != nil {
+			panic("repository: seeding category " + c.Slug + ": " + err.Error())
+		}
+	}
+	return r
+}
+
+func (r *InMemoryCategoryRepo) Create(ctx context.Context, c *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := r.categories[c.Slug]; ok {
+		return ErrDuplicateCategory
+	}
+	c.ID = r.ids.NextID()
+	r.categories[c.Slug] = *c
+	return nil
+}
+
+func (r *InMemoryCategoryRepo) Get(ctx context.Context, slug string) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c, ok := r.categories[slug]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r *InMemoryCategoryRepo) List(ctx context.Context) ([]*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]*models.Category, 0, len(r.categories))
+	for _, c := range r.categories {
+		out = append(out, &c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
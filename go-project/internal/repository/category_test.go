@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func TestInMemoryCategoryRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryCategoryRepo(models.NewCategory("Garden"), models.NewCategory("Books"))
+
+	c := models.NewCategory("Home & Kitchen")
+	if err := repo.Create(ctx, c); err != nil || c.ID != 3 {
+		t.Fatalf("Create: id %d, err %v", c.ID, err)
+	}
+	if err := repo.Create(ctx, models.NewCategory("home kitchen")); !errors.Is(err, ErrDuplicateCategory) {
+		t.Fatalf("Create with a taken slug err = %v, want ErrDuplicateCategory", err)
+	}
+
+	got, err := repo.Get(ctx, "home-kitchen")
+	if err != nil || got.Name != "Home & Kitchen" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err := repo.Get(ctx, "toys"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of unknown slug err = %v, want ErrNotFound", err)
+	}
+
+	all, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var slugs []string
+	for _, c := range all {
+		slugs = append(slugs, c.Slug)
+	}
+	if want := []string{"books", "garden", "home-kitchen"}; len(slugs) != 3 || slugs[0] != want[0] || slugs[1] != want[1] || slugs[2] != want[2] {
+		t.Fatalf("List slugs = %v, want %v", slugs, want)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"go-project/internal/models"
+)
+
+// OrderRepository stores placed orders. It does not touch stock; see
+// service.OrderService for placing an order.
+type OrderRepository interface {
+	// Create stores o and assigns its ID.
+	Create(ctx context.Context, o *models.Order) error
+	// Get returns the order with id, or ErrNotFound.
+	Get(ctx context.Context, id int) (*models.Order, error)
+	// GetByKey returns the order stored with IdempotencyKey key, or
+	// ErrNotFound.
+	GetByKey(ctx context.Context, key string) (*models.Order, error)
+}
+
+// InMemoryOrderRepo is an OrderRepository backed by a map. It is safe for
+// concurrent use and copies orders, items included, in and out.
+type InMemoryOrderRepo struct {
+	ids models.IDGenerator[int]
+
+	mu     sync.RWMutex
+	orders map[int]models.Order
+	keys   map[string]int
+}
+
+// NewInMemoryOrderRepo returns an empty in-memory repository. Like
+// NewInMemoryUserRepo it takes its IDs from a models.Sequence by default.
+func NewInMemoryOrderRepo(opts ...MemoryOption) *InMemoryOrderRepo {
+	return &InMemoryOrderRepo{ids: applyMemoryOptions(opts), orders: make(map[int]models.Order), keys: make(map[string]int)}
+}
+
+func (r *InMemoryOrderRepo) Create(ctx context.Context, o *models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	o.ID = r.ids.NextID()
+	stored := *o
+	stored.Items = slices.Clone(o.Items)
+	r.orders[o.ID] = stored
+	if o.IdempotencyKey != "" {
+		r.keys[o.IdempotencyKey] = o.ID
+	}
+	return nil
+}
+
+func (r *InMemoryOrderRepo) Get(ctx context.Context, id int) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.get(id)
+}
+
+func (r *InMemoryOrderRepo) GetByKey(ctx context.Context, key string) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	id, ok := r.keys[key]
+	if !ok || key == "" {
+		return nil, ErrNotFound
+	}
+	return r.get(id)
+}
+
+// get returns a copy of the order with id. The caller holds r.mu.
+func (r *InMemoryOrderRepo) get(id int) (*models.Order, error) {
+	o, ok := r.orders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	o.Items = slices.Clone(o.Items)
+	return &o, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func TestInMemoryOrderRepo(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepo()
+	o := models.NewOrder(7, models.OrderItem{ProductID: 1, Quantity: 2})
+	o.IdempotencyKey = "k1"
+	if err := repo.Create(ctx, o); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if o.ID != 1 {
+		t.Fatalf("ID = %d, want 1", o.ID)
+	}
+	o.Items[0].Quantity = 99
+
+	got, err := repo.Get(ctx, o.ID)
+	if err != nil || got.UserID != 7 || got.Items[0].Quantity != 2 {
+		t.Fatalf("Get = %+v, %v; want the order as stored", got, err)
+	}
+	got.Items[0].Quantity = 50
+	if byKey, err := repo.GetByKey(ctx, "k1"); err != nil || byKey.ID != o.ID || byKey.Items[0].Quantity != 2 {
+		t.Fatalf("GetByKey = %+v, %v", byKey, err)
+	}
+
+	if _, err := repo.Get(ctx, 2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(unknown) err = %v, want ErrNotFound", err)
+	}
+	for _, key := range []string{"k2", ""} {
+		if _, err := repo.GetByKey(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetByKey(%q) err = %v, want ErrNotFound", key, err)
+		}
+	}
+}
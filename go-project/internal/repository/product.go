@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-project/internal/models"
+)
+
+// ProductSort names the field products are ordered by.
+type ProductSort string
+
+// Supported ProductSort values. The zero value orders by ID.
+const (
+	SortByID    ProductSort = ""
+	SortByName  ProductSort = "name"
+	SortByPrice ProductSort = "price"
+)
+
+// ProductFilter selects and orders the products returned by List. Zero
+// fields do not filter.
+type ProductFilter struct {
+	// Query matches products whose name contains it, ignoring case.
+	Query string
+	// Category matches products in the category with this slug.
+	Category string
+	// MinPrice and MaxPrice bound the price, inclusive. Products priced in
+	// a different currency from a bound never match it.
+	MinPrice, MaxPrice *models.Money
+	Sort               ProductSort
+	Desc               bool
+	// Limit and Offset select a window of the matches; a Limit of zero or
+	// less means no limit. Count ignores both.
+	Limit, Offset  int
+	IncludeDeleted bool
+}
+
+// Match reports whether p passes the filter's name, category, price and
+// deletion criteria.
+func (f ProductFilter) Match(p *models.Product) bool {
+	if p.IsDeleted() && !f.IncludeDeleted {
+		return false
+	}
+	if f.Query != "" && !strings.Contains(strings.ToLower(p.Name), strings.ToLower(f.Query)) {
+		return false
+	}
+	if f.Category != "" && p.Category != f.Category {
+		return false
+	}
+	if f.MinPrice != nil {
+		if c, err := p.Price.Cmp(*f.MinPrice); err != nil || c < 0 {
+			return false
+		}
+	}
+	if f.MaxPrice != nil {
+		if c, err := p.Price.Cmp(*f.MaxPrice); err != nil || c > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ProductRepository stores products.
+type ProductRepository interface {
+	// Create stores p and assigns its ID.
+	Create(ctx context.Context, p *models.Product) error
+	// CreateMany stores every product and assigns their IDs, or stores none
+	// of them on error.
+	CreateMany(ctx context.Context, ps []*models.Product) error
+	// Get returns the product with id, or ErrNotFound when it does not
+	// exist or has been soft-deleted.
+	Get(ctx context.Context, id int) (*models.Product, error)
+	// Update replaces the stored product, including soft-deleted ones.
+	Update(ctx context.Context, p *models.Product) error
+	// Delete soft-deletes the product. Deleting an already deleted product
+	// is a no-op.
+	Delete(ctx context.Context, id int) error
+	// List returns the products matching f, in the order it asks for, with
+	// ties broken by ID.
+	List(ctx context.Context, f ProductFilter) ([]*models.Product, error)
+	// Count returns how many products match f, ignoring Limit and Offset.
+	Count(ctx context.Context, f ProductFilter) (int, error)
+	// Reserve atomically takes n units of stock from the product, failing
+	// with models.ErrInsufficientStock when fewer remain.
+	Reserve(ctx context.Context, id, n int) error
+	// Release atomically returns n units of stock to the product.
+	Release(ctx context.Context, id, n int) error
+	// ReserveItems reserves stock for every item or, if any item cannot be
+	// satisfied, for none of them.
+	ReserveItems(ctx context.Context, items []models.OrderItem) error
+}
+
+// EachProduct yields the products matching f from f.Offset on, ignoring
+// f.Limit, fetching them from repo batch at a time so only one batch is
+// held in memory. A failing List is yielded as the last pair, with a nil
+// product. Products added or deleted while it runs can be skipped or
+// yielded twice, as with offset paging by hand.
+func EachProduct(ctx context.Context, repo ProductRepository, f ProductFilter, batch int) iter.Seq2[*models.Product, error] {
+	return func(yield func(*models.Product, error) bool) {
+		f.Limit = batch
+		for {
+			ps, err := repo.List(ctx, f)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, p := range ps {
+				if !yield(p, nil) {
+					return
+				}
+			}
+			if len(ps) < batch {
+				return
+			}
+			f.Offset += len(ps)
+		}
+	}
+}
+
+// InMemoryProductRepo is a ProductRepository backed by a map. It is safe for
+// concurrent use and copies products in and out. Like InMemoryUserRepo it
+// fails with the context's error once ctx is done.
+type InMemoryProductRepo struct {
+	ids models.IDGenerator[int]
+
+	mu       sync.RWMutex
+	products map[int]models.Product
+}
+
+// NewInMemoryProductRepo returns an empty in-memory repository. Like
+// NewInMemoryUserRepo it takes its IDs from a models.Sequence by default.
+func NewInMemoryProductRepo(opts ...MemoryOption) *InMemoryProductRepo {
+	return &InMemoryProductRepo{ids: applyMemoryOptions(opts), products: make(map[int]models.Product)}
+}
+
+func (r *InMemoryProductRepo) Create(ctx context.Context, p *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.ID = r.ids.NextID()
+	r.products[p.ID] = *p
+	return nil
+}
+
+// CreateMany stores the whole batch under one write lock, so readers never
+// see part of it.
+func (r *InMemoryProductRepo) CreateMany(ctx context.Context, ps []*models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, p := range ps {
+		p.ID = r.ids.NextID()
+		r.products[p.ID] = *p
+	}
+	return nil
+}
+
+func (r *InMemoryProductRepo) Get(ctx context.Context, id int) (*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p, ok := r.products[id]
+	if !ok || p.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *InMemoryProductRepo) Update(ctx context.Context, p *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := r.products[p.ID]; !ok {
+		return ErrNotFound
+	}
+	r.products[p.ID] = *p
+	return nil
+}
+
+func (r *InMemoryProductRepo) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p, ok := r.products[id]
+	if !ok {
+		return ErrNotFound
+	}
+	p.Delete()
+	r.products[id] = p
+	return nil
+}
+
+func (r *InMemoryProductRepo) List(ctx context.Context, f ProductFilter) ([]*models.Product, error) {
+	matches, err := r.match(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(matches, func(i, j int) bool { return productLess(matches[i], matches[j], f) })
+	return page(matches, f.Limit, f.Offset), nil
+}
+
+func (r *InMemoryProductRepo) Count(ctx context.Context, f ProductFilter) (int, error) {
+	matches, err := r.match(ctx, f)
+	return len(matches), err
+}
+
+func (r *InMemoryProductRepo) Reserve(ctx context.Context, id, n int) error {
+	return r.ReserveItems(ctx, []models.OrderItem{{ProductID: id, Quantity: n}})
+}
+
+func (r *InMemoryProductRepo) Release(ctx context.Context, id, n int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p, ok := r.products[id]
+	if !ok || p.IsDeleted() {
+		return ErrNotFound
+	}
+	p.Release(n)
+	r.products[id] = p
+	return nil
+}
+
+// ReserveItems works on copies under the write lock and only stores them
+// once every item has been reserved, so a failure leaves stock untouched.
+func (r *InMemoryProductRepo) ReserveItems(ctx context.Context, items []models.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	reserved := make(map[int]models.Product, len(items))
+	for _, it := range items {
+		p, ok := reserved[it.ProductID]
+		if !ok {
+			if p, ok = r.products[it.ProductID]; !ok || p.IsDeleted() {
+				return ErrNotFound
+			}
+		}
+		if err := p.Reserve(it.Quantity); err != nil {
+			return err
+		}
+		reserved[it.ProductID] = p
+	}
+	for id, p := range reserved {
+		r.products[id] = p
+	}
+	return nil
+}
+
+// match returns copies of the stored products that pass f, in no
+// particular order.
+func (r *InMemoryProductRepo) match(ctx context.Context, f ProductFilter) ([]*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	matches := make([]*models.Product, 0, len(r.products))
+	for _, p := range r.products {
+		if f.Match(&p) {
+			matches = append(matches, &p)
+		}
+	}
+	return matches, nil
+}
+
+// productLess orders a before b according to f.Sort and f.Desc, breaking
+// ties by ascending ID so pages are stable.
+func productLess(a, b *models.Product, f ProductFilter) bool {
+	var c int
+	switch f.Sort {
+	case SortByName:
+		c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortByPrice:
+		// Group by currency first; amounts only compare within one.
+		if c = strings.Compare(a.Price.Currency, b.Price.Currency); c == 0 {
+			c, _ = a.Price.Cmp(b.Price)
+		}
+	default:
+		c = a.ID - b.ID
+	}
+	if c == 0 {
+		return a.ID < b.ID
+	}
+	if f.Desc {
+		return c > 0
+	}
+	return c < 0
+}
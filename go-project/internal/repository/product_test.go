@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func usd(s string) models.Money {
+	m, err := models.ParseMoney(s, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func seedProducts(t *testing.T, repo ProductRepository) {
+	t.Helper()
+	for _, p := range []struct {
+		name     string
+		price    string
+		category string
+	}{
+		{"Blue Widget", "9.99", "widgets"},   // 1
+		{"gadget", "25", ""},                 // 2
+		{"Red widget", "4.50", "widgets"},    // 3
+		{"Widget Deluxe", "25", ""},          // 4
+		{"Sprocket", "0", "parts"},           // 5
+		{"Discontinued", "12.00", "widgets"}, // 6, deleted below
+	} {
+		product := models.NewProduct(p.name, usd(p.price))
+		product.Category = p.category
+		if err := repo.Create(context.Background(), product); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if err := repo.Delete(context.Background(), 6); err != nil {
+		t.Fatalf("seed delete: %v", err)
+	}
+}
+
+func TestInMemoryProductRepoCRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryProductRepo()
+	p := models.NewProduct("Widget", usd("9.99"))
+	if err := repo.Create(ctx, p); err != nil || p.ID != 1 {
+		t.Fatalf("Create: id %d, err %v", p.ID, err)
+	}
+	got, err := repo.Get(ctx, p.ID)
+	if err != nil || got.Name != "Widget" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	got.UpdatePrice(usd("12"))
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if again, _ := repo.Get(ctx, p.ID); again.Price != usd("12") {
+		t.Fatalf("price after update = %v", again.Price)
+	}
+	if err := repo.Delete(ctx, p.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(ctx, p.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after delete err = %v, want ErrNotFound", err)
+	}
+	missing := models.NewProduct("x", usd("1"))
+	missing.ID = 99
+	if err := repo.Update(ctx, missing); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Update of unknown product err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryProductRepoListFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryProductRepo()
+	seedProducts(t, repo)
+	price := func(s string) *models.Money { m := usd(s); return &m }
+
+	tests := []struct {
+		name    string
+		filter  ProductFilter
+		wantIDs []int
+	}{
+		{"everything live", ProductFilter{}, []int{1, 2, 3, 4, 5}},
+		{"include deleted", ProductFilter{IncludeDeleted: true}, []int{1, 2, 3, 4, 5, 6}},
+		{"query ignores case", ProductFilter{Query: "WIDGET"}, []int{1, 3, 4}},
+		{"min price", ProductFilter{MinPrice: price("10")}, []int{2, 4}},
+		{"price range inclusive", ProductFilter{MinPrice: price("4.50"), MaxPrice: price("9.99")}, []int{1, 3}},
+		{"query and price", ProductFilter{Query: "widget", MaxPrice: price("10")}, []int{1, 3}},
+		{"no matches", ProductFilter{Query: "nope"}, []int{}},
+		{"category", ProductFilter{Category: "widgets"}, []int{1, 3}},
+		{"category and query", ProductFilter{Category: "widgets", Query: "red"}, []int{3}},
+		{"unknown category", ProductFilter{Category: "toys"}, []int{}},
+		{"sort by name", ProductFilter{Sort: SortByName}, []int{1, 2, 3, 5, 4}},
+		{"sort by price desc, ties by id", ProductFilter{Sort: SortByPrice, Desc: true}, []int{2, 4, 1, 3, 5}},
+		{"sort by price with window", ProductFilter{Sort: SortByPrice, Limit: 2, Offset: 1}, []int{3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := repo.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			ids := []int{}
+			for _, p := range products {
+				ids = append(ids, p.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("List IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+
+	n, _ := repo.Count(ctx, ProductFilter{Query: "widget", Limit: 1})
+	if n != 3 {
+		t.Fatalf("Count = %d, want 3 regardless of Limit", n)
+	}
+}
+
+func TestInMemoryProductRepoConcurrentReserve(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryProductRepo()
+	p := models.NewProduct("Widget", usd("1"))
+	p.Stock = 50
+	repo.Create(ctx, p)
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int64
+	)
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.Reserve(ctx, p.ID, 1)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, models.ErrInsufficientStock):
+				t.Errorf("Reserve: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := succeeded.Load(); n != 50 {
+		t.Fatalf("%d reservations succeeded, want 50", n)
+	}
+	if got, _ := repo.Get(ctx, p.ID); got.Stock != 0 {
+		t.Fatalf("stock = %d, want 0", got.Stock)
+	}
+
+	if err := repo.Release(ctx, p.ID, 7); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if got, _ := repo.Get(ctx, p.ID); got.Stock != 7 {
+		t.Fatalf("stock after release = %d, want 7", got.Stock)
+	}
+}
+
+func TestInMemoryProductRepoReserveItemsRollsBack(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryProductRepo()
+	a := models.NewProduct("A", usd("1"))
+	a.Stock = 5
+	b := models.NewProduct("B", usd("1"))
+	b.Stock = 1
+	repo.Create(ctx, a)
+	repo.Create(ctx, b)
+
+	err := repo.ReserveItems(ctx, []models.OrderItem{
+		{ProductID: a.ID, Quantity: 2},
+		{ProductID: b.ID, Quantity: 2},
+	})
+	if !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("ReserveItems err = %v, want ErrInsufficientStock", err)
+	}
+	if got, _ := repo.Get(ctx, a.ID); got.Stock != 5 {
+		t.Fatalf("stock of A after failed order = %d, want 5 (rolled back)", got.Stock)
+	}
+
+	// The same product twice counts against one stock level.
+	err = repo.ReserveItems(ctx, []models.OrderItem{
+		{ProductID: a.ID, Quantity: 3},
+		{ProductID: a.ID, Quantity: 3},
+	})
+	if !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("ReserveItems over a repeated product err = %v, want ErrInsufficientStock", err)
+	}
+	if err := repo.ReserveItems(ctx, []models.OrderItem{{ProductID: a.ID, Quantity: 5}, {ProductID: b.ID, Quantity: 1}}); err != nil {
+		t.Fatalf("ReserveItems: %v", err)
+	}
+	if err := repo.ReserveItems(ctx, []models.OrderItem{{ProductID: 99, Quantity: 1}}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ReserveItems of an unknown product err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryProductRepoCreateMany(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryProductRepo()
+	seedProducts(t, repo)
+	batch := []*models.Product{
+		models.NewProduct("Cog", usd("1")),
+		models.NewProduct("Flange", usd("2")),
+	}
+	if err := repo.CreateMany(ctx, batch); err != nil {
+		t.Fatalf("CreateMany: %v", err)
+	}
+	if batch[0].ID != 7 || batch[1].ID != 8 {
+		t.Fatalf("IDs = %d, %d, want 7, 8", batch[0].ID, batch[1].ID)
+	}
+	batch[0].Name = "changed"
+	if got, err := repo.Get(ctx, 7); err != nil || got.Name != "Cog" {
+		t.Fatalf("Get(7) = %+v, %v, want the stored copy of Cog", got, err)
+	}
+}
+
+func TestInMemoryProductRepoHonorsCanceledContext(t *testing.T) {
+	repo := NewInMemoryProductRepo()
+	seedProducts(t, repo)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := repo.CreateMany(ctx, []*models.Product{models.NewProduct("Cog", usd("1"))}); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateMany err = %v, want context.Canceled", err)
+	}
+	if _, err := repo.List(ctx, ProductFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("List err = %v, want context.Canceled", err)
+	}
+	if err := repo.Reserve(ctx, 1, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Reserve err = %v, want context.Canceled", err)
+	}
+	if n, _ := repo.Count(context.Background(), ProductFilter{}); n != 5 {
+		t.Errorf("products after canceled calls = %d, want 5", n)
+	}
+}
+
+func TestEachProductPagesThroughMatches(t *testing.T) {
+	repo := NewInMemoryProductRepo()
+	seedProducts(t, repo)
+	ctx := context.Background()
+
+	var ids []int
+	for p, err := range EachProduct(ctx, repo, ProductFilter{Offset: 1, Limit: 1}, 2) {
+		if err != nil {
+			t.Fatalf("EachProduct: %v", err)
+		}
+		ids = append(ids, p.ID)
+	}
+	if want := []int{2, 3, 4, 5}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("IDs = %v, want %v (from the offset on, ignoring the limit)", ids, want)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	for p, err := range EachProduct(canceled, repo, ProductFilter{}, 2) {
+		if p != nil || !errors.Is(err, context.Canceled) {
+			t.Fatalf("yielded %v, %v; want nil, context.Canceled", p, err)
+		}
+	}
+}
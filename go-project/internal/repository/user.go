@@ -0,0 +1,282 @@
+// Package repository persists application models.
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-project/internal/models"
+)
+
+// ErrNotFound is returned when no record exists for the requested ID.
+var ErrNotFound = errors.New("repository: not found")
+
+// ErrDuplicateEmail is returned by UserRepository.Create and Update when
+// another user, soft-deleted or not, already has the same email ignoring
+// case.
+var ErrDuplicateEmail = errors.New("repository: email already in use")
+
+// ErrUnavailable is returned, possibly wrapped, when the backing store
+// cannot be reached. Callers may retry later.
+var ErrUnavailable = errors.New("repository: backend unavailable")
+
+// UserRepository stores users.
+type UserRepository interface {
+	// Create stores u and assigns its ID. It fails with ErrDuplicateEmail
+	// when u's email is taken.
+	Create(ctx context.Context, u *models.User) error
+	// Get returns the user with id, or ErrNotFound when it does not exist or
+	// has been soft-deleted.
+	Get(ctx context.Context, id int) (*models.User, error)
+	// GetMany returns the users with the given ids that Get would find, in
+	// the order their ids first appear, in a single lookup. Missing ids are
+	// skipped rather than reported as an error.
+	GetMany(ctx context.Context, ids []int) ([]*models.User, error)
+	// Update replaces the stored user. It applies to soft-deleted users too,
+	// which is how a user restored with models.User.Undelete is saved. It
+	// fails with ErrDuplicateEmail when another user has u's email.
+	Update(ctx context.Context, u *models.User) error
+	// Delete soft-deletes the user. Deleting an already deleted user is a
+	// no-op.
+	Delete(ctx context.Context, id int) error
+	// List returns up to limit users ordered by ID, skipping the first
+	// offset. A limit of zero or less means no limit. Soft-deleted users
+	// are skipped unless IncludeDeleted is passed, and with After only users
+	// whose ID is greater are listed.
+	List(ctx context.Context, limit, offset int, opts ...ListOption) ([]*models.User, error)
+	// Count returns how many users List would return without a limit or
+	// offset. It takes the same options as List.
+	Count(ctx context.Context, opts ...ListOption) (int, error)
+	Pinger
+}
+
+// Pinger is implemented by repositories that can check their backing store.
+type Pinger interface {
+	// Ping reports whether the backing store is reachable.
+	Ping(ctx context.Context) error
+}
+
+// ListOption adjusts what List returns.
+type ListOption func(*listOptions)
+
+type listOptions struct {
+	includeDeleted bool
+	after          int
+}
+
+// IncludeDeleted makes List return soft-deleted records too.
+func IncludeDeleted() ListOption {
+	return func(o *listOptions) { o.includeDeleted = true }
+}
+
+// After makes List return only records with an ID greater than id, for
+// keyset pagination: passing the last ID of one page gets the next, and
+// unlike an offset it neither skips nor repeats records when others are
+// added or deleted in between.
+func After(id int) ListOption {
+	return func(o *listOptions) { o.after = id }
+}
+
+// listed reports whether u is one of the users List returns.
+func (o listOptions) listed(u *models.User) bool {
+	return (o.includeDeleted || !u.IsDeleted()) && u.ID > o.after
+}
+
+func applyListOptions(opts []ListOption) listOptions {
+	var o listOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// InMemoryUserRepo is a UserRepository backed by a map. It is safe for
+// concurrent use. Users are copied in and out so callers cannot mutate
+// stored records without going through Update. Every method fails with the
+// context's error, leaving the store untouched, once ctx is done.
+type InMemoryUserRepo struct {
+	ids models.IDGenerator[int]
+
+	mu    sync.RWMutex
+	users map[int]models.User
+	// emails maps emailKey of every stored user to its ID.
+	emails map[string]int
+}
+
+// NewInMemoryUserRepo returns an empty in-memory repository. IDs come from
+// a models.Sequence unless WithIDs says otherwise.
+func NewInMemoryUserRepo(opts ...MemoryOption) *InMemoryUserRepo {
+	return &InMemoryUserRepo{ids: applyMemoryOptions(opts), users: make(map[int]models.User), emails: make(map[string]int)}
+}
+
+// MemoryOption configures an in-memory repository.
+type MemoryOption func(*memoryOptions)
+
+type memoryOptions struct {
+	ids models.IDGenerator[int]
+}
+
+// WithIDs makes an in-memory repository assign IDs from ids, for example
+// to share one sequence between repositories or to start from a known
+// value in tests.
+func WithIDs(ids models.IDGenerator[int]) MemoryOption {
+	return func(o *memoryOptions) { o.ids = ids }
+}
+
+func applyMemoryOptions(opts []MemoryOption) models.IDGenerator[int] {
+	o := memoryOptions{ids: new(models.Sequence)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.ids
+}
+
+// emailKey is the form emails are compared in for uniqueness.
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (r *InMemoryUserRepo) Create(ctx context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	key := emailKey(u.Email)
+	if _, taken := r.emails[key]; taken {
+		return ErrDuplicateEmail
+	}
+	u.ID = r.ids.NextID()
+	r.users[u.ID] = *u
+	r.emails[key] = u.ID
+	return nil
+}
+
+func (r *InMemoryUserRepo) Get(ctx context.Context, id int) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	u, ok := r.users[id]
+	if !ok || u.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r *InMemoryUserRepo) GetMany(ctx context.Context, ids []int) ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	users := make([]*models.User, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		u, ok := r.users[id]
+		if !ok || u.IsDeleted() || seen[id] {
+			continue
+		}
+		seen[id] = true
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepo) Update(ctx context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	old, ok := r.users[u.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	key := emailKey(u.Email)
+	if id, taken := r.emails[key]; taken && id != u.ID {
+		return ErrDuplicateEmail
+	}
+	delete(r.emails, emailKey(old.Email))
+	r.users[u.ID] = *u
+	r.emails[key] = u.ID
+	return nil
+}
+
+func (r *InMemoryUserRepo) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	u.Delete()
+	r.users[id] = u
+	return nil
+}
+
+func (r *InMemoryUserRepo) List(ctx context.Context, limit, offset int, opts ...ListOption) ([]*models.User, error) {
+	o := applyListOptions(opts)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(r.users))
+	for id, u := range r.users {
+		if o.listed(&u) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	ids = page(ids, limit, offset)
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		u := r.users[id]
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+func (r *InMemoryUserRepo) Count(ctx context.Context, opts ...ListOption) (int, error) {
+	o := applyListOptions(opts)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, u := range r.users {
+		if o.listed(&u) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Ping always succeeds while ctx is live; the store is in memory.
+func (r *InMemoryUserRepo) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// page returns the window of s selected by limit and offset.
+func page[T any](s []T, limit, offset int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(s) {
+		return s[:0]
+	}
+	s = s[offset:]
+	if limit > 0 && limit < len(s) {
+		s = s[:limit]
+	}
+	return s
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-project/internal/models"
+	"go-project/internal/tracing"
+)
+
+// UsersSchema creates the table used by SQLUserRepository. It is written
+// for SQLite; other databases need their own auto-increment syntax and
+// case-insensitive unique constraint.
+const UsersSchema = `CREATE TABLE IF NOT EXISTS users (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	name          TEXT      NOT NULL,
+	email         TEXT      NOT NULL UNIQUE COLLATE NOCASE,
+	password_hash TEXT      NOT NULL DEFAULT '',
+	created_at    TIMESTAMP NOT NULL,
+	updated_at    TIMESTAMP NOT NULL,
+	deleted_at    TIMESTAMP NULL
+)`
+
+const (
+	userColumns = `id, name, email, password_hash, created_at, updated_at, deleted_at`
+
+	insertUserSQL = `INSERT INTO users (name, email, password_hash, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?) RETURNING id`
+	getUserSQL = `SELECT ` + userColumns + ` FROM users WHERE id = ? AND deleted_at IS NULL`
+	// getUsersSQL is completed with one ? per id and a closing parenthesis.
+	getUsersSQL   = `SELECT ` + userColumns + ` FROM users WHERE deleted_at IS NULL AND id IN (`
+	updateUserSQL = `UPDATE users SET name = ?, email = ?, password_hash = ?, created_at = ?, updated_at = ?, deleted_at = ?
+		WHERE id = ?`
+	deleteUserSQL = `UPDATE users SET deleted_at = COALESCE(deleted_at, ?) WHERE id = ?`
+	listUsersSQL  = `SELECT ` + userColumns + ` FROM users WHERE (? OR deleted_at IS NULL) AND id > ?
+		ORDER BY id LIMIT ? OFFSET ?`
+	countUsersSQL = `SELECT COUNT(*) FROM users WHERE (? OR deleted_at IS NULL) AND id > ?`
+)
+
+// SQLUserRepository is a UserRepository backed by database/sql. Queries use
+// ? placeholders and INSERT ... RETURNING, so the driver must support both;
+// SQLite 3.35+ does. The users table must already exist, see UsersSchema.
+// Each call is traced as a child of the span in its context.
+type SQLUserRepository struct {
+	db *sql.DB
+}
+
+// NewSQLUserRepository returns a repository that stores users in db. The
+// caller picks the driver and owns the connection pool.
+func NewSQLUserRepository(db *sql.DB) *SQLUserRepository {
+	return &SQLUserRepository{db: db}
+}
+
+func (r *SQLUserRepository) Create(ctx context.Context, u *models.User) error {
+	ctx, span := startSpan(ctx, "Create")
+	defer span.End()
+	var id int
+	err := r.db.QueryRowContext(ctx, insertUserSQL,
+		u.Name, u.Email, u.PasswordHash, u.CreatedAt, u.UpdatedAt, nullTime(u.DeletedAt),
+	).Scan(&id)
+	if err != nil {
+		return mapUniqueEmail(err)
+	}
+	u.ID = id
+	return nil
+}
+
+func (r *SQLUserRepository) Get(ctx context.Context, id int) (*models.User, error) {
+	ctx, span := startSpan(ctx, "Get")
+	defer span.End()
+	u, err := scanUser(r.db.QueryRowContext(ctx, getUserSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return u, err
+}
+
+// GetMany looks the ids up in one SELECT ... WHERE id IN (...) query and
+// puts the rows back in the order of ids.
+func (r *SQLUserRepository) GetMany(ctx context.Context, ids []int) ([]*models.User, error) {
+	ctx, span := startSpan(ctx, "GetMany")
+	defer span.End()
+	if len(ids) == 0 {
+		return []*models.User{}, nil
+	}
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := getUsersSQL + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ")"
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	byID := make(map[int]*models.User, len(ids))
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[u.ID] = u
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	users := make([]*models.User, 0, len(byID))
+	for _, id := range ids {
+		if u, ok := byID[id]; ok {
+			users = append(users, u)
+			delete(byID, id)
+		}
+	}
+	return users, nil
+}
+
+func (r *SQLUserRepository) Update(ctx context.Context, u *models.User) error {
+	ctx, span := startSpan(ctx, "Update")
+	defer span.End()
+	res, err := r.db.ExecContext(ctx, updateUserSQL,
+		u.Name, u.Email, u.PasswordHash, u.CreatedAt, u.UpdatedAt, nullTime(u.DeletedAt), u.ID,
+	)
+	if err != nil {
+		return mapUniqueEmail(err)
+	}
+	return requireAffected(res)
+}
+
+func (r *SQLUserRepository) Delete(ctx context.Context, id int) error {
+	ctx, span := startSpan(ctx, "Delete")
+	defer span.End()
+	res, err := r.db.ExecContext(ctx, deleteUserSQL, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *SQLUserRepository) List(ctx context.Context, limit, offset int, opts ...ListOption) ([]*models.User, error) {
+	ctx, span := startSpan(ctx, "List")
+	defer span.End()
+	o := applyListOptions(opts)
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, err := r.db.QueryContext(ctx, listUsersSQL, o.includeDeleted, o.after, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	users := []*models.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *SQLUserRepository) Count(ctx context.Context, opts ...ListOption) (int, error) {
+	ctx, span := startSpan(ctx, "Count")
+	defer span.End()
+	o := applyListOptions(opts)
+	var n int
+	err := r.db.QueryRowContext(ctx, countUsersSQL, o.includeDeleted, o.after).Scan(&n)
+	return n, err
+}
+
+// Ping checks the database connection with db.PingContext.
+func (r *SQLUserRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+// startSpan starts a client span for one repository call, named after the
+// method, as a child of the span in ctx.
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "users."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.collection.name", "users")),
+	)
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(s scanner) (*models.User, error) {
+	var (
+		u         models.User
+		deletedAt sql.NullTime
+	)
+	err := s.Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	u.CreatedAt = u.CreatedAt.UTC()
+	u.UpdatedAt = u.UpdatedAt.UTC()
+	if deletedAt.Valid {
+		t := deletedAt.Time.UTC()
+		u.DeletedAt = &t
+	}
+	return &u, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// mapUniqueEmail turns SQLite's violation of the unique email constraint
+// into ErrDuplicateEmail. database/sql has no portable constraint error, so
+// this matches the driver's message.
+func mapUniqueEmail(err error) error {
+	if strings.Contains(err.Error(), "UNIQUE constraint failed: users.email") {
+		return ErrDuplicateEmail
+	}
+	return err
+}
+
+// requireAffected maps an UPDATE that matched no rows to ErrNotFound.
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
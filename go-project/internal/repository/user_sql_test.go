@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go-project/internal/models"
+
+	_ "modernc.org/sqlite"
+)
+
+func newSQLTestRepo(t *testing.T) *SQLUserRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Every connection to :memory: is a separate database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(UsersSchema); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	return NewSQLUserRepository(db)
+}
+
+func TestSQLUserRepoCRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLTestRepo(t)
+
+	u := models.NewUser("Ada", "ada@example.com")
+	u.PasswordHash = "hash"
+	if err := repo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if u.ID != 1 {
+		t.Fatalf("assigned ID = %d, want 1", u.ID)
+	}
+
+	got, err := repo.Get(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Name != "Ada" || got.Email != "ada@example.com" || got.PasswordHash != "hash" ||
+		!got.CreatedAt.Equal(u.CreatedAt) || !got.UpdatedAt.Equal(u.UpdatedAt) || got.DeletedAt != nil {
+		t.Fatalf("Get = %+v, want %+v", got, u)
+	}
+	if got.CreatedAt.Location() != time.UTC {
+		t.Errorf("CreatedAt location = %v, want UTC", got.CreatedAt.Location())
+	}
+
+	got.UpdateEmail("ada@new.example.com")
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if again, _ := repo.Get(ctx, u.ID); again.Email != "ada@new.example.com" {
+		t.Fatalf("email after update = %q", again.Email)
+	}
+	nobody := models.NewUser("Nobody", "x@example.com")
+	nobody.ID = 99
+	if err := repo.Update(ctx, nobody); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Update of unknown user err = %v, want ErrNotFound", err)
+	}
+
+	if err := repo.Delete(ctx, u.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(ctx, u.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after delete err = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, 99); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete of unknown user err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLUserRepoSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLTestRepo(t)
+	ada := models.NewUser("Ada", "ada@example.com")
+	grace := models.NewUser("Grace", "grace@example.com")
+	repo.Create(ctx, ada)
+	repo.Create(ctx, grace)
+
+	repo.Delete(ctx, ada.ID)
+	all, _ := repo.List(ctx, 0, 0, IncludeDeleted())
+	deletedAt := *all[0].DeletedAt
+	if err := repo.Delete(ctx, ada.ID); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+
+	live, err := repo.List(ctx, 0, 0)
+	if err != nil || len(live) != 1 || live[0].ID != grace.ID {
+		t.Fatalf("List without deleted = %+v, %v, want only Grace", live, err)
+	}
+	if n, _ := repo.Count(ctx); n != 1 {
+		t.Fatalf("Count without deleted = %d, want 1", n)
+	}
+	if n, _ := repo.Count(ctx, IncludeDeleted()); n != 2 {
+		t.Fatalf("Count with deleted = %d, want 2", n)
+	}
+	all, _ = repo.List(ctx, 0, 0, IncludeDeleted())
+	if !all[0].DeletedAt.Equal(deletedAt) {
+		t.Fatalf("double delete moved DeletedAt from %v to %v", deletedAt, *all[0].DeletedAt)
+	}
+
+	restored := all[0]
+	restored.Undelete()
+	if err := repo.Update(ctx, restored); err != nil {
+		t.Fatalf("Update after Undelete: %v", err)
+	}
+	if _, err := repo.Get(ctx, ada.ID); err != nil {
+		t.Fatalf("Get after undelete: %v", err)
+	}
+}
+
+func TestSQLUserRepoListLimitOffset(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLTestRepo(t)
+	for i := 0; i < 5; i++ {
+		repo.Create(ctx, models.NewUser(fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i)))
+	}
+
+	tests := []struct {
+		limit, offset int
+		wantIDs       []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{2, 0, []int{1, 2}},
+		{2, 3, []int{4, 5}},
+		{10, 4, []int{5}},
+		{2, 5, nil},
+	}
+	for _, tt := range tests {
+		users, err := repo.List(ctx, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("List(%d, %d): %v", tt.limit, tt.offset, err)
+		}
+		if len(users) != len(tt.wantIDs) {
+			t.Fatalf("List(%d, %d) returned %d users, want %d", tt.limit, tt.offset, len(users), len(tt.wantIDs))
+		}
+		for i, u := range users {
+			if u.ID != tt.wantIDs[i] {
+				t.Errorf("List(%d, %d)[%d].ID = %d, want %d", tt.limit, tt.offset, i, u.ID, tt.wantIDs[i])
+			}
+		}
+	}
+}
+
+func TestSQLUserRepoHonorsContext(t *testing.T) {
+	repo := newSQLTestRepo(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := repo.Create(ctx, models.NewUser("Ada", "ada@example.com")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Create with canceled ctx err = %v, want context.Canceled", err)
+	}
+	if _, err := repo.List(ctx, 0, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("List with canceled ctx err = %v, want context.Canceled", err)
+	}
+}
+
+func TestSQLUserRepoPing(t *testing.T) {
+	ctx := context.Background()
+	repo := newSQLTestRepo(t)
+	if err := repo.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+	repo.db.Close()
+	if err := repo.Ping(ctx); err == nil {
+		t.Fatal("Ping succeeded on a closed database")
+	}
+}
+
+func TestSQLUserRepoSpansJoinTheCallersTrace(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	repo := newSQLTestRepo(t)
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	repo.Create(ctx, models.NewUser("Ada", "ada@example.com"))
+	repo.Get(ctx, 1)
+	parent.End()
+
+	var names []string
+	for _, s := range spans.Ended() {
+		if s.Name() == "request" {
+			continue
+		}
+		names = append(names, s.Name())
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s has parent %v, want the caller's span", s.Name(), s.Parent())
+		}
+	}
+	if fmt.Sprint(names) != "[users.Create users.Get]" {
+		t.Fatalf("repository spans = %v, want [users.Create users.Get]", names)
+	}
+}
@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-project/internal/models"
+)
+
+func TestInMemoryUserRepoCRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryUserRepo()
+
+	u := models.NewUser("Ada", "ada@example.com")
+	if err := repo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if u.ID != 1 {
+		t.Fatalf("assigned ID = %d, want 1", u.ID)
+	}
+
+	got, err := repo.Get(ctx, u.ID)
+	if err != nil || got.Email != "ada@example.com" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	got.UpdateEmail("ada@new.example.com")
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if again, _ := repo.Get(ctx, u.ID); again.Email != "ada@new.example.com" {
+		t.Fatalf("email after update = %q", again.Email)
+	}
+
+	if err := repo.Delete(ctx, u.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(ctx, u.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after delete err = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, 99); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete of unknown user err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestInMemoryUserRepoSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryUserRepo()
+	ada := models.NewUser("Ada", "ada@example.com")
+	grace := models.NewUser("Grace", "grace@example.com")
+	repo.Create(ctx, ada)
+	repo.Create(ctx, grace)
+
+	if err := repo.Delete(ctx, ada.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	all, _ := repo.List(ctx, 0, 0, IncludeDeleted())
+	deletedAt := *all[0].DeletedAt
+	if err := repo.Delete(ctx, ada.ID); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+
+	live, _ := repo.List(ctx, 0, 0)
+	if len(live) != 1 || live[0].ID != grace.ID {
+		t.Fatalf("List without deleted = %+v, want only Grace", live)
+	}
+	if n, _ := repo.Count(ctx); n != 1 {
+		t.Fatalf("Count without deleted = %d, want 1", n)
+	}
+	if n, _ := repo.Count(ctx, IncludeDeleted()); n != 2 {
+		t.Fatalf("Count with deleted = %d, want 2", n)
+	}
+	all, _ = repo.List(ctx, 0, 0, IncludeDeleted())
+	if len(all) != 2 || all[0].ID != ada.ID || !all[0].IsDeleted() {
+		t.Fatalf("List with deleted = %+v, want Ada (deleted) and Grace", all)
+	}
+	if !all[0].DeletedAt.Equal(deletedAt) {
+		t.Fatalf("double delete moved DeletedAt from %v to %v", deletedAt, *all[0].DeletedAt)
+	}
+
+	restored := all[0]
+	restored.Undelete()
+	if err := repo.Update(ctx, restored); err != nil {
+		t.Fatalf("Update after Undelete: %v", err)
+	}
+	if _, err := repo.Get(ctx, ada.ID); err != nil {
+		t.Fatalf("Get after undelete: %v", err)
+	}
+}
+
+func TestInMemoryUserRepoReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryUserRepo()
+	u := models.NewUser("Ada", "ada@example.com")
+	repo.Create(ctx, u)
+
+	u.Name = "mutated"
+	got, _ := repo.Get(ctx, u.ID)
+	got.Email = "mutated@example.com"
+
+	if again, _ := repo.Get(ctx, u.ID); again.Name != "Ada" || again.Email != "ada@example.com" {
+		t.Fatalf("stored user was mutated through a pointer: %+v", again)
+	}
+}
+
+func TestInMemoryUserRepoListLimitOffset(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryUserRepo()
+	for i := 0; i < 5; i++ {
+		repo.Create(ctx, models.NewUser(fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i)))
+	}
+
+	tests := []struct {
+		limit, offset int
+		wantIDs       []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{2, 0, []int{1, 2}},
+		{2, 3, []int{4, 5}},
+		{10, 4, []int{5}},
+		{2, 5, nil},
+	}
+	for _, tt := range tests {
+		users, err := repo.List(ctx, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("List(%d, %d): %v", tt.limit, tt.offset, err)
+		}
+		if len(users) != len(tt.wantIDs) {
+			t.Fatalf("List(%d, %d) returned %d users, want %d", tt.limit, tt.offset, len(users), len(tt.wantIDs))
+		}
+		for i, u := range users {
+			if u.ID != tt.wantIDs[i] {
+				t.Errorf("List(%d, %d)[%d].ID = %d, want %d", tt.limit, tt.offset, i, u.ID, tt.wantIDs[i])
+			}
+		}
+	}
+}
+
+func TestInMemoryUserRepoConcurrentCreateGet(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryUserRepo()
+	const workers, perWorker = 8, 50
+
+	var wg sync.WaitGroup
+	ids := make(chan int, workers*perWorker)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				u := models.NewUser("user", fmt.Sprintf("w%d-%d@example.com", w, i))
+				if err := repo.Create(ctx, u); err != nil {
+					t.Errorf("Create: %v", err)
+					return
+				}
+				if _, err := repo.Get(ctx, u.ID); err != nil {
+					t.Errorf("Get(%d): %v", u.ID, err)
+				}
+				ids <- u.ID
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("ID %d assigned twice", id)
+		}
+		seen[id] = true
+	}
+	users, _ := repo.List(ctx, 0, 0)
+	if len(users) != workers*perWorker {
+		t.Fatalf("List returned %d users, want %d", len(users), workers*perWorker)
+	}
+}
+
+func TestUserReposListAfter(t *testing.T) {
+	repos := map[string]func(t *testing.T) UserRepository{
+		"memory": func(*testing.T) UserRepository { return NewInMemoryUserRepo() },
+		"sql":    func(t *testing.T) UserRepository { return newSQLTestRepo(t) },
+	}
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			for i := range 5 {
+				if err := repo.Create(ctx, models.NewUser(fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := repo.Delete(ctx, 4); err != nil {
+				t.Fatal(err)
+			}
+
+			users, err := repo.List(ctx, 2, 0, After(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != 2 || users[0].ID != 3 || users[1].ID != 5 {
+				t.Errorf("List after 2 = %v, want users 3 and 5, skipping the deleted 4", userIDs(users))
+			}
+			if n, _ := repo.Count(ctx, After(2)); n != 2 {
+				t.Errorf("Count after 2 = %d, want 2", n)
+			}
+			if users, _ := repo.List(ctx, 0, 0, After(5)); len(users) != 0 {
+				t.Errorf("List after the last user = %v, want none", userIDs(users))
+			}
+		})
+	}
+}
+
+func userIDs(users []*models.User) []int {
+	ids := make([]int, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func TestUserReposRejectDuplicateEmail(t *testing.T) {
+	repos := map[string]func(t *testing.T) UserRepository{
+		"memory": func(*testing.T) UserRepository { return NewInMemoryUserRepo() },
+		"sql":    func(t *testing.T) UserRepository { return newSQLTestRepo(t) },
+	}
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			ada := models.NewUser("Ada", "ada@example.com")
+			grace := models.NewUser("Grace", "grace@example.com")
+			for _, u := range []*models.User{ada, grace} {
+				if err := repo.Create(ctx, u); err != nil {
+					t.Fatalf("Create(%s): %v", u.Name, err)
+				}
+			}
+
+			for _, email := range []string{"ada@example.com", "ADA@example.com", "Ada@EXAMPLE.COM"} {
+				if err := repo.Create(ctx, models.NewUser("Imposter", email)); !errors.Is(err, ErrDuplicateEmail) {
+					t.Errorf("Create(%q) err = %v, want ErrDuplicateEmail", email, err)
+				}
+			}
+
+			grace.UpdateEmail("Ada@example.com")
+			if err := repo.Update(ctx, grace); !errors.Is(err, ErrDuplicateEmail) {
+				t.Errorf("Update to a taken email err = %v, want ErrDuplicateEmail", err)
+			}
+			// Changing the case of one's own email is not a conflict, and
+			// the old address becomes free.
+			ada.UpdateEmail("ADA@example.com")
+			if err := repo.Update(ctx, ada); err != nil {
+				t.Errorf("Update of own email: %v", err)
+			}
+			ada.UpdateEmail("lovelace@example.com")
+			if err := repo.Update(ctx, ada); err != nil {
+				t.Fatalf("Update to a free email: %v", err)
+			}
+			if err := repo.Create(ctx, models.NewUser("Ada", "ada@example.com")); err != nil {
+				t.Errorf("Create with a released email: %v", err)
+			}
+			if n, _ := repo.Count(ctx); n != 3 {
+				t.Errorf("users = %d, want 3", n)
+			}
+		})
+	}
+}
+
+func TestInMemoryReposAssignIncreasingIDs(t *testing.T) {
+	ctx := context.Background()
+	users := NewInMemoryUserRepo()
+	for want := 1; want <= 3; want++ {
+		u := models.NewUser("User", fmt.Sprintf("user%d@example.com", want))
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if u.ID != want {
+			t.Fatalf("user ID = %d, want %d", u.ID, want)
+		}
+	}
+
+	// Repositories sharing a generator never hand out the same ID.
+	var ids models.Sequence
+	users, products := NewInMemoryUserRepo(WithIDs(&ids)), NewInMemoryProductRepo(WithIDs(&ids))
+	u := models.NewUser("Ada", "ada@example.com")
+	p := models.NewProduct("Widget", models.Money{Amount: 100, Currency: "USD"})
+	if err := users.Create(ctx, u); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+	if err := products.Create(ctx, p); err != nil {
+		t.Fatalf("Create product: %v", err)
+	}
+	if u.ID != 1 || p.ID != 2 {
+		t.Fatalf("IDs from a shared sequence = %d, %d; want 1, 2", u.ID, p.ID)
+	}
+}
+
+func TestUserReposGetMany(t *testing.T) {
+	repos := map[string]func(t *testing.T) UserRepository{
+		"memory": func(*testing.T) UserRepository { return NewInMemoryUserRepo() },
+		"sql":    func(t *testing.T) UserRepository { return newSQLTestRepo(t) },
+	}
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+			for _, name := range []string{"Ada", "Grace", "Linus"} {
+				if err := repo.Create(ctx, models.NewUser(name, strings.ToLower(name)+"@example.com")); err != nil {
+					t.Fatalf("Create(%s): %v", name, err)
+				}
+			}
+			if err := repo.Delete(ctx, 3); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			users, err := repo.GetMany(ctx, []int{2, 99, 3, 1, 2})
+			if err != nil {
+				t.Fatalf("GetMany: %v", err)
+			}
+			var names []string
+			for _, u := range users {
+				names = append(names, u.Name)
+			}
+			if want := []string{"Grace", "Ada"}; !slices.Equal(names, want) {
+				t.Fatalf("GetMany names = %v, want %v", names, want)
+			}
+			if users, err := repo.GetMany(ctx, nil); err != nil || len(users) != 0 {
+				t.Fatalf("GetMany(nil) = %v, %v; want none", users, err)
+			}
+		})
+	}
+}
+
+func TestInMemoryUserRepoHonorsCanceledContext(t *testing.T) {
+	repo := NewInMemoryUserRepo()
+	u := models.NewUser("Ada", "ada@example.com")
+	if err := repo.Create(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := map[string]func() error{
+		"Create": func() error { return repo.Create(ctx, models.NewUser("Grace", "grace@example.com")) },
+		"Get":    func() error { _, err := repo.Get(ctx, u.ID); return err },
+		"Update": func() error { return repo.Update(ctx, u) },
+		"Delete": func() error { return repo.Delete(ctx, u.ID) },
+		"List":   func() error { _, err := repo.List(ctx, 0, 0); return err },
+		"Count":  func() error { _, err := repo.Count(ctx); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with a canceled context: err = %v, want context.Canceled", name, err)
+		}
+	}
+	if n, _ := repo.Count(context.Background(), IncludeDeleted()); n != 1 {
+		t.Errorf("users after canceled calls = %d, want 1", n)
+	}
+}
+
+func TestInMemoryUserRepoCanceledWhileWaitingForLock(t *testing.T) {
+	repo := NewInMemoryUserRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Hold the lock so Create blocks mid-operation, cancel, then let it run.
+	repo.mu.Lock()
+	errc := make(chan error, 1)
+	go func() { errc <- repo.Create(ctx, models.NewUser("Ada", "ada@example.com")) }()
+	cancel()
+	repo.mu.Unlock()
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Create err = %v, want context.Canceled", err)
+	}
+	if n, _ := repo.Count(context.Background()); n != 0 {
+		t.Fatalf("Create stored a user after cancellation (%d users)", n)
+	}
+}
@@ -0,0 +1,132 @@
+// Package seed fills the repositories with generated users and products so
+// a local server has something to show. The data is derived from a seed
+// number: the same seed always generates the same records, and records
+// that are already stored are skipped, so seeding again is harmless.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+// Options says how much to generate and from which seed.
+type Options struct {
+	Users, Products int
+	Seed            uint64
+	// Categories are the slugs products are filed under, picked at random;
+	// with none, products have no category.
+	Categories []string
+}
+
+// Result counts the records Load stored. Records that already existed are
+// not counted.
+type Result struct {
+	Users, Products int
+}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Rob", "Shafi", "Tim", "Yukihiro"}
+	lastNames  = []string{"Allen", "Dijkstra", "Goldwasser", "Hamilton", "Hopper", "Lamport", "Liskov", "Lovelace", "Matsumoto", "Perlman", "Pike", "Ritchie", "Thompson", "Torvalds", "Turing", "Wirth"}
+
+	adjectives = []string{"Compact", "Durable", "Ergonomic", "Handmade", "Lightweight", "Modern", "Portable", "Rustic", "Sleek", "Vintage"}
+	materials  = []string{"Bamboo", "Ceramic", "Cotton", "Glass", "Leather", "Oak", "Steel", "Wool"}
+	nouns      = []string{"Backpack", "Bookend", "Chair", "Desk Lamp", "Headphones", "Kettle", "Mug", "Notebook", "Planter", "Speaker"}
+)
+
+// Record kinds, each drawing from their own random streams.
+const (
+	userStream = iota
+	productStream
+)
+
+// rng returns the generator for the i'th record of a kind. Seeding each
+// record on its own keeps it the same however many are asked for, so
+// raising the count adds records without changing the earlier ones.
+func rng(seed uint64, stream, i int) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, uint64(i)<<1|uint64(stream)))
+}
+
+func pick(r *rand.Rand, words []string) string {
+	return words[r.IntN(len(words))]
+}
+
+// User returns the i'th user generated from seed. Its email is at
+// example.com, which is reserved for examples and receives no mail.
+func User(seed uint64, i int) *models.User {
+	r := rng(seed, userStream, i)
+	first, last := pick(r, firstNames), pick(r, lastNames)
+	email := strings.ToLower(first+"."+last+strconv.Itoa(i+1)) + "@example.com"
+	return models.NewUser(first+" "+last, email)
+}
+
+// Product returns the i'th product generated from seed, priced between
+// $1.00 and $500.00 and filed under one of categories.
+func Product(seed uint64, i int, categories []string) *models.Product {
+	r := rng(seed, productStream, i)
+	name := fmt.Sprintf("%s %s %s %d", pick(r, adjectives), pick(r, materials), pick(r, nouns), i+1)
+	p := models.NewProduct(name, models.NewMoney(100+r.Int64N(49901), models.DefaultCurrency))
+	p.Stock = r.IntN(101)
+	if len(categories) > 0 {
+		p.Category = pick(r, categories)
+	}
+	return p
+}
+
+// Load stores the first opts.Users users and opts.Products products
+// generated from opts.Seed, skipping users whose email is taken and
+// products whose name is already used, deleted or not.
+func Load(ctx context.Context, users repository.UserRepository, products repository.ProductRepository, opts Options) (Result, error) {
+	var res Result
+	for i := range opts.Users {
+		u := User(opts.Seed, i)
+		err := users.Create(ctx, u)
+		switch {
+		case errors.Is(err, repository.ErrDuplicateEmail):
+			continue
+		case err != nil:
+			return res, fmt.Errorf("seed: user %s: %w", u.Email, err)
+		}
+		res.Users++
+	}
+
+	var missing []*models.Product
+	for i := range opts.Products {
+		p := Product(opts.Seed, i, opts.Categories)
+		exists, err := productNamed(ctx, products, p.Name)
+		if err != nil {
+			return res, fmt.Errorf("seed: product %q: %w", p.Name, err)
+		}
+		if !exists {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+	if err := products.CreateMany(ctx, missing); err != nil {
+		return res, fmt.Errorf("seed: products: %w", err)
+	}
+	res.Products = len(missing)
+	return res, nil
+}
+
+// productNamed reports whether a product called exactly name is stored.
+func productNamed(ctx context.Context, products repository.ProductRepository, name string) (bool, error) {
+	matches, err := products.List(ctx, repository.ProductFilter{Query: name, IncludeDeleted: true})
+	if err != nil {
+		return false, err
+	}
+	for _, p := range matches {
+		if p.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
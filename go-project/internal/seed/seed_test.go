@@ -0,0 +1,120 @@
+package seed
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func counts(t *testing.T, users *repository.InMemoryUserRepo, products *repository.InMemoryProductRepo) (int, int) {
+	t.Helper()
+	u, err := users.Count(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := products.Count(context.Background(), repository.ProductFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u, p
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	users, products := repository.NewInMemoryUserRepo(), repository.NewInMemoryProductRepo()
+	opts := Options{Users: 25, Products: 40, Seed: 7, Categories: []string{"books", "electronics"}}
+
+	res, err := Load(ctx, users, products, opts)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if res != (Result{Users: 25, Products: 40}) {
+		t.Errorf("Load = %+v, want 25 users and 40 products", res)
+	}
+	if u, p := counts(t, users, products); u != 25 || p != 40 {
+		t.Fatalf("stored %d users and %d products, want 25 and 40", u, p)
+	}
+
+	all, _ := users.List(ctx, 0, 0)
+	for _, u := range all {
+		if err := u.Validate(); err != nil {
+			t.Errorf("user %q: %v", u.Email, err)
+		}
+	}
+	stored, _ := products.List(ctx, repository.ProductFilter{})
+	for _, p := range stored {
+		if err := p.Validate(); err != nil {
+			t.Errorf("product %q: %v", p.Name, err)
+		}
+		if p.Price.Amount < 100 || p.Price.Amount > 50000 || !slices.Contains(opts.Categories, p.Category) {
+			t.Errorf("product %q: price %v, category %q", p.Name, p.Price, p.Category)
+		}
+	}
+
+	// The same seed again stores nothing new
+	if res, err = Load(ctx, users, products, opts); err != nil || res != (Result{}) {
+		t.Fatalf("Load again = %+v, %v; want nothing stored", res, err)
+	}
+	if u, p := counts(t, users, products); u != 25 || p != 40 {
+		t.Fatalf("after reseeding: %d users and %d products, want 25 and 40", u, p)
+	}
+
+	// Asking for more tops up to the new counts
+	opts.Users, opts.Products = 30, 40
+	if res, err = Load(ctx, users, products, opts); err != nil || res != (Result{Users: 5}) {
+		t.Fatalf("Load with 30 users = %+v, %v; want 5 more users", res, err)
+	}
+}
+
+func TestGeneratedDataFollowsTheSeed(t *testing.T) {
+	for i := range 10 {
+		a, b := User(3, i), User(3, i)
+		if a.Name != b.Name || a.Email != b.Email {
+			t.Errorf("user %d differs between runs: %q <%s> and %q <%s>", i, a.Name, a.Email, b.Name, b.Email)
+		}
+		p, q := Product(3, i, nil), Product(3, i, nil)
+		if p.Name != q.Name || p.Price != q.Price || p.Stock != q.Stock {
+			t.Errorf("product %d differs between runs: %+v and %+v", i, p, q)
+		}
+	}
+
+	names := func(seed uint64) []string {
+		var ns []string
+		for i := range 10 {
+			ns = append(ns, User(seed, i).Name)
+		}
+		return ns
+	}
+	if slices.Equal(names(1), names(2)) {
+		t.Errorf("seeds 1 and 2 generated the same users: %q", names(1))
+	}
+}
+
+func TestLoadSkipsExistingRecords(t *testing.T) {
+	ctx := context.Background()
+	users, products := repository.NewInMemoryUserRepo(), repository.NewInMemoryProductRepo()
+	// Someone already registered with the second generated email, and a
+	// product with the first generated name was deleted
+	taken := User(1, 1)
+	if err := users.Create(ctx, models.NewUser("Someone Else", taken.Email)); err != nil {
+		t.Fatal(err)
+	}
+	deleted := Product(1, 0, nil)
+	if err := products.Create(ctx, deleted); err != nil {
+		t.Fatal(err)
+	}
+	if err := products.Delete(ctx, deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Load(ctx, users, products, Options{Users: 3, Products: 3, Seed: 1})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if res != (Result{Users: 2, Products: 2}) {
+		t.Errorf("Load = %+v, want 2 users and 2 products", res)
+	}
+}
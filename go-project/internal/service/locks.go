@@ -0,0 +1,66 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// keyedLocks is a set of mutexes created on demand, one per key, and
+// dropped again once nobody holds or waits for them. The zero value is
+// ready to use.
+type keyedLocks[K cmp.Ordered] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedMutex
+}
+
+type keyedMutex struct {
+	sync.Mutex
+	refs int // holders and waiters; guarded by keyedLocks.mu
+}
+
+// lock acquires the mutex of every key and returns a function releasing
+// them. Keys are locked in ascending order, whatever order they are given
+// in, so two callers wanting overlapping keys cannot each hold one the
+// other is waiting for. Duplicate keys are locked once.
+func (l *keyedLocks[K]) lock(keys ...K) (unlock func()) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	held := make([]*keyedMutex, len(keys))
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[K]*keyedMutex)
+	}
+	for i, k := range keys {
+		m := l.locks[k]
+		if m == nil {
+			m = &keyedMutex{}
+			l.locks[k] = m
+		}
+		m.refs++
+		held[i] = m
+	}
+	l.mu.Unlock()
+
+	for _, m := range held {
+		m.Lock()
+	}
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].Unlock()
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, k := range keys {
+			if held[i].refs--; held[i].refs == 0 {
+				delete(l.locks, k)
+			}
+		}
+	}
+}
+
+// size returns how many keys have a mutex, for tests.
+func (l *keyedLocks[K]) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.locks)
+}
@@ -0,0 +1,122 @@
+// Package service holds operations that span more than one repository,
+// such as placing an order, which takes stock from products and records
+// the order.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+// ErrIdempotencyKeyReused is returned by OrderService.Create when an order
+// was already placed with the same IdempotencyKey but different items or
+// user.
+var ErrIdempotencyKeyReused = errors.New("service: idempotency key was used for a different order")
+
+// OrderService places orders, reserving stock for them. It is safe for
+// concurrent use. Orders placed through the same OrderService never
+// oversell a product; stock changed behind its back is still caught by the
+// repository's Reserve, at the cost of a rollback.
+type OrderService struct {
+	Orders   repository.OrderRepository
+	Products repository.ProductRepository
+
+	products keyedLocks[int]
+	keys     keyedLocks[string]
+}
+
+// NewOrderService returns a service storing orders in orders and taking
+// their stock from products.
+func NewOrderService(orders repository.OrderRepository, products repository.ProductRepository) *OrderService {
+	return &OrderService{Orders: orders, Products: products}
+}
+
+// Create places o and assigns its ID. It validates o, reserves the stock of
+// every line and stores the order, or does none of it: when a product is
+// unknown or short, or storing fails, every reservation already made is
+// released again. A short product fails with models.ErrInsufficientStock
+// naming it; an unknown one with repository.ErrNotFound. Lines for the same
+// product are added together.
+//
+// While it runs, Create holds a lock per product in o, taken in order of
+// product ID so concurrent orders cannot deadlock. Orders with an
+// IdempotencyKey are also serialized per key: when an order with that key
+// was already placed, o is set to it and nothing is reserved again, unless
+// its items or user differ, which is ErrIdempotencyKeyReused.
+func (s *OrderService) Create(ctx context.Context, o *models.Order) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	if o.IdempotencyKey != "" {
+		unlock := s.keys.lock(o.IdempotencyKey)
+		defer unlock()
+		prev, err := s.Orders.GetByKey(ctx, o.IdempotencyKey)
+		switch {
+		case err == nil:
+			if prev.UserID != o.UserID || !maps.Equal(quantities(prev.Items), quantities(o.Items)) {
+				return ErrIdempotencyKeyReused
+			}
+			*o = *prev
+			return nil
+		case !errors.Is(err, repository.ErrNotFound):
+			return err
+		}
+	}
+
+	wanted := quantities(o.Items)
+	ids := slices.Sorted(maps.Keys(wanted))
+	unlock := s.products.lock(ids...)
+	defer unlock()
+
+	// Check every product before touching any stock, so the usual failure
+	// reserves nothing and needs no rollback.
+	for _, id := range ids {
+		p, err := s.Products.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("product %d: %w", id, err)
+		}
+		if p.Stock < wanted[id] {
+			return fmt.Errorf("%w: product %d (%s) has %d, want %d", models.ErrInsufficientStock, id, p.Name, p.Stock, wanted[id])
+		}
+	}
+
+	var reserved []int
+	rollback := func(err error) error {
+		// Release even if ctx is what made the reservation fail
+		rctx := context.WithoutCancel(ctx)
+		for _, id := range reserved {
+			if rerr := s.Products.Release(rctx, id, wanted[id]); rerr != nil {
+				err = errors.Join(err, fmt.Errorf("releasing stock of product %d: %w", id, rerr))
+			}
+		}
+		return err
+	}
+	for _, id := range ids {
+		if err := s.Products.Reserve(ctx, id, wanted[id]); err != nil {
+			if !errors.Is(err, models.ErrInsufficientStock) {
+				err = fmt.Errorf("product %d: %w", id, err)
+			}
+			return rollback(err)
+		}
+		reserved = append(reserved, id)
+	}
+	if err := s.Orders.Create(ctx, o); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// quantities returns the total quantity ordered of each product in items.
+func quantities(items []models.OrderItem) map[int]int {
+	q := make(map[int]int, len(items))
+	for _, it := range items {
+		q[it.ProductID] += it.Quantity
+	}
+	return q
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-project/internal/models"
+	"go-project/internal/repository"
+)
+
+func newTestService(t *testing.T, stock ...int) (*OrderService, *repository.InMemoryProductRepo, []int) {
+	t.Helper()
+	products := repository.NewInMemoryProductRepo()
+	ids := make([]int, len(stock))
+	for i, n := range stock {
+		p := models.NewProduct("Product "+string(rune('A'+i)), models.Money{Amount: 100, Currency: "USD"})
+		p.Stock = n
+		if err := products.Create(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = p.ID
+	}
+	return NewOrderService(repository.NewInMemoryOrderRepo(), products), products, ids
+}
+
+// stockOf returns the current stock of each product.
+func stockOf(t *testing.T, products repository.ProductRepository, ids ...int) []int {
+	t.Helper()
+	stock := make([]int, len(ids))
+	for i, id := range ids {
+		p, err := products.Get(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stock[i] = p.Stock
+	}
+	return stock
+}
+
+func TestCreateOrderReservesStock(t *testing.T) {
+	svc, products, ids := newTestService(t, 5, 3)
+	o := models.NewOrder(1,
+		models.OrderItem{ProductID: ids[0], Quantity: 2},
+		models.OrderItem{ProductID: ids[1], Quantity: 3},
+		models.OrderItem{ProductID: ids[0], Quantity: 1},
+	)
+	if err := svc.Create(context.Background(), o); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if o.ID == 0 {
+		t.Fatal("Create did not assign an ID")
+	}
+	if got := stockOf(t, products, ids...); got[0] != 2 || got[1] != 0 {
+		t.Errorf("stock after order = %v, want [2 0]", got)
+	}
+	if _, err := svc.Orders.Get(context.Background(), o.ID); err != nil {
+		t.Errorf("order was not stored: %v", err)
+	}
+}
+
+func TestCreateOrderFailsAsAWhole(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   func(ids []int) []models.OrderItem
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "short product",
+			items: func(ids []int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: ids[0], Quantity: 1}, {ProductID: ids[1], Quantity: 4}}
+			},
+			wantErr: models.ErrInsufficientStock,
+			wantMsg: "(Product B) has 3, want 4",
+		},
+		{
+			name: "lines adding up to more than the stock",
+			items: func(ids []int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: ids[1], Quantity: 2}, {ProductID: ids[1], Quantity: 2}}
+			},
+			wantErr: models.ErrInsufficientStock,
+			wantMsg: "want 4",
+		},
+		{
+			name: "unknown product",
+			items: func(ids []int) []models.OrderItem {
+				return []models.OrderItem{{ProductID: ids[0], Quantity: 1}, {ProductID: 99, Quantity: 1}}
+			},
+			wantErr: repository.ErrNotFound,
+			wantMsg: "product 99",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, products, ids := newTestService(t, 5, 3)
+			err := svc.Create(context.Background(), models.NewOrder(1, tt.items(ids)...))
+			if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("err = %v, want %v mentioning %q", err, tt.wantErr, tt.wantMsg)
+			}
+			if got := stockOf(t, products, ids...); got[0] != 5 || got[1] != 3 {
+				t.Errorf("stock after failed order = %v, want it unchanged", got)
+			}
+		})
+	}
+
+	svc, _, _ := newTestService(t)
+	var v *models.ValidationError
+	if err := svc.Create(context.Background(), models.NewOrder(1)); !errors.As(err, &v) {
+		t.Errorf("order without items: err = %v, want a *models.ValidationError", err)
+	}
+}
+
+// racingProducts lets one product's stock vanish between OrderService's
+// check and its reservation, as a writer bypassing the service would.
+type racingProducts struct {
+	*repository.InMemoryProductRepo
+	steal int
+}
+
+func (r *racingProducts) Reserve(ctx context.Context, id, n int) error {
+	if id == r.steal {
+		if err := r.InMemoryProductRepo.Reserve(ctx, id, 1); err != nil {
+			return err
+		}
+	}
+	return r.InMemoryProductRepo.Reserve(ctx, id, n)
+}
+
+func TestCreateOrderRollsBackReservations(t *testing.T) {
+	svc, products, ids := newTestService(t, 5, 1)
+	svc.Products = &racingProducts{InMemoryProductRepo: products, steal: ids[1]}
+	items := []models.OrderItem{{ProductID: ids[0], Quantity: 2}, {ProductID: ids[1], Quantity: 1}}
+	if err := svc.Create(context.Background(), models.NewOrder(1, items...)); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("err = %v, want ErrInsufficientStock", err)
+	}
+	if got := stockOf(t, products, ids[0]); got[0] != 5 {
+		t.Errorf("stock of the reserved product = %d, want it released back to 5", got[0])
+	}
+
+	svc, products, ids = newTestService(t, 5, 1)
+	svc.Orders = failingOrders{}
+	if err := svc.Create(context.Background(), models.NewOrder(1, items...)); !errors.Is(err, errStore) {
+		t.Fatalf("err = %v, want the store's error", err)
+	}
+	if got := stockOf(t, products, ids...); got[0] != 5 || got[1] != 1 {
+		t.Errorf("stock after the order could not be stored = %v, want [5 1]", got)
+	}
+}
+
+var errStore = errors.New("store is down")
+
+type failingOrders struct{ repository.OrderRepository }
+
+func (failingOrders) Create(context.Context, *models.Order) error { return errStore }
+
+func TestConcurrentOrdersForTheLastUnit(t *testing.T) {
+	svc, products, ids := newTestService(t, 1)
+	const buyers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, buyers)
+	start := make(chan struct{})
+	for range buyers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- svc.Create(context.Background(), models.NewOrder(1, models.OrderItem{ProductID: ids[0], Quantity: 1}))
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, models.ErrInsufficientStock):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d orders got the last unit, want exactly 1", succeeded)
+	}
+	if got := stockOf(t, products, ids[0]); got[0] != 0 {
+		t.Errorf("stock = %d, want 0", got[0])
+	}
+}
+
+func TestConcurrentOrdersDoNotDeadlock(t *testing.T) {
+	svc, products, ids := newTestService(t, 1000, 1000)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := range 100 {
+			// Half the orders list the products the other way round
+			first, second := ids[0], ids[1]
+			if i%2 == 1 {
+				first, second = second, first
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				o := models.NewOrder(1, models.OrderItem{ProductID: first, Quantity: 1}, models.OrderItem{ProductID: second, Quantity: 1})
+				if err := svc.Create(context.Background(), o); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("orders did not complete; deadlock?")
+	}
+	if got := stockOf(t, products, ids...); got[0] != 900 || got[1] != 900 {
+		t.Errorf("stock = %v, want [900 900]", got)
+	}
+	if n := svc.products.size(); n != 0 {
+		t.Errorf("%d product locks left behind", n)
+	}
+}
+
+func TestCreateOrderIsIdempotent(t *testing.T) {
+	svc, products, ids := newTestService(t, 5)
+	place := func(quantity int) (*models.Order, error) {
+		o := models.NewOrder(1, models.OrderItem{ProductID: ids[0], Quantity: quantity})
+		o.IdempotencyKey = "checkout-42"
+		return o, svc.Create(context.Background(), o)
+	}
+
+	first, err := place(2)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			retry, err := place(2)
+			if err != nil || retry.ID != first.ID {
+				t.Errorf("retry = order %d, %v; want order %d", retry.ID, err, first.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := stockOf(t, products, ids[0]); got[0] != 3 {
+		t.Errorf("stock after retries = %d, want 3: reserved once", got[0])
+	}
+
+	if _, err := place(1); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("same key, different items: err = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Package tracing connects the service to OpenTelemetry. Trace context
+// travels in W3C traceparent and tracestate headers, both on the requests
+// the server receives and on the ones it sends.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the service's spans to the TracerProvider.
+const instrumentationName = "go-project"
+
+// propagator reads and writes the W3C trace context headers. It is used
+// directly rather than through otel's global propagator so trace context is
+// carried even when Setup has not run.
+var propagator = propagation.TraceContext{}
+
+// Tracer returns the tracer for the service's spans, from the global
+// TracerProvider. Until Setup runs that provider is a no-op: spans record
+// nothing, but a span context taken from an incoming request still flows
+// through them to outgoing ones.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs a global TracerProvider that sends finished spans to
+// exporter in batches. With a nil exporter spans are still created, so
+// requests without a traceparent start a new trace and its IDs are passed
+// on, but nothing is exported. The returned function flushes any pending
+// spans and stops the provider.
+func Setup(exporter sdktrace.SpanExporter) (shutdown func(context.Context) error) {
+	var opts []sdktrace.TracerProviderOption
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	return tp.Shutdown
+}
+
+// Extract returns ctx carrying the remote span context named by h's
+// traceparent header, or ctx unchanged when there is none or it is
+// malformed.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(h))
+}
+
+// Inject sets h's traceparent and tracestate headers from the span in ctx.
+// It leaves h alone when ctx has no valid span.
+func Inject(ctx context.Context, h http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestExtractInjectRoundTrip(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	in := http.Header{}
+	in.Set("traceparent", traceparent)
+	out := http.Header{}
+	Inject(Extract(context.Background(), in), out)
+	if got := out.Get("traceparent"); got != traceparent {
+		t.Fatalf("traceparent = %q, want %q", got, traceparent)
+	}
+
+	bad := http.Header{}
+	bad.Set("traceparent", "00-not-a-trace-01")
+	out = http.Header{}
+	Inject(Extract(context.Background(), bad), out)
+	if got := out.Get("traceparent"); got != "" {
+		t.Fatalf("malformed traceparent was passed on as %q", got)
+	}
+}
+
+func TestSetupWithoutExporterStartsTraces(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	shutdown := Setup(nil)
+	t.Cleanup(func() {
+		shutdown(context.Background())
+		otel.SetTracerProvider(prev)
+	})
+
+	ctx, span := Tracer().Start(context.Background(), "root")
+	defer span.End()
+	if !span.SpanContext().IsValid() {
+		t.Fatal("span started without an exporter has no trace ID")
+	}
+	h := http.Header{}
+	Inject(ctx, h)
+	if want := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"; h.Get("traceparent") != want {
+		t.Fatalf("traceparent = %q, want %q", h.Get("traceparent"), want)
+	}
+}
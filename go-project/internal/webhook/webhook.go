@@ -0,0 +1,248 @@
+// Package webhook notifies registered HTTP endpoints of product events.
+// Each delivery is a signed JSON POST, retried with backoff in the
+// background so the code that raised the event never waits on a receiver.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"go-project/internal/models"
+	"go-project/internal/tracing"
+	"go-project/pkg/utils"
+)
+
+// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the
+// request body, keyed with the dispatcher's secret.
+const SignatureHeader = "X-Webhook-Signature"
+
+// EventHeader repeats the event type so receivers can route without
+// parsing the body.
+const EventHeader = "X-Webhook-Event"
+
+// PriceUpdated is the type of the event sent when a product's price
+// changes.
+const PriceUpdated = "product.price_updated"
+
+// Event is the JSON body of a delivery.
+type Event struct {
+	Type      string       `json:"type"`
+	ProductID int          `json:"product_id"`
+	OldPrice  models.Money `json:"old_price"`
+	NewPrice  models.Money `json:"new_price"`
+	At        time.Time    `json:"at"`
+}
+
+// Delivery limits. They are variables so tests can shrink them.
+var (
+	// Attempts is how many times a delivery is tried before it is dropped.
+	Attempts = 5
+	// AttemptTimeout bounds a single POST, including reading the response.
+	AttemptTimeout = 10 * time.Second
+	// MaxRetryAfter caps the wait a receiver can ask for with Retry-After,
+	// so one cannot park a delivery goroutine indefinitely.
+	MaxRetryAfter = time.Minute
+)
+
+// Dispatcher POSTs events to every registered URL. It is safe for
+// concurrent use.
+type Dispatcher struct {
+	secret []byte
+	client *http.Client
+
+	mu   sync.RWMutex
+	urls []string
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher returns a Dispatcher that signs deliveries with secret.
+func NewDispatcher(secret []byte) *Dispatcher {
+	return &Dispatcher{secret: secret, client: &http.Client{}}
+}
+
+// Register adds an endpoint to deliver events to. It must be an absolute
+// http or https URL.
+func (d *Dispatcher) Register(rawURL string) error {
+	if _, err := utils.IsValidURL(rawURL); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	u, _ := url.Parse(rawURL)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("webhook: %q is not an http or https URL", rawURL)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.urls = append(d.urls, u.String())
+	return nil
+}
+
+// PriceChanged sends a PriceUpdated event for c to every registered URL
+// without blocking, traced as children of the span in ctx like
+// DispatchContext. It has the signature models.OnPriceChange expects.
+func (d *Dispatcher) PriceChanged(ctx context.Context, c models.PriceChange) {
+	d.DispatchContext(ctx, Event{Type: PriceUpdated, ProductID: c.ProductID, OldPrice: c.OldPrice, NewPrice: c.NewPrice, At: c.At})
+}
+
+// Dispatch sends e to every registered URL in the background. Failed
+// deliveries are retried with utils.Retry and logged once they give up.
+// Each delivery starts a new trace; use DispatchContext to continue the
+// caller's.
+func (d *Dispatcher) Dispatch(e Event) {
+	d.DispatchContext(context.Background(), e)
+}
+
+// DispatchContext is Dispatch with the deliveries traced as children of
+// the span in ctx. Each attempt is a client span whose context is sent in
+// the traceparent header. Canceling ctx does not stop the deliveries.
+func (d *Dispatcher) DispatchContext(ctx context.Context, e Event) {
+	ctx = context.WithoutCancel(ctx)
+	body, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("webhook: encoding event", "type", e.Type, "error", err)
+		return
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, u := range d.urls {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			err := utils.Retry(ctx, Attempts, func() error { return d.deliver(ctx, u, e.Type, body) })
+			if err != nil {
+				slog.Warn("webhook delivery failed", "url", u, "type", e.Type, "error", err)
+			}
+		}()
+	}
+}
+
+// Wait blocks until every delivery started so far has succeeded or given
+// up. Deliveries still retrying when the process exits are lost.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Shutdown is Wait bounded by ctx, for a lifecycle.Manager stop function. It
+// returns ctx's error when deliveries are still running once ctx is done;
+// those are lost when the process exits.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// deliver makes one attempt. Any 2xx response is success; other 4xx
+// responses except 429 mean the receiver rejected the event, which a retry
+// will not change. A 429 or 503 with a Retry-After header sets the wait
+// before the next attempt, up to MaxRetryAfter.
+func (d *Dispatcher) deliver(ctx context.Context, u, eventType string, body []byte) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "webhook "+eventType,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("url.full", u)),
+	)
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	ctx, cancel := context.WithTimeout(ctx, AttemptTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return utils.Permanent(err)
+	}
+	tracing.Inject(ctx, req.Header)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(SignatureHeader, Sign(d.secret, body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	err = fmt.Errorf("webhook: %s answered %s", u, resp.Status)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return utils.RetryAfter(err, min(d, MaxRetryAfter))
+		}
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return utils.Permanent(err)
+	}
+	return err
+}
+
+// parseRetryAfter reads a Retry-After value in either of its forms,
+// delta-seconds or an HTTP date, as a wait from now. A date in the past
+// means no wait.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseUint(v, 10, 32); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
+
+// Sign returns the SignatureHeader value for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrBadSignature is returned by Verify for a malformed or wrong signature.
+var ErrBadSignature = errors.New("webhook: signature does not match")
+
+// Verify checks a SignatureHeader value against body in constant time. It
+// is what receivers written in Go should call.
+func Verify(secret, body []byte, signature string) error {
+	got, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrBadSignature
+	}
+	sum, err := hex.DecodeString(got)
+	if err != nil {
+		return ErrBadSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return ErrBadSignature
+	}
+	return nil
+}
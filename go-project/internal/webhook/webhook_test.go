@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"go-project/internal/models"
+	"go-project/internal/tracing"
+	"go-project/pkg/utils"
+)
+
+var secret = []byte("test-secret")
+
+func usd(s string) models.Money {
+	m, err := models.ParseMoney(s, "USD")
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// fastRetries shrinks the backoff between delivery attempts for the test.
+func fastRetries(t *testing.T) {
+	base, max := utils.RetryBaseDelay, utils.RetryMaxDelay
+	utils.RetryBaseDelay, utils.RetryMaxDelay = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { utils.RetryBaseDelay, utils.RetryMaxDelay = base, max })
+}
+
+func TestPriceUpdateIsDeliveredSigned(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	var (
+		mu  sync.Mutex
+		got []delivery
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		got = append(got, delivery{r.Header.Clone(), body})
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(secret)
+	if err := d.Register(srv.URL + "/hook"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	models.OnPriceChange(d.PriceChanged)
+	t.Cleanup(func() { models.OnPriceChange(nil) })
+
+	p := models.NewProduct("Widget", usd("5"))
+	p.ID = 7
+	c, _ := p.UpdatePrice(usd("6.50"))
+	models.NotifyPriceChange(context.Background(), c)
+	d.Wait()
+
+	if len(got) != 1 {
+		t.Fatalf("deliveries = %d, want 1", len(got))
+	}
+	if err := Verify(secret, got[0].body, got[0].header.Get(SignatureHeader)); err != nil {
+		t.Fatalf("Verify: %v (signature %q)", err, got[0].header.Get(SignatureHeader))
+	}
+	if err := Verify([]byte("other"), got[0].body, got[0].header.Get(SignatureHeader)); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("Verify with the wrong secret err = %v, want ErrBadSignature", err)
+	}
+	if ct := got[0].header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var e Event
+	if err := json.Unmarshal(got[0].body, &e); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if e.Type != PriceUpdated || e.ProductID != 7 || e.OldPrice != usd("5") || e.NewPrice != usd("6.50") {
+		t.Fatalf("event = %+v", e)
+	}
+	if h := got[0].header.Get(EventHeader); h != PriceUpdated {
+		t.Errorf("%s = %q, want %q", EventHeader, h, PriceUpdated)
+	}
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	fastRetries(t)
+	tests := []struct {
+		name      string
+		statuses  []int // answered in turn, then 200
+		wantCalls int32
+	}{
+		{"server errors are retried", []int{http.StatusInternalServerError, http.StatusBadGateway}, 3},
+		{"rate limiting is retried", []int{http.StatusTooManyRequests}, 2},
+		{"rejection is not retried", []int{http.StatusBadRequest}, 1},
+		{"gives up after Attempts", []int{500, 500, 500, 500, 500, 500}, int32(Attempts)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if n := int(calls.Add(1)); n <= len(tt.statuses) {
+					w.WriteHeader(tt.statuses[n-1])
+				}
+			}))
+			defer srv.Close()
+
+			d := NewDispatcher(secret)
+			d.Register(srv.URL)
+			d.Dispatch(Event{Type: PriceUpdated, ProductID: 1})
+			d.Wait()
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("attempts = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestShutdownWaitsForDeliveries(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	d := NewDispatcher(secret)
+	d.Register(srv.URL)
+	d.Dispatch(Event{Type: PriceUpdated, ProductID: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with a delivery in flight = %v, want a deadline error", err)
+	}
+	close(release)
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown once the delivery finished: %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"2", 2 * time.Second, true},
+		{" 120 ", 2 * time.Minute, true},
+		{"0", 0, true},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"1.5", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// attemptTimes delivers one event to a server that answers the first
+// attempt with status and retryAfter, then 200, and returns when each
+// attempt arrived.
+func attemptTimes(t *testing.T, status int, retryAfter string) []time.Time {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(status)
+		}
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher(secret)
+	d.Register(srv.URL)
+	d.Dispatch(Event{Type: PriceUpdated, ProductID: 1})
+	d.Wait()
+	if len(times) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(times))
+	}
+	return times
+}
+
+func TestDeliveryHonorsRetryAfter(t *testing.T) {
+	fastRetries(t)
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			t.Parallel()
+			times := attemptTimes(t, status, "2")
+			if gap := times[1].Sub(times[0]); gap < 2*time.Second || gap > 4*time.Second {
+				t.Fatalf("next attempt came after %v, want about 2s", gap)
+			}
+		})
+	}
+}
+
+func TestDeliveryCapsRetryAfter(t *testing.T) {
+	fastRetries(t)
+	defer func(d time.Duration) { MaxRetryAfter = d }(MaxRetryAfter)
+	MaxRetryAfter = 50 * time.Millisecond
+
+	times := attemptTimes(t, http.StatusTooManyRequests, "3600")
+	if gap := times[1].Sub(times[0]); gap < MaxRetryAfter || gap > time.Second {
+		t.Fatalf("next attempt came after %v, want about %v", gap, MaxRetryAfter)
+	}
+}
+
+func TestDeliveryForwardsTraceContext(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	receivedTraceparent := func(t *testing.T) string {
+		t.Helper()
+		var got string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("traceparent")
+		}))
+		defer srv.Close()
+		d := NewDispatcher(secret)
+		d.Register(srv.URL)
+		h := http.Header{}
+		h.Set("traceparent", parent)
+		// The request that caused the event is over before its deliveries.
+		ctx, cancel := context.WithCancel(tracing.Extract(context.Background(), h))
+		cancel()
+		d.DispatchContext(ctx, Event{Type: PriceUpdated, ProductID: 1})
+		d.Wait()
+		return got
+	}
+
+	t.Run("with a provider", func(t *testing.T) {
+		prev := otel.GetTracerProvider()
+		spans := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+		t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+		got := receivedTraceparent(t)
+		ended := spans.Ended()
+		if len(ended) != 1 {
+			t.Fatalf("spans = %d, want 1", len(ended))
+		}
+		delivery := ended[0].SpanContext()
+		if want := "00-" + delivery.TraceID().String() + "-" + delivery.SpanID().String() + "-01"; got != want {
+			t.Fatalf("traceparent = %q, want the delivery span's %q", got, want)
+		}
+		if delivery.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || ended[0].Parent().SpanID().String() != "00f067aa0ba902b7" {
+			t.Fatalf("delivery span %v has parent %v, want a child of the incoming span", delivery, ended[0].Parent())
+		}
+	})
+	t.Run("no-op provider", func(t *testing.T) {
+		prev := otel.GetTracerProvider()
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		t.Cleanup(func() { otel.SetTracerProvider(prev) })
+		if got := receivedTraceparent(t); got != parent {
+			t.Fatalf("traceparent = %q, want the incoming %q passed on", got, parent)
+		}
+	})
+}
+
+func TestRegisterRejectsInvalidURLs(t *testing.T) {
+	d := NewDispatcher(secret)
+	for _, u := range []string{"", "example.com/hook", "ftp://example.com/hook", "http://", "https://exa mple.com"} {
+		if err := d.Register(u); err == nil {
+			t.Errorf("Register(%q): want an error", u)
+		}
+	}
+	if err := d.Register("https://example.com/hook"); err != nil {
+		t.Errorf("Register of a valid URL: %v", err)
+	}
+}
+
+func TestVerifyRejectsMalformedSignatures(t *testing.T) {
+	body := []byte(`{"type":"product.price_updated"}`)
+	for _, sig := range []string{"", "md5=abc", "sha256=zz", Sign(secret, []byte("other"))} {
+		if err := Verify(secret, body, sig); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("Verify(%q) err = %v, want ErrBadSignature", sig, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// ShortIDLength is the length of IDs returned by NewShortID. Eleven base62
+// characters hold slightly more than 64 bits.
+const ShortIDLength = 11
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// GenerateID creates a simple unique identifier.
+func GenerateID() string {
+	return uuid.New().String()
+}
+
+// GenerateIDWith returns a version 4 UUID built from the bytes of r instead
+// of crypto/rand, so a fixed reader yields a fixed ID.
+func GenerateIDWith(r io.Reader) (string, error) {
+	id, err := uuid.NewRandomFromReader(r)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// NewShortID returns a random ShortIDLength-character base62 identifier,
+// which is URL-safe without escaping.
+func NewShortID() string {
+	id, err := NewShortIDWith(rand.Reader)
+	if err != nil {
+		// crypto/rand.Read never fails on supported platforms.
+		panic(err)
+	}
+	return id
+}
+
+// NewShortIDWith is NewShortID reading its randomness from r.
+func NewShortIDWith(r io.Reader) (string, error) {
+	// Bytes at or above the largest multiple of 62 are skipped so every
+	// character is equally likely.
+	const limit = 256 - 256%len(base62Alphabet)
+	id := make([]byte, 0, ShortIDLength)
+	buf := make([]byte, ShortIDLength)
+	for len(id) < ShortIDLength {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(id) < ShortIDLength {
+				id = append(id, base62Alphabet[int(b)%len(base62Alphabet)])
+			}
+		}
+	}
+	return string(id), nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"regexp"
+	"testing"
+)
+
+// seeded returns a deterministic randomness source for the ID helpers.
+func seeded(seed uint64) *rand.ChaCha8 {
+	var s [32]byte
+	s[0] = byte(seed)
+	return rand.NewChaCha8(s)
+}
+
+func TestNewShortIDIsURLSafe(t *testing.T) {
+	urlSafe := regexp.MustCompile(`^[0-9A-Za-z]{11}$`)
+	seen := map[string]bool{}
+	for range 1000 {
+		id := NewShortID()
+		if !urlSafe.MatchString(id) {
+			t.Fatalf("NewShortID() = %q, want 11 base62 characters", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewShortID returned %q twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewShortIDWithSameSeedSameID(t *testing.T) {
+	a, err := NewShortIDWith(seeded(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewShortIDWith(seeded(1))
+	c, _ := NewShortIDWith(seeded(2))
+	if a != b {
+		t.Errorf("same seed gave %q and %q", a, b)
+	}
+	if a == c {
+		t.Errorf("different seeds both gave %q", a)
+	}
+}
+
+func TestNewShortIDWithSkipsBiasedBytes(t *testing.T) {
+	// 248..255 would favour the first characters of the alphabet.
+	src := append(bytes.Repeat([]byte{255}, 11), []byte{0, 1, 61, 62, 247, 0, 1, 2, 3, 4, 5}...)
+	id, err := NewShortIDWith(bytes.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "01z0z012345"; id != want {
+		t.Errorf("NewShortIDWith = %q, want %q", id, want)
+	}
+	if _, err := NewShortIDWith(bytes.NewReader(nil)); err == nil {
+		t.Error("NewShortIDWith of an empty reader succeeded")
+	}
+}
+
+func TestGenerateIDWithSameSeedSameID(t *testing.T) {
+	a, err := GenerateIDWith(seeded(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateIDWith(seeded(1))
+	if a != b {
+		t.Errorf("same seed gave %q and %q", a, b)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(a) {
+		t.Errorf("GenerateIDWith = %q, want a version 4 UUID", a)
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "strings"
+
+// Mask hides the middle of s behind asterisks, keeping its first and last
+// characters: "secret" becomes "s****t". Strings of one or two characters
+// are masked completely since keeping both ends would reveal them, and the
+// empty string is returned unchanged.
+func Mask(s string) string {
+	r := []rune(s)
+	if len(r) <= 2 {
+		return strings.Repeat("*", len(r))
+	}
+	return string(r[0]) + strings.Repeat("*", len(r)-2) + string(r[len(r)-1])
+}
+
+// MaskEmail masks the local part of email with Mask and keeps the domain, so
+// "jason@example.com" becomes "j***n@example.com". Input without an "@" is
+// masked as a whole.
+func MaskEmail(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return Mask(email)
+	}
+	return Mask(email[:i]) + email[i:]
+}
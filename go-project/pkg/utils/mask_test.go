@@ -0,0 +1,35 @@
+package utils
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"a", "*"},
+		{"ab", "**"},
+		{"abc", "a*c"},
+		{"secret-token", "s**********n"},
+		{"héllo", "h***o"},
+	}
+	for _, tt := range tests {
+		if got := Mask(tt.in); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"jason@example.com", "j***n@example.com"},
+		{"jo@example.com", "**@example.com"},
+		{"@example.com", "@example.com"},
+		{"not-an-email", "n**********l"},
+		{`"a@b"@example.com`, `"***"@example.com`},
+	}
+	for _, tt := range tests {
+		if got := MaskEmail(tt.in); got != tt.want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
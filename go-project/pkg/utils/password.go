@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptCost is the work factor used by HashPassword. Raise it as hardware
+// gets faster; lower it only in tests.
+var BcryptCost = bcrypt.DefaultCost
+
+// ErrPasswordMismatch is returned by ComparePassword when the password does
+// not match the hash.
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// HashPassword returns the bcrypt hash of plain.
+func HashPassword(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// ComparePassword checks plain against a hash produced by HashPassword. It
+// returns nil on a match and ErrPasswordMismatch when the password is wrong.
+func ComparePassword(hash, plain string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+// MaxPasswordBytes is the longest password bcrypt hashes in full. It ignores
+// every byte after the 72nd, so IsValidPassword rejects longer passwords
+// rather than let two of them share a hash.
+const MaxPasswordBytes = 72
+
+// Errors for each rule of a PasswordPolicy, wrapped by the
+// *PasswordPolicyError IsValidPassword returns.
+var (
+	ErrPasswordTooShort = errors.New("password is too short")
+	ErrPasswordTooLong  = errors.New("password is too long")
+	ErrPasswordNoUpper  = errors.New("password has no uppercase letter")
+	ErrPasswordNoLower  = errors.New("password has no lowercase letter")
+	ErrPasswordNoDigit  = errors.New("password has no digit")
+	ErrPasswordNoSymbol = errors.New("password has no symbol")
+)
+
+// PasswordPolicy is the set of rules IsValidPassword checks. The zero value
+// only enforces MaxPasswordBytes.
+type PasswordPolicy struct {
+	// MinLength is the fewest characters a password may have.
+	MinLength int
+	// MaxLength is the most bytes a password may have. Zero, or anything
+	// above MaxPasswordBytes, means MaxPasswordBytes.
+	MaxLength int
+	// RequireUpper, RequireLower, RequireDigit and RequireSymbol each ask
+	// for at least one character of their class. Symbols are Unicode
+	// punctuation and symbols such as ! or €.
+	RequireUpper, RequireLower, RequireDigit, RequireSymbol bool
+}
+
+// PasswordPolicyError lists every rule of a PasswordPolicy a password
+// breaks. errors.Is matches it against each rule's error, such as
+// ErrPasswordTooShort.
+type PasswordPolicyError struct {
+	// Rules are the broken rules, phrased to follow "password must".
+	Rules []string
+	errs  []error
+}
+
+func (e *PasswordPolicyError) Error() string {
+	rules := e.Rules
+	if len(rules) > 1 {
+		rules = append(rules[:len(rules)-1:len(rules)-1], "and "+rules[len(rules)-1])
+	}
+	sep := ", "
+	if len(rules) == 2 {
+		sep = " "
+	}
+	return "password must " + strings.Join(rules, sep)
+}
+
+func (e *PasswordPolicyError) Unwrap() []error { return e.errs }
+
+func (e *PasswordPolicyError) add(err error, rule string) {
+	e.errs = append(e.errs, err)
+	e.Rules = append(e.Rules, rule)
+}
+
+// IsValidPassword checks pw against policy and returns nil when it follows
+// every rule, or a *PasswordPolicyError naming all the rules it breaks.
+func IsValidPassword(pw string, policy PasswordPolicy) error {
+	e := &PasswordPolicyError{}
+	if utf8.RuneCountInString(pw) < policy.MinLength {
+		e.add(ErrPasswordTooShort, fmt.Sprintf("be at least %d characters", policy.MinLength))
+	}
+	maxLen := policy.MaxLength
+	if maxLen <= 0 || maxLen > MaxPasswordBytes {
+		maxLen = MaxPasswordBytes
+	}
+	if len(pw) > maxLen {
+		e.add(ErrPasswordTooLong, fmt.Sprintf("be at most %d bytes", maxLen))
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range pw {
+		upper = upper || unicode.IsUpper(r)
+		lower = lower || unicode.IsLower(r)
+		digit = digit || unicode.IsDigit(r)
+		symbol = symbol || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}
+	for _, class := range []struct {
+		required, found bool
+		err             error
+		rule            string
+	}{
+		{policy.RequireUpper, upper, ErrPasswordNoUpper, "contain an uppercase letter"},
+		{policy.RequireLower, lower, ErrPasswordNoLower, "contain a lowercase letter"},
+		{policy.RequireDigit, digit, ErrPasswordNoDigit, "contain a digit"},
+		{policy.RequireSymbol, symbol, ErrPasswordNoSymbol, "contain a symbol"},
+	} {
+		if class.required && !class.found {
+			e.add(class.err, class.rule)
+		}
+	}
+	if len(e.Rules) > 0 {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashAndComparePassword(t *testing.T) {
+	defer func(c int) { BcryptCost = c }(BcryptCost)
+	BcryptCost = bcrypt.MinCost
+
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	if hash == "correct horse" {
+		t.Fatal("HashPassword returned the plain text")
+	}
+	if err := ComparePassword(hash, "correct horse"); err != nil {
+		t.Fatalf("ComparePassword with the right password: %v", err)
+	}
+	if err := ComparePassword(hash, "battery staple"); !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("ComparePassword with a wrong password = %v, want ErrPasswordMismatch", err)
+	}
+}
+
+func TestIsValidPasswordRules(t *testing.T) {
+	strict := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+	tests := []struct {
+		name   string
+		pw     string
+		policy PasswordPolicy
+		want   error
+	}{
+		{"too short", "Ab1!", strict, ErrPasswordTooShort},
+		{"length counts characters, not bytes", "Ünïcödé-1Ö", strict, nil},
+		{"too long for bcrypt", "Aa1!" + strings.Repeat("x", MaxPasswordBytes-3), strict, ErrPasswordTooLong},
+		{"exactly the bcrypt limit", "Aa1!" + strings.Repeat("x", MaxPasswordBytes-4), strict, nil},
+		{"over a lower maximum", "Aa1!xxxxxxxxx", PasswordPolicy{MaxLength: 12}, ErrPasswordTooLong},
+		{"maximum above bcrypt's is capped", strings.Repeat("x", MaxPasswordBytes+1), PasswordPolicy{MaxLength: 100}, ErrPasswordTooLong},
+		{"no uppercase letter", "abcdefgh1!", strict, ErrPasswordNoUpper},
+		{"no lowercase letter", "ABCDEFGH1!", strict, ErrPasswordNoLower},
+		{"no digit", "Abcdefghi!", strict, ErrPasswordNoDigit},
+		{"no symbol", "Abcdefghi1", strict, ErrPasswordNoSymbol},
+		{"a currency sign is a symbol", "Abcdefgh1€", strict, nil},
+		{"zero policy accepts anything bcrypt can hash", "", PasswordPolicy{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidPassword(tt.pw, tt.policy)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("IsValidPassword(%q) = %v, want nil", tt.pw, err)
+				}
+				return
+			}
+			var perr *PasswordPolicyError
+			if !errors.Is(err, tt.want) || !errors.As(err, &perr) || len(perr.Rules) != 1 {
+				t.Fatalf("IsValidPassword(%q) = %v, want only %v", tt.pw, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidPasswordReportsEveryBrokenRule(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 12, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+	err := IsValidPassword("lowercase", policy)
+	for _, want := range []error{ErrPasswordTooShort, ErrPasswordNoUpper, ErrPasswordNoDigit, ErrPasswordNoSymbol} {
+		if !errors.Is(err, want) {
+			t.Errorf("error %v does not match %v", err, want)
+		}
+	}
+	if errors.Is(err, ErrPasswordNoLower) {
+		t.Errorf("error %v reports a lowercase letter missing", err)
+	}
+	want := "password must be at least 12 characters, contain an uppercase letter, contain a digit, and contain a symbol"
+	if err == nil || err.Error() != want {
+		t.Errorf("message = %q, want %q", err, want)
+	}
+
+	policy = PasswordPolicy{MinLength: 12, RequireDigit: true}
+	if err := IsValidPassword("short", policy); err == nil || err.Error() != "password must be at least 12 characters and contain a digit" {
+		t.Errorf("two rules: message = %q", err)
+	}
+}
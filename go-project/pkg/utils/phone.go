@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// E.164 allows at most 15 digits after the "+", country code included. No
+// assigned number is shorter than 7.
+const (
+	minPhoneDigits = 7
+	maxPhoneDigits = 15
+)
+
+// PhoneOption changes how IsValidPhone reads its input.
+type PhoneOption func(*phoneOptions)
+
+type phoneOptions struct {
+	lenient bool
+}
+
+// LenientPhone makes IsValidPhone ignore the spaces, dashes, dots and
+// parentheses people write numbers with, so "+1 (415) 555-2671" is read as
+// "+14155552671". The country code is still required.
+func LenientPhone() PhoneOption {
+	return func(o *phoneOptions) { o.lenient = true }
+}
+
+// IsValidPhone checks that s is a phone number in E.164 format: a "+"
+// followed by 7 to 15 digits, the first of which is not 0.
+func IsValidPhone(s string, opts ...PhoneOption) (bool, error) {
+	var o phoneOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if strings.TrimSpace(s) == "" {
+		return false, errors.New("phone number cannot be empty")
+	}
+	if o.lenient {
+		s = strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', '-', '.', '(', ')':
+				return -1
+			}
+			return r
+		}, s)
+	}
+	digits, ok := strings.CutPrefix(s, "+")
+	if !ok || len(digits) < minPhoneDigits || len(digits) > maxPhoneDigits || digits[0] == '0' {
+		return false, nil
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import "testing"
+
+func TestIsValidPhone(t *testing.T) {
+	tests := []struct {
+		phone   string
+		lenient bool
+		want    bool
+	}{
+		{"+14155552671", false, true},
+		{"+442071838750", false, true},
+		{"+6834002", false, true},
+		{"+1 (415) 555-2671", true, true},
+		{"+1.415.555.2671", true, true},
+		{"+1 (415) 555-2671", false, false},
+		{"14155552671", false, false},
+		{"(415) 555-2671", true, false},
+		{"+04155552671", false, false},
+		{"+123456", false, false},
+		{"+1234567890123456", false, false},
+		{"+1415555267a", false, false},
+		{"++14155552671", true, false},
+		{"+", false, false},
+	}
+	for _, tt := range tests {
+		var opts []PhoneOption
+		if tt.lenient {
+			opts = append(opts, LenientPhone())
+		}
+		if got, err := IsValidPhone(tt.phone, opts...); got != tt.want || err != nil {
+			t.Errorf("IsValidPhone(%q, lenient %v) = %v, %v; want %v, nil", tt.phone, tt.lenient, got, err, tt.want)
+		}
+	}
+	for _, empty := range []string{"", "   "} {
+		if ok, err := IsValidPhone(empty, LenientPhone()); ok || err == nil {
+			t.Errorf("IsValidPhone(%q) = %v, %v; want false and an error", empty, ok, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff bounds used by Retry. The wait before attempt n+1 is a random
+// duration in [0, min(RetryBaseDelay*2^(n-1), RetryMaxDelay)). They are
+// variables so tests can shrink them.
+var (
+	RetryBaseDelay = 100 * time.Millisecond
+	RetryMaxDelay  = 5 * time.Second
+)
+
+// permanentError marks an error that Retry must not retry.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Retry gives up immediately when fn returns it.
+// Permanent(nil) returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// retryAfterError carries the wait a failed attempt asked for.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// RetryAfter wraps err so that Retry waits delay before the next attempt
+// instead of its usual backoff, as when a server answers with a
+// Retry-After header. Callers should cap delay themselves. RetryAfter(nil,
+// d) returns nil.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: max(delay, 0)}
+}
+
+// Retry calls fn up to attempts times, sleeping with exponential backoff and
+// full jitter between failed attempts, or for the delay given to
+// RetryAfter when fn's error carries one. It stops early when fn succeeds,
+// when fn returns an error wrapped with Permanent, or when ctx is done. The
+// returned error wraps fn's last error (unwrapped from Permanent) and says
+// how many attempts were made; on cancellation it also wraps ctx.Err().
+func Retry(ctx context.Context, attempts int, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for n := 1; ; n++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return fmt.Errorf("permanent error after %d attempt(s): %w", n, perm.err)
+		}
+		if n == attempts {
+			return fmt.Errorf("giving up after %d attempt(s): %w", n, err)
+		}
+
+		wait := backoff(n)
+		var after *retryAfterError
+		if errors.As(err, &after) {
+			wait = after.delay
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("retry canceled after %d attempt(s): %w: %w", n, ctx.Err(), err)
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns the jittered wait after the n-th failed attempt.
+func backoff(n int) time.Duration {
+	d := RetryMaxDelay
+	if shift := n - 1; shift < 32 {
+		if exp := RetryBaseDelay << shift; exp > 0 && exp < d {
+			d = exp
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fastRetry(t *testing.T) {
+	t.Helper()
+	base, maxDelay := RetryBaseDelay, RetryMaxDelay
+	t.Cleanup(func() { RetryBaseDelay, RetryMaxDelay = base, maxDelay })
+	RetryBaseDelay, RetryMaxDelay = time.Millisecond, 5*time.Millisecond
+}
+
+func TestRetrySucceedsOnThirdTry(t *testing.T) {
+	fastRetry(t)
+	calls := 0
+	err := Retry(context.Background(), 5, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("flaky")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Retry = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Fatalf("fn called %d times, want 3", calls)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	fastRetry(t)
+	boom := errors.New("boom")
+	calls := 0
+	err := Retry(context.Background(), 4, func() error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Retry = %v, want it to wrap %v", err, boom)
+	}
+	if calls != 4 {
+		t.Fatalf("fn called %d times, want 4", calls)
+	}
+	if want := "giving up after 4 attempt(s): boom"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err, want)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	fastRetry(t)
+	RetryBaseDelay, RetryMaxDelay = time.Hour, time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	boom := errors.New("boom")
+	calls := 0
+	err := Retry(ctx, 10, func() error {
+		calls++
+		cancel()
+		return boom
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, boom) {
+		t.Fatalf("Retry = %v, want it to wrap context.Canceled and %v", err, boom)
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times after cancel, want 1", calls)
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+	fastRetry(t)
+	bad := errors.New("bad request")
+	calls := 0
+	err := Retry(context.Background(), 5, func() error {
+		calls++
+		return Permanent(bad)
+	})
+	if !errors.Is(err, bad) {
+		t.Fatalf("Retry = %v, want it to wrap %v", err, bad)
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+	if Permanent(nil) != nil {
+		t.Fatal("Permanent(nil) != nil")
+	}
+}
+
+func TestRetryAfterOverridesBackoff(t *testing.T) {
+	fastRetry(t)
+	RetryBaseDelay, RetryMaxDelay = time.Hour, time.Hour
+
+	busy := errors.New("busy")
+	var times []time.Time
+	err := Retry(context.Background(), 2, func() error {
+		times = append(times, time.Now())
+		return RetryAfter(busy, 20*time.Millisecond)
+	})
+	if !errors.Is(err, busy) {
+		t.Fatalf("Retry = %v, want it to wrap %v", err, busy)
+	}
+	if len(times) != 2 {
+		t.Fatalf("fn called %d times, want 2", len(times))
+	}
+	// An hour of backoff would have timed the test out.
+	if gap := times[1].Sub(times[0]); gap < 20*time.Millisecond {
+		t.Fatalf("waited %v between attempts, want at least 20ms", gap)
+	}
+	if RetryAfter(nil, time.Second) != nil {
+		t.Fatal("RetryAfter(nil) != nil")
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// scriptOrStyle matches script and style elements, whose content is
+	// code rather than text and is dropped along with the tags.
+	scriptOrStyle = regexp.MustCompile(`(?is)<script\b.*?(</script\s*>|$)|<style\b.*?(</style\s*>|$)`)
+	// htmlTag matches a tag, comment or doctype, including one left open at
+	// the end of the input. A "<" not followed by a letter, "/", "!" or "?"
+	// is not a tag, so text such as "a < b" is kept.
+	htmlTag = regexp.MustCompile(`<[a-zA-Z/!?][^>]*(>|$)`)
+)
+
+// SanitizeText makes user-supplied text such as a name safe to store: it
+// removes HTML tags, along with the contents of script and style elements,
+// turns tabs and line breaks into spaces, drops other control characters
+// and trims surrounding whitespace. Everything
+// else, including quotes, apostrophes, ampersands and non-ASCII letters,
+// is kept as is. Nothing is escaped, so sanitizing already-safe or
+// already-sanitized text returns it unchanged; escaping is left to the
+// code that renders it.
+func SanitizeText(s string) string {
+	// Removing one tag can join the text around it into another, as in
+	// "<<b>script>", so repeat until nothing changes.
+	for {
+		stripped := htmlTag.ReplaceAllString(scriptOrStyle.ReplaceAllString(s, ""), "")
+		if stripped == s {
+			break
+		}
+		s = stripped
+	}
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
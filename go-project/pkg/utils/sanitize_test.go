@@ -0,0 +1,33 @@
+package utils
+
+import "testing"
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"<script>alert('xss')</script>", ""},
+		{"Ada<script>document.cookie</script> Lovelace", "Ada Lovelace"},
+		{`<img src=x onerror="alert(1)">Widget`, "Widget"},
+		{"<b>Bold</b> name", "Bold name"},
+		{"Widget<script", "Widget"},
+		{"<!-- note -->Gadget", "Gadget"},
+		{"O'Brien", "O'Brien"},
+		{`Tom & Jerry's "Best"`, `Tom & Jerry's "Best"`},
+		{"2 < 3 > 1", "2 < 3 > 1"},
+		{"&lt;b&gt; stays escaped", "&lt;b&gt; stays escaped"},
+		{"Zoë Ångström 東京", "Zoë Ångström 東京"},
+		{"<<b>script>alert(1)<</b>/script>", ""},
+		{"  tab\tand\x00null\x1b  ", "tab andnull"},
+		{"line\r\nbreak", "line  break"},
+	}
+	for _, tt := range tests {
+		got := SanitizeText(tt.in)
+		if got != tt.want {
+			t.Errorf("SanitizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if again := SanitizeText(got); again != got {
+			t.Errorf("SanitizeText is not idempotent on %q: %q", got, again)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations maps common accented and ligature characters to ASCII.
+// Lookups happen after lowercasing, so only lowercase forms are listed.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe",
+	'ř': "r",
+	'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s",
+	'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slugify turns s into a lowercase, hyphen-separated slug made only of
+// ASCII letters and digits, e.g. "Café Crème (500g)" becomes
+// "cafe-creme-500g". Common accented letters are transliterated; anything
+// else is treated as a separator. The result is safe to use as a URL path
+// segment without escaping and is empty when s has no usable characters.
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		part := transliterations[r]
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			part = string(r)
+		}
+		if part == "" {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
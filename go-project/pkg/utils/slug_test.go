@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello World", "hello-world"},
+		{"  MiXeD CaSe  ", "mixed-case"},
+		{"Widget, Deluxe! (v2.0)", "widget-deluxe-v2-0"},
+		{"--already-slugged--", "already-slugged"},
+		{"Café Crème", "cafe-creme"},
+		{"Straße & Smørrebrød", "strasse-smorrebrod"},
+		{"Ærøskøbing Œuvre", "aeroskobing-oeuvre"},
+		{"日本語 tea", "tea"},
+		{"!!! ??? ***", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := Slugify(tt.in)
+		if got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if url.PathEscape(got) != got {
+			t.Errorf("Slugify(%q) = %q needs URL escaping", tt.in, got)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URLReason says why IsValidURL rejected a URL.
+type URLReason string
+
+// Reasons reported in a *URLError.
+const (
+	URLEmpty         URLReason = "empty"
+	URLMalformed     URLReason = "malformed"
+	URLMissingScheme URLReason = "missing scheme"
+	URLMissingHost   URLReason = "missing host"
+	URLNotHTTPS      URLReason = "scheme is not https"
+	URLPrivateHost   URLReason = "host is private or loopback"
+)
+
+// URLError is the error IsValidURL returns for a rejected URL.
+type URLError struct {
+	URL    string
+	Reason URLReason
+}
+
+func (e *URLError) Error() string {
+	return strconv.Quote(e.URL) + " is not a valid URL: " + string(e.Reason)
+}
+
+// URLOption adds a requirement to IsValidURL.
+type URLOption func(*urlOptions)
+
+type urlOptions struct {
+	httpsOnly  bool
+	publicOnly bool
+}
+
+// HTTPSOnly rejects URLs whose scheme is not https.
+func HTTPSOnly() URLOption {
+	return func(o *urlOptions) { o.httpsOnly = true }
+}
+
+// PublicHostOnly rejects URLs that name a loopback, private, link-local or
+// unspecified IP address, or localhost, so a server fetching the URL cannot
+// be pointed at itself or its internal network. Other host names are not
+// resolved, so a name that resolves to a private address passes; code that
+// dials the URL must check the address it connects to if that matters.
+func PublicHostOnly() URLOption {
+	return func(o *urlOptions) { o.publicOnly = true }
+}
+
+// IsValidURL checks that s parses with net/url as an absolute URL with a
+// scheme and a host, plus any requirements added by opts. A rejected URL
+// yields false and a *URLError naming the reason.
+func IsValidURL(s string, opts ...URLOption) (bool, error) {
+	var o urlOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	reject := func(r URLReason) (bool, error) { return false, &URLError{URL: s, Reason: r} }
+
+	if strings.TrimSpace(s) == "" {
+		return reject(URLEmpty)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return reject(URLMalformed)
+	}
+	if u.Scheme == "" {
+		return reject(URLMissingScheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return reject(URLMissingHost)
+	}
+	if o.httpsOnly && !strings.EqualFold(u.Scheme, "https") {
+		return reject(URLNotHTTPS)
+	}
+	if o.publicOnly && privateHost(host) {
+		return reject(URLPrivateHost)
+	}
+	return true, nil
+}
+
+// privateHost reports whether host is localhost or an IP address that does
+// not reach the public internet.
+func privateHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsUnspecified() || addr.IsInterfaceLocalMulticast()
+}
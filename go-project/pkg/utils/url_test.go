@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsValidURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		opts []URLOption
+		want URLReason // empty when the URL is valid
+	}{
+		{"https://example.com/hook", nil, ""},
+		{"http://example.com:8080/hook?x=1", nil, ""},
+		{"http://127.0.0.1/hook", nil, ""},
+		{"", nil, URLEmpty},
+		{"http://exa mple.com", nil, URLMalformed},
+		{"example.com/hook", nil, URLMissingScheme},
+		{"https:///hook", nil, URLMissingHost},
+		{"mailto:ada@example.com", nil, URLMissingHost},
+
+		{"https://example.com", []URLOption{HTTPSOnly()}, ""},
+		{"HTTPS://example.com", []URLOption{HTTPSOnly()}, ""},
+		{"http://example.com", []URLOption{HTTPSOnly()}, URLNotHTTPS},
+		{"ftp://example.com", []URLOption{HTTPSOnly()}, URLNotHTTPS},
+
+		{"https://93.184.216.34/", []URLOption{PublicHostOnly()}, ""},
+		{"https://example.com/", []URLOption{PublicHostOnly()}, ""},
+		{"http://127.0.0.1:9000/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://localhost/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://api.localhost./", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://10.0.0.5/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://192.168.1.1/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://172.16.0.1/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://169.254.169.254/latest/meta-data", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://0.0.0.0/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://[::1]/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://[fd00::1]/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://[::ffff:127.0.0.1]/", []URLOption{PublicHostOnly()}, URLPrivateHost},
+		{"http://10.0.0.5/", []URLOption{HTTPSOnly(), PublicHostOnly()}, URLNotHTTPS},
+	}
+	for _, tt := range tests {
+		ok, err := IsValidURL(tt.url, tt.opts...)
+		if tt.want == "" {
+			if !ok || err != nil {
+				t.Errorf("IsValidURL(%q) = %v, %v; want true", tt.url, ok, err)
+			}
+			continue
+		}
+		var uerr *URLError
+		if ok || !errors.As(err, &uerr) || uerr.Reason != tt.want || uerr.URL != tt.url {
+			t.Errorf("IsValidURL(%q) = %v, %v; want false and reason %q", tt.url, ok, err, tt.want)
+		}
+	}
+}
@@ -3,25 +3,52 @@ package utils
 import (
 	"errors"
 	"regexp"
+	"strings"
 )
 
+// Length limits from RFC 5321 section 4.5.3.1.
+const (
+	maxEmailLength      = 254
+	maxEmailLocalLength = 64
+)
+
+// emailRegex matches local@domain where the domain is dot-separated labels
+// ending in an alphabetic TLD. Labels may contain Unicode letters so
+// internationalized domain names are accepted. Dot placement in the local
+// part is checked separately.
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[\p{L}\p{N}-]+(\.[\p{L}\p{N}-]+)*\.\p{L}{2,}$`)
+
 // IsValidEmail checks if the provided email address is valid.
 func IsValidEmail(email string) (bool, error) {
 	if email == "" {
 		return false, errors.New("email cannot be empty")
 	}
-	// Simple regex for validating an email address
-	const emailRegex = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
-	re := regexp.MustCompile(emailRegex)
-	return re.MatchString(email), nil
+	if len(email) > maxEmailLength {
+		return false, nil
+	}
+	local, _, ok := strings.Cut(email, "@")
+	if !ok || len(local) > maxEmailLocalLength {
+		return false, nil
+	}
+	if strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return false, nil
+	}
+	return emailRegex.MatchString(email), nil
+}
+
+// NormalizeEmail trims surrounding whitespace from email and lowercases its
+// domain, which is case-insensitive. The local part keeps its case since
+// RFC 5321 leaves its interpretation to the receiving server.
+func NormalizeEmail(email string) string {
+	email = strings.TrimSpace(email)
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return email
+	}
+	return email[:i] + strings.ToLower(email[i:])
 }
 
 // FormatString trims whitespace from the beginning and end of a string.
 func FormatString(s string) string {
 	return strings.TrimSpace(s)
 }
-
-// GenerateID creates a simple unique identifier.
-func GenerateID() string {
-	return uuid.New().String()
-}
\ No newline at end of file
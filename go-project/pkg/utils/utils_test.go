@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestFormatStringAndGenerateID exercises the helpers whose imports were once
+// missing, so the package failing to compile shows up as a test failure.
+func TestFormatStringAndGenerateID(t *testing.T) {
+	if got := FormatString("  padded\t\n"); got != "padded" {
+		t.Errorf("FormatString = %q, want %q", got, "padded")
+	}
+
+	a, b := GenerateID(), GenerateID()
+	if _, err := uuid.Parse(a); err != nil {
+		t.Errorf("GenerateID() = %q is not a UUID: %v", a, err)
+	}
+	if a == b {
+		t.Errorf("GenerateID returned %q twice", a)
+	}
+}
+
+func TestIsValidEmail(t *testing.T) {
+	valid := []string{
+		"ada@example.com",
+		"ada.lovelace@example.co.uk",
+		"first+tag@sub.example.org",
+		"under_score%x@example.io",
+		"user@bücher.de",
+		"user@例え.テスト",
+		strings.Repeat("a", 64) + "@example.com",
+	}
+	invalid := []string{
+		"plainaddress",
+		"@example.com",
+		"ada@",
+		"ada@example",
+		"ada@example.c",
+		"ada@@example.com",
+		".ada@example.com",
+		"ada.@example.com",
+		"ada..lovelace@example.com",
+		"ada@example..com",
+		"ada@.example.com",
+		"ada @example.com",
+		strings.Repeat("a", 65) + "@example.com",
+		"ada@" + strings.Repeat("a", 250) + ".com",
+	}
+	for _, email := range valid {
+		if ok, err := IsValidEmail(email); !ok || err != nil {
+			t.Errorf("IsValidEmail(%q) = %v, %v; want true", email, ok, err)
+		}
+	}
+	for _, email := range invalid {
+		if ok, err := IsValidEmail(email); ok || err != nil {
+			t.Errorf("IsValidEmail(%q) = %v, %v; want false, nil", email, ok, err)
+		}
+	}
+	if ok, err := IsValidEmail(""); ok || err == nil {
+		t.Errorf("IsValidEmail(\"\") = %v, %v; want false and an error", ok, err)
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"ada@example.com", "ada@example.com"},
+		{"  Ada.Lovelace@EXAMPLE.Com\t", "Ada.Lovelace@example.com"},
+		{"no-at-sign ", "no-at-sign"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeEmail(tt.in); got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkIsValidEmail(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IsValidEmail("ada.lovelace@example.co.uk")
+	}
+}
+
+// BenchmarkIsValidEmailCompileEachCall measures the previous behaviour of
+// compiling the pattern on every call, for comparison.
+func BenchmarkIsValidEmailCompileEachCall(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		regexp.MustCompile(emailRegex.String()).MatchString("ada.lovelace@example.co.uk")
+	}
+}
@@ -11,9 +11,14 @@ Tools exposed:
   - generate_gitlab_ci_pipeline       : Create a GitLab CI .gitlab-ci.yml
   - generate_jenkins_pipeline         : Create a Jenkins Declarative Pipeline
   - generate_k8s_config               : Create Kubernetes manifests
+  - generate_k8s_manifests            : Create Deployment / Service YAML with health probes
   - generate_argocd_config            : Create ArgoCD Application / AppProject CRs
   - generate_sre_configs              : Create Prometheus rules, Grafana dashboard, SLO manifest
   - scaffold_devcontainer             : Create a dev-container configuration
+  - generate_unittest_config          : Create unit test configs and sample tests
+  - generate_dockerfile               : Create a multi-stage Dockerfile for a Go service
+  - validate_yaml                     : Check Deployment / Service / ArgoCD Application YAML
+  - list_generators                   : List the CLI generators with their parameters and defaults
 """
 
 import sys
@@ -30,6 +35,9 @@ sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
 from mcp.server.fastmcp import FastMCP
 import yaml
 
+from cli.scaffold_dockerfile import DEFAULT_GO_VERSION, DEFAULT_PORT
+from cli.scaffold_k8s import DEFAULT_LIVENESS_PATH, DEFAULT_READINESS_PATH
+
 
 class _NoAliasDumper(yaml.Dumper):
     """Custom YAML Dumper that never emits anchors or aliases.
@@ -101,6 +109,44 @@ def _build_jenkins_args(
     )
 
 
+def _build_k8s_args(
+    name: str,
+    image: str,
+    replicas: int,
+    port: int,
+    namespace: str,
+    liveness_path: str,
+    readiness_path: str,
+) -> argparse.Namespace:
+    """Build an argparse.Namespace compatible with scaffold_k8s functions."""
+    return argparse.Namespace(
+        name=name,
+        image=image,
+        replicas=replicas,
+        port=port,
+        namespace=namespace,
+        cpu_request="100m",
+        memory_request="64Mi",
+        cpu_limit="500m",
+        memory_limit="128Mi",
+        liveness_path=liveness_path,
+        readiness_path=readiness_path,
+        ingress_host="",
+        ingress_class="",
+        out="k8s",
+        force=False,
+    )
+
+
+def _tool_error(code: str, message: str) -> str:
+    """Return the JSON error document tools send back instead of raising.
+
+    Raising inside a tool surfaces as an opaque transport error; a structured
+    reply lets the assistant show the message and retry with fixed arguments.
+    """
+    return json.dumps({"error": {"code": code, "message": message}}, indent=2)
+
+
 # ---------------------------------------------------------------------------
 # MCP Server
 # ---------------------------------------------------------------------------
@@ -110,7 +156,8 @@ mcp = FastMCP(
     instructions=(
         "DevOps-OS MCP Server provides tools for generating DevOps automation "
         "artifacts including GitHub Actions workflows, Jenkins pipelines, "
-        "Kubernetes manifests, and dev-container configurations."
+        "Kubernetes manifests, Dockerfiles for Go services, and dev-container "
+        "configurations."
     ),
 )
 
@@ -317,6 +364,52 @@ def generate_k8s_config(
     return "---\n".join(manifests)
 
 
+# ---------------------------------------------------------------------------
+# Tool: generate_k8s_manifests
+# ---------------------------------------------------------------------------
+
+@mcp.tool()
+def generate_k8s_manifests(
+    name: str = "my-app",
+    image: str = "ghcr.io/myorg/my-app:latest",
+    replicas: int = 2,
+    port: int = 8080,
+    namespace: str = "default",
+    liveness_path: str = DEFAULT_LIVENESS_PATH,
+    readiness_path: str = DEFAULT_READINESS_PATH,
+) -> str:
+    """
+    Generate a Kubernetes Deployment and ClusterIP Service with health probes.
+
+    Uses the same generator as `devopsos generate k8s`: the container gets
+    liveness and readiness probes, CPU/memory requests and limits, and a
+    non-root, read-only security context; the Service exposes it on port 80.
+
+    Args:
+        name: Application name used for every resource (a DNS-1123 label).
+        image: Container image reference (registry/name:tag).
+        replicas: Number of pod replicas (at least 1).
+        port: Container port the service listens on (1-65535).
+        namespace: Kubernetes namespace (a DNS-1123 label).
+        liveness_path: HTTP path of the liveness probe.
+        readiness_path: HTTP path of the readiness probe.
+
+    Returns:
+        The Deployment and Service as one multi-document YAML string, or a
+        JSON object {"error": {"code", "message"}} when an argument is invalid.
+    """
+    from cli import scaffold_k8s
+
+    if replicas < 1:
+        return _tool_error("invalid_argument", f"replicas must be at least 1, got {replicas}")
+    args = _build_k8s_args(name, image, replicas, port, namespace, liveness_path, readiness_path)
+    try:
+        rendered = scaffold_k8s.render_manifests(args)
+    except ValueError as exc:
+        return _tool_error("invalid_argument", str(exc))
+    return "---\n".join(rendered.values())
+
+
 # ---------------------------------------------------------------------------
 # Tool: scaffold_devcontainer
 # ---------------------------------------------------------------------------
@@ -663,6 +756,102 @@ def generate_unittest_config(
     return json.dumps(result, indent=2)
 
 
+# ---------------------------------------------------------------------------
+# Tool: generate_dockerfile
+# ---------------------------------------------------------------------------
+
+@mcp.tool()
+def generate_dockerfile(
+    go_version: str = DEFAULT_GO_VERSION,
+    port: int = DEFAULT_PORT,
+    binary_name: str = "server",
+) -> str:
+    """
+    Generate a multi-stage Dockerfile for a Go HTTP service.
+
+    Uses the same generator as `devopsos generate dockerfile`: a golang build
+    stage producing a static binary, and a distroless nonroot runtime stage
+    with EXPOSE and a HEALTHCHECK against /healthz.
+
+    Args:
+        go_version: Go version for the build stage, e.g. '1.23' or '1.23.4'.
+        port: Port the service listens on (1-65535).
+        binary_name: Name of the compiled binary in the image.
+
+    Returns:
+        The Dockerfile text, or a JSON object {"error": {"code", "message"}}
+        when an argument is invalid.
+    """
+    from cli import scaffold_dockerfile
+
+    try:
+        return scaffold_dockerfile.render_dockerfile(go_version, port, binary_name)
+    except ValueError as exc:
+        return _tool_error("invalid_argument", str(exc))
+
+
+# ---------------------------------------------------------------------------
+# Tool: validate_yaml
+# ---------------------------------------------------------------------------
+
+@mcp.tool()
+def validate_yaml(
+    yaml_content: str,
+    schema: str = "",
+    strict: bool = False,
+) -> str:
+    """
+    Validate Kubernetes or Argo CD YAML before it is applied.
+
+    Each document is parsed and decoded with the same typed schemas the
+    DevOps-OS generators use (Deployment, Service, Ingress, Argo CD
+    Application). Multi-document YAML separated by '---' is supported.
+
+    Args:
+        yaml_content: The YAML text to check.
+        schema: Optional hint forcing every document's type: 'deployment',
+                'service' or 'argocd-app'. Empty means use each document's kind.
+        strict: Also report fields the DevOps-OS schemas do not model.
+
+    Returns:
+        JSON {"valid": true}, or {"valid": false, "errors": [...]} where each
+        error has 'line' (1-based), 'path', 'message' and 'document' (1-based).
+    """
+    from dataclasses import asdict
+    from cli import yaml_validation
+
+    try:
+        problems = yaml_validation.validate_yaml(yaml_content, schema or None, strict)
+    except ValueError as exc:
+        return _tool_error("invalid_argument", str(exc))
+    if not problems:
+        return json.dumps({"valid": True})
+    return json.dumps({"valid": False, "errors": [asdict(p) for p in problems]}, indent=2)
+
+
+# ---------------------------------------------------------------------------
+# Tool: list_generators
+# ---------------------------------------------------------------------------
+
+@mcp.tool()
+def list_generators() -> str:
+    """
+    List the generators the devopsos CLI offers and the parameters each takes.
+
+    The catalog is read from the CLI's own command definitions, so names,
+    types, defaults and environment variables match what `devopsos` accepts.
+
+    Returns:
+        JSON {"generators": [...]} where each generator has 'name' (e.g.
+        'terraform'), 'command' (e.g. 'devopsos iac terraform'),
+        'description' and 'parameters', a JSON Schema object whose properties
+        also carry each option's 'flag' and 'envvar'.
+    """
+    from cli import generators
+
+    return json.dumps({"generators": generators.catalog()}, indent=2)
+
+
 # ---------------------------------------------------------------------------
 # Entry point
 # ---------------------------------------------------------------------------
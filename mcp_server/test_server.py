@@ -6,14 +6,19 @@ import os
 # Ensure repo root is on path when run from CLI
 sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
 
+from cli.scaffold_dockerfile import DEFAULT_GO_VERSION, DEFAULT_PORT
 from mcp_server.server import (
     generate_github_actions_workflow,
     generate_gitlab_ci_pipeline,
     generate_jenkins_pipeline,
     generate_k8s_config,
+    generate_k8s_manifests,
     generate_argocd_config,
     generate_sre_configs,
     scaffold_devcontainer,
+    generate_dockerfile,
+    validate_yaml,
+    list_generators,
 )
 
 
@@ -197,3 +202,149 @@ def test_generate_sre_configs_slo_service():
     import yaml
     slo = yaml.safe_load(data["slo_yaml"])
     assert slo["service"] == "my-svc"
+
+
+def test_generate_dockerfile_default():
+    result = generate_dockerfile()
+    assert "FROM golang:1.23" in result
+    assert "EXPOSE 8080" in result
+    assert "HEALTHCHECK" in result
+    assert 'ENTRYPOINT ["/server"]' in result
+
+
+def test_generate_dockerfile_custom():
+    result = generate_dockerfile(go_version="1.22.5", port=9090, binary_name="orders")
+    assert "FROM golang:1.22.5" in result
+    assert "EXPOSE 9090" in result
+    assert "http://127.0.0.1:9090/healthz" in result
+    assert 'ENTRYPOINT ["/orders"]' in result
+
+
+def test_generate_dockerfile_rejects_port_out_of_range():
+    error = json.loads(generate_dockerfile(port=70000))["error"]
+    assert error["code"] == "invalid_argument"
+    assert "port" in error["message"]
+
+
+def test_generate_dockerfile_rejects_go_version():
+    error = json.loads(generate_dockerfile(go_version="latest"))["error"]
+    assert error["code"] == "invalid_argument"
+    assert "go_version" in error["message"]
+
+
+def test_generate_k8s_manifests_replicas_and_probes():
+    import yaml
+    docs = list(yaml.safe_load_all(generate_k8s_manifests(name="api", replicas=4, port=9090, namespace="prod")))
+    deployment, service = docs
+    assert deployment["kind"] == "Deployment" and service["kind"] == "Service"
+    assert deployment["spec"]["replicas"] == 4
+    assert deployment["metadata"]["namespace"] == "prod"
+    container = deployment["spec"]["template"]["spec"]["containers"][0]
+    assert container["ports"][0]["containerPort"] == 9090
+    assert container["livenessProbe"]["httpGet"]["path"] == "/healthz"
+    assert container["readinessProbe"]["httpGet"]["path"] == "/readyz"
+
+
+def test_generate_k8s_manifests_custom_probe_paths():
+    import yaml
+    deployment = next(yaml.safe_load_all(generate_k8s_manifests(liveness_path="/live", readiness_path="/ready")))
+    container = deployment["spec"]["template"]["spec"]["containers"][0]
+    assert container["livenessProbe"]["httpGet"]["path"] == "/live"
+    assert container["readinessProbe"]["httpGet"]["path"] == "/ready"
+
+
+def test_generate_k8s_manifests_passes_validate_yaml():
+    assert json.loads(validate_yaml(generate_k8s_manifests())) == {"valid": True}
+
+
+def test_generate_k8s_manifests_rejects_invalid_input():
+    for kwargs, field in [({"replicas": 0}, "replicas"), ({"port": 70000}, "port"),
+                          ({"name": "My_App"}, "DNS-1123"), ({"readiness_path": "readyz"}, "readiness-path")]:
+        error = json.loads(generate_k8s_manifests(**kwargs))["error"]
+        assert error["code"] == "invalid_argument"
+        assert field in error["message"]
+
+
+_DEPLOYMENT_YAML = """\
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: my-app
+  template:
+    metadata:
+      labels:
+        app: my-app
+    spec:
+      containers:
+        - name: my-app
+          image: ghcr.io/org/my-app:v1
+          ports:
+            - containerPort: 8080
+"""
+
+
+def test_validate_yaml_accepts_deployment():
+    assert json.loads(validate_yaml(_DEPLOYMENT_YAML, schema="deployment")) == {"valid": True}
+
+
+def test_validate_yaml_accepts_generated_k8s_config():
+    result = json.loads(validate_yaml(generate_k8s_config()))
+    assert result == {"valid": True}
+
+
+def test_validate_yaml_missing_api_version():
+    content = _DEPLOYMENT_YAML.replace("apiVersion: apps/v1\n", "")
+    result = json.loads(validate_yaml(content))
+    assert result["valid"] is False
+    assert result["errors"] == [
+        {"line": 1, "path": "apiVersion", "message": "apiVersion: is required", "document": 1},
+    ]
+
+
+def test_validate_yaml_reports_line_of_bad_field():
+    content = _DEPLOYMENT_YAML.replace("containerPort: 8080", "containerPort: http")
+    (error,) = json.loads(validate_yaml(content))["errors"]
+    assert error["line"] == 19
+    assert "must be an integer" in error["message"]
+
+
+def test_validate_yaml_rejects_unknown_schema():
+    error = json.loads(validate_yaml(_DEPLOYMENT_YAML, schema="statefulset"))["error"]
+    assert error["code"] == "invalid_argument"
+
+
+def test_list_generators_catalog():
+    generators = {g["name"]: g for g in json.loads(list_generators())["generators"]}
+    assert {"terraform", "pulumi", "dockerfile", "k8s", "helm", "github-actions", "argocd"} <= set(generators)
+
+    terraform = generators["terraform"]
+    assert terraform["command"] == "devopsos iac terraform"
+    props = terraform["parameters"]["properties"]
+    assert {"cloud", "name", "region", "out", "force"} <= set(props)
+    assert props["cloud"] == {
+        "type": "string",
+        "default": "aws",
+        "description": "Cloud provider: aws | gcp | azure",
+        "flag": "--cloud",
+        "envvar": "DEVOPS_OS_TERRAFORM_CLOUD",
+    }
+    assert props["force"]["type"] == "boolean" and props["force"]["default"] is False
+    assert terraform["parameters"]["required"] == []
+
+    dockerfile = generators["dockerfile"]
+    props = dockerfile["parameters"]["properties"]
+    assert {"go_version", "port", "binary", "build_path", "runtime", "out", "force"} <= set(props)
+    assert props["go_version"]["flag"] == "--go-version"
+    assert props["go_version"]["default"] == DEFAULT_GO_VERSION
+    assert props["port"] == {
+        "type": "integer",
+        "default": DEFAULT_PORT,
+        "description": "Port the service listens on",
+        "flag": "--port",
+        "envvar": "DEVOPS_OS_DOCKERFILE_PORT",
+    }
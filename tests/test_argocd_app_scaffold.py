@@ -0,0 +1,168 @@
+"""
+Unit tests for the DevOps-OS ArgoCD Application generator (devopsos generate argocd).
+
+Tests cover:
+  - Required option and value validation
+  - Application source, destination and sync policy, and its typed round trip
+  - AppProject sourceRepos locked to the repo URL or exactly ["*"]
+  - Output to stdout or --out, and overwrite protection in main()
+"""
+
+import argparse
+import os
+import sys
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import k8s_types, scaffold_argocd_app
+
+REPO = "https://github.com/myorg/my-app.git"
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _app_args(**kwargs):
+    defaults = dict(
+        app_name="my-app", repo_url=REPO, path="k8s", revision="HEAD",
+        dest_namespace="default", dest_server="https://kubernetes.default.svc",
+        project="", appproject=False, allow_any_source_repo=False, auto_sync=False,
+        out="", force=False,
+    )
+    defaults.update(kwargs)
+    return argparse.Namespace(**defaults)
+
+
+def _by_kind(args):
+    return {doc["kind"]: doc for doc in scaffold_argocd_app.build_documents(args)}
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_argocd_app.py", *flags])
+    scaffold_argocd_app.main()
+
+
+# ---------------------------------------------------------------------------
+# Validation
+# ---------------------------------------------------------------------------
+
+class TestValidation:
+    def test_reports_every_missing_required_option(self):
+        with pytest.raises(ValueError, match="missing required options: --app-name, --repo-url, --path"):
+            scaffold_argocd_app.validate_args(_app_args(app_name="", repo_url="", path=""))
+
+    @pytest.mark.parametrize("overrides,message", [
+        ({"app_name": "My_App"}, "app name"),
+        ({"dest_namespace": "Prod"}, "destination namespace"),
+        ({"project": "Team A"}, "project"),
+        ({"repo_url": "github.com/myorg/my-app"}, "repo URL"),
+    ])
+    def test_rejects_invalid_values(self, overrides, message):
+        with pytest.raises(ValueError, match=message):
+            scaffold_argocd_app.validate_args(_app_args(**overrides))
+
+    @pytest.mark.parametrize("url", [REPO, "ssh://git@github.com/myorg/my-app.git", "git@github.com:myorg/my-app.git"])
+    def test_accepts_repo_urls(self, url):
+        scaffold_argocd_app.validate_args(_app_args(repo_url=url))
+
+    def test_wildcard_requires_appproject(self):
+        with pytest.raises(ValueError, match="--appproject"):
+            scaffold_argocd_app.validate_args(_app_args(allow_any_source_repo=True))
+
+
+# ---------------------------------------------------------------------------
+# Documents
+# ---------------------------------------------------------------------------
+
+class TestApplication:
+    def test_application_only_by_default(self):
+        docs = scaffold_argocd_app.build_documents(_app_args())
+        assert [doc["kind"] for doc in docs] == ["Application"]
+
+    def test_source_and_destination(self):
+        app = _by_kind(_app_args(path="deploy/prod", revision="v1.2.0", dest_namespace="prod"))["Application"]
+        assert app["apiVersion"] == "argoproj.io/v1alpha1"
+        assert app["metadata"]["name"] == "my-app"
+        assert app["spec"]["source"] == {"repoURL": REPO, "targetRevision": "v1.2.0", "path": "deploy/prod"}
+        assert app["spec"]["destination"]["namespace"] == "prod"
+        assert app["spec"]["project"] == "default"
+
+    def test_auto_sync(self):
+        app = _by_kind(_app_args(auto_sync=True))["Application"]
+        assert app["spec"]["syncPolicy"]["automated"] == {"prune": True, "selfHeal": True}
+
+    @pytest.mark.parametrize("auto_sync", [False, True])
+    def test_decodes_into_typed_application(self, auto_sync):
+        app = _by_kind(_app_args(auto_sync=auto_sync))["Application"]
+        typed = k8s_types.decode(k8s_types.Application, app)
+        assert typed.spec.source.repo_url == REPO
+        assert k8s_types.encode(typed) == app
+
+
+class TestAppProject:
+    def test_locked_to_repo_url(self):
+        project = _by_kind(_app_args(appproject=True))["AppProject"]
+        assert project["spec"]["sourceRepos"] == [REPO]
+
+    def test_allow_any_source_repo_is_exactly_wildcard(self):
+        project = _by_kind(_app_args(appproject=True, allow_any_source_repo=True))["AppProject"]
+        assert project["spec"]["sourceRepos"] == ["*"]
+
+    def test_project_defaults_to_app_name(self):
+        docs = _by_kind(_app_args(appproject=True))
+        assert docs["AppProject"]["metadata"]["name"] == "my-app"
+        assert docs["Application"]["spec"]["project"] == "my-app"
+
+    def test_explicit_project(self):
+        docs = _by_kind(_app_args(appproject=True, project="team-a"))
+        assert docs["AppProject"]["metadata"]["name"] == "team-a"
+        assert docs["Application"]["spec"]["project"] == "team-a"
+
+    def test_appproject_precedes_application(self):
+        docs = scaffold_argocd_app.build_documents(_app_args(appproject=True))
+        assert [doc["kind"] for doc in docs] == ["AppProject", "Application"]
+
+    def test_destination_namespace_allowed(self):
+        project = _by_kind(_app_args(appproject=True, dest_namespace="prod"))["AppProject"]
+        assert {"namespace": "prod", "server": "https://kubernetes.default.svc"} in project["spec"]["destinations"]
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_to_stdout(self, monkeypatch, capsys):
+        _run_main(monkeypatch, "--app-name", "my-app", "--repo-url", REPO, "--path", "k8s", "--appproject")
+        docs = list(yaml.safe_load_all(capsys.readouterr().out))
+        assert [doc["kind"] for doc in docs] == ["AppProject", "Application"]
+
+    def test_writes_to_out(self, tmp_path, monkeypatch):
+        out = tmp_path / "argocd" / "my-app.yaml"
+        _run_main(monkeypatch, "--app-name", "my-app", "--repo-url", REPO, "--path", "k8s", "--out", str(out))
+        assert yaml.safe_load(out.read_text())["kind"] == "Application"
+
+    def test_required_options_from_env(self, monkeypatch, capsys):
+        monkeypatch.setenv("DEVOPS_OS_ARGOCD_APP_NAME", "env-app")
+        monkeypatch.setenv("DEVOPS_OS_ARGOCD_APP_REPO_URL", REPO)
+        monkeypatch.setenv("DEVOPS_OS_ARGOCD_APP_PATH", "k8s")
+        _run_main(monkeypatch)
+        assert yaml.safe_load(capsys.readouterr().out)["metadata"]["name"] == "env-app"
+
+    def test_missing_options_exit_with_error(self, monkeypatch, capsys):
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--app-name", "my-app")
+        assert exc.value.code == 1
+        assert "--repo-url, --path" in capsys.readouterr().err
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "app.yaml"
+        out.write_text("kind: Application\n")
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--app-name", "my-app", "--repo-url", REPO, "--path", "k8s", "--out", str(out))
+        assert "--force" in capsys.readouterr().err
+        assert out.read_text() == "kind: Application\n"
@@ -0,0 +1,149 @@
+"""
+Unit tests for the DevOps-OS GitHub Actions CI generator for Go services.
+
+Tests cover:
+  - The test job (vet, test -race, build) and the optional lint step
+  - The optional Docker build/push job and registry credentials
+  - The tag-gated release job
+  - Go version validation and overwrite protection in main()
+"""
+
+import os
+import sys
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_ci_github
+
+
+def _workflow(**kwargs):
+    return yaml.safe_load(scaffold_ci_github.render_workflow(**kwargs))
+
+
+def _steps(workflow, job):
+    return {step.get("name", step.get("uses")): step for step in workflow["jobs"][job]["steps"]}
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_ci_github.py", *flags])
+    scaffold_ci_github.main()
+
+
+# ---------------------------------------------------------------------------
+# Test job
+# ---------------------------------------------------------------------------
+
+class TestTestJob:
+    def test_runs_vet_race_tests_and_build(self):
+        steps = _steps(_workflow(), "test")
+        assert steps["Vet"]["run"] == "go vet ./..."
+        assert "-race" in steps["Test"]["run"]
+        assert "./..." in steps["Test"]["run"]
+        assert steps["Build"]["run"] == "go build ./..."
+
+    def test_go_version(self):
+        setup = _steps(_workflow(go_version="1.22"), "test")["Set up Go"]
+        assert setup["with"]["go-version"] == "1.22"
+
+    def test_lint_enabled_by_default(self):
+        lint = _steps(_workflow(), "test")["Lint"]
+        assert lint["uses"].startswith("golangci/golangci-lint-action@")
+
+    def test_disabling_lint_omits_step(self):
+        steps = _steps(_workflow(lint=False), "test")
+        assert "Lint" not in steps
+        assert "Test" in steps
+
+    def test_working_directory(self):
+        workflow = _workflow(working_directory="go-project")
+        assert workflow["defaults"]["run"]["working-directory"] == "go-project"
+        assert _steps(workflow, "test")["Lint"]["with"]["working-directory"] == "go-project"
+
+    def test_triggers(self):
+        # PyYAML reads the bare `on` key as boolean True
+        triggers = _workflow(branch="develop")[True]
+        assert triggers["push"]["branches"] == ["develop"]
+        assert triggers["push"]["tags"] == ["v*"]
+        assert triggers["pull_request"]["branches"] == ["develop"]
+
+    @pytest.mark.parametrize("version", ["", "go1.22", "latest"])
+    def test_rejects_invalid_go_version(self, version):
+        with pytest.raises(ValueError, match="go_version"):
+            scaffold_ci_github.render_workflow(go_version=version)
+
+
+# ---------------------------------------------------------------------------
+# Docker and release jobs
+# ---------------------------------------------------------------------------
+
+class TestDockerJob:
+    def test_omitted_by_default(self):
+        assert set(_workflow()["jobs"]) == {"test", "release"}
+
+    def test_builds_and_pushes_outside_pull_requests(self):
+        workflow = _workflow(docker=True, image="ghcr.io/myorg/api")
+        steps = _steps(workflow, "docker")
+        assert steps["Build and push"]["uses"].startswith("docker/build-push-action@")
+        assert "pull_request" in steps["Build and push"]["with"]["push"]
+        assert steps["Image metadata"]["with"]["images"] == "ghcr.io/myorg/api"
+        assert workflow["jobs"]["docker"]["needs"] == ["test"]
+
+    def test_ghcr_uses_github_token(self):
+        login = _steps(_workflow(docker=True), "docker")["Log in to the registry"]
+        assert login["with"]["registry"] == "ghcr.io"
+        assert "GITHUB_TOKEN" in login["with"]["password"]
+
+    def test_other_registries_use_secrets(self):
+        login = _steps(_workflow(docker=True, image="registry.example.com/api"), "docker")["Log in to the registry"]
+        assert login["with"]["registry"] == "registry.example.com"
+        assert "REGISTRY_PASSWORD" in login["with"]["password"]
+
+    def test_docker_hub_image(self):
+        login = _steps(_workflow(docker=True, image="myorg/api"), "docker")["Log in to the registry"]
+        assert login["with"]["registry"] == "docker.io"
+
+
+class TestReleaseJob:
+    def test_gated_on_tags(self):
+        release = _workflow()["jobs"]["release"]
+        assert "refs/tags/" in release["if"]
+        assert release["needs"] == ["test"]
+        assert release["permissions"]["contents"] == "write"
+
+    def test_waits_for_docker_job(self):
+        assert _workflow(docker=True)["jobs"]["release"]["needs"] == ["test", "docker"]
+
+    def test_builds_binary(self):
+        build = _steps(_workflow(binary="api", build_path="./cmd/api"), "release")["Build release binaries"]
+        assert "dist/api-$os-$arch" in build["run"]
+        assert "./cmd/api" in build["run"]
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_workflow(self, tmp_path, monkeypatch):
+        out = tmp_path / ".github" / "workflows" / "ci.yml"
+        _run_main(monkeypatch, "--go-version", "1.22", "--no-lint", "--out", str(out))
+        workflow = yaml.safe_load(out.read_text())
+        assert "Lint" not in _steps(workflow, "test")
+
+    def test_env_toggles(self, tmp_path, monkeypatch):
+        monkeypatch.setenv("DEVOPS_OS_CI_GITHUB_DOCKER", "true")
+        out = tmp_path / "ci.yml"
+        _run_main(monkeypatch, "--out", str(out))
+        assert "docker" in yaml.safe_load(out.read_text())["jobs"]
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "ci.yml"
+        out.write_text("name: old\n")
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--out", str(out))
+        assert exc.value.code == 1
+        assert "--force" in capsys.readouterr().err
+        assert out.read_text() == "name: old\n"
@@ -0,0 +1,149 @@
+"""
+Unit tests for cli/config.py, the .devopsos.yaml loader.
+
+Tests cover:
+  - A missing default file yields empty settings; a missing --config path fails
+  - Flattening nested command sections and the global output key
+  - Resolving option names against the real devopsos command tree
+  - Precedence: explicit flag > config file > built-in default
+"""
+
+import os
+import sys
+
+import pytest
+import typer
+from typer.testing import CliRunner
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import config
+from cli.devopsos import app
+
+ROOT = typer.main.get_command(app)
+
+SAMPLE = """\
+output: yaml
+iac:
+  terraform:
+    cloud: gcp
+generate:
+  ci:
+    github:
+      go-version: "1.22"
+      lint: false
+"""
+
+
+def _write(tmp_path, text, name=config.CONFIG_FILE):
+    path = tmp_path / name
+    path.write_text(text)
+    return path
+
+
+# ---------------------------------------------------------------------------
+# load_config
+# ---------------------------------------------------------------------------
+
+class TestLoadConfig:
+    def test_missing_default_file_is_not_an_error(self, tmp_path, monkeypatch):
+        monkeypatch.chdir(tmp_path)
+        assert config.load_config() == config.Config()
+
+    def test_missing_explicit_file_is_an_error(self, tmp_path):
+        with pytest.raises(config.ConfigError, match="not found"):
+            config.load_config(tmp_path / "nope.yaml")
+
+    def test_reads_file_from_current_directory(self, tmp_path, monkeypatch):
+        _write(tmp_path, SAMPLE)
+        monkeypatch.chdir(tmp_path)
+        settings = config.load_config()
+        assert settings.output == "yaml"
+        assert settings.commands == {
+            "iac terraform": {"cloud": "gcp"},
+            "generate ci github": {"go-version": "1.22", "lint": False},
+        }
+
+    def test_empty_file(self, tmp_path):
+        assert config.load_config(_write(tmp_path, "")).commands == {}
+
+    @pytest.mark.parametrize("text, message", [
+        ("- a\n- b\n", "mapping at the top level"),
+        ("output: xml\n", "output must be one of"),
+        ("cloud: aws\n", "unknown top-level key 'cloud'"),
+        ("iac: [\n", "invalid YAML"),
+    ])
+    def test_rejects_malformed_files(self, tmp_path, text, message):
+        with pytest.raises(config.ConfigError, match=message):
+            config.load_config(_write(tmp_path, text))
+
+
+# ---------------------------------------------------------------------------
+# Config.default_map
+# ---------------------------------------------------------------------------
+
+class TestDefaultMap:
+    def test_nested_map_uses_parameter_names(self, tmp_path):
+        settings = config.load_config(_write(tmp_path, SAMPLE))
+        assert settings.default_map(ROOT) == {
+            "iac": {"terraform": {"cloud": "gcp"}},
+            "generate": {"ci": {"github": {"go_version": "1.22", "lint": False}}},
+        }
+
+    def test_flag_spelling_maps_to_parameter(self, tmp_path):
+        settings = config.load_config(_write(tmp_path, "scaffold:\n  gha:\n    type: build\n"))
+        assert settings.default_map(ROOT) == {"scaffold": {"gha": {"workflow_type": "build"}}}
+
+    def test_lists_become_comma_separated(self, tmp_path):
+        settings = config.load_config(_write(tmp_path, "scaffold:\n  gitlab:\n    languages: [python, go]\n"))
+        assert settings.default_map(ROOT)["scaffold"]["gitlab"]["languages"] == "python,go"
+
+    def test_unknown_command(self, tmp_path):
+        settings = config.load_config(_write(tmp_path, "generate:\n  helmet:\n    name: x\n"))
+        with pytest.raises(config.ConfigError, match="unknown command 'generate helmet'"):
+            settings.default_map(ROOT)
+
+    def test_unknown_option(self, tmp_path):
+        settings = config.load_config(_write(tmp_path, "iac:\n  terraform:\n    clod: aws\n"))
+        with pytest.raises(config.ConfigError, match="has no option 'clod'"):
+            settings.default_map(ROOT)
+
+
+# ---------------------------------------------------------------------------
+# Precedence through the CLI
+# ---------------------------------------------------------------------------
+
+class TestPrecedence:
+    def _run(self, tmp_path, monkeypatch, *args):
+        monkeypatch.chdir(tmp_path)
+        monkeypatch.delenv("DEVOPS_OS_OUTPUT", raising=False)
+        monkeypatch.setattr(sys, "argv", ["devopsos", *args])
+        return CliRunner().invoke(app, list(args))
+
+    def test_file_value_replaces_builtin_default(self, tmp_path, monkeypatch):
+        _write(tmp_path, "generate:\n  helm:\n    name: orders\n")
+        result = self._run(tmp_path, monkeypatch, "generate", "helm")
+        assert result.exit_code == 0, result.output
+        assert (tmp_path / "charts" / "orders" / "Chart.yaml").is_file()
+
+    def test_flag_overrides_file_value(self, tmp_path, monkeypatch):
+        _write(tmp_path, "generate:\n  helm:\n    name: orders\n")
+        result = self._run(tmp_path, monkeypatch, "generate", "helm", "--name", "payments")
+        assert result.exit_code == 0, result.output
+        assert (tmp_path / "charts" / "payments").is_dir()
+        assert not (tmp_path / "charts" / "orders").exists()
+
+    def test_output_flag_overrides_file_value(self, tmp_path, monkeypatch):
+        _write(tmp_path, "output: json\n")
+        result = self._run(tmp_path, monkeypatch, "-o", "table", "generate", "helm", "--name", "orders")
+        assert result.output.startswith("Helm chart generated (orders):")
+
+    def test_output_from_file(self, tmp_path, monkeypatch):
+        _write(tmp_path, "output: json\n")
+        result = self._run(tmp_path, monkeypatch, "generate", "helm", "--name", "orders")
+        assert '"command": "generate helm"' in result.output
+
+    def test_config_errors_exit_non_zero(self, tmp_path, monkeypatch):
+        result = self._run(tmp_path, monkeypatch, "--config", "missing.yaml", "generate", "helm")
+        assert result.exit_code == 1
@@ -0,0 +1,123 @@
+"""
+Unit tests for the DevOps-OS Go service Dockerfile generator.
+
+Tests cover:
+  - Go version, port and binary name validation
+  - Builder / runtime stages, EXPOSE, USER and HEALTHCHECK in the output
+  - Overwrite protection in main()
+"""
+
+import os
+import sys
+import pytest
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_dockerfile
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_dockerfile.py", *flags])
+    scaffold_dockerfile.main()
+
+
+# ---------------------------------------------------------------------------
+# Validation
+# ---------------------------------------------------------------------------
+
+class TestValidateOptions:
+    @pytest.mark.parametrize("version", ["1.22", "1.23.4"])
+    def test_accepts_go_versions(self, version):
+        scaffold_dockerfile.validate_options(version, 8080, "server")
+
+    @pytest.mark.parametrize("version", ["", "1", "go1.22", "1.22-alpine", "latest"])
+    def test_rejects_go_versions(self, version):
+        with pytest.raises(ValueError, match="go_version"):
+            scaffold_dockerfile.validate_options(version, 8080, "server")
+
+    @pytest.mark.parametrize("port", [0, -1, 65536, "8080", True])
+    def test_rejects_ports(self, port):
+        with pytest.raises(ValueError, match="port"):
+            scaffold_dockerfile.validate_options("1.22", port, "server")
+
+    @pytest.mark.parametrize("binary", ["", "../server", "my app"])
+    def test_rejects_binary_names(self, binary):
+        with pytest.raises(ValueError, match="binary"):
+            scaffold_dockerfile.validate_options("1.22", 8080, binary)
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderDockerfile:
+    def test_requested_go_version_and_port(self):
+        content = scaffold_dockerfile.render_dockerfile(go_version="1.22", port=9090)
+        assert "FROM golang:1.22 AS builder" in content
+        assert "EXPOSE 9090" in content
+        assert "ENV PORT=9090" in content
+        assert "http://127.0.0.1:9090/healthz" in content
+
+    def test_multi_stage_with_distroless_runtime(self):
+        content = scaffold_dockerfile.render_dockerfile()
+        assert content.count("\nFROM ") == 2
+        assert "FROM gcr.io/distroless/static-debian12:nonroot" in content
+
+    def test_scratch_runtime(self):
+        content = scaffold_dockerfile.render_dockerfile(runtime="scratch")
+        assert "FROM scratch" in content
+        assert "ca-certificates.crt" in content
+
+    def test_runs_as_non_root(self):
+        content = scaffold_dockerfile.render_dockerfile()
+        assert "USER 65532:65532" in content
+
+    def test_healthcheck_targets_healthz(self):
+        content = scaffold_dockerfile.render_dockerfile()
+        assert "HEALTHCHECK" in content
+        assert '"/healthcheck", "http://127.0.0.1:8080/healthz"' in content
+
+    def test_binary_name_and_build_path(self):
+        content = scaffold_dockerfile.render_dockerfile(binary="api", build_path="./cmd/api")
+        assert "-o /out/api ./cmd/api" in content
+        assert 'ENTRYPOINT ["/api"]' in content
+
+    def test_unknown_runtime(self):
+        with pytest.raises(ValueError, match="runtime"):
+            scaffold_dockerfile.render_dockerfile(runtime="alpine")
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_dockerfile(self, tmp_path, monkeypatch):
+        out = tmp_path / "Dockerfile"
+        _run_main(monkeypatch, "--go-version", "1.22", "--port", "8081", "--out", str(out))
+        content = out.read_text()
+        assert "golang:1.22" in content
+        assert "EXPOSE 8081" in content
+
+    def test_refuses_to_clobber_without_force(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "Dockerfile"
+        out.write_text("FROM scratch\n")
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--out", str(out))
+        assert exc.value.code == 1
+        assert "--force" in capsys.readouterr().err
+        assert out.read_text() == "FROM scratch\n"
+
+    def test_force_overwrites(self, tmp_path, monkeypatch):
+        out = tmp_path / "Dockerfile"
+        out.write_text("FROM scratch\n")
+        _run_main(monkeypatch, "--out", str(out), "--force")
+        assert "HEALTHCHECK" in out.read_text()
+
+    def test_invalid_port_exits_with_error(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "Dockerfile"
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--port", "70000", "--out", str(out))
+        assert "port must be between 1 and 65535" in capsys.readouterr().err
+        assert not out.exists()
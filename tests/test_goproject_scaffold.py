@@ -0,0 +1,144 @@
+"""
+Unit tests for the DevOps-OS Go project generator (devopsos init <name>).
+
+Tests cover:
+  - Project name, module path and Go version validation
+  - The generated layout mirroring go-project/ and the go.mod module path
+  - `go build ./...` and `go vet ./...` inside the generated tree (needs a Go toolchain)
+  - Refusing non-empty directories unless --force
+"""
+
+import os
+import shutil
+import subprocess
+import sys
+import pytest
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_goproject
+
+requires_go = pytest.mark.skipif(shutil.which("go") is None, reason="Go toolchain not installed")
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_goproject.py", *flags])
+    scaffold_goproject.main()
+
+
+def _go(args, cwd):
+    env = dict(os.environ, GOFLAGS="-mod=mod", GOTOOLCHAIN="local")
+    return subprocess.run(["go", *args], cwd=cwd, env=env, capture_output=True, text=True)
+
+
+# ---------------------------------------------------------------------------
+# Validation
+# ---------------------------------------------------------------------------
+
+class TestValidateOptions:
+    @pytest.mark.parametrize("module", ["orders", "github.com/you/orders", "example.com/team/orders.v2"])
+    def test_accepts_module_paths(self, module):
+        scaffold_goproject.validate_options("orders", module, "1.23")
+
+    @pytest.mark.parametrize("module", ["/orders", "github.com/you/", "github.com//orders", "my module", "-x"])
+    def test_rejects_module_paths(self, module):
+        with pytest.raises(ValueError, match="module path"):
+            scaffold_goproject.validate_options("orders", module, "1.23")
+
+    @pytest.mark.parametrize("name", ["", "../orders", "my app", ".hidden"])
+    def test_rejects_project_names(self, name):
+        with pytest.raises(ValueError, match="project name"):
+            scaffold_goproject.validate_options(name, "orders", "1.23")
+
+    def test_rejects_go_version(self):
+        with pytest.raises(ValueError, match="go_version"):
+            scaffold_goproject.render_project("orders", go_version="go1.23")
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderProject:
+    def test_layout_mirrors_go_project(self):
+        files = scaffold_goproject.render_project("orders")
+        for path in ("go.mod", "cmd/main.go", "internal/handlers/handler.go",
+                     "internal/handlers/health.go", "internal/models/model.go", "pkg/utils/slug.go",
+                     "README.md", ".gitignore"):
+            assert path in files
+        assert not any(path.endswith(".tpl") for path in files)
+
+    def test_go_mod_uses_module_path(self):
+        go_mod = scaffold_goproject.render_project("orders", "github.com/you/orders", "1.22")["go.mod"]
+        assert go_mod.splitlines()[0] == "module github.com/you/orders"
+        assert "go 1.22" in go_mod
+
+    def test_module_defaults_to_name(self):
+        files = scaffold_goproject.render_project("orders")
+        assert files["go.mod"].startswith("module orders\n")
+        assert '"orders/internal/handlers"' in files["cmd/main.go"]
+
+    def test_imports_use_module_path(self):
+        files = scaffold_goproject.render_project("orders", "github.com/you/orders")
+        assert '"github.com/you/orders/internal/handlers"' in files["cmd/main.go"]
+        assert '"github.com/you/orders/pkg/utils"' in files["internal/handlers/handler.go"]
+
+    def test_serves_health_probes(self):
+        main_go = scaffold_goproject.render_project("orders")["cmd/main.go"]
+        assert '"GET /healthz"' in main_go
+        assert '"GET /readyz"' in main_go
+
+
+@requires_go
+class TestGeneratedTreeBuilds:
+    def test_go_build_vet_and_test(self, tmp_path, monkeypatch):
+        _run_main(monkeypatch, "--name", "orders", "--module", "github.com/you/orders", "--dir", str(tmp_path))
+        project = tmp_path / "orders"
+        for args in (["build", "./..."], ["vet", "./..."], ["test", "./..."]):
+            result = _go(args, project)
+            assert result.returncode == 0, f"go {' '.join(args)} failed:\n{result.stderr}"
+
+    def test_gofmt_clean(self, tmp_path, monkeypatch):
+        _run_main(monkeypatch, "--name", "orders", "--dir", str(tmp_path))
+        result = subprocess.run(["gofmt", "-l", "."], cwd=tmp_path / "orders", capture_output=True, text=True)
+        assert result.stdout == ""
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_creates_project_directory(self, tmp_path, monkeypatch):
+        _run_main(monkeypatch, "--name", "orders", "--dir", str(tmp_path))
+        assert (tmp_path / "orders" / "cmd" / "main.go").is_file()
+        assert (tmp_path / "orders" / ".gitignore").is_file()
+
+    def test_empty_existing_directory_is_allowed(self, tmp_path, monkeypatch):
+        (tmp_path / "orders").mkdir()
+        _run_main(monkeypatch, "--name", "orders", "--dir", str(tmp_path))
+        assert (tmp_path / "orders" / "go.mod").is_file()
+
+    def test_refuses_non_empty_directory(self, tmp_path, monkeypatch, capsys):
+        project = tmp_path / "orders"
+        project.mkdir()
+        (project / "notes.txt").write_text("keep me\n")
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--name", "orders", "--dir", str(tmp_path))
+        assert exc.value.code == 1
+        assert "non-empty" in capsys.readouterr().err
+        assert not (project / "go.mod").exists()
+
+    def test_force_scaffolds_into_non_empty_directory(self, tmp_path, monkeypatch):
+        project = tmp_path / "orders"
+        project.mkdir()
+        (project / "notes.txt").write_text("keep me\n")
+        _run_main(monkeypatch, "--name", "orders", "--dir", str(tmp_path), "--force")
+        assert (project / "go.mod").is_file()
+        assert (project / "notes.txt").read_text() == "keep me\n"
+
+    def test_requires_name(self, tmp_path, monkeypatch, capsys):
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--dir", str(tmp_path))
+        assert "project name is required" in capsys.readouterr().err
@@ -0,0 +1,165 @@
+"""
+Unit tests for the DevOps-OS Helm chart generator.
+
+Tests cover:
+  - Chart name validation against Helm's DNS-1123 rules
+  - Chart.yaml / values.yaml structure and the values the templates rely on
+  - Standard naming helpers and the template tokens helm template needs
+  - Output directory defaults and overwrite protection in main()
+"""
+
+import os
+import re
+import sys
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_helm
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_helm.py", *flags])
+    scaffold_helm.main()
+
+
+def _values_paths(template):
+    """Return every .Values.<path> referenced by a template."""
+    return set(re.findall(r"\.Values\.([A-Za-z0-9_.]+)", template))
+
+
+def _lookup(values, dotted):
+    node = values
+    for key in dotted.split("."):
+        assert isinstance(node, dict) and key in node, f"values.yaml has no {dotted}"
+        node = node[key]
+    return node
+
+
+# ---------------------------------------------------------------------------
+# Validation
+# ---------------------------------------------------------------------------
+
+class TestValidateChartName:
+    @pytest.mark.parametrize("name", ["myapp", "my-app", "app2"])
+    def test_accepts(self, name):
+        scaffold_helm.validate_chart_name(name)
+
+    @pytest.mark.parametrize("name", ["", "MyApp", "my_app", "-app", "app-", "my.app", "a" * 64])
+    def test_rejects(self, name):
+        with pytest.raises(ValueError, match="DNS-1123"):
+            scaffold_helm.validate_chart_name(name)
+
+    def test_rejects_invalid_port(self):
+        with pytest.raises(ValueError, match="port"):
+            scaffold_helm.render_chart("myapp", port=0)
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderChart:
+    def test_file_layout(self):
+        assert set(scaffold_helm.render_chart("myapp")) == {
+            "Chart.yaml", "values.yaml", ".helmignore",
+            "templates/_helpers.tpl", "templates/deployment.yaml", "templates/service.yaml",
+        }
+
+    def test_chart_yaml(self):
+        chart = yaml.safe_load(scaffold_helm.render_chart("myapp")["Chart.yaml"])
+        assert chart["apiVersion"] == "v2"
+        assert chart["name"] == "myapp"
+        assert chart["type"] == "application"
+        assert chart["version"] and chart["appVersion"]
+
+    def test_values_yaml(self):
+        values = yaml.safe_load(scaffold_helm.render_chart("myapp", image="ghcr.io/org/myapp", port=9090)["values.yaml"])
+        assert values["image"]["repository"] == "ghcr.io/org/myapp"
+        assert "tag" in values["image"]
+        assert values["replicaCount"] == 1
+        assert values["service"]["port"] == 80
+        assert values["containerPort"] == 9090
+        assert values["probes"]["liveness"]["path"] == "/healthz"
+        assert values["probes"]["readiness"]["path"] == "/readyz"
+
+    def test_default_image_repository(self):
+        values = yaml.safe_load(scaffold_helm.render_chart("myapp")["values.yaml"])
+        assert values["image"]["repository"] == "ghcr.io/myorg/myapp"
+
+    def test_helpers_define_standard_names(self):
+        helpers = scaffold_helm.render_chart("my-app")["templates/_helpers.tpl"]
+        for helper in ("name", "fullname", "chart", "labels", "selectorLabels"):
+            assert f'{{{{- define "my-app.{helper}" -}}}}' in helpers
+
+    def test_template_blocks_are_closed(self):
+        for path, content in scaffold_helm.render_chart("myapp").items():
+            opened = len(re.findall(r"{{-? (define|if|range|with) ", content))
+            closed = len(re.findall(r"{{-? end -?}}", content))
+            assert opened == closed, path
+
+    def test_templates_include_only_defined_helpers(self):
+        files = scaffold_helm.render_chart("my-app")
+        defined = set(re.findall(r'define "([^"]+)"', files["templates/_helpers.tpl"]))
+        for path in ("templates/deployment.yaml", "templates/service.yaml", "templates/_helpers.tpl"):
+            for used in re.findall(r'include "([^"]+)"', files[path]):
+                assert used in defined, f"{path} includes undefined helper {used}"
+
+    def test_template_values_exist_in_values_yaml(self):
+        files = scaffold_helm.render_chart("myapp")
+        values = yaml.safe_load(files["values.yaml"])
+        for path in ("templates/deployment.yaml", "templates/service.yaml"):
+            for dotted in _values_paths(files[path]):
+                _lookup(values, dotted)
+
+    def test_deployment_tokens(self):
+        deployment = scaffold_helm.render_chart("myapp")["templates/deployment.yaml"]
+        assert "apiVersion: apps/v1" in deployment
+        assert "kind: Deployment" in deployment
+        assert "replicas: {{ .Values.replicaCount }}" in deployment
+        assert '{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}' in deployment
+        assert "path: {{ .Values.probes.liveness.path }}" in deployment
+        assert "path: {{ .Values.probes.readiness.path }}" in deployment
+
+    def test_service_tokens(self):
+        service = scaffold_helm.render_chart("myapp")["templates/service.yaml"]
+        assert "kind: Service" in service
+        assert "port: {{ .Values.service.port }}" in service
+        assert "targetPort: http" in service
+
+    def test_template_delimiters_are_balanced(self):
+        for path, content in scaffold_helm.render_chart("myapp").items():
+            assert content.count("{{") == content.count("}}"), path
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_chart(self, tmp_path, monkeypatch):
+        out = tmp_path / "chart"
+        _run_main(monkeypatch, "--name", "api", "--out", str(out))
+        assert (out / "Chart.yaml").exists()
+        assert (out / "templates" / "_helpers.tpl").exists()
+
+    def test_default_out_is_charts_name(self, tmp_path, monkeypatch):
+        monkeypatch.chdir(tmp_path)
+        _run_main(monkeypatch, "--name", "api")
+        assert (tmp_path / "charts" / "api" / "values.yaml").exists()
+
+    def test_invalid_name_exits_with_error(self, tmp_path, monkeypatch, capsys):
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--name", "My_App", "--out", str(tmp_path))
+        assert exc.value.code == 1
+        assert "DNS-1123" in capsys.readouterr().err
+        assert not any(tmp_path.iterdir())
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        (tmp_path / "Chart.yaml").write_text("name: old\n")
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--out", str(tmp_path))
+        assert "--force" in capsys.readouterr().err
+        assert (tmp_path / "Chart.yaml").read_text() == "name: old\n"
@@ -0,0 +1,191 @@
+"""
+Unit tests for the DevOps-OS Kubernetes manifest generator.
+
+Tests cover:
+  - The typed schema in cli.k8s_types (encode / decode round trip, error reporting)
+  - Generated Deployment, Service and Ingress decoded back into the typed classes
+  - Option validation and overwrite protection in main()
+"""
+
+import argparse
+import os
+import sys
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import k8s_types, scaffold_k8s
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _k8s_args(**kwargs):
+    defaults = dict(
+        name="my-app", image="ghcr.io/myorg/my-app:1.0.0", replicas=2, port=8080,
+        namespace="default", cpu_request="100m", memory_request="64Mi",
+        cpu_limit="500m", memory_limit="128Mi", liveness_path="/healthz",
+        readiness_path="/readyz", ingress_host="", ingress_class="",
+        out="k8s", force=False,
+    )
+    defaults.update(kwargs)
+    return argparse.Namespace(**defaults)
+
+
+def _decoded(args):
+    """Render manifests and decode each back into its typed class."""
+    return {
+        name: k8s_types.decode_manifest(yaml.safe_load(content))
+        for name, content in scaffold_k8s.render_manifests(args).items()
+    }
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_k8s.py", *flags])
+    scaffold_k8s.main()
+
+
+# ---------------------------------------------------------------------------
+# Typed schema
+# ---------------------------------------------------------------------------
+
+class TestK8sTypes:
+    def test_encode_uses_camel_case_and_drops_none(self):
+        port = k8s_types.ContainerPort(container_port=8080, name="http")
+        assert k8s_types.encode(port) == {"containerPort": 8080, "name": "http"}
+
+    def test_encode_puts_api_version_and_kind_first(self):
+        svc = k8s_types.Service(metadata=k8s_types.ObjectMeta(name="a"),
+                                spec=k8s_types.ServiceSpec(ports=[k8s_types.ServicePort(port=80)]))
+        assert list(k8s_types.encode(svc))[:2] == ["apiVersion", "kind"]
+
+    def test_decode_reports_missing_and_mistyped_fields(self):
+        doc = {"apiVersion": "v1", "kind": "Service", "metadata": {},
+               "spec": {"ports": [{"port": "80"}]}}
+        with pytest.raises(k8s_types.ManifestError) as exc:
+            k8s_types.decode_manifest(doc)
+        assert exc.value.errors == [
+            "metadata.name: is required",
+            "spec.ports[0].port: must be an integer",
+        ]
+
+    def test_decode_strict_rejects_unknown_fields(self):
+        doc = {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "a", "nmae": "b"},
+               "spec": {"ports": [{"port": 80}]}}
+        with pytest.raises(k8s_types.ManifestError, match="metadata.nmae: unknown field"):
+            k8s_types.decode_manifest(doc)
+        assert k8s_types.decode_manifest(doc, strict=False).metadata.name == "a"
+
+    def test_decode_checks_api_version(self):
+        doc = {"apiVersion": "apps/v1beta1", "kind": "Deployment", "metadata": {"name": "a"},
+               "spec": {"selector": {"matchLabels": {}}, "template": {
+                   "metadata": {"name": "a"}, "spec": {"containers": []}}}}
+        with pytest.raises(k8s_types.ManifestError, match="apiVersion: must be 'apps/v1'"):
+            k8s_types.decode_manifest(doc)
+
+    @pytest.mark.parametrize("name,valid", [
+        ("my-app", True), ("a", True), ("app1", True),
+        ("My-App", False), ("-app", False), ("app-", False), ("my_app", False), ("a" * 64, False),
+    ])
+    def test_is_dns1123_label(self, name, valid):
+        assert k8s_types.is_dns1123_label(name) is valid
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderManifests:
+    def test_outputs_round_trip_into_typed_classes(self):
+        decoded = _decoded(_k8s_args())
+        assert isinstance(decoded["deployment.yaml"], k8s_types.Deployment)
+        assert isinstance(decoded["service.yaml"], k8s_types.Service)
+
+    def test_ingress_is_optional(self):
+        assert "ingress.yaml" not in scaffold_k8s.render_manifests(_k8s_args())
+        decoded = _decoded(_k8s_args(ingress_host="app.example.com", ingress_class="nginx"))
+        ingress = decoded["ingress.yaml"]
+        assert isinstance(ingress, k8s_types.Ingress)
+        assert ingress.spec.ingress_class_name == "nginx"
+        rule = ingress.spec.rules[0]
+        assert rule.host == "app.example.com"
+        assert rule.http.paths[0].backend.service.name == "my-app"
+
+    def test_probes(self):
+        container = _decoded(_k8s_args())["deployment.yaml"].spec.template.spec.containers[0]
+        assert container.liveness_probe.http_get.path == "/healthz"
+        assert container.readiness_probe.http_get.path == "/readyz"
+        assert container.liveness_probe.http_get.port == "http"
+
+    def test_probe_paths(self):
+        args = _k8s_args(liveness_path="/live", readiness_path="/ready")
+        container = _decoded(args)["deployment.yaml"].spec.template.spec.containers[0]
+        assert container.liveness_probe.http_get.path == "/live"
+        assert container.readiness_probe.http_get.path == "/ready"
+
+    def test_replicas_port_and_image(self):
+        deployment = _decoded(_k8s_args(replicas=3, port=9090))["deployment.yaml"]
+        assert deployment.spec.replicas == 3
+        container = deployment.spec.template.spec.containers[0]
+        assert container.image == "ghcr.io/myorg/my-app:1.0.0"
+        assert container.ports[0].container_port == 9090
+        assert container.env[0].value == "9090"
+
+    def test_namespace_applies_to_every_resource(self):
+        decoded = _decoded(_k8s_args(namespace="prod", ingress_host="app.example.com"))
+        assert {obj.metadata.namespace for obj in decoded.values()} == {"prod"}
+
+    def test_resources(self):
+        args = _k8s_args(cpu_request="250m", memory_request="128Mi", cpu_limit="1", memory_limit="1Gi")
+        resources = _decoded(args)["deployment.yaml"].spec.template.spec.containers[0].resources
+        assert resources.requests == {"cpu": "250m", "memory": "128Mi"}
+        assert resources.limits == {"cpu": "1", "memory": "1Gi"}
+
+    def test_service_selects_deployment_pods(self):
+        decoded = _decoded(_k8s_args())
+        pod_labels = decoded["deployment.yaml"].spec.template.metadata.labels
+        assert decoded["service.yaml"].spec.selector == pod_labels
+
+    def test_runs_as_non_root(self):
+        ctx = _decoded(_k8s_args())["deployment.yaml"].spec.template.spec.containers[0].security_context
+        assert ctx.run_as_non_root is True
+        assert ctx.allow_privilege_escalation is False
+
+    @pytest.mark.parametrize("overrides,message", [
+        ({"name": "My_App"}, "DNS-1123"),
+        ({"namespace": "Prod"}, "DNS-1123"),
+        ({"replicas": -1}, "replicas"),
+        ({"port": 0}, "port"),
+        ({"memory_limit": "lots"}, "--memory-limit"),
+        ({"readiness_path": "readyz"}, "--readiness-path"),
+    ])
+    def test_invalid_options(self, overrides, message):
+        with pytest.raises(ValueError, match=message):
+            scaffold_k8s.render_manifests(_k8s_args(**overrides))
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_manifests(self, tmp_path, monkeypatch):
+        _run_main(monkeypatch, "--name", "api", "--out", str(tmp_path))
+        assert (tmp_path / "deployment.yaml").exists()
+        assert (tmp_path / "service.yaml").exists()
+        assert not (tmp_path / "ingress.yaml").exists()
+
+    def test_invalid_name_exits_with_error(self, tmp_path, monkeypatch, capsys):
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--name", "Not Valid", "--out", str(tmp_path))
+        assert exc.value.code == 1
+        assert "DNS-1123" in capsys.readouterr().err
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        (tmp_path / "service.yaml").write_text("kind: Service\n")
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--out", str(tmp_path))
+        assert "--force" in capsys.readouterr().err
@@ -41,10 +41,14 @@ EXPECTED_TOOLS = {
     "generate_gitlab_ci_pipeline",
     "generate_jenkins_pipeline",
     "generate_k8s_config",
+    "generate_k8s_manifests",
     "generate_argocd_config",
     "generate_sre_configs",
     "scaffold_devcontainer",
     "generate_unittest_config",
+    "generate_dockerfile",
+    "validate_yaml",
+    "list_generators",
 }
 
 # Root of the repository (one level above this tests/ directory)
@@ -0,0 +1,96 @@
+"""
+Unit tests for cli/output.py, the renderer behind the global --output flag.
+
+Tests cover:
+  - Conversion of dataclasses, enums and paths to plain values
+  - JSON and YAML round-trips of the same result
+  - Table layout: column alignment, headers and cell formatting
+"""
+
+import dataclasses
+import enum
+import json
+import os
+import sys
+from pathlib import Path
+
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import output
+
+
+class Kind(enum.Enum):
+    deployment = "Deployment"
+    service = "Service"
+
+
+@dataclasses.dataclass
+class Manifest:
+    kind: Kind
+    name: str
+    path: Path
+    replicas: int = 1
+
+
+SAMPLE = [
+    Manifest(Kind.deployment, "my-app", Path("k8s/deployment.yaml"), 3),
+    Manifest(Kind.service, "my-app-svc", Path("k8s/service.yaml")),
+]
+
+
+class TestToPlain:
+    def test_dataclass_enum_and_path(self):
+        assert output.to_plain(SAMPLE[0]) == {
+            "kind": "Deployment", "name": "my-app", "path": "k8s/deployment.yaml", "replicas": 3,
+        }
+
+    def test_nested_containers(self):
+        assert output.to_plain({"files": (Path("a"), Path("b"))}) == {"files": ["a", "b"]}
+
+
+class TestStructuredFormats:
+    def test_json_round_trip(self):
+        data = json.loads(output.render(SAMPLE, "json"))
+        assert [item["kind"] for item in data] == ["Deployment", "Service"]
+
+    def test_yaml_round_trip_matches_json(self):
+        assert yaml.safe_load(output.render(SAMPLE, "yaml")) == json.loads(output.render(SAMPLE, "json"))
+
+    def test_yaml_keeps_field_order(self):
+        assert output.render({"b": 1, "a": 2}, "yaml") == "b: 1\na: 2"
+
+    def test_unknown_format(self):
+        with pytest.raises(ValueError, match="unknown output format"):
+            output.render(SAMPLE, "xml")
+
+
+class TestTable:
+    def test_list_of_records_is_aligned(self):
+        assert output.render(SAMPLE).splitlines() == [
+            "KIND        NAME        PATH                 REPLICAS",
+            "Deployment  my-app      k8s/deployment.yaml  3",
+            "Service     my-app-svc  k8s/service.yaml     1",
+        ]
+
+    def test_mapping_is_field_value_rows(self):
+        table = output.render({"command": "generate helm", "files": ["a.yaml", "b.yaml"], "force": False})
+        assert table.splitlines() == [
+            "FIELD    VALUE",
+            "command  generate helm",
+            "files    a.yaml, b.yaml",
+            "force    false",
+        ]
+
+    def test_missing_keys_are_blank(self):
+        table = output.render([{"name": "a", "port": 80}, {"name": "bb"}])
+        assert table.splitlines() == ["NAME  PORT", "a     80", "bb"]
+
+    def test_list_of_scalars(self):
+        assert output.render(["x", "yy"]) == "VALUE\nx\nyy"
+
+    def test_no_trailing_whitespace(self):
+        assert all(line == line.rstrip() for line in output.render(SAMPLE).splitlines())
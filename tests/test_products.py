@@ -0,0 +1,229 @@
+"""
+Tests for cli/products.py and `devopsos products adjust-price`.
+
+The go-project API is simulated by a small HTTP server on a random local
+port that serves the product endpoints the command uses:
+  - GET /api/products with category, limit and offset
+  - GET /api/products/{id} with an ETag
+  - PUT /api/products/{id} honouring If-Match
+"""
+
+import copy
+import json
+import os
+import re
+import sys
+import threading
+import urllib.parse
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+
+import pytest
+from typer.testing import CliRunner
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import products
+from cli.devopsos import app
+
+
+def _product(pid, name, amount, category="books", currency="USD"):
+    return {"id": pid, "name": name, "price": {"amount": amount, "currency": currency},
+            "stock": 3, "category": category}
+
+
+class FakeAPI:
+    """An in-memory stand-in for the go-project product endpoints."""
+
+    def __init__(self, items):
+        self.products = {p["id"]: copy.deepcopy(p) for p in items}
+        self.versions = {pid: 1 for pid in self.products}
+        self.fail_put = set()
+        # Products someone else edits right after the command reads them
+        self.edit_after_get = set()
+        self.puts = []
+        self.page_requests = 0
+
+    def etag(self, pid):
+        return f'W/"{pid}-{self.versions[pid]}"'
+
+    def handler(self):
+        api = self
+
+        class Handler(BaseHTTPRequestHandler):
+            def log_message(self, *args):
+                pass
+
+            def _send(self, status, body, headers=None):
+                data = json.dumps(body).encode()
+                self.send_response(status)
+                self.send_header("Content-Type", "application/json")
+                for key, value in (headers or {}).items():
+                    self.send_header(key, value)
+                self.send_header("Content-Length", str(len(data)))
+                self.end_headers()
+                self.wfile.write(data)
+
+            def _error(self, status, message):
+                self._send(status, {"error": {"code": "error", "message": message}})
+
+            def do_GET(self):
+                url = urllib.parse.urlparse(self.path)
+                if url.path == "/api/products":
+                    api.page_requests += 1
+                    q = urllib.parse.parse_qs(url.query)
+                    limit, offset = int(q["limit"][0]), int(q["offset"][0])
+                    matches = [p for _, p in sorted(api.products.items())
+                               if p.get("category") == q["category"][0]]
+                    data = matches[offset:offset + limit]
+                    next_offset = offset + len(data) if offset + len(data) < len(matches) else None
+                    self._send(200, {"data": data, "pagination": {"total": len(matches), "next_offset": next_offset}})
+                    return
+                match = re.fullmatch(r"/api/products/(\d+)", url.path)
+                if not match or int(match.group(1)) not in api.products:
+                    self._error(404, "product not found")
+                    return
+                pid = int(match.group(1))
+                etag = api.etag(pid)
+                if pid in api.edit_after_get:
+                    api.versions[pid] += 1
+                self._send(200, api.products[pid], {"ETag": etag})
+
+            def do_PUT(self):
+                pid = int(self.path.rsplit("/", 1)[1])
+                body = json.loads(self.rfile.read(int(self.headers["Content-Length"])))
+                api.puts.append((pid, body, self.headers.get("If-Match")))
+                if pid in api.fail_put:
+                    self._error(500, "internal server error")
+                    return
+                if self.headers.get("If-Match") not in (None, api.etag(pid)):
+                    self._error(412, "resource has changed; fetch it again before updating")
+                    return
+                api.products[pid].update(body)
+                api.versions[pid] += 1
+                self._send(200, api.products[pid], {"ETag": api.etag(pid)})
+
+        return Handler
+
+
+@pytest.fixture
+def serve():
+    """Start a FakeAPI holding the given products and return ``(api, url)``."""
+    servers = []
+
+    def start(items):
+        api = FakeAPI(items)
+        server = ThreadingHTTPServer(("127.0.0.1", 0), api.handler())
+        threading.Thread(target=server.serve_forever, daemon=True).start()
+        servers.append(server)
+        return api, f"http://127.0.0.1:{server.server_address[1]}"
+
+    yield start
+    for server in servers:
+        server.shutdown()
+        server.server_close()
+
+
+class TestAdjustedAmount:
+    @pytest.mark.parametrize("amount, percent, want", [
+        ("10.00", 10, "11.00"),
+        ("10.00", -15, "8.50"),
+        ("0.05", 10, "0.06"),    # 0.055 rounds half away from zero
+        ("19.99", 0, "19.99"),
+        ("1000", 3.5, "1035"),   # zero-decimal currencies such as JPY
+        ("12.34", -100, "0.00"),
+    ])
+    def test_rounds_to_the_same_places(self, amount, percent, want):
+        assert products.adjusted_amount(amount, percent) == want
+
+    def test_rejects_non_decimal(self):
+        with pytest.raises(ValueError):
+            products.adjusted_amount("abc", 10)
+
+
+class TestAdjustPrices:
+    def test_updates_every_product_in_the_category(self, serve):
+        api, url = serve([
+            _product(1, "Go in Action", "30.00"),
+            _product(2, "Widget", "5.00", category="tools"),
+            _product(3, "The Go Book", "19.99"),
+        ])
+        rows = products.adjust_prices(url, "books", 10)
+        assert [(r["id"], r["before"], r["after"], r["status"]) for r in rows] == [
+            (1, "30.00", "33.00", "updated"),
+            (3, "19.99", "21.99", "updated"),
+        ]
+        assert api.products[1]["price"] == {"amount": "33.00", "currency": "USD"}
+        assert api.products[2]["price"]["amount"] == "5.00"
+        # The rest of the product is sent back unchanged, with the ETag read
+        pid, body, if_match = api.puts[0]
+        assert body == {"name": "Go in Action", "price": {"amount": "33.00", "currency": "USD"},
+                        "stock": 3, "category": "books"}
+        assert if_match == 'W/"1-1"'
+
+    def test_dry_run_does_not_write(self, serve):
+        api, url = serve([_product(1, "Go in Action", "30.00")])
+        rows = products.adjust_prices(url, "books", -10, dry_run=True)
+        assert rows[0]["after"] == "27.00" and rows[0]["status"] == "planned"
+        assert api.puts == []
+        assert api.products[1]["price"]["amount"] == "30.00"
+
+    def test_follows_pagination(self, serve, monkeypatch):
+        monkeypatch.setattr(products, "PAGE_SIZE", 2)
+        api, url = serve([_product(i, f"Book {i}", "1.00") for i in range(1, 6)])
+        rows = products.adjust_prices(url, "books", 50, dry_run=True)
+        assert [r["id"] for r in rows] == [1, 2, 3, 4, 5]
+        assert api.page_requests == 3
+
+    def test_continues_after_a_failed_product(self, serve):
+        api, url = serve([_product(1, "A", "10.00"), _product(2, "B", "10.00"), _product(3, "C", "10.00")])
+        api.fail_put.add(2)
+        rows = products.adjust_prices(url, "books", 10)
+        assert [r["status"] for r in rows] == ["updated", "error: 500 internal server error", "updated"]
+        assert api.products[3]["price"]["amount"] == "11.00"
+
+    def test_reports_a_product_changed_meanwhile(self, serve):
+        api, url = serve([_product(1, "A", "10.00")])
+        api.edit_after_get.add(1)
+        rows = products.adjust_prices(url, "books", 10)
+        assert rows[0]["status"].startswith("error: 412")
+        assert api.products[1]["price"]["amount"] == "10.00"
+
+    def test_unreachable_api_raises(self):
+        with pytest.raises(products.APIError):
+            products.adjust_prices("http://127.0.0.1:1", "books", 10)
+
+
+class TestAdjustPriceCommand:
+    def invoke(self, *args):
+        return CliRunner().invoke(app, list(args))
+
+    def test_prints_before_and_after_table(self, serve):
+        _, url = serve([_product(1, "Go in Action", "30.00")])
+        result = self.invoke("products", "adjust-price", "--category", "books", "--percent", "10",
+                             "--dry-run", "--api-url", url)
+        assert result.exit_code == 0, result.output
+        header = result.output.splitlines()[0].split()
+        assert header[:6] == ["ID", "NAME", "CURRENCY", "BEFORE", "AFTER", "STATUS"]
+        assert "30.00" in result.output and "33.00" in result.output
+        assert "Dry run" in result.output
+
+    def test_json_output(self, serve):
+        _, url = serve([_product(1, "Go in Action", "30.00")])
+        result = self.invoke("-o", "json", "products", "adjust-price", "--category", "books",
+                             "--percent", "10", "--api-url", url)
+        assert result.exit_code == 0, result.output
+        assert json.loads(result.output)[0]["after"] == "33.00"
+
+    def test_exit_status_reports_failures(self, serve):
+        api, url = serve([_product(1, "A", "10.00"), _product(2, "B", "10.00")])
+        api.fail_put.add(1)
+        result = self.invoke("products", "adjust-price", "--category", "books", "--percent", "10",
+                             "--api-url", url)
+        assert result.exit_code == 1
+        assert "updated" in result.output
+
+    def test_requires_category_and_percent(self):
+        result = self.invoke("products", "adjust-price", "--percent", "10")
+        assert result.exit_code != 0
+        assert "--category" in result.output
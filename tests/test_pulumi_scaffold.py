@@ -0,0 +1,125 @@
+"""
+Unit tests for the DevOps-OS Pulumi project generator.
+
+Tests cover:
+  - Language / cloud validation
+  - Rendered project, stack, program and dependency files
+  - --dry-run and overwrite protection in main()
+"""
+
+import argparse
+import os
+import sys
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_pulumi
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _pulumi_args(**kwargs):
+    defaults = dict(language="go", cloud="aws", name="my-app", region="",
+                    out="infra", dry_run=False, force=False)
+    defaults.update(kwargs)
+    return argparse.Namespace(**defaults)
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_pulumi.py", *flags])
+    scaffold_pulumi.main()
+
+
+# ---------------------------------------------------------------------------
+# Validation
+# ---------------------------------------------------------------------------
+
+class TestValidateTarget:
+    @pytest.mark.parametrize("language,cloud", sorted(scaffold_pulumi.PROVIDERS))
+    def test_supported_pairs(self, language, cloud):
+        scaffold_pulumi.validate_target(language, cloud)
+
+    def test_unknown_language(self):
+        with pytest.raises(ValueError, match="unsupported language 'java'"):
+            scaffold_pulumi.validate_target("java", "aws")
+
+    def test_unknown_cloud(self):
+        with pytest.raises(ValueError, match="unsupported cloud 'oracle'"):
+            scaffold_pulumi.validate_target("go", "oracle")
+
+    def test_unsupported_pair(self, monkeypatch):
+        providers = dict(scaffold_pulumi.PROVIDERS)
+        del providers[("go", "azure")]
+        monkeypatch.setattr(scaffold_pulumi, "PROVIDERS", providers)
+        with pytest.raises(ValueError, match="go is not supported on azure"):
+            scaffold_pulumi.validate_target("go", "azure")
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderPulumi:
+    @pytest.mark.parametrize("language,cloud", sorted(scaffold_pulumi.PROVIDERS))
+    def test_every_pair_renders(self, language, cloud):
+        files = scaffold_pulumi.render_pulumi(_pulumi_args(language=language, cloud=cloud))
+        lang = scaffold_pulumi.LANGUAGES[language]
+        assert sorted(files) == sorted(["Pulumi.yaml", "Pulumi.dev.yaml", lang["program"], lang["deps"]])
+        assert yaml.safe_load(files["Pulumi.yaml"])["name"] == "my-app"
+        assert "config" in yaml.safe_load(files["Pulumi.dev.yaml"])
+
+    def test_go_aws(self):
+        files = scaffold_pulumi.render_pulumi(_pulumi_args(language="go", cloud="aws"))
+        assert yaml.safe_load(files["Pulumi.yaml"])["runtime"] == "go"
+        assert yaml.safe_load(files["Pulumi.dev.yaml"])["config"]["aws:region"] == "us-east-1"
+        assert "pulumi.Run(" in files["main.go"]
+        assert "github.com/pulumi/pulumi-aws/sdk/v6/go/aws/s3" in files["main.go"]
+        assert "github.com/pulumi/pulumi-aws/sdk/v6 v6.50.0" in files["go.mod"]
+
+    def test_typescript_runtime(self):
+        files = scaffold_pulumi.render_pulumi(_pulumi_args(language="typescript", cloud="gcp"))
+        assert yaml.safe_load(files["Pulumi.yaml"])["runtime"]["name"] == "nodejs"
+        assert '"@pulumi/gcp"' in files["package.json"]
+
+    def test_python_runtime(self):
+        files = scaffold_pulumi.render_pulumi(_pulumi_args(language="python", cloud="azure", region="westeurope"))
+        assert yaml.safe_load(files["Pulumi.yaml"])["runtime"]["name"] == "python"
+        assert yaml.safe_load(files["Pulumi.dev.yaml"])["config"]["azure-native:location"] == "westeurope"
+        assert "pulumi-azure-native>=2.0.0,<3.0.0" in files["requirements.txt"]
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_writes_go_aws_project(self, tmp_path, monkeypatch):
+        _run_main(monkeypatch, "--language", "go", "--cloud", "aws", "--out", str(tmp_path))
+        for name in ("Pulumi.yaml", "Pulumi.dev.yaml", "main.go", "go.mod"):
+            assert (tmp_path / name).exists(), f"Expected {name} to be generated"
+
+    def test_dry_run_writes_nothing(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "infra"
+        _run_main(monkeypatch, "--out", str(out), "--dry-run")
+        printed = capsys.readouterr().out
+        assert "Pulumi.yaml" in printed and "main.go" in printed
+        assert not out.exists()
+
+    def test_invalid_language_exits_with_error(self, tmp_path, monkeypatch, capsys):
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--language", "java", "--out", str(tmp_path))
+        assert exc.value.code == 1
+        assert "unsupported language 'java'" in capsys.readouterr().err
+        assert list(tmp_path.iterdir()) == []
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        (tmp_path / "Pulumi.yaml").write_text("name: existing\n")
+        with pytest.raises(SystemExit):
+            _run_main(monkeypatch, "--out", str(tmp_path))
+        assert "--force" in capsys.readouterr().err
+        assert (tmp_path / "Pulumi.yaml").read_text() == "name: existing\n"
@@ -0,0 +1,148 @@
+"""
+Tests for cli/server_config.py and `devopsos config validate`.
+
+Tests cover:
+  - The flags and working directory the server command is run with
+  - Passing the report and a failing exit status through
+  - Resolving relative paths in the environment against the caller's directory
+  - Exit status 2 when the server exits without a report
+  - A real go-project server rejecting a missing TLS certificate (needs a Go toolchain)
+"""
+
+import json
+import os
+import shutil
+import subprocess
+import sys
+import textwrap
+
+import pytest
+from typer.testing import CliRunner
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import server_config
+from cli.devopsos import app
+
+GO_PROJECT = os.path.join(os.path.dirname(__file__), "..", "go-project")
+
+requires_go = pytest.mark.skipif(shutil.which("go") is None, reason="Go toolchain not installed")
+
+
+@pytest.fixture
+def fake_server(tmp_path):
+    """A stand-in server that records its arguments, working directory and TLS_CERT_FILE.
+
+    It fails its checks when FAKE_SERVER_FAIL is set, as the real server
+    does when a check fails, and exits without a report when
+    FAKE_SERVER_CRASH is set, as ``go run`` does when compiling fails.
+    """
+    script = tmp_path / "server.py"
+    script.write_text(textwrap.dedent("""\
+        import json, os, sys
+        if os.environ.get("FAKE_SERVER_CRASH"):
+            print("cmd/main.go:1:1: syntax error", file=sys.stderr)
+            sys.exit(1)
+        print(json.dumps({"args": sys.argv[1:], "cwd": os.getcwd(), "cert": os.environ.get("TLS_CERT_FILE")}))
+        if os.environ.get("FAKE_SERVER_FAIL"):
+            print("FAIL  TLS  TLS_CERT_FILE: no such file", file=sys.stdout)
+            print("configuration is invalid")
+            sys.exit(1)
+        print("configuration is valid")
+    """))
+    return f"{sys.executable} {script}"
+
+
+def invoke(*args, env=None):
+    return CliRunner(mix_stderr=False).invoke(app, ["config", "validate", *args], env=env)
+
+
+class TestValidate:
+    def test_runs_the_server_with_validate_config(self, fake_server, tmp_path):
+        result = server_config.validate(None, fake_server, str(tmp_path))
+        assert result.returncode == 0
+        assert '"args": ["-validate-config"]' in result.stdout
+        assert os.path.samefile(tmp_path, result.stdout.split('"cwd": "')[1].split('"')[0])
+
+    def test_passes_the_config_file_as_an_absolute_path(self, fake_server, tmp_path, monkeypatch):
+        monkeypatch.chdir(tmp_path)
+        (tmp_path / "prod.json").write_text("{}")
+        result = server_config.validate("prod.json", fake_server, str(tmp_path))
+        assert f'"-config", "{(tmp_path / "prod.json").resolve()}"' in result.stdout
+
+    def test_resolves_relative_env_paths_against_the_caller(self, fake_server, tmp_path, monkeypatch):
+        caller, project = tmp_path / "caller", tmp_path / "project"
+        caller.mkdir()
+        project.mkdir()
+        monkeypatch.chdir(caller)
+        monkeypatch.setenv("TLS_CERT_FILE", "cert.pem")
+        monkeypatch.setenv("AUDIT_LOG", "-")
+        result = server_config.validate(None, fake_server, str(project))
+        report = json.loads(result.stdout.splitlines()[0])
+        assert report["cert"] == str((caller / "cert.pem").resolve())
+
+    def test_server_env_leaves_absolute_paths_and_stdout_alone(self, tmp_path):
+        env = server_config.server_env({"TLS_KEY_FILE": str(tmp_path / "key.pem"), "AUDIT_LOG": "-", "PORT": "8080"})
+        assert env == {"TLS_KEY_FILE": str(tmp_path / "key.pem"), "AUDIT_LOG": "-", "PORT": "8080"}
+
+    def test_missing_server_raises(self, tmp_path):
+        with pytest.raises(server_config.ServerError):
+            server_config.validate(None, "no-such-server-binary", str(tmp_path))
+
+
+class TestValidateCommand:
+    def test_passing_config_exits_zero(self, fake_server, tmp_path):
+        result = invoke("--server", fake_server, "--project-dir", str(tmp_path))
+        assert result.exit_code == 0, result.output
+        assert "configuration is valid" in result.stdout
+
+    def test_failing_check_exits_non_zero(self, fake_server, tmp_path):
+        result = invoke("--server", fake_server, "--project-dir", str(tmp_path), env={"FAKE_SERVER_FAIL": "1"})
+        assert result.exit_code == 1
+        assert "FAIL  TLS" in result.stdout
+
+    def test_server_failing_without_a_report_exits_two(self, fake_server, tmp_path):
+        result = invoke("--server", fake_server, "--project-dir", str(tmp_path), env={"FAKE_SERVER_CRASH": "1"})
+        assert result.exit_code == 2
+        assert "syntax error" in result.stderr
+        assert "without a report" in result.stderr
+
+    def test_unstartable_server_is_reported(self, tmp_path):
+        result = invoke("--server", "no-such-server-binary", "--project-dir", str(tmp_path))
+        assert result.exit_code == 2
+        assert "could not run" in result.stderr
+
+
+@requires_go
+class TestGoProjectServer:
+    @pytest.fixture(scope="class")
+    def server_binary(self, tmp_path_factory):
+        binary = tmp_path_factory.mktemp("bin") / "server"
+        env = dict(os.environ, GOFLAGS="-mod=mod", GOTOOLCHAIN="local")
+        subprocess.run(["go", "build", "-o", str(binary), "./cmd"], cwd=GO_PROJECT, env=env, check=True)
+        return str(binary)
+
+    def test_missing_tls_cert_fails_and_names_the_check(self, server_binary, tmp_path):
+        env = {"TLS_CERT_FILE": str(tmp_path / "missing-cert.pem"), "TLS_KEY_FILE": str(tmp_path / "key.pem")}
+        result = invoke("--server", server_binary, "--project-dir", GO_PROJECT, env=env)
+        assert result.exit_code == 1, result.stdout
+        failing = [line for line in result.stdout.splitlines() if line.startswith("FAIL")]
+        assert len(failing) == 1
+        assert "TLS" in failing[0] and "missing-cert.pem" in failing[0]
+        assert "configuration is invalid" in result.stdout
+
+    def test_relative_tls_cert_is_read_from_the_callers_directory(self, server_binary, tmp_path, monkeypatch):
+        monkeypatch.chdir(tmp_path)
+        env = {"TLS_CERT_FILE": "missing-cert.pem", "TLS_KEY_FILE": "key.pem"}
+        result = invoke("--server", server_binary, "--project-dir", GO_PROJECT, env=env)
+        assert result.exit_code == 1, result.stdout
+        failing = [line for line in result.stdout.splitlines() if line.startswith("FAIL")]
+        assert len(failing) == 1 and str(tmp_path / "missing-cert.pem") in failing[0]
+
+    def test_bad_config_file_fails(self, server_binary, tmp_path):
+        config_file = tmp_path / "server.json"
+        config_file.write_text('{"rate_limit_rps": 0}')
+        result = invoke("--config", str(config_file), "--server", server_binary, "--project-dir", GO_PROJECT)
+        assert result.exit_code == 1
+        assert "rate_limit_rps" in result.stdout
@@ -0,0 +1,140 @@
+"""
+Unit tests for the DevOps-OS Terraform skeleton generator.
+
+Tests cover:
+  - Template rendering helpers (cli.templating)
+  - Provider and backend blocks for each supported cloud
+  - Refusing to overwrite existing files unless --force is passed
+"""
+
+import argparse
+import os
+import sys
+import pytest
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_terraform, templating
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _terraform_args(**kwargs):
+    defaults = dict(cloud="aws", name="my-app", region="", out="infra", force=False)
+    defaults.update(kwargs)
+    return argparse.Namespace(**defaults)
+
+
+def _run_main(monkeypatch, *flags):
+    monkeypatch.setattr(sys, "argv", ["scaffold_terraform.py", *flags])
+    scaffold_terraform.main()
+
+
+# ---------------------------------------------------------------------------
+# Templating helpers
+# ---------------------------------------------------------------------------
+
+class TestTemplating:
+    def test_render_replaces_placeholders(self):
+        assert templating.render("name = __NAME__", {"NAME": "app"}) == "name = app"
+
+    def test_render_rejects_unresolved_placeholders(self):
+        with pytest.raises(ValueError, match="__REGION__"):
+            templating.render("region = __REGION__", {})
+
+    def test_write_files_refuses_to_overwrite(self, tmp_path):
+        target = tmp_path / "main.tf"
+        target.write_text("keep")
+        other = tmp_path / "variables.tf"
+        with pytest.raises(FileExistsError, match="main.tf"):
+            templating.write_files({target: "new", other: "new"})
+        assert target.read_text() == "keep"
+        assert not other.exists(), "nothing may be written when any file conflicts"
+
+    def test_write_files_force_overwrites(self, tmp_path):
+        target = tmp_path / "main.tf"
+        target.write_text("old")
+        templating.write_files({target: "new"}, force=True)
+        assert target.read_text() == "new"
+
+
+# ---------------------------------------------------------------------------
+# Rendering
+# ---------------------------------------------------------------------------
+
+class TestRenderTerraform:
+    @pytest.mark.parametrize("cloud", scaffold_terraform.CLOUDS)
+    def test_renders_all_files(self, cloud):
+        files = scaffold_terraform.render_terraform(_terraform_args(cloud=cloud))
+        assert sorted(files) == sorted(scaffold_terraform.TEMPLATE_FILES)
+
+    @pytest.mark.parametrize("cloud,provider,source,backend", [
+        ("aws", 'provider "aws"', "hashicorp/aws", 'backend "s3"'),
+        ("gcp", 'provider "google"', "hashicorp/google", 'backend "gcs"'),
+        ("azure", 'provider "azurerm"', "hashicorp/azurerm", 'backend "azurerm"'),
+    ])
+    def test_provider_and_backend_blocks(self, cloud, provider, source, backend):
+        files = scaffold_terraform.render_terraform(_terraform_args(cloud=cloud))
+        assert provider in files["provider.tf"]
+        assert f'source  = "{source}"' in files["provider.tf"]
+        assert backend in files["backend.tf"]
+
+    @pytest.mark.parametrize("cloud", scaffold_terraform.CLOUDS)
+    def test_default_region(self, cloud):
+        files = scaffold_terraform.render_terraform(_terraform_args(cloud=cloud))
+        assert f'default     = "{scaffold_terraform.DEFAULT_REGIONS[cloud]}"' in files["variables.tf"]
+
+    def test_explicit_region(self):
+        files = scaffold_terraform.render_terraform(_terraform_args(region="eu-west-1"))
+        assert 'default     = "eu-west-1"' in files["variables.tf"]
+        assert 'region         = "eu-west-1"' in files["backend.tf"]
+
+    def test_name_used_for_state_bucket(self):
+        files = scaffold_terraform.render_terraform(_terraform_args(name="billing"))
+        assert 'bucket         = "billing-tfstate"' in files["backend.tf"]
+
+    def test_azure_storage_account_name_is_valid(self):
+        files = scaffold_terraform.render_terraform(
+            _terraform_args(cloud="azure", name="My-Very-Long-Billing-Service"))
+        account = files["backend.tf"].split('storage_account_name = "')[1].split('"')[0]
+        assert account.isalnum() and account.islower()
+        assert 3 <= len(account) <= 24
+
+    def test_gcp_requires_project_id(self):
+        files = scaffold_terraform.render_terraform(_terraform_args(cloud="gcp"))
+        assert 'variable "project_id"' in files["variables.tf"]
+        assert "project = var.project_id" in files["provider.tf"]
+
+
+# ---------------------------------------------------------------------------
+# main()
+# ---------------------------------------------------------------------------
+
+class TestMain:
+    def test_generates_files_into_out_dir(self, tmp_path, monkeypatch):
+        out = tmp_path / "infra"
+        _run_main(monkeypatch, "--cloud", "gcp", "--out", str(out))
+        for name in scaffold_terraform.TEMPLATE_FILES:
+            assert (out / name).exists(), f"Expected {name} to be generated"
+        assert 'provider "google"' in (out / "provider.tf").read_text()
+
+    def test_refuses_to_overwrite_without_force(self, tmp_path, monkeypatch, capsys):
+        out = tmp_path / "infra"
+        out.mkdir()
+        (out / "main.tf").write_text("# hand-written")
+        with pytest.raises(SystemExit) as exc:
+            _run_main(monkeypatch, "--out", str(out))
+        assert exc.value.code == 1
+        assert "--force" in capsys.readouterr().err
+        assert (out / "main.tf").read_text() == "# hand-written"
+        assert not (out / "provider.tf").exists()
+
+    def test_force_overwrites(self, tmp_path, monkeypatch):
+        out = tmp_path / "infra"
+        out.mkdir()
+        (out / "main.tf").write_text("# hand-written")
+        _run_main(monkeypatch, "--out", str(out), "--force")
+        assert "locals" in (out / "main.tf").read_text()
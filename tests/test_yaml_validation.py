@@ -0,0 +1,132 @@
+"""
+Unit tests for cli/yaml_validation.py (used by the validate_yaml MCP tool).
+
+Tests cover:
+  - Generated Deployment, Service and ArgoCD Application output validates
+  - Line numbers for mistyped, unknown and missing fields
+  - Schema hints, multi-document input and YAML syntax errors
+"""
+
+import argparse
+import os
+import sys
+
+import pytest
+import yaml
+
+# Ensure repo root is on path
+sys.path.insert(0, os.path.join(os.path.dirname(__file__), ".."))
+
+from cli import scaffold_argocd_app, scaffold_k8s
+from cli.yaml_validation import ValidationError, validate_yaml
+
+SERVICE = """\
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-app
+spec:
+  selector:
+    app: my-app
+  ports:
+    - port: 80
+      targetPort: http
+"""
+
+
+def _k8s_manifests():
+    args = argparse.Namespace(
+        name="my-app", image="ghcr.io/myorg/my-app:1.0.0", replicas=2, port=8080,
+        namespace="default", cpu_request="100m", memory_request="64Mi",
+        cpu_limit="500m", memory_limit="128Mi", liveness_path="/healthz",
+        readiness_path="/readyz", ingress_host="my-app.example.com",
+        ingress_class="nginx", out="k8s", force=False,
+    )
+    return scaffold_k8s.render_manifests(args)
+
+
+def _argocd_app():
+    args = argparse.Namespace(
+        app_name="my-app", repo_url="https://github.com/myorg/my-app.git", path="k8s",
+        revision="HEAD", dest_namespace="default", dest_server="https://kubernetes.default.svc",
+        project="", appproject=False, allow_any_source_repo=False, auto_sync=True,
+        out="", force=False,
+    )
+    return scaffold_argocd_app.render_documents(args)
+
+
+# ---------------------------------------------------------------------------
+# Generator output
+# ---------------------------------------------------------------------------
+
+class TestGeneratedOutput:
+    @pytest.mark.parametrize("name", ["deployment.yaml", "service.yaml", "ingress.yaml"])
+    def test_k8s_manifests_are_valid_in_strict_mode(self, name):
+        assert validate_yaml(_k8s_manifests()[name], strict=True) == []
+
+    def test_argocd_application_is_valid_in_strict_mode(self):
+        assert validate_yaml(_argocd_app(), schema="argocd-app", strict=True) == []
+
+
+# ---------------------------------------------------------------------------
+# Line numbers
+# ---------------------------------------------------------------------------
+
+class TestLineNumbers:
+    def test_mistyped_field(self):
+        content = SERVICE.replace("port: 80", "port: eighty")
+        assert validate_yaml(content) == [ValidationError(
+            line=9, path="spec.ports[0].port", message="spec.ports[0].port: must be an integer")]
+
+    def test_missing_field_points_at_parent(self):
+        content = SERVICE.replace("    - port: 80\n      targetPort: http\n", "    - name: http\n")
+        (problem,) = validate_yaml(content)
+        assert (problem.line, problem.path) == (9, "spec.ports[0].port")
+        assert problem.message.endswith("is required")
+
+    def test_missing_api_version_points_at_document_start(self):
+        (problem,) = validate_yaml("# service\n" + SERVICE.replace("apiVersion: v1\n", ""))
+        assert (problem.line, problem.message) == (2, "apiVersion: is required")
+
+    def test_unknown_field_only_in_strict_mode(self):
+        content = SERVICE.replace("  selector:", "  clusterIP: None\n  selector:")
+        assert validate_yaml(content) == []
+        (problem,) = validate_yaml(content, strict=True)
+        assert (problem.line, problem.message) == (6, "spec.clusterIP: unknown field")
+
+
+# ---------------------------------------------------------------------------
+# Input handling
+# ---------------------------------------------------------------------------
+
+class TestInput:
+    def test_schema_hint_overrides_kind(self):
+        messages = [p.message for p in validate_yaml(SERVICE, schema="deployment")]
+        assert "kind: must be 'Deployment', got 'Service'" in messages
+
+    def test_unknown_schema(self):
+        with pytest.raises(ValueError, match="schema must be one of"):
+            validate_yaml(SERVICE, schema="pod")
+
+    def test_unknown_kind(self):
+        (problem,) = validate_yaml("apiVersion: v1\nkind: Pod\n")
+        assert "kind: must be one of" in problem.message
+
+    def test_multiple_documents(self):
+        broken = SERVICE.replace("port: 80", "port: '80'")
+        problems = validate_yaml(SERVICE + "---\n" + broken)
+        assert [(p.document, p.line) for p in problems] == [(2, 20)]
+
+    def test_syntax_error_reports_line(self):
+        (problem,) = validate_yaml("apiVersion: v1\nkind: Service\nmetadata: [\n")
+        assert problem.line == 4
+        assert problem.message.startswith("invalid YAML:")
+
+    @pytest.mark.parametrize("content", ["", "# just a comment\n"])
+    def test_empty_input(self, content):
+        (problem,) = validate_yaml(content)
+        assert problem.message == "no YAML documents found"
+
+    def test_non_mapping_document(self):
+        (problem,) = validate_yaml(yaml.safe_dump(["a", "b"]))
+        assert (problem.line, problem.path, problem.message) == (1, "", "document: must be a mapping")